@@ -0,0 +1,980 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/compliance"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/duration"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/eventstream"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/licensepolicy"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/query"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/remediation"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/rollout"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/sbom"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/casks"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/siem"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/staledevices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/vulnreport"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/vulnwatch"
+)
+
+// action executes a parsed command
+type action func(ctx context.Context, a *app, args []string) error
+
+// command is a single action on a resource, e.g. "devices list"
+type command struct {
+	name    string
+	args    string
+	summary string
+
+	// nargs is the exact number of positional arguments required, or -1 for any
+	nargs int
+
+	// setup registers command specific flags and returns the action that uses them
+	setup func(fs *flag.FlagSet) action
+}
+
+// resource groups the commands for one API service
+type resource struct {
+	name     string
+	commands []command
+}
+
+func (r *resource) find(name string) *command {
+	for i := range r.commands {
+		if r.commands[i].name == name {
+			return &r.commands[i]
+		}
+	}
+	return nil
+}
+
+func findResource(name string) *resource {
+	for i := range resources {
+		if resources[i].name == name {
+			return &resources[i]
+		}
+	}
+	return nil
+}
+
+// fetchFunc calls a JSON endpoint and returns the decoded result
+type fetchFunc func(ctx context.Context, a *app, args []string) (any, error)
+
+// fetchCSVFunc calls the matching CSV export endpoint
+type fetchCSVFunc func(ctx context.Context, a *app, args []string) ([]byte, error)
+
+// jsonResult adapts a service method's return values for rendering
+func jsonResult[T any](result *T, _ *interfaces.Response, err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// csvResult adapts a service CSV method's return values
+func csvResult(data []byte, _ *interfaces.Response, err error) ([]byte, error) {
+	return data, err
+}
+
+// fetchAndRender renders a JSON result, or streams the CSV export when -o csv is selected
+func fetchAndRender(ctx context.Context, a *app, args []string, fetch fetchFunc, fetchCSV fetchCSVFunc) error {
+	if a.format == formatCSV && fetchCSV != nil {
+		data, err := fetchCSV(ctx, a, args)
+		if err != nil {
+			return err
+		}
+		if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+			data = append(data, '\n')
+		}
+		_, err = a.stdout.Write(data)
+		return err
+	}
+
+	result, err := fetch(ctx, a, args)
+	if err != nil {
+		return err
+	}
+	return a.render(result)
+}
+
+// listCommand builds a command without flags that renders a list endpoint
+func listCommand(name, args, summary string, nargs int, fetch fetchFunc, fetchCSV fetchCSVFunc) command {
+	return command{
+		name:    name,
+		args:    args,
+		summary: summary,
+		nargs:   nargs,
+		setup: func(fs *flag.FlagSet) action {
+			return func(ctx context.Context, a *app, positional []string) error {
+				return fetchAndRender(ctx, a, positional, fetch, fetchCSV)
+			}
+		},
+	}
+}
+
+// queryCommand builds a command that lists every item of an endpoint, then
+// filters, sorts, projects or groups them on the client
+func queryCommand[T any](list func(ctx context.Context, a *app) iter.Seq2[T, error]) command {
+	return command{
+		name:    "query",
+		summary: "Filter, sort, project or group the list on the client, e.g. --where 'outdated && devices > 10'",
+		nargs:   0,
+		setup: func(fs *flag.FlagSet) action {
+			q := query.Query{}
+			fs.StringVar(&q.Where, "where", "", "filter expression, e.g. 'outdated && name ~ \"^python\"'")
+			sortKeys := fs.String("sort", "", "comma-separated fields to sort by, each prefixed with - for descending order")
+			fields := fs.String("fields", "", "comma-separated fields to output instead of whole items")
+			fs.StringVar(&q.GroupBy, "group-by", "", "output the number of items per value of this field")
+			fs.IntVar(&q.Limit, "limit", 0, "output at most this many items, or groups with --group-by")
+			listFields := fs.Bool("list-fields", false, "print the fields a query can use and exit")
+			return func(ctx context.Context, a *app, _ []string) error {
+				if *listFields {
+					for _, name := range query.Fields[T]() {
+						fmt.Fprintln(a.stdout, name)
+					}
+					return nil
+				}
+				q.Sort = splitList(*sortKeys)
+				q.Fields = splitList(*fields)
+
+				// Reject an invalid query before any API call is made
+				if _, err := query.Run([]T(nil), q); err != nil {
+					return err
+				}
+				var items []T
+				for item, err := range list(ctx, a) {
+					if err != nil {
+						return err
+					}
+					items = append(items, item)
+				}
+				result, err := query.Run(items, q)
+				if err != nil {
+					return err
+				}
+
+				switch {
+				case q.GroupBy != "":
+					return a.render(result.Groups)
+				case len(q.Fields) > 0:
+					return a.render(result.Rows)
+				}
+				return a.render(result.Items)
+			}
+		},
+	}
+}
+
+// resources is the command table covering every service method exposed by the SDK
+var resources = []resource{
+	{
+		name: "analytics",
+		commands: []command{
+			listCommand("list", "", "List command analytics", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.Analytics.ListAnalytics(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.Analytics.ListAnalyticsCSV(ctx))
+				}),
+		},
+	},
+	{
+		name: "brew-commands",
+		commands: []command{
+			listCommand("list", "", "List brew commands", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.BrewCommands.ListBrewCommands(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.BrewCommands.ListBrewCommandsCSV(ctx))
+				}),
+			{
+				name:    "create",
+				summary: "Create a brew command",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					arguments := fs.String("arguments", "", "brew arguments, e.g. \"install wget\" (required)")
+					deviceIDs := fs.String("device-ids", "", "comma-separated device UUIDs (default: all devices)")
+					runAfter := fs.String("run-after", "", "run after this local date time, e.g. 2025-01-10T10:09")
+					recurrence := fs.String("recurrence", "", "once, daily, weekly or monthly")
+					return func(ctx context.Context, a *app, _ []string) error {
+						if *arguments == "" {
+							return fmt.Errorf("--arguments is required")
+						}
+						request := &brewcommands.CreateBrewCommandRequest{
+							Arguments:        *arguments,
+							DeviceIDs:        optional(*deviceIDs),
+							RunAfterDatetime: optional(*runAfter),
+							Recurrence:       optional(*recurrence),
+						}
+						result, err := jsonResult(a.client.BrewCommands.CreateBrewCommand(ctx, request))
+						if err != nil {
+							return err
+						}
+						return a.render(result)
+					}
+				},
+			},
+			{
+				name:    "rollout",
+				summary: "Plan staged upgrades of outdated packages by device group, and execute them with --execute",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					opts := &rollout.Options{}
+					fs.Func("wave", "comma-separated device groups upgraded together; repeat for each wave, canary first", func(value string) error {
+						opts.Waves = append(opts.Waves, splitList(value))
+						return nil
+					})
+					exclude := fs.String("exclude", "", "comma-separated formulae and casks never upgraded")
+					fs.BoolVar(&opts.IncludeUngrouped, "include-ungrouped", false, "upgrade devices in no device group in the last wave")
+					execute := fs.Bool("execute", false, "create the brew commands; without it only the plan is printed")
+					execOpts := &rollout.ExecuteOptions{}
+					fs.Float64Var(&execOpts.MinSuccessRate, "min-success-rate", rollout.DefaultMinSuccessRate, "fraction of a wave's runs that must succeed before the next wave")
+					fs.DurationVar(&execOpts.WaveTimeout, "wave-timeout", time.Hour, "how long to wait for each wave's runs")
+					deviceIDsFile := fs.String("device-ids-file", "", "JSON file mapping device serial numbers to device UUIDs (required with --execute)")
+					return func(ctx context.Context, a *app, _ []string) error {
+						if *execute {
+							deviceID, err := loadDeviceIDs(*deviceIDsFile)
+							if err != nil {
+								return err
+							}
+							execOpts.DeviceID = deviceID
+						}
+						opts.Exclude = splitList(*exclude)
+						plan, err := rollout.NewPlan(ctx, rollout.NewSource(a.client), opts)
+						if err != nil {
+							return err
+						}
+						if !*execute {
+							fmt.Fprint(a.stdout, plan)
+							return nil
+						}
+
+						result, err := rollout.NewExecutor(a.client.BrewCommands, execOpts).Execute(ctx, plan)
+						if result != nil {
+							encoder := json.NewEncoder(a.stdout)
+							encoder.SetIndent("", "  ")
+							if encodeErr := encoder.Encode(result); encodeErr != nil && err == nil {
+								err = encodeErr
+							}
+						}
+						if err != nil {
+							return err
+						}
+						if !result.Completed {
+							return fmt.Errorf("rollout halted after wave %d: success rate below %.2f", result.HaltedAfter, execOpts.MinSuccessRate)
+						}
+						return nil
+					}
+				},
+			},
+			listCommand("runs", "<label>", "List runs of a brew command", 1,
+				func(ctx context.Context, a *app, args []string) (any, error) {
+					return jsonResult(a.client.BrewCommands.ListBrewCommandRuns(ctx, args[0]))
+				},
+				func(ctx context.Context, a *app, args []string) ([]byte, error) {
+					return csvResult(a.client.BrewCommands.ListBrewCommandRunsCSV(ctx, args[0]))
+				}),
+		},
+	},
+	{
+		name: "brew-configurations",
+		commands: []command{
+			listCommand("list", "", "List brew configurations", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.BrewConfigurations.ListBrewConfigurations(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.BrewConfigurations.ListBrewConfigurationsCSV(ctx))
+				}),
+		},
+	},
+	{
+		name: "brewfiles",
+		commands: []command{
+			listCommand("list", "", "List brewfiles", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.Brewfiles.ListBrewfiles(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.Brewfiles.ListBrewfilesCSV(ctx))
+				}),
+			{
+				name:    "create",
+				summary: "Create a brewfile",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					label := fs.String("label", "", "brewfile label (required)")
+					content := fs.String("content", "", "brewfile content")
+					contentFile := fs.String("content-file", "", "read brewfile content from a file (- for stdin)")
+					devices := fs.String("devices", "", "comma-separated device serial numbers")
+					group := fs.String("group-id", "", "device group ID")
+					return func(ctx context.Context, a *app, _ []string) error {
+						if *label == "" {
+							return fmt.Errorf("--label is required")
+						}
+						body, err := readContent(*content, *contentFile)
+						if err != nil {
+							return err
+						}
+						request := &brewfiles.CreateBrewfileRequest{
+							Label:               *label,
+							Content:             body,
+							DeviceSerialNumbers: optional(*devices),
+							DeviceGroupID:       optional(*group),
+						}
+						result, err := jsonResult(a.client.Brewfiles.CreateBrewfile(ctx, request))
+						if err != nil {
+							return err
+						}
+						return a.render(result)
+					}
+				},
+			},
+			{
+				name:    "update",
+				args:    "<label>",
+				summary: "Update a brewfile's content or assignment",
+				nargs:   1,
+				setup: func(fs *flag.FlagSet) action {
+					content := fs.String("content", "", "brewfile content")
+					contentFile := fs.String("content-file", "", "read brewfile content from a file (- for stdin)")
+					devices := fs.String("devices", "", "comma-separated device serial numbers")
+					group := fs.String("group-id", "", "device group ID")
+					return func(ctx context.Context, a *app, args []string) error {
+						body, err := readContent(*content, *contentFile)
+						if err != nil {
+							return err
+						}
+						request := &brewfiles.UpdateBrewfileRequest{
+							Content:             body,
+							DeviceSerialNumbers: optional(*devices),
+							DeviceGroupID:       optional(*group),
+						}
+						result, err := jsonResult(a.client.Brewfiles.UpdateBrewfile(ctx, args[0], request))
+						if err != nil {
+							return err
+						}
+						return a.render(result)
+					}
+				},
+			},
+			{
+				name:    "delete",
+				args:    "<label>",
+				summary: "Delete a brewfile",
+				nargs:   1,
+				setup: func(fs *flag.FlagSet) action {
+					return func(ctx context.Context, a *app, args []string) error {
+						result, err := jsonResult(a.client.Brewfiles.DeleteBrewfile(ctx, args[0]))
+						if err != nil {
+							return err
+						}
+						return a.render(result)
+					}
+				},
+			},
+			listCommand("runs", "<label>", "List runs of a brewfile", 1,
+				func(ctx context.Context, a *app, args []string) (any, error) {
+					return jsonResult(a.client.Brewfiles.ListBrewfileRuns(ctx, args[0]))
+				},
+				func(ctx context.Context, a *app, args []string) ([]byte, error) {
+					return csvResult(a.client.Brewfiles.ListBrewfileRunsCSV(ctx, args[0]))
+				}),
+		},
+	},
+	{
+		name: "brew-taps",
+		commands: []command{
+			listCommand("list", "", "List brew taps", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.BrewTaps.ListBrewTaps(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.BrewTaps.ListBrewTapsCSV(ctx))
+				}),
+		},
+	},
+	{
+		name: "casks",
+		commands: []command{
+			listCommand("list", "", "List casks", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.Casks.ListCasks(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.Casks.ListCasksCSV(ctx))
+				}),
+			queryCommand(func(ctx context.Context, a *app) iter.Seq2[casks.Cask, error] {
+				return a.client.Casks.ListCasksSeq(ctx, nil)
+			}),
+		},
+	},
+	{
+		name: "device-groups",
+		commands: []command{
+			listCommand("list", "", "List device groups", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.DeviceGroups.ListDeviceGroups(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.DeviceGroups.ListDeviceGroupsCSV(ctx))
+				}),
+		},
+	},
+	{
+		name: "devices",
+		commands: []command{
+			listCommand("list", "", "List devices", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.Devices.ListDevices(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.Devices.ListDevicesCSV(ctx))
+				}),
+			queryCommand(func(ctx context.Context, a *app) iter.Seq2[devices.Device, error] {
+				return a.client.Devices.ListDevicesSeq(ctx, nil)
+			}),
+			{
+				name:    "compliance",
+				summary: "Check every device against YAML or JSON compliance rules",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					rulesPath := fs.String("rules", "", "YAML or JSON compliance rules file (required)")
+					noVulnerabilities := fs.Bool("no-vulnerabilities", false, "skip listing vulnerabilities for vulnerability rules")
+					fail := fs.Bool("fail", false, "exit non-zero when any device is non-compliant")
+					return func(ctx context.Context, a *app, _ []string) error {
+						if *rulesPath == "" {
+							return fmt.Errorf("--rules is required")
+						}
+						rules, err := compliance.LoadRules(*rulesPath)
+						if err != nil {
+							return err
+						}
+
+						src := compliance.NewSource(a.client, os.Getenv("WORKBREW_WORKSPACE"))
+						if *noVulnerabilities {
+							src.Vulnerabilities = nil
+						}
+						report, err := compliance.Evaluate(ctx, src, rules)
+						if err != nil {
+							return err
+						}
+						if err := report.WriteJSON(a.stdout); err != nil {
+							return err
+						}
+						if *fail && report.Summary.NonCompliant > 0 {
+							return fmt.Errorf("%d of %d device(s) are non-compliant", report.Summary.NonCompliant, report.Summary.Devices)
+						}
+						return nil
+					}
+				},
+			},
+			{
+				name:    "sbom",
+				args:    "[serial...]",
+				summary: "Write a per-device SPDX or CycloneDX software bill of materials",
+				nargs:   -1,
+				setup: func(fs *flag.FlagSet) action {
+					format := fs.String("format", "spdx", "SBOM format: spdx or cyclonedx")
+					dir := fs.String("dir", "", "write one file per device to this directory instead of stdout")
+					return func(ctx context.Context, a *app, args []string) error {
+						sbomFormat, err := sbom.ParseFormat(*format)
+						if err != nil {
+							return fmt.Errorf("invalid --format: %w", err)
+						}
+						if *dir == "" && len(args) != 1 {
+							return fmt.Errorf("exactly one serial number is required without --dir")
+						}
+
+						inventory, err := sbom.Collect(ctx, sbom.NewSource(a.client, os.Getenv("WORKBREW_WORKSPACE")))
+						if err != nil {
+							return err
+						}
+						if *dir == "" {
+							return inventory.Write(a.stdout, args[0], sbomFormat)
+						}
+
+						if len(args) > 0 {
+							var selected []sbom.Device
+							for _, serial := range args {
+								device, ok := inventory.Device(serial)
+								if !ok {
+									return fmt.Errorf("device %s not found", serial)
+								}
+								selected = append(selected, *device)
+							}
+							inventory.Devices = selected
+						}
+						paths, err := inventory.WriteDir(*dir, sbomFormat)
+						for _, path := range paths {
+							fmt.Fprintln(a.stdout, path)
+						}
+						return err
+					}
+				},
+			},
+			{
+				name:    "stale",
+				summary: "Report stale, never-seen and orphaned devices as JSON or CSV",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					format := fs.String("format", "json", "report format: json or csv")
+					staleAfter := fs.String("stale-after", "30d", "last-seen age above which a device is stale, e.g. 14d or 36h")
+					thresholds := fs.String("buckets", "1d,7d,30d,90d", "comma-separated ascending age bucket boundaries")
+					flagged := fs.Bool("flagged", false, "only include devices with at least one flag")
+					return func(ctx context.Context, a *app, _ []string) error {
+						if *format != "json" && *format != "csv" {
+							return fmt.Errorf("invalid --format %q (expected json or csv)", *format)
+						}
+						opts := &staledevices.Options{}
+						age, err := duration.Parse(*staleAfter)
+						if err != nil {
+							return fmt.Errorf("invalid --stale-after: %w", err)
+						}
+						opts.StaleAfter = age
+						for _, value := range splitList(*thresholds) {
+							threshold, err := duration.Parse(value)
+							if err != nil {
+								return fmt.Errorf("invalid --buckets: %w", err)
+							}
+							opts.Thresholds = append(opts.Thresholds, threshold)
+						}
+
+						report, err := staledevices.Generate(ctx, staledevices.NewSource(a.client, os.Getenv("WORKBREW_WORKSPACE")), opts)
+						if err != nil {
+							return err
+						}
+						if *flagged {
+							report.Devices = append([]staledevices.DeviceEntry{}, report.Flagged()...)
+						}
+						if *format == "csv" {
+							return report.WriteCSV(a.stdout)
+						}
+						return report.WriteJSON(a.stdout)
+					}
+				},
+			},
+		},
+	},
+	{
+		name: "events",
+		commands: []command{
+			{
+				name:    "list",
+				summary: "List audit log events",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					opts := &events.RequestQueryOptions{}
+					fs.StringVar(&opts.Filter, "filter", "", "filter by actor type: user, system or all")
+					fs.BoolVar(&opts.Download, "download", false, "request the CSV export as an attachment (with -o csv)")
+					return func(ctx context.Context, a *app, args []string) error {
+						return fetchAndRender(ctx, a, args,
+							func(ctx context.Context, a *app, _ []string) (any, error) {
+								return jsonResult(a.client.Events.ListEvents(ctx, opts))
+							},
+							func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+								return csvResult(a.client.Events.ListEventsCSV(ctx, opts))
+							})
+					}
+				},
+			},
+			{
+				name:    "tail",
+				summary: "Follow the audit log, printing new events as JSON lines until interrupted",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					opts := &eventstream.Options{}
+					fs.StringVar(&opts.Filter, "filter", "", "filter by actor type: user, system or all")
+					fs.DurationVar(&opts.Interval, "interval", eventstream.DefaultInterval, "time between polls")
+					checkpoint := fs.String("checkpoint", "", "file to resume from and save progress to")
+					since := fs.String("since", "", "skip events before this RFC 3339 time")
+					format := fs.String("format", "json", "output format: json, cef, leef or ocsf")
+					return func(ctx context.Context, a *app, _ []string) error {
+						write, err := eventWriter(a.stdout, *format)
+						if err != nil {
+							return err
+						}
+						if *checkpoint != "" {
+							opts.Checkpoints = eventstream.NewFileCheckpointStore(*checkpoint)
+						}
+						if *since != "" {
+							startTime, err := time.Parse(time.RFC3339, *since)
+							if err != nil {
+								return fmt.Errorf("invalid --since: %w", err)
+							}
+							opts.StartTime = startTime
+						}
+
+						tailer := eventstream.NewTailer(a.client.Events, opts)
+						for event := range tailer.Tail(ctx) {
+							if err := write(&event); err != nil {
+								return err
+							}
+						}
+						return tailer.Err()
+					}
+				},
+			},
+		},
+	},
+	{
+		name: "formulae",
+		commands: []command{
+			listCommand("list", "", "List formulae", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.Formulae.ListFormulae(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.Formulae.ListFormulaeCSV(ctx))
+				}),
+			queryCommand(func(ctx context.Context, a *app) iter.Seq2[formulae.Formula, error] {
+				return a.client.Formulae.ListFormulaeSeq(ctx, nil)
+			}),
+		},
+	},
+	{
+		name: "licenses",
+		commands: []command{
+			listCommand("list", "", "List licenses", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.Licenses.ListLicenses(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.Licenses.ListLicensesCSV(ctx))
+				}),
+			{
+				name:    "check",
+				summary: "Check installed formulae against a license policy, failing on violations",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					policyPath := fs.String("policy", "", "JSON license policy file (required)")
+					format := fs.String("format", "json", "report format: json or junit")
+					failOnReview := fs.Bool("fail-on-review", false, "also fail on formulae whose licenses require review")
+					return func(ctx context.Context, a *app, _ []string) error {
+						if *policyPath == "" {
+							return fmt.Errorf("--policy is required")
+						}
+						if *format != "json" && *format != "junit" {
+							return fmt.Errorf("invalid --format %q (expected json or junit)", *format)
+						}
+						policy, err := licensepolicy.LoadPolicy(*policyPath)
+						if err != nil {
+							return err
+						}
+						engine, err := licensepolicy.NewEngine(policy)
+						if err != nil {
+							return err
+						}
+
+						report, err := licensepolicy.Evaluate(ctx, a.client.Formulae, a.client.DeviceGroups, engine, &licensepolicy.Options{
+							Workspace:    os.Getenv("WORKBREW_WORKSPACE"),
+							FailOnReview: *failOnReview,
+						})
+						if err != nil {
+							return err
+						}
+						if *format == "junit" {
+							err = report.WriteJUnit(a.stdout)
+						} else {
+							err = report.WriteJSON(a.stdout)
+						}
+						if err != nil {
+							return err
+						}
+						if !report.Passed {
+							return fmt.Errorf("license policy check failed: %d formula(e) violate the policy", len(report.Violations()))
+						}
+						return nil
+					}
+				},
+			},
+		},
+	},
+	{
+		name: "vulnerabilities",
+		commands: []command{
+			listCommand("list", "", "List vulnerabilities", 0,
+				func(ctx context.Context, a *app, _ []string) (any, error) {
+					return jsonResult(a.client.Vulnerabilities.ListVulnerabilities(ctx))
+				},
+				func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+					return csvResult(a.client.Vulnerabilities.ListVulnerabilitiesCSV(ctx))
+				}),
+			queryCommand(func(ctx context.Context, a *app) iter.Seq2[vulnerabilities.Vulnerability, error] {
+				return a.client.Vulnerabilities.ListVulnerabilitiesSeq(ctx, nil)
+			}),
+			{
+				name:    "report",
+				summary: "Write a SARIF or CycloneDX VEX vulnerability report",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					format := fs.String("format", "sarif", "report format: sarif or cyclonedx")
+					minSeverity := fs.String("min-severity", "", "only findings at least this severe: low, medium, high or critical")
+					artifact := fs.String("artifact", vulnreport.DefaultSARIFArtifactURI, "file SARIF results are attached to")
+					noFormulae := fs.Bool("no-formulae", false, "skip enriching findings with formula licenses and devices")
+					return func(ctx context.Context, a *app, _ []string) error {
+						opts := &vulnreport.Options{Workspace: os.Getenv("WORKBREW_WORKSPACE")}
+						if *minSeverity != "" {
+							severity, err := vulnerabilities.ParseSeverity(*minSeverity)
+							if err != nil {
+								return fmt.Errorf("invalid --min-severity: %w", err)
+							}
+							opts.MinSeverity = severity
+						}
+						if *format != "sarif" && *format != "cyclonedx" {
+							return fmt.Errorf("invalid --format %q (expected sarif or cyclonedx)", *format)
+						}
+
+						var formulaeService formulae.FormulaeServiceInterface
+						if !*noFormulae {
+							formulaeService = a.client.Formulae
+						}
+						report, err := vulnreport.Generate(ctx, a.client.Vulnerabilities, formulaeService, opts)
+						if err != nil {
+							return err
+						}
+						if *format == "cyclonedx" {
+							return report.WriteCycloneDX(a.stdout)
+						}
+						return report.WriteSARIF(a.stdout, &vulnreport.SARIFOptions{ArtifactURI: *artifact})
+					}
+				},
+			},
+			{
+				name:    "remediate",
+				summary: "Plan upgrades of vulnerable formulae on their outdated devices, and execute and verify them with --execute",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					planOpts := &remediation.PlanOptions{}
+					fs.Float64Var(&planOpts.MinCVSSScore, "min-cvss", remediation.DefaultMinCVSSScore, "lowest CVSS score remediated")
+					exclude := fs.String("exclude", "", "comma-separated formulae never upgraded")
+					execute := fs.Bool("execute", false, "create the brew commands and verify the fixes; without it only the plan is printed")
+					runAfter := fs.String("run-after", "", "run the brew commands after this RFC 3339 time")
+					opts := &remediation.Options{}
+					fs.DurationVar(&opts.RunTimeout, "run-timeout", time.Hour, "how long to wait for the brew command runs")
+					fs.DurationVar(&opts.VerifyTimeout, "verify-timeout", time.Hour, "how long to wait for Workbrew to report the fixes")
+					fs.DurationVar(&opts.VerifyInterval, "verify-interval", remediation.DefaultVerifyInterval, "wait between fix checks")
+					deviceIDsFile := fs.String("device-ids-file", "", "JSON file mapping device serial numbers to device UUIDs (required with --execute)")
+					return func(ctx context.Context, a *app, _ []string) error {
+						planOpts.Exclude = splitList(*exclude)
+						if *runAfter != "" {
+							t, err := time.Parse(time.RFC3339, *runAfter)
+							if err != nil {
+								return fmt.Errorf("invalid --run-after: %w", err)
+							}
+							opts.RunAfter = t
+						}
+						if *execute {
+							deviceID, err := loadDeviceIDs(*deviceIDsFile)
+							if err != nil {
+								return err
+							}
+							opts.DeviceID = deviceID
+						}
+						src := remediation.NewSource(a.client)
+						plan, err := remediation.NewPlan(ctx, src.Vulnerabilities, planOpts)
+						if err != nil {
+							return err
+						}
+						if !*execute {
+							fmt.Fprint(a.stdout, plan)
+							return nil
+						}
+
+						report, err := remediation.NewOrchestrator(src, opts).Execute(ctx, plan)
+						if report != nil {
+							if writeErr := report.WriteJSON(a.stdout); writeErr != nil && err == nil {
+								err = writeErr
+							}
+						}
+						if err != nil {
+							return err
+						}
+						if report.Summary.Outstanding > 0 {
+							return fmt.Errorf("%d of %d device upgrade(s) not confirmed fixed", report.Summary.Outstanding, report.Summary.Devices)
+						}
+						return nil
+					}
+				},
+			},
+		},
+	},
+	{
+		name: "vulnerability-changes",
+		commands: []command{
+			{
+				name:    "list",
+				summary: "List vulnerability detected/fixed changes",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					opts := &vulnerabilitychanges.RequestQueryOptions{}
+					fs.StringVar(&opts.Status, "status", "", "filter by status: detected or fixed")
+					fs.StringVar(&opts.Query, "query", "", "search formula, version, vulnerability ID or device")
+					fs.BoolVar(&opts.Download, "download", false, "request the CSV export as an attachment (with -o csv)")
+					return func(ctx context.Context, a *app, args []string) error {
+						return fetchAndRender(ctx, a, args,
+							func(ctx context.Context, a *app, _ []string) (any, error) {
+								return jsonResult(a.client.VulnerabilityChanges.ListVulnerabilityChanges(ctx, opts))
+							},
+							func(ctx context.Context, a *app, _ []string) ([]byte, error) {
+								return csvResult(a.client.VulnerabilityChanges.ListVulnerabilityChangesCSV(ctx, opts))
+							})
+					}
+				},
+			},
+			{
+				name:    "watch",
+				summary: "Watch for new vulnerability changes, printing matches as JSON lines until interrupted",
+				nargs:   0,
+				setup: func(fs *flag.FlagSet) action {
+					opts := &vulnwatch.Options{}
+					status := fs.String("status", "", "only changes with this status: detected or fixed")
+					minSeverity := fs.String("min-severity", "", "only changes at least this severe: low, medium, high or critical")
+					fs.Float64Var(&opts.Filter.MinScore, "min-score", 0, "only changes with at least this CVSS score")
+					fs.BoolVar(&opts.Filter.IncludeUnrated, "include-unrated", false, "keep changes without a severity or score when a threshold is set")
+					allow := fs.String("allow", "", "comma-separated formula patterns to include, e.g. openssl@*")
+					deny := fs.String("deny", "", "comma-separated formula patterns to exclude")
+					fs.DurationVar(&opts.Interval, "interval", vulnwatch.DefaultInterval, "time between checks")
+					checkpoint := fs.String("checkpoint", "", "file to resume from and save progress to")
+					webhook := fs.String("webhook", "", "also POST each batch of matches to this URL")
+					file := fs.String("file", "", "also append matches to this file as JSON lines")
+					format := fs.String("format", "json", "standard output format: json, cef, leef or ocsf")
+					return func(ctx context.Context, a *app, _ []string) error {
+						output, err := changeSink(a.stdout, *format)
+						if err != nil {
+							return err
+						}
+						if *status != "" {
+							opts.Filter.Statuses = []string{*status}
+						}
+						if *minSeverity != "" {
+							severity, err := vulnerabilities.ParseSeverity(*minSeverity)
+							if err != nil {
+								return fmt.Errorf("invalid --min-severity: %w", err)
+							}
+							opts.Filter.MinSeverity = severity
+						}
+						opts.Filter.AllowFormulae = splitList(*allow)
+						opts.Filter.DenyFormulae = splitList(*deny)
+						if *checkpoint != "" {
+							opts.Checkpoints = eventstream.NewFileCheckpointStore(*checkpoint)
+						}
+
+						opts.Sinks = []vulnwatch.Sink{output}
+						if *webhook != "" {
+							opts.Sinks = append(opts.Sinks, vulnwatch.NewWebhookSink(*webhook))
+						}
+						if *file != "" {
+							opts.Sinks = append(opts.Sinks, vulnwatch.NewFileSink(*file))
+						}
+
+						return vulnwatch.NewWatcher(a.client.VulnerabilityChanges, opts).Run(ctx)
+					}
+				},
+			},
+		},
+	},
+}
+
+// readContent returns the value of an inline flag or the contents of a file flag
+func readContent(inline, path string) (string, error) {
+	if inline != "" && path != "" {
+		return "", fmt.Errorf("only one of --content and --content-file may be set")
+	}
+	if path == "" {
+		if inline == "" {
+			return "", fmt.Errorf("one of --content or --content-file is required")
+		}
+		return inline, nil
+	}
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read content: %w", err)
+	}
+	return string(data), nil
+}
+
+// optional returns nil for empty strings so omitempty request fields are left unset
+func optional(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// loadDeviceIDs reads the --device-ids-file mapping that --execute needs: brew
+// commands target devices by UUID, while plans name them by serial number
+func loadDeviceIDs(path string) (func(serial string) (string, error), error) {
+	if path == "" {
+		return nil, fmt.Errorf("--device-ids-file is required with --execute: brew commands target devices by UUID")
+	}
+	ids, err := rollout.LoadDeviceIDs(path)
+	if err != nil {
+		return nil, err
+	}
+	return ids.Lookup, nil
+}
+
+// eventWriter returns a function that writes events to w as JSON lines or in a SIEM format
+func eventWriter(w io.Writer, format string) (func(*events.Event) error, error) {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		return func(event *events.Event) error { return encoder.Encode(event) }, nil
+	}
+	formatter, err := siem.NewFormatter(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format: %w", err)
+	}
+	return siem.NewWriter(w, formatter).WriteEvent, nil
+}
+
+// changeSink returns a sink that writes vulnerability changes to w as JSON lines or in a SIEM format
+func changeSink(w io.Writer, format string) (vulnwatch.Sink, error) {
+	if format == "json" {
+		return vulnwatch.NewWriterSink(w), nil
+	}
+	formatter, err := siem.NewFormatter(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format: %w", err)
+	}
+	return siem.NewWriter(w, formatter), nil
+}
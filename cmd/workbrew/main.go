@@ -0,0 +1,246 @@
+// Command workbrew is a command-line interface for the Workbrew API.
+//
+// Credentials are read from the environment in the same way as workbrew.NewClientFromEnv:
+//
+//	WORKBREW_API_KEY      (required) API key
+//	WORKBREW_WORKSPACE    (required) workspace slug
+//	WORKBREW_BASE_URL     (optional) custom base URL
+//	WORKBREW_API_VERSION  (optional) API version
+//
+// Usage:
+//
+//	workbrew [global flags] <resource> <action> [flags] [args]
+//
+// Examples:
+//
+//	workbrew devices list
+//	workbrew -o json formulae list
+//	workbrew formulae query --where 'outdated && devices > 10 && name ~ "^python"' --sort -devices --fields name,devices
+//	workbrew devices query --where 'last_seen_at < ago("30d")' --group-by groups
+//	workbrew events list --filter user
+//	workbrew events tail --checkpoint events.checkpoint.json
+//	workbrew vulnerability-changes watch --status detected --min-severity critical
+//	workbrew vulnerabilities report --format sarif > workbrew.sarif
+//	workbrew vulnerabilities remediate --min-cvss 9 --device-ids-file device-ids.json --execute > remediation.json
+//	workbrew devices sbom TC6R2DHVHG --format cyclonedx > TC6R2DHVHG.cdx.json
+//	workbrew devices sbom --dir sboms
+//	workbrew devices compliance --rules compliance.yaml --fail
+//	workbrew devices stale --stale-after 14d --flagged --format csv > stale.csv
+//	workbrew licenses check --policy license-policy.json --format junit > licenses.xml
+//	workbrew brew-commands runs outdated -o csv
+//	workbrew brew-commands rollout --wave Canary --min-success-rate 0.95 --device-ids-file device-ids.json --execute
+//	workbrew brewfiles create --label dev --content-file ./dev.Brewfile --devices TC6R2DHVHG
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"go.uber.org/zap"
+)
+
+// errUsage signals that usage has already been printed
+var errUsage = errors.New("usage")
+
+// app holds the state shared by all commands
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+	format string
+	debug  bool
+
+	// newClient is swapped out in tests
+	newClient func(options ...client.ClientOption) (*workbrew.Client, error)
+	client    *workbrew.Client
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := &app{
+		stdout:    os.Stdout,
+		stderr:    os.Stderr,
+		newClient: workbrew.NewClientFromEnv,
+	}
+
+	if err := a.run(ctx, os.Args[1:]); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run parses global flags and dispatches to the matching resource command
+func (a *app) run(ctx context.Context, args []string) error {
+	global := flag.NewFlagSet("workbrew", flag.ContinueOnError)
+	global.SetOutput(a.stderr)
+	a.registerGlobalFlags(global)
+	global.Usage = func() { a.printUsage() }
+
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+
+	rest := global.Args()
+	if len(rest) == 0 {
+		a.printUsage()
+		return errUsage
+	}
+	if rest[0] == "help" {
+		a.printUsage()
+		return nil
+	}
+
+	res := findResource(rest[0])
+	if res == nil {
+		fmt.Fprintf(a.stderr, "unknown resource %q\n\n", rest[0])
+		a.printUsage()
+		return errUsage
+	}
+
+	if len(rest) < 2 {
+		a.printResourceUsage(res)
+		return errUsage
+	}
+
+	cmd := res.find(rest[1])
+	if cmd == nil {
+		fmt.Fprintf(a.stderr, "unknown action %q for %s\n\n", rest[1], res.name)
+		a.printResourceUsage(res)
+		return errUsage
+	}
+
+	fs := flag.NewFlagSet(res.name+" "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	a.registerGlobalFlags(fs)
+	act := cmd.setup(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: workbrew %s %s %s\n\n%s\n\nFlags:\n", res.name, cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(interleave(fs, rest[2:])); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	if err := validateFormat(a.format); err != nil {
+		return err
+	}
+	if cmd.nargs >= 0 && fs.NArg() != cmd.nargs {
+		fs.Usage()
+		return errUsage
+	}
+
+	if err := a.connect(); err != nil {
+		return err
+	}
+
+	return act(ctx, a, fs.Args())
+}
+
+// registerGlobalFlags adds the flags accepted before or after any command
+func (a *app) registerGlobalFlags(fs *flag.FlagSet) {
+	if a.format == "" {
+		a.format = formatTable
+	}
+	fs.StringVar(&a.format, "o", a.format, "output format: table, json, yaml or csv")
+	fs.StringVar(&a.format, "output", a.format, "output format: table, json, yaml or csv")
+	fs.BoolVar(&a.debug, "debug", a.debug, "enable debug logging of HTTP requests")
+}
+
+// connect creates the API client from the environment
+func (a *app) connect() error {
+	if a.client != nil {
+		return nil
+	}
+
+	logger := zap.NewNop()
+	options := []client.ClientOption{client.WithLogger(logger)}
+	if a.debug {
+		devLogger, err := zap.NewDevelopment()
+		if err != nil {
+			return fmt.Errorf("failed to create logger: %w", err)
+		}
+		options = []client.ClientOption{client.WithLogger(devLogger), client.WithDebug()}
+	}
+
+	c, err := a.newClient(options...)
+	if err != nil {
+		return err
+	}
+	a.client = c
+	return nil
+}
+
+// interleave moves flags that appear after positional arguments to the front,
+// so "brewfiles runs my-label -o json" works like "brewfiles runs -o json my-label".
+func interleave(fs *flag.FlagSet, args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+
+		flags = append(flags, arg)
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := fs.Lookup(name); f != nil {
+			if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+				continue
+			}
+			if i+1 < len(args) {
+				flags = append(flags, args[i+1])
+				i++
+			}
+		}
+	}
+	return append(flags, positional...)
+}
+
+// printUsage writes the top-level help text
+func (a *app) printUsage() {
+	fmt.Fprintln(a.stderr, "Usage: workbrew [-o table|json|yaml|csv] [--debug] <resource> <action> [flags] [args]")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Credentials are read from WORKBREW_API_KEY and WORKBREW_WORKSPACE")
+	fmt.Fprintln(a.stderr, "(optionally WORKBREW_BASE_URL and WORKBREW_API_VERSION).")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Resources:")
+	for _, res := range resources {
+		actions := make([]string, 0, len(res.commands))
+		for _, cmd := range res.commands {
+			actions = append(actions, cmd.name)
+		}
+		fmt.Fprintf(a.stderr, "  %-24s %s\n", res.name, strings.Join(actions, ", "))
+	}
+}
+
+// printResourceUsage writes the help text for a single resource
+func (a *app) printResourceUsage(res *resource) {
+	fmt.Fprintf(a.stderr, "Usage: workbrew %s <action> [flags] [args]\n\nActions:\n", res.name)
+	for _, cmd := range res.commands {
+		fmt.Fprintf(a.stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+}
@@ -0,0 +1,452 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const devicesJSON = `[
+  {
+    "serial_number": "TC6R2DHVHG",
+    "groups": ["Engineering", "Design"],
+    "mdm_user_or_device_name": "alice",
+    "last_seen_at": "2024-01-01T12:00:00Z",
+    "command_last_run_at": "Never",
+    "device_type": "MacBook Pro",
+    "os_version": "14.2",
+    "homebrew_prefix": "/opt/homebrew",
+    "homebrew_version": "4.2.0",
+    "workbrew_version": "1.0.0",
+    "formulae_count": 12,
+    "casks_count": 3
+  }
+]`
+
+type recordedRequest struct {
+	method string
+	path   string
+	query  string
+	body   string
+}
+
+// newTestApp returns an app wired to a test server that answers every request with body
+func newTestApp(t *testing.T, contentType, body string) (*app, *bytes.Buffer, *[]recordedRequest) {
+	t.Helper()
+
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, body: string(data)})
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	stdout := &bytes.Buffer{}
+	a := &app{
+		stdout: stdout,
+		stderr: io.Discard,
+		newClient: func(options ...client.ClientOption) (*workbrew.Client, error) {
+			options = append(options, client.WithBaseURL(server.URL), client.WithRetryCount(0))
+			return workbrew.NewClient("test-key", "test-workspace", options...)
+		},
+	}
+	return a, stdout, &requests
+}
+
+func TestRun_DevicesListTable(t *testing.T) {
+	a, stdout, requests := newTestApp(t, "application/json", devicesJSON)
+
+	require.NoError(t, a.run(context.Background(), []string{"devices", "list"}))
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/workspaces/test-workspace/devices.json", (*requests)[0].path)
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "SERIAL_NUMBER"))
+	assert.Contains(t, lines[1], "TC6R2DHVHG")
+	assert.Contains(t, lines[1], "Engineering, Design")
+	assert.Contains(t, lines[1], "Never")
+}
+
+func TestRun_DevicesListJSON(t *testing.T) {
+	a, stdout, _ := newTestApp(t, "application/json", devicesJSON)
+
+	require.NoError(t, a.run(context.Background(), []string{"-o", "json", "devices", "list"}))
+
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &decoded))
+	require.Len(t, decoded, 1)
+	assert.Equal(t, "TC6R2DHVHG", decoded[0]["serial_number"])
+	assert.Equal(t, "Never", decoded[0]["command_last_run_at"])
+}
+
+func TestRun_DevicesListYAML(t *testing.T) {
+	a, stdout, _ := newTestApp(t, "application/json", devicesJSON)
+
+	require.NoError(t, a.run(context.Background(), []string{"devices", "list", "--output", "yaml"}))
+
+	out := stdout.String()
+	assert.Contains(t, out, "- serial_number: TC6R2DHVHG")
+	assert.Contains(t, out, "    - Engineering")
+	assert.Contains(t, out, `os_version: "14.2"`)
+	assert.Contains(t, out, "formulae_count: 12")
+}
+
+func TestRun_CSVUsesExportEndpoint(t *testing.T) {
+	csvBody := "serial_number,groups\nTC6R2DHVHG,Engineering\n"
+	a, stdout, requests := newTestApp(t, "text/csv", csvBody)
+
+	require.NoError(t, a.run(context.Background(), []string{"devices", "list", "-o", "csv"}))
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/workspaces/test-workspace/devices.csv", (*requests)[0].path)
+	assert.Equal(t, csvBody, stdout.String())
+}
+
+func TestRun_PositionalArgumentAndQueryFlags(t *testing.T) {
+	a, _, requests := newTestApp(t, "application/json", "[]")
+
+	require.NoError(t, a.run(context.Background(), []string{"brewfiles", "runs", "dev-tools", "-o", "json"}))
+	require.NoError(t, a.run(context.Background(), []string{"vulnerability-changes", "list", "--status", "fixed"}))
+
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "/workspaces/test-workspace/brewfiles/dev-tools/runs.json", (*requests)[0].path)
+	assert.Equal(t, "/workspaces/test-workspace/vulnerability_changes.json", (*requests)[1].path)
+	assert.Contains(t, (*requests)[1].query, "status=fixed")
+}
+
+func TestRun_BrewfilesCreate(t *testing.T) {
+	a, stdout, requests := newTestApp(t, "application/json", `{"message":"Brewfile was successfully created."}`)
+
+	err := a.run(context.Background(), []string{
+		"brewfiles", "create", "--label", "dev", "--content", "brew \"wget\"", "--devices", "TC6R2DHVHG",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, http.MethodPost, (*requests)[0].method)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal([]byte((*requests)[0].body), &body))
+	assert.Equal(t, "dev", body["label"])
+	assert.Equal(t, "TC6R2DHVHG", body["device_serial_numbers"])
+	assert.NotContains(t, body, "device_group_id")
+
+	assert.Contains(t, stdout.String(), "Brewfile was successfully created.")
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "no arguments", args: nil, want: "usage"},
+		{name: "unknown resource", args: []string{"widgets", "list"}, want: "usage"},
+		{name: "unknown action", args: []string{"devices", "explode"}, want: "usage"},
+		{name: "missing positional", args: []string{"brewfiles", "delete"}, want: "usage"},
+		{name: "bad format", args: []string{"-o", "xml", "devices", "list"}, want: "unsupported output format"},
+		{name: "missing required flag", args: []string{"brew-commands", "create"}, want: "--arguments is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _, requests := newTestApp(t, "application/json", "[]")
+			err := a.run(context.Background(), tt.args)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			assert.Empty(t, *requests)
+		})
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	a := &app{}
+	fs := newFlagSetForTest(a)
+	fs.Bool("download", false, "")
+
+	got := interleave(fs, []string{"label", "-o", "json", "--download", "--debug", "extra"})
+	assert.Equal(t, []string{"-o", "json", "--download", "--debug", "label", "extra"}, got)
+}
+
+func TestRun_EventsTail(t *testing.T) {
+	eventsJSON := `[{"id":"e1","event_type":"device.created","occurred_at":"2025-01-02T10:00:00Z"}]`
+	a, stdout, requests := newTestApp(t, "application/json", eventsJSON)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.NoError(t, a.run(ctx, []string{"events", "tail", "--interval", "10ms", "--filter", "user"}))
+
+	// Polled repeatedly, but the event is printed once
+	assert.Greater(t, len(*requests), 1)
+	assert.Equal(t, "filter=user", (*requests)[0].query)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"id":"e1"`)
+}
+
+func TestRun_VulnerabilityChangesWatch(t *testing.T) {
+	changesJSON := `[
+		{"id":"v1","occurred_at":"2025-01-04T09:15:00Z","status":"detected","formula_name":"curl","cvss_severity":"Medium","cvss_score":6.5},
+		{"id":"v2","occurred_at":"2025-01-05T14:00:00Z","status":"detected","formula_name":"wget","cvss_severity":"Critical","cvss_score":9.1}
+	]`
+	a, stdout, requests := newTestApp(t, "application/json", changesJSON)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.NoError(t, a.run(ctx, []string{"vulnerability-changes", "watch", "--interval", "10ms", "--status", "detected", "--min-severity", "critical"}))
+
+	// Checked repeatedly, but the matching change is printed once
+	assert.Greater(t, len(*requests), 1)
+	assert.Equal(t, "status=detected", (*requests)[0].query)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"id":"v2"`)
+}
+
+func TestRun_EventsTailCEF(t *testing.T) {
+	eventsJSON := `[{"id":"e1","event_type":"device.created","occurred_at":"2025-01-02T10:00:00Z"}]`
+	a, stdout, _ := newTestApp(t, "application/json", eventsJSON)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.NoError(t, a.run(ctx, []string{"events", "tail", "--interval", "10ms", "--format", "cef"}))
+
+	assert.True(t, strings.HasPrefix(stdout.String(), "CEF:0|Workbrew|Workbrew Console|"))
+	assert.Contains(t, stdout.String(), "externalId=e1")
+
+	err := a.run(ctx, []string{"events", "tail", "--format", "xml"})
+	assert.ErrorContains(t, err, "invalid --format")
+}
+
+func TestRun_VulnerabilitiesReport(t *testing.T) {
+	vulnerabilitiesJSON := `[{"vulnerabilities":[{"clean_id":"CVE-2024-10524","cvss_score":9.1}],"formula":"wget","outdated_devices":["TC6R2DHVHG"],"supported":true,"homebrew_core_version":"1.25.0"}]`
+	a, stdout, requests := newTestApp(t, "application/json", vulnerabilitiesJSON)
+
+	require.NoError(t, a.run(context.Background(), []string{"vulnerabilities", "report", "--no-formulae", "--format", "cyclonedx"}))
+
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/workspaces/test-workspace/vulnerabilities.json", (*requests)[0].path)
+	var bom map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &bom))
+	assert.Equal(t, "CycloneDX", bom["bomFormat"])
+
+	stdout.Reset()
+	require.NoError(t, a.run(context.Background(), []string{"vulnerabilities", "report", "--no-formulae", "--artifact", "infra/Brewfile"}))
+	assert.Contains(t, stdout.String(), `"uri": "infra/Brewfile"`)
+	assert.Contains(t, stdout.String(), `"ruleId": "CVE-2024-10524"`)
+
+	err := a.run(context.Background(), []string{"vulnerabilities", "report", "--format", "pdf"})
+	assert.ErrorContains(t, err, "invalid --format")
+}
+
+func TestRun_VulnerabilitiesRemediate(t *testing.T) {
+	vulnerabilitiesJSON := `[{"vulnerabilities":[{"clean_id":"CVE-2024-10524","cvss_score":9.1}],"formula":"wget","outdated_devices":["TC6R2DHVHG"],"supported":true,"homebrew_core_version":"1.25.0"}]`
+	a, stdout, requests := newTestApp(t, "application/json", vulnerabilitiesJSON)
+
+	require.NoError(t, a.run(context.Background(), []string{"vulnerabilities", "remediate", "--exclude", "curl"}))
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "brew upgrade --formula wget on TC6R2DHVHG (CVE-2024-10524, CVSS 9.1)\n"+
+		"Plan: 1 formula(e) to upgrade on 1 device(s), CVSS 7.0 or higher.\n", stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, a.run(context.Background(), []string{"vulnerabilities", "remediate", "--min-cvss", "9.5"}))
+	assert.Contains(t, stdout.String(), "Plan: 0 formula(e)")
+
+	err := a.run(context.Background(), []string{"vulnerabilities", "remediate", "--run-after", "tomorrow", "--execute"})
+	assert.ErrorContains(t, err, "invalid --run-after")
+
+	*requests = nil
+	err = a.run(context.Background(), []string{"vulnerabilities", "remediate", "--execute"})
+	assert.ErrorContains(t, err, "--device-ids-file is required with --execute")
+	assert.Empty(t, *requests)
+}
+
+func TestRun_FormulaeQuery(t *testing.T) {
+	formulaeJSON := `[
+  {"name":"python@3.12","devices":["A","B","C"],"outdated":true,"installed_on_request":true,"installed_as_dependency":false,"vulnerabilities":[]},
+  {"name":"python@3.13","devices":["A"],"outdated":true,"installed_on_request":true,"installed_as_dependency":false,"vulnerabilities":[]},
+  {"name":"jq","devices":["A","B"],"outdated":true,"installed_on_request":true,"installed_as_dependency":false,"vulnerabilities":[]}
+]`
+	a, stdout, requests := newTestApp(t, "application/json", formulaeJSON)
+
+	require.NoError(t, a.run(context.Background(), []string{"formulae", "query",
+		"--where", `outdated && devices > 1 && name ~ "^python"`, "--fields", "name,devices"}))
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/workspaces/test-workspace/formulae.json", (*requests)[0].path)
+	assert.Empty(t, (*requests)[0].query, "no page parameters are sent")
+	assert.Equal(t, "NAME         DEVICES\npython@3.12  A, B, C\n", stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, a.run(context.Background(), []string{"-o", "json", "formulae", "query", "--sort", "-devices,name", "--limit", "2", "--fields", "name"}))
+	assert.JSONEq(t, `[{"name":"python@3.12"},{"name":"jq"}]`, stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, a.run(context.Background(), []string{"-o", "csv", "formulae", "query", "--group-by", "devices"}))
+	assert.Equal(t, "value,count\nA,3\nB,2\nC,1\n", stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, a.run(context.Background(), []string{"formulae", "query", "--list-fields"}))
+	assert.Contains(t, stdout.String(), "installed_on_request\n")
+
+	*requests = nil
+	err := a.run(context.Background(), []string{"formulae", "query", "--where", "size > 1"})
+	assert.ErrorContains(t, err, `unknown field "size"`)
+	err = a.run(context.Background(), []string{"formulae", "query", "--fields", "name", "--group-by", "name"})
+	assert.ErrorContains(t, err, "cannot be combined")
+	assert.Empty(t, *requests)
+}
+
+func TestRun_DevicesSBOM(t *testing.T) {
+	// Every list endpoint answers with the same body, so one object serves as device, formula, cask and tap
+	inventoryJSON := `[{"serial_number":"TC6R2DHVHG","name":"jq","devices":["TC6R2DHVHG"],"tap":"homebrew/core","license":["MIT"],"homebrew_core_version":"1.7.1"}]`
+	a, stdout, requests := newTestApp(t, "application/json", inventoryJSON)
+
+	require.NoError(t, a.run(context.Background(), []string{"devices", "sbom", "TC6R2DHVHG"}))
+	require.Len(t, *requests, 4)
+	assert.Contains(t, stdout.String(), `"spdxVersion": "SPDX-2.3"`)
+	assert.Contains(t, stdout.String(), `"referenceLocator": "pkg:brew/jq@1.7.1"`)
+
+	dir := t.TempDir()
+	stdout.Reset()
+	require.NoError(t, a.run(context.Background(), []string{"devices", "sbom", "--format", "cyclonedx", "--dir", dir}))
+	assert.Equal(t, filepath.Join(dir, "TC6R2DHVHG.cdx.json")+"\n", stdout.String())
+
+	err := a.run(context.Background(), []string{"devices", "sbom"})
+	assert.ErrorContains(t, err, "exactly one serial number")
+
+	err = a.run(context.Background(), []string{"devices", "sbom", "--format", "swid", "TC6R2DHVHG"})
+	assert.ErrorContains(t, err, "invalid --format")
+
+	err = a.run(context.Background(), []string{"devices", "sbom", "UNKNOWN"})
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestRun_LicensesCheck(t *testing.T) {
+	// Every list endpoint answers with the same body, so the formula is also a device group
+	formulaeJSON := `[{"name":"wget","devices":["TC6R2DHVHG"],"license":["GPL-3.0-or-later"]}]`
+	a, stdout, requests := newTestApp(t, "application/json", formulaeJSON)
+
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(policyPath, []byte(`{"allow": ["MIT"], "deny": ["GPL-*"]}`), 0o644))
+
+	err := a.run(context.Background(), []string{"licenses", "check", "--policy", policyPath})
+	assert.ErrorContains(t, err, "license policy check failed: 1 formula(e)")
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "/workspaces/test-workspace/formulae.json", (*requests)[0].path)
+	assert.Contains(t, stdout.String(), `"decision": "deny"`)
+
+	stdout.Reset()
+	err = a.run(context.Background(), []string{"licenses", "check", "--policy", policyPath, "--format", "junit"})
+	assert.Error(t, err)
+	assert.Contains(t, stdout.String(), `<failure message="denied: GPL-3.0-or-later" type="deny">`)
+
+	require.NoError(t, os.WriteFile(policyPath, []byte(`{"allow": ["GPL-*"]}`), 0o644))
+	require.NoError(t, a.run(context.Background(), []string{"licenses", "check", "--policy", policyPath}))
+
+	err = a.run(context.Background(), []string{"licenses", "check"})
+	assert.ErrorContains(t, err, "--policy is required")
+}
+
+func TestRun_DevicesCompliance(t *testing.T) {
+	// Every list endpoint answers with the same body, so the device is also a group containing itself
+	inventoryJSON := `[{"serial_number":"TC6R2DHVHG","name":"Admin","devices":["TC6R2DHVHG"],"homebrew_version":"4.4.15"}]`
+	a, stdout, requests := newTestApp(t, "application/json", inventoryJSON)
+
+	rulesPath := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(rulesPath, []byte("rules:\n  - name: grouped\n    min_groups: 1\n  - name: homebrew-5\n    min_homebrew_version: \"5.0.0\"\n"), 0o644))
+
+	require.NoError(t, a.run(context.Background(), []string{"devices", "compliance", "--rules", rulesPath, "--no-vulnerabilities"}))
+	require.Len(t, *requests, 2)
+	assert.Contains(t, stdout.String(), `"reason": "Homebrew 4.4.15 is older than 5.0.0"`)
+	assert.Contains(t, stdout.String(), `"non_compliant": 1`)
+
+	err := a.run(context.Background(), []string{"devices", "compliance", "--rules", rulesPath, "--fail"})
+	assert.ErrorContains(t, err, "1 of 1 device(s) are non-compliant")
+	assert.Len(t, *requests, 5)
+
+	err = a.run(context.Background(), []string{"devices", "compliance"})
+	assert.ErrorContains(t, err, "--rules is required")
+}
+
+func TestRun_DevicesStale(t *testing.T) {
+	// Every list endpoint answers with the same body, so the device is also a group containing itself
+	inventoryJSON := `[{"serial_number":"TC6R2DHVHG","name":"Admin","devices":["TC6R2DHVHG"],"last_seen_at":"Never","command_last_run_at":"2025-01-06T10:02:00Z","formulae_count":3}]`
+	a, stdout, requests := newTestApp(t, "application/json", inventoryJSON)
+
+	require.NoError(t, a.run(context.Background(), []string{"devices", "stale"}))
+	require.Len(t, *requests, 2)
+	assert.Contains(t, stdout.String(), `"last_seen_bucket": "never"`)
+	assert.Contains(t, stdout.String(), `"never_seen"`)
+
+	stdout.Reset()
+	require.NoError(t, a.run(context.Background(), []string{"devices", "stale", "--format", "csv", "--buckets", "7d,2w"}))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "serial_number,name,device_type"))
+	assert.Contains(t, lines[1], ",Admin,3,0,never_seen")
+	assert.Contains(t, lines[1], ",14d+,")
+
+	err := a.run(context.Background(), []string{"devices", "stale", "--stale-after", "soon"})
+	assert.ErrorContains(t, err, "invalid --stale-after")
+
+	err = a.run(context.Background(), []string{"devices", "stale", "--format", "xml"})
+	assert.ErrorContains(t, err, "invalid --format")
+}
+
+func TestRun_BrewCommandsRollout(t *testing.T) {
+	// Every list endpoint answers with the same body, so "Admin" is an outdated formula, an outdated cask and a device group
+	inventoryJSON := `[{"name":"Admin","devices":["TC6R2DHVHG"],"outdated":true}]`
+	a, stdout, requests := newTestApp(t, "application/json", inventoryJSON)
+
+	require.NoError(t, a.run(context.Background(), []string{"brew-commands", "rollout", "--wave", "Admin"}))
+	assert.Len(t, *requests, 3)
+	assert.Equal(t, "wave 1: Admin (1 devices)\n"+
+		"  brew upgrade --formula Admin on TC6R2DHVHG\n"+
+		"  brew upgrade --cask Admin on TC6R2DHVHG\n"+
+		"Plan: 1 wave(s) upgrading 1 device(s).\n", stdout.String())
+
+	err := a.run(context.Background(), []string{"brew-commands", "rollout", "--wave", "Canary"})
+	assert.EqualError(t, err, `device group "Canary" not found`)
+}
+
+func TestRun_BrewCommandsRolloutDeviceIDs(t *testing.T) {
+	inventoryJSON := `[{"name":"Admin","devices":["TC6R2DHVHG"],"outdated":true}]`
+	a, _, requests := newTestApp(t, "application/json", inventoryJSON)
+
+	err := a.run(context.Background(), []string{"brew-commands", "rollout", "--wave", "Admin", "--execute"})
+	assert.ErrorContains(t, err, "--device-ids-file is required with --execute")
+	assert.Empty(t, *requests)
+
+	path := filepath.Join(t.TempDir(), "device-ids.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"TC6R2DHVHG": "7c1e8f0a-3b2d-4e5f-9a6b-1c2d3e4f5a6b"}`), 0o600))
+	_ = a.run(context.Background(), []string{"brew-commands", "rollout", "--wave", "Admin", "--device-ids-file", path, "--execute"})
+
+	var created []string
+	for _, request := range *requests {
+		if request.method == http.MethodPost {
+			created = append(created, request.body)
+		}
+	}
+	require.NotEmpty(t, created)
+	for _, body := range created {
+		assert.Contains(t, body, `"device_ids":"7c1e8f0a-3b2d-4e5f-9a6b-1c2d3e4f5a6b"`)
+		assert.NotContains(t, body, "TC6R2DHVHG", "serial numbers are never sent as device_ids")
+	}
+}
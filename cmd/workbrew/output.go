@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by -o/--output
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	formatCSV   = "csv"
+)
+
+// validateFormat rejects unknown output formats before any API call is made
+func validateFormat(format string) error {
+	switch format {
+	case formatTable, formatJSON, formatYAML, formatCSV:
+		return nil
+	}
+	return fmt.Errorf("unsupported output format %q (expected table, json, yaml or csv)", format)
+}
+
+// render writes result to stdout in the selected output format
+func (a *app) render(result any) error {
+	switch a.format {
+	case formatJSON:
+		encoder := json.NewEncoder(a.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case formatYAML:
+		return renderYAML(a, result)
+	case formatCSV:
+		return renderRecords(a, result, func(rows [][]string) error {
+			w := csv.NewWriter(a.stdout)
+			if err := w.WriteAll(rows); err != nil {
+				return err
+			}
+			return w.Error()
+		})
+	default:
+		return renderRecords(a, result, func(rows [][]string) error {
+			w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+			for i, row := range rows {
+				if i == 0 {
+					for j := range row {
+						row[j] = strings.ToUpper(row[j])
+					}
+				}
+				fmt.Fprintln(w, strings.Join(row, "\t"))
+			}
+			return w.Flush()
+		})
+	}
+}
+
+// renderYAML converts the JSON form of result to YAML so that custom JSON
+// marshalers (e.g. devices.TimeOrNever) and json field names are respected
+func renderYAML(a *app, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	clearStyle(&node)
+
+	encoder := yaml.NewEncoder(a.stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// clearStyle drops the JSON flow style so the output reads as block YAML
+func clearStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && needsQuoting(node.Value) {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
+
+// needsQuoting reports whether a plain scalar would be read back as a different type
+func needsQuoting(value string) bool {
+	var decoded any
+	if err := yaml.Unmarshal([]byte(value), &decoded); err != nil {
+		return true
+	}
+	s, ok := decoded.(string)
+	return !ok || s != value
+}
+
+// renderRecords flattens result into a header row plus one row per item and
+// passes them to write. Lists produce one row per element; single objects
+// produce a single row.
+func renderRecords(a *app, result any, write func(rows [][]string) error) error {
+	columns := columnsFor(result)
+	if columns == nil {
+		columns = []string{"value"}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return err
+	}
+
+	var items []any
+	switch v := decoded.(type) {
+	case []any:
+		items = v
+	case nil:
+	default:
+		items = []any{v}
+	}
+
+	rows := make([][]string, 0, len(items)+1)
+	rows = append(rows, append([]string(nil), columns...))
+	for _, item := range items {
+		object, ok := item.(map[string]any)
+		if !ok {
+			rows = append(rows, []string{formatCell(item)})
+			continue
+		}
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = formatCell(object[column])
+		}
+		rows = append(rows, row)
+	}
+
+	return write(rows)
+}
+
+// columnsFor returns the json field names of the struct underlying result,
+// in declaration order. Results with a Columns method (e.g. query.Rows) name
+// their own columns. Other non-struct results return nil columns.
+func columnsFor(result any) []string {
+	if columnar, ok := result.(interface{ Columns() []string }); ok {
+		return columnar.Columns()
+	}
+	t := reflect.TypeOf(result)
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	columns := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+// formatCell converts a decoded JSON value to a single table or CSV cell
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, formatCell(item))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFlagSetForTest(a *app) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	a.registerGlobalFlags(fs)
+	return fs
+}
+
+type sampleRow struct {
+	Name    string   `json:"name"`
+	Tags    []string `json:"tags"`
+	Count   int      `json:"count"`
+	Comment *string  `json:"comment"`
+	Hidden  string   `json:"-"`
+}
+
+func TestRender_Table(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	a := &app{stdout: stdout, format: formatTable}
+
+	require.NoError(t, a.render(&[]sampleRow{{Name: "wget", Tags: []string{"a", "b"}, Count: 2, Hidden: "x"}}))
+
+	assert.Equal(t, "NAME  TAGS  COUNT  COMMENT\nwget  a, b  2      \n", stdout.String())
+}
+
+func TestRender_CSVForSingleObject(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	a := &app{stdout: stdout, format: formatCSV}
+
+	comment := "needs, quoting"
+	require.NoError(t, a.render(&sampleRow{Name: "wget", Count: 1, Comment: &comment}))
+
+	assert.Equal(t, "name,tags,count,comment\nwget,,1,\"needs, quoting\"\n", stdout.String())
+}
+
+func TestRender_YAMLQuotesAmbiguousStrings(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	a := &app{stdout: stdout, format: formatYAML}
+
+	require.NoError(t, a.render(sampleRow{Name: "true", Tags: []string{"1.0"}}))
+
+	assert.Equal(t, "name: \"true\"\ntags:\n  - \"1.0\"\ncount: 0\ncomment: null\n", stdout.String())
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{formatTable, formatJSON, formatYAML, formatCSV} {
+		assert.NoError(t, validateFormat(format))
+	}
+	assert.Error(t, validateFormat("xml"))
+}
@@ -35,14 +35,14 @@ func main() {
 	}
 
 
-	// Create brew command request
-	recurrence := "once"
-	request := &brewcommands.CreateBrewCommandRequest{
-		Arguments:  "install wget",
-		Recurrence: &recurrence,
-		// DeviceIDs: nil to run on all devices
-		// RunAfterDatetime: nil to run immediately
-		// To run on specific devices, use: DeviceIDs: ptr("device-uuid-1,device-uuid-2")
+	// Build a validated brew command request.
+	// Without OnDevices the command runs on all devices; without RunAfter it runs immediately.
+	// To schedule it, use e.g. RunAfter(time.Now().Add(time.Hour)).Recurring(brewcommands.RecurrenceDaily)
+	request, err := brewcommands.Install("wget").
+		Recurring(brewcommands.RecurrenceOnce).
+		Build()
+	if err != nil {
+		log.Fatalf("Invalid brew command: %v", err)
 	}
 
 	ctx := context.Background()
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/brewfilesync"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Directory containing <label>.Brewfile files and optional <label>.json assignments
+	definitions, err := brewfilesync.LoadDir("./brewfiles")
+	if err != nil {
+		log.Fatalf("Failed to load definitions: %v", err)
+	}
+
+	reconciler := brewfilesync.NewReconciler(workbrewClient.Brewfiles, &brewfilesync.Options{
+		Prune:  false,
+		DryRun: true, // Set to false to apply changes
+		Logger: logger,
+	})
+
+	ctx := context.Background()
+	plan, err := reconciler.Plan(ctx, definitions)
+	if err != nil {
+		log.Fatalf("Failed to plan: %v", err)
+	}
+
+	fmt.Print(plan.String())
+
+	if !plan.HasChanges() {
+		return
+	}
+
+	result, err := reconciler.Apply(ctx, plan)
+	if err != nil {
+		log.Fatalf("Failed to apply plan: %v", err)
+	}
+
+	fmt.Printf("Applied %d changes (dry run: %t)\n", len(result.Applied), result.DryRun)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/compliance"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Built-in rules, plus a Go predicate for Apple silicon Homebrew installs
+	rules := []compliance.Rule{
+		compliance.MinHomebrewVersion("4.4.0"),
+		compliance.SeenWithin(7 * 24 * time.Hour),
+		compliance.MaxVulnerabilitySeverity(vulnerabilities.SeverityHigh),
+		compliance.MinGroups(1),
+		compliance.NewRule("apple-silicon-prefix", func(facts *compliance.Facts) error {
+			if facts.Device.HomebrewPrefix != "/opt/homebrew" {
+				return fmt.Errorf("Homebrew is installed in %s", facts.Device.HomebrewPrefix)
+			}
+			return nil
+		}).ForDeviceTypes("Mac*"),
+	}
+
+	report, err := compliance.Evaluate(context.Background(), compliance.NewSource(workbrewClient, workspace), rules)
+	if err != nil {
+		log.Fatalf("Failed to evaluate compliance: %v", err)
+	}
+
+	fmt.Printf("%d of %d devices compliant\n", report.Summary.Compliant, report.Summary.Devices)
+	for _, device := range report.NonCompliant() {
+		fmt.Printf("  %s\n", device.SerialNumber)
+		for _, failure := range device.Failures() {
+			fmt.Printf("    %s: %s\n", failure.Rule, failure.Reason)
+		}
+	}
+
+	fmt.Println("By device group:")
+	for _, group := range report.Groups {
+		fmt.Printf("  %s: %d/%d compliant\n", group.Name, group.Compliant, group.Devices)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
 	"go.uber.org/zap"
 )
 
@@ -42,4 +43,14 @@ func main() {
 
 	fmt.Printf("Devices CSV (%d bytes):\n", len(csvData))
 	fmt.Println(string(csvData))
+
+	// Decode the CSV export into the same typed slice returned by ListDevices
+	decoded, err := devices.DecodeDevicesCSV(csvData)
+	if err != nil {
+		log.Fatalf("Failed to decode devices CSV: %v", err)
+	}
+
+	for _, device := range *decoded {
+		fmt.Printf("%s last seen: %s\n", device.SerialNumber, device.LastSeenAt)
+	}
 }
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// The devices endpoint documents no pagination, so the list is requested once
+	count := 0
+	for device, err := range workbrewClient.Devices.ListDevicesSeq(ctx, nil) {
+		if err != nil {
+			log.Fatalf("Failed to list devices: %v", err)
+		}
+		count++
+		fmt.Printf("%s\t%s\t%s\n", device.SerialNumber, device.DeviceType, device.OSVersion)
+	}
+	fmt.Printf("Retrieved %d devices\n", count)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/licensepolicy"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Allow permissive licenses, deny strong copyleft and review everything else
+	engine, err := licensepolicy.NewEngine(&licensepolicy.Policy{
+		Allow:  []string{"MIT", "Apache-2.0", "BSD-*", "ISC"},
+		Review: []string{"LGPL-*", "MPL-2.0"},
+		Deny:   []string{"AGPL-*", "GPL-3.0-only OR GPL-3.0-or-later"},
+	})
+	if err != nil {
+		log.Fatalf("Invalid license policy: %v", err)
+	}
+
+	report, err := licensepolicy.Evaluate(context.Background(), workbrewClient.Formulae, workbrewClient.DeviceGroups, engine, &licensepolicy.Options{
+		Workspace: workspace,
+	})
+	if err != nil {
+		log.Fatalf("Failed to evaluate license policy: %v", err)
+	}
+
+	fmt.Printf("Evaluated %d formulae: %d allowed, %d for review, %d denied\n",
+		report.Summary.Formulae, report.Summary.Allowed, report.Summary.Review, report.Summary.Denied)
+
+	for _, violation := range report.Violations() {
+		fmt.Printf("  %s (%s) on %s\n", violation.Formula, violation.Reason, strings.Join(violation.Devices, ", "))
+		if len(violation.Groups) > 0 {
+			fmt.Printf("    groups: %s\n", strings.Join(violation.Groups, ", "))
+		}
+	}
+
+	junitFile, err := os.Create("licenses.xml")
+	if err != nil {
+		log.Fatalf("Failed to create JUnit file: %v", err)
+	}
+	if err := report.WriteJUnit(junitFile); err != nil {
+		log.Fatalf("Failed to write JUnit report: %v", err)
+	}
+	junitFile.Close()
+
+	if !report.Passed {
+		os.Exit(1)
+	}
+}
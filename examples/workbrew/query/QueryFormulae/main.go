@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/query"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Compile first so a typo fails before any API call
+	expr, err := query.Compile[formulae.Formula](`outdated && devices > 10 && name ~ "^python"`)
+	if err != nil {
+		log.Fatalf("Invalid query: %v", err)
+	}
+
+	var matched []formulae.Formula
+	for formula, err := range workbrewClient.Formulae.ListFormulaeSeq(ctx, nil) {
+		if err != nil {
+			log.Fatalf("Failed to list formulae: %v", err)
+		}
+		if expr.Match(formula) {
+			matched = append(matched, formula)
+		}
+	}
+
+	if err := query.Sort(matched, "-devices", "name"); err != nil {
+		log.Fatalf("Failed to sort formulae: %v", err)
+	}
+	for _, formula := range matched {
+		fmt.Printf("%s\toutdated on %d devices\n", formula.Name, len(formula.Devices))
+	}
+
+	// Count devices not seen for 30 days in each device group
+	allDevices, _, err := workbrewClient.Devices.ListDevices(ctx)
+	if err != nil {
+		log.Fatalf("Failed to list devices: %v", err)
+	}
+	result, err := query.Run(*allDevices, query.Query{
+		Where:   `last_seen_at < ago("30d")`,
+		GroupBy: "groups",
+	})
+	if err != nil {
+		log.Fatalf("Failed to query devices: %v", err)
+	}
+	fmt.Printf("%d devices not seen for 30 days\n", result.Matched)
+	for _, group := range result.Groups {
+		fmt.Printf("%v\t%d\n", group.Value, group.Count)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/remediation"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/rollout"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Upgrade formulae with critical vulnerabilities on their outdated devices
+	plan, err := remediation.NewPlan(ctx, workbrewClient.Vulnerabilities, &remediation.PlanOptions{
+		MinCVSSScore: 9.0,
+	})
+	if err != nil {
+		log.Fatalf("Failed to plan remediation: %v", err)
+	}
+	fmt.Print(plan)
+
+	// Brew commands target devices by UUID; map the plan's serial numbers to them
+	deviceIDs, err := rollout.LoadDeviceIDs("device-ids.json")
+	if err != nil {
+		log.Fatalf("Failed to load device IDs: %v", err)
+	}
+
+	report, err := remediation.NewOrchestrator(remediation.NewSource(workbrewClient), &remediation.Options{
+		DeviceID:      deviceIDs.Lookup,
+		RunTimeout:    time.Hour,
+		VerifyTimeout: 2 * time.Hour,
+		Logger:        logger,
+	}).Execute(ctx, plan)
+	if err != nil {
+		log.Fatalf("Remediation failed: %v", err)
+	}
+
+	if err := report.WriteJSON(os.Stdout); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+	fmt.Printf("%d fixed, %d outstanding\n", report.Summary.Fixed, report.Summary.Outstanding)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/rollout"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Upgrade the Admin group first, then every other group
+	plan, err := rollout.NewPlan(ctx, rollout.NewSource(workbrewClient), &rollout.Options{
+		Waves: [][]string{{"Admin"}},
+	})
+	if err != nil {
+		log.Fatalf("Failed to plan rollout: %v", err)
+	}
+	fmt.Print(plan)
+
+	// Brew commands target devices by UUID; map the plan's serial numbers to them
+	deviceIDs, err := rollout.LoadDeviceIDs("device-ids.json")
+	if err != nil {
+		log.Fatalf("Failed to load device IDs: %v", err)
+	}
+
+	executor := rollout.NewExecutor(workbrewClient.BrewCommands, &rollout.ExecuteOptions{
+		DeviceID:       deviceIDs.Lookup,
+		MinSuccessRate: 0.9,
+		WaveTimeout:    time.Hour,
+		Logger:         logger,
+		OnWave: func(wave rollout.WaveResult) {
+			fmt.Printf("Wave %d: %d/%d runs succeeded\n", wave.Wave, wave.Succeeded, wave.Total)
+		},
+	})
+
+	result, err := executor.Execute(ctx, plan)
+	if err != nil {
+		log.Fatalf("Rollout failed: %v", err)
+	}
+	if !result.Completed {
+		log.Fatalf("Rollout halted after wave %d", result.HaltedAfter)
+	}
+	fmt.Println("Rollout completed")
+}
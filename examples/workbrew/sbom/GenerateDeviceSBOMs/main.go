@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/sbom"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Invert the package-to-devices mapping into a per-device inventory
+	inventory, err := sbom.Collect(context.Background(), sbom.NewSource(workbrewClient, workspace))
+	if err != nil {
+		log.Fatalf("Failed to collect inventory: %v", err)
+	}
+
+	// Write one SPDX document per device
+	paths, err := inventory.WriteDir("sboms", sbom.FormatSPDX)
+	if err != nil {
+		log.Fatalf("Failed to write SBOMs: %v", err)
+	}
+
+	for _, device := range inventory.Devices {
+		fmt.Printf("%s: %d packages\n", device.SerialNumber, len(device.Packages))
+	}
+	fmt.Printf("Wrote %d SBOMs to sboms/\n", len(paths))
+}
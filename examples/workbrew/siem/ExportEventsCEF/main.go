@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/siem"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	auditLog, _, err := workbrewClient.Events.ListEvents(ctx, &events.RequestQueryOptions{Filter: events.FilterAll})
+	if err != nil {
+		log.Fatalf("Failed to list events: %v", err)
+	}
+	changes, _, err := workbrewClient.VulnerabilityChanges.ListVulnerabilityChanges(ctx, nil)
+	if err != nil {
+		log.Fatalf("Failed to list vulnerability changes: %v", err)
+	}
+
+	// Report the acting user as suser rather than suid
+	formatter := siem.NewCEFFormatter()
+	formatter.Mapping[siem.FieldActorID] = "suser"
+
+	writer := siem.NewWriter(os.Stdout, formatter)
+	for i := range *auditLog {
+		if err := writer.WriteEvent(&(*auditLog)[i]); err != nil {
+			log.Fatalf("Failed to write event: %v", err)
+		}
+	}
+	for i := range *changes {
+		if err := writer.WriteVulnerabilityChange(&(*changes)[i]); err != nil {
+			log.Fatalf("Failed to write vulnerability change: %v", err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/snapshot"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Path of the previous snapshot to compare against
+	previousPath := "snapshot-previous.json"
+
+	ctx := context.Background()
+	current, err := snapshot.Capture(ctx, snapshot.NewSource(workbrewClient, workspace))
+	if err != nil {
+		log.Fatalf("Failed to capture snapshot: %v", err)
+	}
+
+	fmt.Printf("Captured %d devices, %d formulae, %d casks, %d vulnerabilities\n",
+		len(current.Devices), len(current.Formulae), len(current.Casks), len(current.Vulnerabilities))
+
+	if previous, err := snapshot.Load(previousPath); err == nil {
+		diff := snapshot.Compare(previous, current)
+		if diff.IsEmpty() {
+			fmt.Println("No changes since previous snapshot")
+		} else {
+			out, _ := json.MarshalIndent(diff, "", "  ")
+			fmt.Println(string(out))
+		}
+	}
+
+	if err := current.Save(previousPath); err != nil {
+		log.Fatalf("Failed to save snapshot: %v", err)
+	}
+}
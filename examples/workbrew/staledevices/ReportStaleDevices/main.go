@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/staledevices"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	report, err := staledevices.Generate(context.Background(), staledevices.NewSource(workbrewClient, workspace), &staledevices.Options{
+		StaleAfter: 14 * staledevices.Day,
+	})
+	if err != nil {
+		log.Fatalf("Failed to generate report: %v", err)
+	}
+
+	fmt.Printf("%d of %d devices need follow-up\n", report.Summary.Flagged, report.Summary.Devices)
+	fmt.Println("Last seen:")
+	for _, bucket := range report.Summary.LastSeen {
+		fmt.Printf("  %-8s %d\n", bucket.Bucket, bucket.Devices)
+	}
+
+	fmt.Println("Orphaned devices:")
+	for _, device := range report.Flagged(staledevices.FlagNoGroups) {
+		fmt.Printf("  %s (%s), last seen %s\n", device.SerialNumber, device.DeviceType, device.LastSeenAt)
+	}
+
+	// Export every device for reconciliation against the asset register
+	csvFile, err := os.Create("stale-devices.csv")
+	if err != nil {
+		log.Fatalf("Failed to create CSV file: %v", err)
+	}
+	defer csvFile.Close()
+
+	if err := report.WriteCSV(csvFile); err != nil {
+		log.Fatalf("Failed to write CSV: %v", err)
+	}
+	fmt.Println("Wrote stale-devices.csv")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/vulnreport"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Report medium and higher vulnerabilities, enriched with formula details
+	report, err := vulnreport.Generate(context.Background(), workbrewClient.Vulnerabilities, workbrewClient.Formulae, &vulnreport.Options{
+		Workspace:   workspace,
+		MinSeverity: vulnerabilities.SeverityMedium,
+	})
+	if err != nil {
+		log.Fatalf("Failed to generate report: %v", err)
+	}
+
+	sarifFile, err := os.Create("workbrew.sarif")
+	if err != nil {
+		log.Fatalf("Failed to create SARIF file: %v", err)
+	}
+	defer sarifFile.Close()
+
+	// Attach results to the repository's Brewfile for GitHub code scanning
+	if err := report.WriteSARIF(sarifFile, &vulnreport.SARIFOptions{ArtifactURI: "Brewfile"}); err != nil {
+		log.Fatalf("Failed to write SARIF report: %v", err)
+	}
+
+	vexFile, err := os.Create("workbrew.vex.json")
+	if err != nil {
+		log.Fatalf("Failed to create VEX file: %v", err)
+	}
+	defer vexFile.Close()
+
+	if err := report.WriteCycloneDX(vexFile); err != nil {
+		log.Fatalf("Failed to write CycloneDX VEX report: %v", err)
+	}
+
+	fmt.Printf("Wrote %d findings to workbrew.sarif and workbrew.vex.json\n", len(report.Findings))
+}
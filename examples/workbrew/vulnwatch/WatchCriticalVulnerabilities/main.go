@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/eventstream"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/vulnwatch"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Print newly detected critical CVEs; also post them to a webhook when one is set
+	sinks := []vulnwatch.Sink{vulnwatch.NewStdoutSink()}
+	if webhook := os.Getenv("ALERT_WEBHOOK_URL"); webhook != "" {
+		sinks = append(sinks, vulnwatch.NewWebhookSink(webhook))
+	}
+
+	watcher := vulnwatch.NewWatcher(workbrewClient.VulnerabilityChanges, &vulnwatch.Options{
+		Filter: vulnwatch.Filter{
+			Statuses:    []string{vulnerabilitychanges.StatusDetected},
+			MinSeverity: vulnerabilities.SeverityCritical,
+		},
+		Sinks:       sinks,
+		Workspace:   workspace,
+		Checkpoints: eventstream.NewFileCheckpointStore("vulnerabilities.checkpoint.json"),
+		Logger:      logger,
+	})
+
+	// Watch until interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := watcher.Run(ctx); err != nil {
+		log.Fatalf("Watcher stopped: %v", err)
+	}
+}
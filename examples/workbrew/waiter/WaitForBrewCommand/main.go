@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/waiter"
+	"go.uber.org/zap"
+)
+
+func main() {
+	apiKey := os.Getenv("WORKBREW_API_KEY")
+	workspace := os.Getenv("WORKBREW_WORKSPACE")
+
+	if apiKey == "" || workspace == "" {
+		log.Fatal("WORKBREW_API_KEY and WORKBREW_WORKSPACE environment variables must be set")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	workbrewClient, err := workbrew.NewClient(apiKey, workspace,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Label of the brew command to wait for, as shown by ListBrewCommands
+	label := "install-wget"
+
+	// Give up after 30 minutes
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	result, err := waiter.WaitForBrewCommand(ctx, workbrewClient.BrewCommands, label, &waiter.Options{
+		Logger: logger,
+		OnProgress: func(p waiter.Progress) {
+			for _, outcome := range p.Changed {
+				fmt.Printf("  %s: %s\n", outcome.Device, outcome.Status)
+			}
+			fmt.Printf("%d/%d devices finished\n", p.Finished, p.Total)
+		},
+	})
+	if err != nil {
+		log.Printf("Stopped waiting: %v", err)
+	}
+	if result == nil {
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n%s: %d succeeded, %d failed, %d unfinished\n", result.Label,
+		result.Count(waiter.StatusSucceeded), result.Count(waiter.StatusFailed),
+		len(result.Devices)-result.Count(waiter.StatusSucceeded)-result.Count(waiter.StatusFailed))
+	for _, outcome := range result.Failed() {
+		fmt.Printf("\n%s failed:\n%s\n", outcome.Device, outcome.Output)
+	}
+
+	if !result.Succeeded() {
+		os.Exit(1)
+	}
+}
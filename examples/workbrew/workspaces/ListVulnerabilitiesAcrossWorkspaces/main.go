@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Comma separated workspace=api-key pairs, e.g. "production=key1,staging=key2"
+	workspaceKeys := os.Getenv("WORKBREW_WORKSPACE_KEYS")
+	if workspaceKeys == "" {
+		log.Fatal("WORKBREW_WORKSPACE_KEYS environment variable must be set")
+	}
+
+	apiKeys := make(map[string]string)
+	for _, pair := range strings.Split(workspaceKeys, ",") {
+		workspace, apiKey, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			log.Fatalf("Invalid workspace=api-key pair: %q", pair)
+		}
+		apiKeys[workspace] = apiKey
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+
+	set, err := workbrew.NewWorkspaceSet(apiKeys,
+		client.WithLogger(logger),
+		client.WithBaseURL("https://console.workbrew.com"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create workspace set: %v", err)
+	}
+
+	ctx := context.Background()
+	vulns, err := workbrew.Collect(ctx, set, func(ctx context.Context, c *workbrew.Client) (*vulnerabilities.VulnerabilitiesResponse, *interfaces.Response, error) {
+		return c.Vulnerabilities.ListVulnerabilities(ctx)
+	})
+	if err != nil {
+		// Results from the workspaces that succeeded are still returned
+		fmt.Printf("Some workspaces failed: %v\n", err)
+	}
+
+	fmt.Printf("Retrieved %d vulnerable formulae across %d workspaces\n", len(vulns), set.Len())
+	for _, v := range vulns {
+		fmt.Printf("  [%s] %s %s: %d vulnerabilities on %d devices\n",
+			v.Workspace, v.Item.Formula, v.Item.HomebrewCoreVersion,
+			len(v.Item.Vulnerabilities), len(v.Item.OutdatedDevices))
+	}
+}
@@ -15,7 +15,8 @@ type TestConfig struct {
 	APIKey                string
 	WorkspaceName         string
 	BaseURL               string
-	RateLimitDelay        time.Duration
+	RateLimit             float64
+	RateLimitBurst        int
 	RequestTimeout        time.Duration
 	SkipCleanup           bool
 	Verbose               bool
@@ -41,7 +42,8 @@ func init() {
 		APIKey:                getEnv("WORKBREW_API_KEY", ""),
 		WorkspaceName:         getEnv("WORKBREW_WORKSPACE_NAME", ""),
 		BaseURL:               getEnv("WORKBREW_BASE_URL", "https://console.workbrew.com"),
-		RateLimitDelay:        getDurationEnv("WORKBREW_RATE_LIMIT_DELAY", 2*time.Second), // Conservative default
+		RateLimit:             getFloatEnv("WORKBREW_RATE_LIMIT", 0.5), // Conservative default: one request every 2s
+		RateLimitBurst:        getIntEnv("WORKBREW_RATE_LIMIT_BURST", 1),
 		RequestTimeout:        getDurationEnv("WORKBREW_REQUEST_TIMEOUT", 30*time.Second),
 		SkipCleanup:           getBoolEnv("WORKBREW_SKIP_CLEANUP", false),
 		Verbose:               getBoolEnv("WORKBREW_VERBOSE", false),
@@ -71,6 +73,7 @@ func InitClient() error {
 		Config.WorkspaceName,
 		client.WithBaseURL(Config.BaseURL),
 		client.WithTimeout(Config.RequestTimeout),
+		client.WithRateLimit(Config.RateLimit, Config.RateLimitBurst),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create Workbrew client: %w", err)
@@ -119,6 +122,32 @@ func getBoolEnv(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getFloatEnv retrieves a float environment variable or returns a default value
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Printf("Warning: invalid float value for %s: %s, using default: %v", key, value, defaultValue)
+			return defaultValue
+		}
+		return parsed
+	}
+	return defaultValue
+}
+
+// getIntEnv retrieves an integer environment variable or returns a default value
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Printf("Warning: invalid integer value for %s: %s, using default: %v", key, value, defaultValue)
+			return defaultValue
+		}
+		return parsed
+	}
+	return defaultValue
+}
+
 // getDurationEnv retrieves a duration environment variable or returns a default value
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
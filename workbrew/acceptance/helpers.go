@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -46,14 +45,11 @@ func RequireClient(t *testing.T) {
 	}
 }
 
-// RateLimitedTest wraps a test function with rate limiting
-// Automatically sleeps after test execution to respect API rate limits
+// RateLimitedTest wraps a test function that calls the API
+// Request pacing is handled by the shared client's rate limiter (see WORKBREW_RATE_LIMIT),
+// so tests no longer sleep between runs
 func RateLimitedTest(t *testing.T, testFunc func(t *testing.T)) {
 	t.Helper()
-	defer func() {
-		LogTestWarning(t, "Rate limiting: sleeping for %v", Config.RateLimitDelay)
-		time.Sleep(Config.RateLimitDelay)
-	}()
 	testFunc(t)
 }
 
@@ -0,0 +1,128 @@
+package brewfilesync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// BrewfileExtension is the file extension used for Brewfile definitions in a directory.
+const BrewfileExtension = ".Brewfile"
+
+// Definition is the desired state of a single Brewfile
+type Definition struct {
+	// Label identifies the Brewfile in Workbrew
+	Label string `json:"label"`
+
+	// Content is the Brewfile body
+	Content string `json:"content"`
+
+	// DeviceSerialNumbers assigns the Brewfile to specific devices
+	DeviceSerialNumbers []string `json:"device_serial_numbers,omitempty"`
+
+	// DeviceGroupID assigns the Brewfile to a device group.
+	// Group assignment is not returned by ListBrewfiles, so it is only applied on create/update
+	// and never reported as drift on its own.
+	DeviceGroupID string `json:"device_group_id,omitempty"`
+}
+
+// Validate checks the definition is well formed
+func (d *Definition) Validate() error {
+	if d.Label == "" {
+		return fmt.Errorf("brewfile label is required")
+	}
+	if strings.TrimSpace(d.Content) == "" {
+		return fmt.Errorf("brewfile %q: content is required", d.Label)
+	}
+	if len(d.DeviceSerialNumbers) > 0 && d.DeviceGroupID != "" {
+		return fmt.Errorf("brewfile %q: device_serial_numbers and device_group_id are mutually exclusive", d.Label)
+	}
+	return nil
+}
+
+// assignment is the optional sidecar file stored alongside a Brewfile definition
+type assignment struct {
+	Label               string   `json:"label,omitempty"`
+	DeviceSerialNumbers []string `json:"device_serial_numbers,omitempty"`
+	DeviceGroupID       string   `json:"device_group_id,omitempty"`
+}
+
+// LoadDir reads Brewfile definitions from a directory.
+//
+// Each "<label>.Brewfile" file provides the content for the Brewfile named label.
+// An optional "<label>.json" sidecar sets the device assignment:
+//
+//	{
+//	  "device_serial_numbers": ["TC6R2DHVHG"],
+//	  "device_group_id": ""
+//	}
+//
+// The sidecar may also override the label with a "label" key.
+//
+// Parameters:
+//   - dir: The directory containing definitions
+//
+// Returns:
+//   - []Definition: Definitions sorted by label
+//   - error: Any read, parse or validation error
+func LoadDir(dir string) ([]Definition, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definitions directory: %w", err)
+	}
+
+	var definitions []Definition
+	seen := make(map[string]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != BrewfileExtension {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		base := strings.TrimSuffix(entry.Name(), BrewfileExtension)
+		definition := Definition{
+			Label:   base,
+			Content: string(content),
+		}
+
+		sidecarPath := filepath.Join(dir, base+".json")
+		if data, err := os.ReadFile(sidecarPath); err == nil {
+			var a assignment
+			if err := json.Unmarshal(data, &a); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", sidecarPath, err)
+			}
+			if a.Label != "" {
+				definition.Label = a.Label
+			}
+			definition.DeviceSerialNumbers = a.DeviceSerialNumbers
+			definition.DeviceGroupID = a.DeviceGroupID
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", sidecarPath, err)
+		}
+
+		if err := definition.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if other, ok := seen[definition.Label]; ok {
+			return nil, fmt.Errorf("duplicate brewfile label %q in %s and %s", definition.Label, other, path)
+		}
+		seen[definition.Label] = path
+
+		definitions = append(definitions, definition)
+	}
+
+	slices.SortFunc(definitions, func(a, b Definition) int {
+		return strings.Compare(a.Label, b.Label)
+	})
+
+	return definitions, nil
+}
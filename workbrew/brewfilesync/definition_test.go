@@ -0,0 +1,54 @@
+package brewfilesync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "dev.Brewfile", "brew \"go\"\n")
+	writeFile(t, dir, "dev.json", `{"device_serial_numbers": ["AAA", "BBB"]}`)
+	writeFile(t, dir, "base.Brewfile", "brew \"git\"\n")
+	writeFile(t, dir, "other.Brewfile", "brew \"jq\"\n")
+	writeFile(t, dir, "other.json", `{"label": "renamed", "device_group_id": "group-1"}`)
+	writeFile(t, dir, "README.md", "ignored")
+
+	definitions, err := LoadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, definitions, 3)
+
+	assert.Equal(t, "base", definitions[0].Label)
+	assert.Equal(t, "dev", definitions[1].Label)
+	assert.Equal(t, []string{"AAA", "BBB"}, definitions[1].DeviceSerialNumbers)
+	assert.Equal(t, "renamed", definitions[2].Label)
+	assert.Equal(t, "group-1", definitions[2].DeviceGroupID)
+}
+
+func TestLoadDir_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "bad.Brewfile", "brew \"go\"\n")
+	writeFile(t, dir, "bad.json", `{"device_serial_numbers": ["AAA"], "device_group_id": "g"}`)
+
+	_, err := LoadDir(dir)
+	assert.ErrorContains(t, err, "mutually exclusive")
+}
+
+func TestLoadDir_Duplicate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.Brewfile", "brew \"go\"\n")
+	writeFile(t, dir, "b.Brewfile", "brew \"go\"\n")
+	writeFile(t, dir, "b.json", `{"label": "a"}`)
+
+	_, err := LoadDir(dir)
+	assert.ErrorContains(t, err, "duplicate brewfile label")
+}
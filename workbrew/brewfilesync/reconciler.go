@@ -0,0 +1,341 @@
+package brewfilesync
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"go.uber.org/zap"
+)
+
+// Action is the operation planned for a Brewfile
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionNoop   Action = "noop"
+)
+
+// Change is a single planned operation against a Brewfile
+type Change struct {
+	Action Action `json:"action"`
+	Label  string `json:"label"`
+
+	// Desired is the definition being applied (nil for deletes)
+	Desired *Definition `json:"desired,omitempty"`
+
+	// Current is the Brewfile as returned by ListBrewfiles (nil for creates)
+	Current *brewfiles.Brewfile `json:"current,omitempty"`
+
+	// ContentDiff is a line diff from the current to the desired content
+	ContentDiff string `json:"content_diff,omitempty"`
+
+	// DevicesAdded and DevicesRemoved describe serial number assignment drift
+	DevicesAdded   []string `json:"devices_added,omitempty"`
+	DevicesRemoved []string `json:"devices_removed,omitempty"`
+}
+
+// Plan is the ordered set of changes needed to reach the desired state
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasChanges reports whether applying the plan would modify anything
+func (p *Plan) HasChanges() bool {
+	for _, change := range p.Changes {
+		if change.Action != ActionNoop {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of changes with the given action
+func (p *Plan) Count(action Action) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// String renders a human readable summary of the plan including content diffs
+func (p *Plan) String() string {
+	var sb strings.Builder
+	for _, change := range p.Changes {
+		if change.Action == ActionNoop {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s %s\n", change.Action, change.Label)
+		if len(change.DevicesAdded) > 0 {
+			fmt.Fprintf(&sb, "  devices added: %s\n", strings.Join(change.DevicesAdded, ", "))
+		}
+		if len(change.DevicesRemoved) > 0 {
+			fmt.Fprintf(&sb, "  devices removed: %s\n", strings.Join(change.DevicesRemoved, ", "))
+		}
+		for _, line := range strings.Split(strings.TrimRight(change.ContentDiff, "\n"), "\n") {
+			if line != "" {
+				fmt.Fprintf(&sb, "  %s\n", line)
+			}
+		}
+	}
+	fmt.Fprintf(&sb, "Plan: %d to create, %d to update, %d to delete.\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete))
+	return sb.String()
+}
+
+// Options configures a Reconciler
+type Options struct {
+	// Prune deletes Brewfiles that exist in Workbrew but have no definition
+	Prune bool
+
+	// DryRun computes and logs the plan without calling any mutating endpoint
+	DryRun bool
+
+	// Logger receives progress messages. Defaults to a no-op logger.
+	Logger *zap.Logger
+}
+
+// Reconciler drives Brewfiles in a workspace towards a set of definitions
+type Reconciler struct {
+	service brewfiles.BrewfilesServiceInterface
+	options Options
+}
+
+// NewReconciler creates a reconciler using the given brewfiles service
+//
+// Parameters:
+//   - service: The brewfiles service (e.g. client.Brewfiles)
+//   - options: Reconciler options; nil uses defaults (no prune, no dry run)
+func NewReconciler(service brewfiles.BrewfilesServiceInterface, options *Options) *Reconciler {
+	r := &Reconciler{service: service}
+	if options != nil {
+		r.options = *options
+	}
+	if r.options.Logger == nil {
+		r.options.Logger = zap.NewNop()
+	}
+	return r
+}
+
+// Plan compares the desired definitions with the Brewfiles returned by ListBrewfiles
+//
+// Parameters:
+//   - ctx: Context for the API call
+//   - desired: The desired Brewfile definitions
+//
+// Returns:
+//   - *Plan: Creates and updates sorted by label, followed by deletes when pruning
+//   - error: Validation or API errors
+func (r *Reconciler) Plan(ctx context.Context, desired []Definition) (*Plan, error) {
+	seen := make(map[string]bool, len(desired))
+	for i := range desired {
+		if err := desired[i].Validate(); err != nil {
+			return nil, err
+		}
+		if seen[desired[i].Label] {
+			return nil, fmt.Errorf("duplicate brewfile label %q", desired[i].Label)
+		}
+		seen[desired[i].Label] = true
+	}
+
+	current, _, err := r.service.ListBrewfiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list brewfiles: %w", err)
+	}
+
+	existing := make(map[string]*brewfiles.Brewfile, len(*current))
+	for i := range *current {
+		existing[(*current)[i].Label] = &(*current)[i]
+	}
+
+	plan := &Plan{}
+
+	for i := range desired {
+		definition := &desired[i]
+		currentBrewfile, ok := existing[definition.Label]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{
+				Action:       ActionCreate,
+				Label:        definition.Label,
+				Desired:      definition,
+				ContentDiff:  contentDiff("", definition.Content),
+				DevicesAdded: sortedCopy(definition.DeviceSerialNumbers),
+			})
+			continue
+		}
+
+		change := Change{
+			Action:      ActionNoop,
+			Label:       definition.Label,
+			Desired:     definition,
+			Current:     currentBrewfile,
+			ContentDiff: contentDiff(currentBrewfile.Content, definition.Content),
+		}
+
+		if len(definition.DeviceSerialNumbers) > 0 {
+			currentSerials := make([]string, 0, len(currentBrewfile.Devices))
+			for _, device := range currentBrewfile.Devices {
+				currentSerials = append(currentSerials, device.SerialNumber)
+			}
+			change.DevicesAdded, change.DevicesRemoved = stringSetDelta(currentSerials, definition.DeviceSerialNumbers)
+		}
+
+		if change.ContentDiff != "" || len(change.DevicesAdded) > 0 || len(change.DevicesRemoved) > 0 {
+			change.Action = ActionUpdate
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	slices.SortFunc(plan.Changes, func(a, b Change) int {
+		return strings.Compare(a.Label, b.Label)
+	})
+
+	if r.options.Prune {
+		var deletes []Change
+		for label, currentBrewfile := range existing {
+			if seen[label] {
+				continue
+			}
+			deletes = append(deletes, Change{
+				Action:      ActionDelete,
+				Label:       label,
+				Current:     currentBrewfile,
+				ContentDiff: contentDiff(currentBrewfile.Content, ""),
+			})
+		}
+		slices.SortFunc(deletes, func(a, b Change) int {
+			return strings.Compare(a.Label, b.Label)
+		})
+		plan.Changes = append(plan.Changes, deletes...)
+	}
+
+	return plan, nil
+}
+
+// ApplyResult records which changes were applied
+type ApplyResult struct {
+	// Applied lists the changes that succeeded (or would have, in dry-run mode)
+	Applied []Change `json:"applied"`
+
+	// DryRun is true when no mutating calls were made
+	DryRun bool `json:"dry_run"`
+}
+
+// Apply executes a plan using CreateBrewfile, UpdateBrewfile and DeleteBrewfile.
+// Execution stops at the first failing change; the result lists the changes applied before it.
+// Delete changes are skipped unless the reconciler was created with Prune enabled.
+//
+// Parameters:
+//   - ctx: Context for the API calls
+//   - plan: A plan produced by Plan
+//
+// Returns:
+//   - *ApplyResult: The changes applied
+//   - error: The first API error encountered, if any
+func (r *Reconciler) Apply(ctx context.Context, plan *Plan) (*ApplyResult, error) {
+	result := &ApplyResult{DryRun: r.options.DryRun}
+	logger := r.options.Logger
+
+	for _, change := range plan.Changes {
+		if change.Action == ActionNoop {
+			continue
+		}
+		if change.Action == ActionDelete && !r.options.Prune {
+			logger.Debug("Skipping delete because prune is disabled", zap.String("label", change.Label))
+			continue
+		}
+
+		if r.options.DryRun {
+			logger.Info("Dry run: would apply brewfile change",
+				zap.String("action", string(change.Action)),
+				zap.String("label", change.Label))
+			result.Applied = append(result.Applied, change)
+			continue
+		}
+
+		if err := r.applyChange(ctx, change); err != nil {
+			return result, fmt.Errorf("failed to %s brewfile %q: %w", change.Action, change.Label, err)
+		}
+
+		logger.Info("Applied brewfile change",
+			zap.String("action", string(change.Action)),
+			zap.String("label", change.Label))
+		result.Applied = append(result.Applied, change)
+	}
+
+	return result, nil
+}
+
+// applyChange performs the API call for a single change
+func (r *Reconciler) applyChange(ctx context.Context, change Change) error {
+	switch change.Action {
+	case ActionCreate:
+		request := &brewfiles.CreateBrewfileRequest{
+			Label:   change.Desired.Label,
+			Content: change.Desired.Content,
+		}
+		request.DeviceSerialNumbers, request.DeviceGroupID = assignmentFields(change.Desired)
+		_, _, err := r.service.CreateBrewfile(ctx, request)
+		return err
+
+	case ActionUpdate:
+		request := &brewfiles.UpdateBrewfileRequest{
+			Content: change.Desired.Content,
+		}
+		request.DeviceSerialNumbers, request.DeviceGroupID = assignmentFields(change.Desired)
+		_, _, err := r.service.UpdateBrewfile(ctx, change.Label, request)
+		return err
+
+	case ActionDelete:
+		_, _, err := r.service.DeleteBrewfile(ctx, change.Label)
+		return err
+	}
+
+	return fmt.Errorf("unsupported action %q", change.Action)
+}
+
+// assignmentFields converts a definition's assignment to the request's optional fields
+func assignmentFields(definition *Definition) (serials *string, groupID *string) {
+	if len(definition.DeviceSerialNumbers) > 0 {
+		joined := strings.Join(definition.DeviceSerialNumbers, ",")
+		serials = &joined
+	}
+	if definition.DeviceGroupID != "" {
+		id := definition.DeviceGroupID
+		groupID = &id
+	}
+	return serials, groupID
+}
+
+// stringSetDelta returns the sorted values only in desired (added) and only in current (removed)
+func stringSetDelta(current, desired []string) (added, removed []string) {
+	for _, value := range desired {
+		if !slices.Contains(current, value) {
+			added = append(added, value)
+		}
+	}
+	for _, value := range current {
+		if !slices.Contains(desired, value) {
+			removed = append(removed, value)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
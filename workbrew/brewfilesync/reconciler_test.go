@@ -0,0 +1,174 @@
+package brewfilesync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBrewfiles records mutating calls made against an in-memory list of brewfiles
+type fakeBrewfiles struct {
+	brewfiles.BrewfilesServiceInterface
+	existing brewfiles.BrewfilesResponse
+	created  []*brewfiles.CreateBrewfileRequest
+	updated  map[string]*brewfiles.UpdateBrewfileRequest
+	deleted  []string
+	failOn   string
+}
+
+func (f *fakeBrewfiles) ListBrewfiles(ctx context.Context) (*brewfiles.BrewfilesResponse, *interfaces.Response, error) {
+	return &f.existing, nil, nil
+}
+
+func (f *fakeBrewfiles) CreateBrewfile(ctx context.Context, request *brewfiles.CreateBrewfileRequest) (*brewfiles.BrewfileMessageResponse, *interfaces.Response, error) {
+	if request.Label == f.failOn {
+		return nil, nil, errors.New("validation failed")
+	}
+	f.created = append(f.created, request)
+	return &brewfiles.BrewfileMessageResponse{}, nil, nil
+}
+
+func (f *fakeBrewfiles) UpdateBrewfile(ctx context.Context, label string, request *brewfiles.UpdateBrewfileRequest) (*brewfiles.BrewfileMessageResponse, *interfaces.Response, error) {
+	if f.updated == nil {
+		f.updated = make(map[string]*brewfiles.UpdateBrewfileRequest)
+	}
+	f.updated[label] = request
+	return &brewfiles.BrewfileMessageResponse{}, nil, nil
+}
+
+func (f *fakeBrewfiles) DeleteBrewfile(ctx context.Context, label string) (*brewfiles.BrewfileMessageResponse, *interfaces.Response, error) {
+	f.deleted = append(f.deleted, label)
+	return &brewfiles.BrewfileMessageResponse{}, nil, nil
+}
+
+func newFake() *fakeBrewfiles {
+	return &fakeBrewfiles{
+		existing: brewfiles.BrewfilesResponse{
+			{Label: "base", Content: "brew \"git\"\n"},
+			{Label: "dev", Content: "brew \"go\"", Devices: []brewfiles.BrewfileDevice{{SerialNumber: "AAA"}}},
+			{Label: "legacy", Content: "brew \"svn\""},
+		},
+	}
+}
+
+func desiredState() []Definition {
+	return []Definition{
+		{Label: "base", Content: "brew \"git\""},
+		{Label: "dev", Content: "brew \"go\"\nbrew \"node\"", DeviceSerialNumbers: []string{"AAA", "BBB"}},
+		{Label: "new", Content: "brew \"jq\"", DeviceGroupID: "group-1"},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	reconciler := NewReconciler(newFake(), &Options{Prune: true})
+
+	plan, err := reconciler.Plan(context.Background(), desiredState())
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 4)
+
+	assert.Equal(t, ActionNoop, plan.Changes[0].Action)
+	assert.Equal(t, "base", plan.Changes[0].Label)
+
+	dev := plan.Changes[1]
+	assert.Equal(t, ActionUpdate, dev.Action)
+	assert.Equal(t, []string{"BBB"}, dev.DevicesAdded)
+	assert.Empty(t, dev.DevicesRemoved)
+	assert.Equal(t, " brew \"go\"\n+brew \"node\"\n", dev.ContentDiff)
+
+	assert.Equal(t, ActionCreate, plan.Changes[2].Action)
+	assert.Equal(t, "new", plan.Changes[2].Label)
+
+	assert.Equal(t, ActionDelete, plan.Changes[3].Action)
+	assert.Equal(t, "legacy", plan.Changes[3].Label)
+
+	assert.True(t, plan.HasChanges())
+	assert.Contains(t, plan.String(), "Plan: 1 to create, 1 to update, 1 to delete.")
+}
+
+func TestPlan_NoPrune(t *testing.T) {
+	reconciler := NewReconciler(newFake(), nil)
+
+	plan, err := reconciler.Plan(context.Background(), desiredState())
+	require.NoError(t, err)
+	assert.Equal(t, 0, plan.Count(ActionDelete))
+}
+
+func TestPlan_InvalidDefinition(t *testing.T) {
+	reconciler := NewReconciler(newFake(), nil)
+
+	_, err := reconciler.Plan(context.Background(), []Definition{{Label: "x", Content: ""}})
+	assert.Error(t, err)
+
+	_, err = reconciler.Plan(context.Background(), []Definition{
+		{Label: "x", Content: "brew \"a\""},
+		{Label: "x", Content: "brew \"b\""},
+	})
+	assert.ErrorContains(t, err, "duplicate")
+}
+
+func TestApply(t *testing.T) {
+	fake := newFake()
+	reconciler := NewReconciler(fake, &Options{Prune: true})
+
+	plan, err := reconciler.Plan(context.Background(), desiredState())
+	require.NoError(t, err)
+
+	result, err := reconciler.Apply(context.Background(), plan)
+	require.NoError(t, err)
+	assert.False(t, result.DryRun)
+	assert.Len(t, result.Applied, 3)
+
+	require.Len(t, fake.created, 1)
+	assert.Equal(t, "new", fake.created[0].Label)
+	require.NotNil(t, fake.created[0].DeviceGroupID)
+	assert.Equal(t, "group-1", *fake.created[0].DeviceGroupID)
+	assert.Nil(t, fake.created[0].DeviceSerialNumbers)
+
+	require.Contains(t, fake.updated, "dev")
+	require.NotNil(t, fake.updated["dev"].DeviceSerialNumbers)
+	assert.Equal(t, "AAA,BBB", *fake.updated["dev"].DeviceSerialNumbers)
+
+	assert.Equal(t, []string{"legacy"}, fake.deleted)
+}
+
+func TestApply_DryRun(t *testing.T) {
+	fake := newFake()
+	reconciler := NewReconciler(fake, &Options{Prune: true, DryRun: true})
+
+	plan, err := reconciler.Plan(context.Background(), desiredState())
+	require.NoError(t, err)
+
+	result, err := reconciler.Apply(context.Background(), plan)
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Len(t, result.Applied, 3)
+	assert.Empty(t, fake.created)
+	assert.Empty(t, fake.updated)
+	assert.Empty(t, fake.deleted)
+}
+
+func TestApply_StopsOnError(t *testing.T) {
+	fake := newFake()
+	fake.failOn = "new"
+	reconciler := NewReconciler(fake, &Options{Prune: true})
+
+	plan, err := reconciler.Plan(context.Background(), desiredState())
+	require.NoError(t, err)
+
+	result, err := reconciler.Apply(context.Background(), plan)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to create brewfile "new"`)
+	assert.Len(t, result.Applied, 1)
+	assert.Empty(t, fake.deleted)
+}
+
+func TestContentDiff(t *testing.T) {
+	assert.Empty(t, contentDiff("a\nb\n", "a\r\nb  "))
+	assert.Equal(t, "-a\n+c\n b\n", contentDiff("a\nb", "c\nb"))
+	assert.Equal(t, "+a\n", contentDiff("", "a"))
+}
@@ -0,0 +1,75 @@
+package brewfilesync
+
+import "strings"
+
+// contentDiff renders a line-based diff between two Brewfile bodies.
+// Lines are prefixed with "-" (removed), "+" (added) or " " (unchanged).
+// Returns an empty string when the normalised contents are equal.
+func contentDiff(from, to string) string {
+	if normaliseContent(from) == normaliseContent(to) {
+		return ""
+	}
+
+	a := splitLines(from)
+	b := splitLines(to)
+
+	// Longest common subsequence table
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			sb.WriteString(" " + a[i] + "\n")
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			sb.WriteString("-" + a[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+" + b[j] + "\n")
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		sb.WriteString("-" + a[i] + "\n")
+	}
+	for ; j < len(b); j++ {
+		sb.WriteString("+" + b[j] + "\n")
+	}
+
+	return sb.String()
+}
+
+// normaliseContent strips line ending and trailing whitespace differences
+// so that cosmetic changes are not reported as drift.
+func normaliseContent(content string) string {
+	return strings.Join(splitLines(content), "\n")
+}
+
+func splitLines(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimRight(content, "\n")
+	if content == "" {
+		return nil
+	}
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return lines
+}
@@ -0,0 +1,358 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"go.uber.org/zap"
+)
+
+// DefaultCacheTTL is used for endpoints without a TTL in CacheConfig.TTLs when DefaultTTL is unset
+const DefaultCacheTTL = 30 * time.Second
+
+// CacheEntry is a stored GET response. Fields are exported so that external
+// stores can serialise entries (e.g. as JSON in Redis or on disk).
+type CacheEntry struct {
+	StatusCode   int         `json:"status_code"`
+	Status       string      `json:"status"`
+	Headers      http.Header `json:"headers"`
+	Body         []byte      `json:"body"`
+	StoredAt     time.Time   `json:"stored_at"`
+	ExpiresAt    time.Time   `json:"expires_at"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+}
+
+// fresh reports whether the entry can be served without contacting the API
+func (e *CacheEntry) fresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// revalidatable reports whether the entry carries validators for a conditional request
+func (e *CacheEntry) revalidatable() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// CacheStore is a backend for cached GET responses.
+// Implementations must be safe for concurrent use. Store errors are logged and
+// treated as cache misses, so a failing backend never fails an API call.
+type CacheStore interface {
+	// Get returns the entry for key, or false if it is not stored
+	Get(ctx context.Context, key string) (*CacheEntry, bool, error)
+	// Set stores entry under key, replacing any previous entry
+	Set(ctx context.Context, key string, entry *CacheEntry) error
+	// Delete removes the entry for key if present
+	Delete(ctx context.Context, key string) error
+}
+
+// CacheConfig configures response caching for GET requests.
+type CacheConfig struct {
+	// Store is the cache backend. Defaults to an in-memory LRU of 256 entries.
+	Store CacheStore
+
+	// DefaultTTL is how long responses stay fresh. Defaults to DefaultCacheTTL.
+	DefaultTTL time.Duration
+
+	// TTLs overrides DefaultTTL per endpoint. Keys are path.Match patterns
+	// relative to the workspace, e.g. "/devices.json" or "/brewfiles/*/runs.json".
+	// A TTL of zero disables caching for matching endpoints.
+	TTLs map[string]time.Duration
+}
+
+// ttlFor returns the TTL for an endpoint path. When several patterns match,
+// the longest (most specific) pattern wins.
+func (c *CacheConfig) ttlFor(endpoint string) time.Duration {
+	ttl, best := c.DefaultTTL, -1
+	for pattern, patternTTL := range c.TTLs {
+		if matched, err := path.Match(pattern, endpoint); err == nil && matched && len(pattern) > best {
+			ttl, best = patternTTL, len(pattern)
+		}
+	}
+	return ttl
+}
+
+// responseCache holds the caching state of a Transport
+type responseCache struct {
+	config *CacheConfig
+	now    func() time.Time
+
+	// generations are bumped when a resource is modified so that cached reads of
+	// it are no longer addressed by new cache keys
+	mu          sync.Mutex
+	generations map[string]uint64
+}
+
+// newResponseCache applies defaults to config
+func newResponseCache(config *CacheConfig) *responseCache {
+	resolved := *config
+	if resolved.Store == nil {
+		resolved.Store = NewLRUCache(256)
+	}
+	if resolved.DefaultTTL <= 0 {
+		resolved.DefaultTTL = DefaultCacheTTL
+	}
+	return &responseCache{
+		config:      &resolved,
+		now:         time.Now,
+		generations: make(map[string]uint64),
+	}
+}
+
+// resourceOf returns the top-level resource of an endpoint path, e.g.
+// "brewfiles" for both "/brewfiles.json" and "/brewfiles/dev/runs.json"
+func resourceOf(endpoint string) string {
+	first, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/"), "/")
+	name, _, _ := strings.Cut(first, ".")
+	return name
+}
+
+// key builds the cache key for a GET request
+func (c *responseCache) key(baseURL, endpoint string, queryParams map[string]string) string {
+	values := url.Values{}
+	for k, v := range queryParams {
+		if v != "" {
+			values.Set(k, v)
+		}
+	}
+
+	c.mu.Lock()
+	generation := c.generations[resourceOf(endpoint)]
+	c.mu.Unlock()
+
+	return fmt.Sprintf("%s%s?%s#%d", baseURL, endpoint, values.Encode(), generation)
+}
+
+// invalidate makes cached reads of the resource behind endpoint unreachable
+func (c *responseCache) invalidate(endpoint string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[resourceOf(endpoint)]++
+}
+
+// cacheFetchFunc performs an uncached GET with the given extra headers and returns
+// response metadata and the raw body
+type cacheFetchFunc func(extraHeaders map[string]string) (*interfaces.Response, []byte, error)
+
+// cachedGet serves a GET from the cache when fresh, revalidates stale entries
+// with If-None-Match/If-Modified-Since, and stores successful responses.
+// The returned bool is true when the body came from a cache entry (hit or 304).
+func (t *Transport) cachedGet(ctx context.Context, endpoint string, queryParams map[string]string, headers map[string]string, fetch cacheFetchFunc) (*interfaces.Response, []byte, bool, error) {
+	cache := t.cache
+	ttl := cache.config.ttlFor(endpoint)
+	if ttl <= 0 {
+		resp, body, err := fetch(headers)
+		return resp, body, false, err
+	}
+
+	key := cache.key(t.client.BaseURL(), endpoint, queryParams)
+	store := cache.config.Store
+
+	entry, found, err := store.Get(ctx, key)
+	if err != nil {
+		t.logger.Warn("Response cache read failed", zap.String("path", endpoint), zap.Error(err))
+		found = false
+	}
+
+	now := cache.now()
+	if found && entry.fresh(now) {
+		t.logger.Debug("Response cache hit", zap.String("path", endpoint))
+		return entry.response(interfaces.CacheStatusHit, now), entry.Body, true, nil
+	}
+
+	requestHeaders := headers
+	if found && entry.revalidatable() {
+		requestHeaders = make(map[string]string, len(headers)+2)
+		for k, v := range headers {
+			requestHeaders[k] = v
+		}
+		if entry.ETag != "" {
+			requestHeaders["If-None-Match"] = entry.ETag
+		}
+		if entry.LastModified != "" {
+			requestHeaders["If-Modified-Since"] = entry.LastModified
+		}
+	}
+
+	resp, body, err := fetch(requestHeaders)
+	if err != nil {
+		return resp, body, false, err
+	}
+
+	now = cache.now()
+	if resp.StatusCode == http.StatusNotModified && found {
+		entry.StoredAt = now
+		entry.ExpiresAt = now.Add(ttl)
+		t.storeEntry(ctx, key, entry)
+
+		t.logger.Debug("Response cache revalidated", zap.String("path", endpoint))
+		revalidated := entry.response(interfaces.CacheStatusRevalidated, now)
+		revalidated.Duration = resp.Duration
+		revalidated.ReceivedAt = resp.ReceivedAt
+		revalidated.Attempts = resp.Attempts
+		return revalidated, entry.Body, true, nil
+	}
+
+	resp.CacheStatus = interfaces.CacheStatusMiss
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 && !noStore(resp.Headers) {
+		t.storeEntry(ctx, key, &CacheEntry{
+			StatusCode:   resp.StatusCode,
+			Status:       resp.Status,
+			Headers:      resp.Headers.Clone(),
+			Body:         body,
+			StoredAt:     now,
+			ExpiresAt:    now.Add(ttl),
+			ETag:         resp.Headers.Get("ETag"),
+			LastModified: resp.Headers.Get("Last-Modified"),
+		})
+	}
+
+	return resp, body, false, nil
+}
+
+// storeEntry writes an entry, logging rather than returning store failures
+func (t *Transport) storeEntry(ctx context.Context, key string, entry *CacheEntry) {
+	if err := t.cache.config.Store.Set(ctx, key, entry); err != nil {
+		t.logger.Warn("Response cache write failed", zap.Error(err))
+	}
+}
+
+// invalidateCache is called after successful writes so subsequent reads of the
+// modified resource go to the API
+func (t *Transport) invalidateCache(method, endpoint string) {
+	if t.cache == nil || method == http.MethodGet {
+		return
+	}
+	t.cache.invalidate(endpoint)
+}
+
+// response rebuilds response metadata from a cache entry
+func (e *CacheEntry) response(status string, now time.Time) *interfaces.Response {
+	return &interfaces.Response{
+		StatusCode:  e.StatusCode,
+		Status:      e.Status,
+		Headers:     e.Headers.Clone(),
+		Body:        e.Body,
+		ReceivedAt:  now,
+		Size:        int64(len(e.Body)),
+		CacheStatus: status,
+		CacheAge:    now.Sub(e.StoredAt),
+	}
+}
+
+// noStore reports whether the server asked for the response not to be cached
+func noStore(headers http.Header) bool {
+	for _, directive := range strings.Split(headers.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeCachedBody unmarshals a cached JSON body into result
+func decodeCachedBody(body []byte, result any) error {
+	if result == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to decode cached response: %w", err)
+	}
+	return nil
+}
+
+// LRUCache is an in-memory CacheStore that evicts the least recently used
+// entry once capacity is reached.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+// lruItem is the value stored in each list element
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache creates an in-memory LRU cache store.
+//
+// Parameters:
+//   - capacity: Maximum number of responses kept (minimum 1)
+//
+// Returns:
+//   - *LRUCache: An empty cache store
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements CacheStore.
+func (c *LRUCache) Get(_ context.Context, key string) (*CacheEntry, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	c.order.MoveToFront(element)
+
+	entry := *element.Value.(*lruItem).entry
+	return &entry, true, nil
+}
+
+// Set implements CacheStore.
+func (c *LRUCache) Set(_ context.Context, key string, entry *CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored := *entry
+	if element, ok := c.items[key]; ok {
+		element.Value.(*lruItem).entry = &stored
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&lruItem{key: key, entry: &stored})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruItem).key)
+	}
+	return nil
+}
+
+// Delete implements CacheStore.
+func (c *LRUCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.Remove(element)
+		delete(c.items, key)
+	}
+	return nil
+}
+
+// Len returns the number of cached responses.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type cacheTestServer struct {
+	calls       atomic.Int32
+	conditional atomic.Int32
+	etag        string
+	body        string
+}
+
+func (s *cacheTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.calls.Add(1)
+	if s.etag != "" && r.Header.Get("If-None-Match") == s.etag {
+		s.conditional.Add(1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(s.body))
+}
+
+func newCacheTestTransport(t *testing.T, handler http.Handler, config *CacheConfig) (*Transport, *time.Time) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	transport, err := NewTransport("test-api-key", "test-workspace",
+		WithLogger(zaptest.NewLogger(t)),
+		WithBaseURL(server.URL),
+		WithCache(config),
+	)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	transport.cache.now = func() time.Time { return now }
+	return transport, &now
+}
+
+func TestCache_HitWithinTTL(t *testing.T) {
+	server := &cacheTestServer{body: `[{"serial_number":"ABC"}]`}
+	transport, now := newCacheTestTransport(t, server, &CacheConfig{DefaultTTL: time.Minute})
+
+	var first []map[string]any
+	resp, err := transport.Get(context.Background(), "/devices.json", nil, nil, &first)
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.CacheStatusMiss, resp.CacheStatus)
+	assert.False(t, resp.FromCache())
+
+	*now = now.Add(30 * time.Second)
+
+	var second []map[string]any
+	resp, err = transport.Get(context.Background(), "/devices.json", nil, nil, &second)
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.CacheStatusHit, resp.CacheStatus)
+	assert.True(t, resp.FromCache())
+	assert.Equal(t, 30*time.Second, resp.CacheAge)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), server.calls.Load())
+}
+
+func TestCache_ExpiredWithoutValidatorsRefetches(t *testing.T) {
+	server := &cacheTestServer{body: `[]`}
+	transport, now := newCacheTestTransport(t, server, &CacheConfig{DefaultTTL: time.Minute})
+
+	var result []map[string]any
+	_, err := transport.Get(context.Background(), "/devices.json", nil, nil, &result)
+	require.NoError(t, err)
+
+	*now = now.Add(2 * time.Minute)
+
+	resp, err := transport.Get(context.Background(), "/devices.json", nil, nil, &result)
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.CacheStatusMiss, resp.CacheStatus)
+	assert.Equal(t, int32(2), server.calls.Load())
+	assert.Equal(t, int32(0), server.conditional.Load())
+}
+
+func TestCache_RevalidatesWithETag(t *testing.T) {
+	server := &cacheTestServer{body: `[{"name":"wget"}]`, etag: `"v1"`}
+	transport, now := newCacheTestTransport(t, server, &CacheConfig{DefaultTTL: time.Minute})
+
+	var first []map[string]any
+	_, err := transport.Get(context.Background(), "/formulae.json", nil, nil, &first)
+	require.NoError(t, err)
+
+	*now = now.Add(2 * time.Minute)
+
+	var second []map[string]any
+	resp, err := transport.Get(context.Background(), "/formulae.json", nil, nil, &second)
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.CacheStatusRevalidated, resp.CacheStatus)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), server.conditional.Load())
+
+	// Revalidation refreshes the TTL
+	resp, err = transport.Get(context.Background(), "/formulae.json", nil, nil, &second)
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.CacheStatusHit, resp.CacheStatus)
+	assert.Equal(t, int32(2), server.calls.Load())
+}
+
+func TestCache_GetBytes(t *testing.T) {
+	server := &cacheTestServer{body: "serial_number\nABC\n"}
+	transport, _ := newCacheTestTransport(t, server, nil)
+
+	_, first, err := transport.GetBytes(context.Background(), "/devices.csv", nil, nil)
+	require.NoError(t, err)
+
+	resp, second, err := transport.GetBytes(context.Background(), "/devices.csv", nil, nil)
+	require.NoError(t, err)
+	assert.True(t, resp.FromCache())
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), server.calls.Load())
+}
+
+func TestCache_KeyIncludesQueryParameters(t *testing.T) {
+	server := &cacheTestServer{body: `[]`}
+	transport, _ := newCacheTestTransport(t, server, nil)
+
+	var result []map[string]any
+	_, err := transport.Get(context.Background(), "/events.json", map[string]string{"filter": "user"}, nil, &result)
+	require.NoError(t, err)
+	_, err = transport.Get(context.Background(), "/events.json", map[string]string{"filter": "system"}, nil, &result)
+	require.NoError(t, err)
+	_, err = transport.Get(context.Background(), "/events.json", map[string]string{"filter": "user"}, nil, &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), server.calls.Load())
+}
+
+func TestCache_PerEndpointTTL(t *testing.T) {
+	server := &cacheTestServer{body: `[]`}
+	transport, now := newCacheTestTransport(t, server, &CacheConfig{
+		DefaultTTL: time.Minute,
+		TTLs: map[string]time.Duration{
+			"/events.json":           0,
+			"/brewfiles/*/runs.json": 10 * time.Second,
+		},
+	})
+
+	var result []map[string]any
+	for range 2 {
+		_, err := transport.Get(context.Background(), "/events.json", nil, nil, &result)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), server.calls.Load(), "zero TTL disables caching")
+
+	_, err := transport.Get(context.Background(), "/brewfiles/dev/runs.json", nil, nil, &result)
+	require.NoError(t, err)
+	*now = now.Add(15 * time.Second)
+	resp, err := transport.Get(context.Background(), "/brewfiles/dev/runs.json", nil, nil, &result)
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.CacheStatusMiss, resp.CacheStatus)
+	assert.Equal(t, int32(4), server.calls.Load())
+}
+
+func TestCache_WritesInvalidateResource(t *testing.T) {
+	server := &cacheTestServer{body: `{"message":"ok"}`}
+	transport, _ := newCacheTestTransport(t, server, nil)
+
+	var result map[string]any
+	_, err := transport.Get(context.Background(), "/brewfiles.json", nil, nil, &result)
+	require.NoError(t, err)
+	_, err = transport.Get(context.Background(), "/devices.json", nil, nil, &result)
+	require.NoError(t, err)
+
+	_, err = transport.Put(context.Background(), "/brewfiles/dev.json", map[string]string{"content": ""}, nil, &result)
+	require.NoError(t, err)
+
+	resp, err := transport.Get(context.Background(), "/brewfiles.json", nil, nil, &result)
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.CacheStatusMiss, resp.CacheStatus)
+
+	resp, err = transport.Get(context.Background(), "/devices.json", nil, nil, &result)
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.CacheStatusHit, resp.CacheStatus)
+}
+
+func TestCache_NoStoreIsNotCached(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	})
+	transport, _ := newCacheTestTransport(t, handler, nil)
+
+	var result []map[string]any
+	for range 2 {
+		resp, err := transport.Get(context.Background(), "/devices.json", nil, nil, &result)
+		require.NoError(t, err)
+		assert.Equal(t, interfaces.CacheStatusMiss, resp.CacheStatus)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Get(context.Context, string) (*CacheEntry, bool, error) {
+	return nil, false, errors.New("store unavailable")
+}
+func (failingStore) Set(context.Context, string, *CacheEntry) error {
+	return errors.New("store unavailable")
+}
+func (failingStore) Delete(context.Context, string) error { return nil }
+
+func TestCache_StoreErrorsFallBackToAPI(t *testing.T) {
+	server := &cacheTestServer{body: `[]`}
+	transport, _ := newCacheTestTransport(t, server, &CacheConfig{Store: failingStore{}})
+
+	var result []map[string]any
+	resp, err := transport.Get(context.Background(), "/devices.json", nil, nil, &result)
+	require.NoError(t, err)
+	assert.Equal(t, interfaces.CacheStatusMiss, resp.CacheStatus)
+}
+
+func TestWithCache_InvalidPattern(t *testing.T) {
+	_, err := NewTransport("test-api-key", "test-workspace",
+		WithCache(&CacheConfig{TTLs: map[string]time.Duration{"[": time.Second}}))
+	assert.Error(t, err)
+}
+
+func TestLRUCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(2)
+
+	require.NoError(t, cache.Set(ctx, "a", &CacheEntry{Body: []byte("a")}))
+	require.NoError(t, cache.Set(ctx, "b", &CacheEntry{Body: []byte("b")}))
+
+	// Touch "a" so "b" becomes least recently used
+	_, found, _ := cache.Get(ctx, "a")
+	require.True(t, found)
+
+	require.NoError(t, cache.Set(ctx, "c", &CacheEntry{Body: []byte("c")}))
+	assert.Equal(t, 2, cache.Len())
+
+	_, found, _ = cache.Get(ctx, "b")
+	assert.False(t, found)
+	_, found, _ = cache.Get(ctx, "a")
+	assert.True(t, found)
+
+	require.NoError(t, cache.Delete(ctx, "a"))
+	_, found, _ = cache.Get(ctx, "a")
+	assert.False(t, found)
+}
@@ -63,3 +63,11 @@ const (
 	ContentTypeJSON = "application/json"
 	AcceptJSON      = "application/json"
 )
+
+// Rate limit response headers
+const (
+	HeaderQuotaLimit     = "X-Api-Quota-Limit"
+	HeaderQuotaRemaining = "X-Api-Quota-Remaining"
+	HeaderQuotaReset     = "X-Api-Quota-Reset"
+	HeaderRetryAfter     = "Retry-After"
+)
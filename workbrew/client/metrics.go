@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// instrumentationName identifies the SDK as the source of its metrics
+const instrumentationName = "github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+
+// Metric attribute keys
+const (
+	attrOperation  = attribute.Key("workbrew.operation")
+	attrMethod     = attribute.Key("http.request.method")
+	attrStatusCode = attribute.Key("http.response.status_code")
+	attrErrorType  = attribute.Key("error.type")
+)
+
+// clientMetrics holds the OpenTelemetry instruments recorded by a Transport.
+// A nil *clientMetrics records nothing.
+type clientMetrics struct {
+	requests      metric.Int64Counter
+	duration      metric.Float64Histogram
+	errors        metric.Int64Counter
+	retries       metric.Int64Counter
+	rateLimitWait metric.Float64Histogram
+}
+
+// newClientMetrics creates the SDK's instruments on the given meter provider
+func newClientMetrics(provider metric.MeterProvider) (*clientMetrics, error) {
+	meter := provider.Meter(instrumentationName, metric.WithInstrumentationVersion(Version))
+
+	var m clientMetrics
+	var err error
+	if m.requests, err = meter.Int64Counter("workbrew.client.requests",
+		metric.WithDescription("Number of Workbrew API operations performed"),
+		metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+	if m.duration, err = meter.Float64Histogram("workbrew.client.request.duration",
+		metric.WithDescription("Duration of Workbrew API operations, including retries"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if m.errors, err = meter.Int64Counter("workbrew.client.errors",
+		metric.WithDescription("Number of Workbrew API operations that returned an error"),
+		metric.WithUnit("{error}")); err != nil {
+		return nil, err
+	}
+	if m.retries, err = meter.Int64Counter("workbrew.client.retries",
+		metric.WithDescription("Number of request attempts retried by the retry policy"),
+		metric.WithUnit("{retry}")); err != nil {
+		return nil, err
+	}
+	if m.rateLimitWait, err = meter.Float64Histogram("workbrew.client.rate_limit.wait",
+		metric.WithDescription("Time requests spent waiting on the client-side rate limiter"),
+		metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// EnableMetrics records request, latency, error, retry and rate limit metrics per
+// SDK operation, without enabling tracing.
+//
+// Parameters:
+//   - provider: The meter provider that creates the instruments; the global meter provider when nil
+//
+// Returns:
+//   - error: If the instruments cannot be created
+func (t *Transport) EnableMetrics(provider metric.MeterProvider) error {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	metrics, err := newClientMetrics(provider)
+	if err != nil {
+		return fmt.Errorf("failed to create OpenTelemetry metrics: %w", err)
+	}
+	t.metrics = metrics
+
+	t.logger.Info("OpenTelemetry metrics enabled")
+	return nil
+}
+
+// operationAttributes returns the attributes identifying the operation behind ctx
+func operationAttributes(ctx context.Context, method string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attrMethod.String(method)}
+	if operation := interfaces.OperationFromContext(ctx); operation != "" {
+		attrs = append(attrs, attrOperation.String(operation))
+	}
+	return attrs
+}
+
+// recordRequest records the outcome of an operation after all retries
+func (m *clientMetrics) recordRequest(ctx context.Context, method string, start time.Time, resp *interfaces.Response, err error) {
+	if m == nil {
+		return
+	}
+
+	attrs := operationAttributes(ctx, method)
+	if resp != nil && resp.StatusCode != 0 {
+		attrs = append(attrs, attrStatusCode.Int(resp.StatusCode))
+	}
+	if err != nil {
+		attrs = append(attrs, attrErrorType.String(errorType(err)))
+	}
+	set := metric.WithAttributes(attrs...)
+
+	m.requests.Add(ctx, 1, set)
+	m.duration.Record(ctx, time.Since(start).Seconds(), set)
+	if err != nil {
+		m.errors.Add(ctx, 1, set)
+	}
+}
+
+// recordRetry records a retry scheduled by the retry policy
+func (m *clientMetrics) recordRetry(ctx context.Context, method string) {
+	if m == nil {
+		return
+	}
+	m.retries.Add(ctx, 1, metric.WithAttributes(operationAttributes(ctx, method)...))
+}
+
+// recordRateLimitWait records time spent blocked on the rate limiter
+func (m *clientMetrics) recordRateLimitWait(ctx context.Context, method string, wait time.Duration) {
+	if m == nil {
+		return
+	}
+	m.rateLimitWait.Record(ctx, wait.Seconds(), metric.WithAttributes(operationAttributes(ctx, method)...))
+}
+
+// errorType classifies an error for the error.type attribute: the status code
+// for API errors, "timeout" or "canceled" for context errors, "_OTHER" otherwise
+func errorType(err error) string {
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr):
+		return strconv.Itoa(apiErr.StatusCode)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	return "_OTHER"
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap/zaptest"
+)
+
+// collectMetrics returns the metrics gathered by reader keyed by instrument name
+func collectMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	metrics := make(map[string]metricdata.Metrics)
+	for _, scope := range rm.ScopeMetrics {
+		if scope.Scope.Name != instrumentationName {
+			continue
+		}
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+// sumFor returns the value of the counter data point carrying the given operation
+func sumFor(t *testing.T, m metricdata.Metrics, operation string) int64 {
+	t.Helper()
+
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok, "%s is not an int64 sum", m.Name)
+
+	var total int64
+	for _, point := range sum.DataPoints {
+		if value, ok := point.Attributes.Value(attrOperation); ok && value.AsString() == operation {
+			total += point.Value
+		}
+	}
+	return total
+}
+
+func TestMetrics_RecordedPerOperation(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/workspaces/test-workspace/brewfiles/missing.json":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"not found"}`))
+		case calls.Add(1) == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"message":"try again"}`))
+		default:
+			w.Write([]byte(`{"message":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	reader := sdkmetric.NewManualReader()
+	transport, err := NewTransport("test-api-key", "test-workspace",
+		WithLogger(zaptest.NewLogger(t)),
+		WithBaseURL(server.URL),
+		WithRetryPolicy(&DefaultRetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithRateLimit(50, 1),
+		WithTracing(&OTelConfig{MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))}),
+	)
+	require.NoError(t, err)
+
+	var result map[string]any
+	ctx := interfaces.WithOperation(context.Background(), "devices.ListDevices")
+	_, err = transport.Get(ctx, "/devices.json", nil, nil, &result)
+	require.NoError(t, err)
+
+	ctx = interfaces.WithOperation(context.Background(), "brewfiles.DeleteBrewfile")
+	_, err = transport.Delete(ctx, "/brewfiles/missing.json", nil, nil, &result)
+	require.Error(t, err)
+
+	metrics := collectMetrics(t, reader)
+
+	// One logical request per operation, however many attempts it took
+	assert.Equal(t, int64(1), sumFor(t, metrics["workbrew.client.requests"], "devices.ListDevices"))
+	assert.Equal(t, int64(1), sumFor(t, metrics["workbrew.client.requests"], "brewfiles.DeleteBrewfile"))
+
+	assert.Equal(t, int64(1), sumFor(t, metrics["workbrew.client.retries"], "devices.ListDevices"))
+	assert.Equal(t, int64(0), sumFor(t, metrics["workbrew.client.errors"], "devices.ListDevices"))
+	assert.Equal(t, int64(1), sumFor(t, metrics["workbrew.client.errors"], "brewfiles.DeleteBrewfile"))
+
+	errorsSum := metrics["workbrew.client.errors"].Data.(metricdata.Sum[int64])
+	require.Len(t, errorsSum.DataPoints, 1)
+	errorType, _ := errorsSum.DataPoints[0].Attributes.Value(attrErrorType)
+	assert.Equal(t, "404", errorType.AsString())
+
+	duration, ok := metrics["workbrew.client.request.duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Len(t, duration.DataPoints, 2)
+
+	// The retry is paced by the limiter (burst of 1), so at least one wait is recorded
+	wait, ok := metrics["workbrew.client.rate_limit.wait"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.NotEmpty(t, wait.DataPoints)
+	for _, point := range wait.DataPoints {
+		assert.Greater(t, point.Sum, 0.0)
+	}
+}
+
+func TestWithMetrics_WithoutTracing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	reader := sdkmetric.NewManualReader()
+	transport, err := NewTransport("test-api-key", "test-workspace",
+		WithLogger(zaptest.NewLogger(t)),
+		WithBaseURL(server.URL),
+		WithMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	require.NoError(t, err)
+
+	// No otelhttp instrumentation is installed
+	_, instrumented := transport.client.Client().Transport.(*otelhttp.Transport)
+	assert.False(t, instrumented)
+
+	var result map[string]any
+	ctx := interfaces.WithOperation(context.Background(), "devices.ListDevices")
+	_, err = transport.Get(ctx, "/devices.json", nil, nil, &result)
+	require.NoError(t, err)
+
+	metrics := collectMetrics(t, reader)
+	assert.Equal(t, int64(1), sumFor(t, metrics["workbrew.client.requests"], "devices.ListDevices"))
+}
+
+func TestEnableTracing_KeepsMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	transport, err := NewTransport("test-api-key", "test-workspace",
+		WithLogger(zaptest.NewLogger(t)),
+		WithMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	require.NoError(t, err)
+	metrics := transport.metrics
+
+	require.NoError(t, transport.EnableTracing(nil))
+	assert.Same(t, metrics, transport.metrics)
+}
+
+func TestErrorType(t *testing.T) {
+	assert.Equal(t, "429", errorType(&APIError{StatusCode: StatusTooManyRequests}))
+	assert.Equal(t, "timeout", errorType(context.DeadlineExceeded))
+	assert.Equal(t, "canceled", errorType(context.Canceled))
+	assert.Equal(t, "_OTHER", errorType(assert.AnError))
+}
+
+func TestOperationAttributes(t *testing.T) {
+	attrs := operationAttributes(context.Background(), "GET")
+	assert.Equal(t, []attribute.KeyValue{attrMethod.String("GET")}, attrs)
+
+	ctx := interfaces.WithOperation(context.Background(), "casks.ListCasks")
+	attrs = operationAttributes(ctx, "GET")
+	assert.Contains(t, attrs, attrOperation.String("casks.ListCasks"))
+}
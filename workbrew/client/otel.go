@@ -3,8 +3,10 @@ package client
 import (
 	"net/http"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
@@ -16,6 +18,11 @@ type OTelConfig struct {
 	// If nil, the global tracer provider will be used.
 	TracerProvider trace.TracerProvider
 
+	// MeterProvider is the OpenTelemetry meter provider used for request,
+	// latency, error, retry and rate limit metrics.
+	// If nil, the global meter provider will be used.
+	MeterProvider metric.MeterProvider
+
 	// Propagators is the propagator to use for context propagation.
 	// If nil, the global propagator will be used.
 	Propagators propagation.TextMapPropagator
@@ -24,8 +31,10 @@ type OTelConfig struct {
 	// Defaults to "workbrew-client"
 	ServiceName string
 
-	// SpanNameFormatter allows customizing span names.
-	// If nil, defaults to "HTTP {method}" format.
+	// SpanNameFormatter allows customizing span names. operation is the SDK
+	// operation that made the request (e.g. "brewfiles.UpdateBrewfile"), or ""
+	// for requests made directly through the transport.
+	// If nil, spans are named after the operation, falling back to "HTTP {method}".
 	SpanNameFormatter func(operation string, req *http.Request) string
 }
 
@@ -33,6 +42,7 @@ type OTelConfig struct {
 func DefaultOTelConfig() *OTelConfig {
 	return &OTelConfig{
 		TracerProvider: otel.GetTracerProvider(),
+		MeterProvider:  otel.GetMeterProvider(),
 		Propagators:    otel.GetTextMapPropagator(),
 		ServiceName:    "workbrew-client",
 	}
@@ -46,8 +56,12 @@ func DefaultOTelConfig() *OTelConfig {
 // - Request and response headers (configurable)
 // - Error details
 // - Request/response timing
+// - Per-operation metrics: request counts, latency, errors, retries and rate limit waits
 //
-// All spans follow OpenTelemetry semantic conventions for HTTP clients.
+// Spans are named after the SDK operation (e.g. "brewfiles.UpdateBrewfile") and
+// follow OpenTelemetry semantic conventions for HTTP clients. Metrics already
+// enabled with WithMetrics or EnableMetrics are kept; otherwise they are enabled
+// with config.MeterProvider.
 func (t *Transport) EnableTracing(config *OTelConfig) error {
 	if config == nil {
 		config = DefaultOTelConfig()
@@ -65,15 +79,23 @@ func (t *Transport) EnableTracing(config *OTelConfig) error {
 		transport = http.DefaultTransport
 	}
 
+	meterProvider := config.MeterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+
+	if t.metrics == nil {
+		if err := t.EnableMetrics(meterProvider); err != nil {
+			return err
+		}
+	}
+
 	// Configure otelhttp options
 	opts := []otelhttp.Option{
 		otelhttp.WithTracerProvider(config.TracerProvider),
+		otelhttp.WithMeterProvider(meterProvider),
 		otelhttp.WithPropagators(config.Propagators),
-	}
-
-	// Add custom span name formatter if provided
-	if config.SpanNameFormatter != nil {
-		opts = append(opts, otelhttp.WithSpanNameFormatter(config.SpanNameFormatter))
+		otelhttp.WithSpanNameFormatter(operationSpanNameFormatter(config.SpanNameFormatter)),
 	}
 
 	// Wrap transport with OpenTelemetry instrumentation
@@ -85,3 +107,18 @@ func (t *Transport) EnableTracing(config *OTelConfig) error {
 
 	return nil
 }
+
+// operationSpanNameFormatter passes the SDK operation recorded on the request
+// context to formatter, or names the span after it when formatter is nil
+func operationSpanNameFormatter(formatter func(operation string, req *http.Request) string) func(string, *http.Request) string {
+	return func(_ string, req *http.Request) string {
+		operation := interfaces.OperationFromContext(req.Context())
+		if formatter != nil {
+			return formatter(operation, req)
+		}
+		if operation != "" {
+			return operation
+		}
+		return "HTTP " + req.Method
+	}
+}
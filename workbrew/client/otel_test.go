@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
@@ -259,3 +260,50 @@ func TestEnableTracing_ErrorPropagation(t *testing.T) {
 	}
 	assert.True(t, foundStatusCode, "Expected http.status_code or http.response.status_code attribute for error response")
 }
+
+// TestEnableTracing_OperationSpanName tests that spans are named after the SDK operation
+func TestEnableTracing_OperationSpanName(t *testing.T) {
+	spanRecorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(spanRecorder),
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"message": "ok"}`))
+	}))
+	defer server.Close()
+
+	transport, err := NewTransport("test-api-key", "test-workspace",
+		WithBaseURL(server.URL),
+		WithTracing(&OTelConfig{TracerProvider: tracerProvider}),
+	)
+	require.NoError(t, err)
+
+	ctx := interfaces.WithOperation(context.Background(), "brewfiles.UpdateBrewfile")
+	var result map[string]any
+	_, err = transport.Put(ctx, "/brewfiles/dev.json", map[string]string{"content": "brew \"wget\""}, nil, &result)
+	require.NoError(t, err)
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "brewfiles.UpdateBrewfile", spans[0].Name())
+
+	// Custom formatters receive the operation name
+	var formatted string
+	transport, err = NewTransport("test-api-key", "test-workspace",
+		WithBaseURL(server.URL),
+		WithTracing(&OTelConfig{
+			TracerProvider: tracerProvider,
+			SpanNameFormatter: func(operation string, req *http.Request) string {
+				formatted = "workbrew " + operation
+				return formatted
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = transport.Get(interfaces.WithOperation(context.Background(), "devices.ListDevices"), "/devices.json", nil, nil, &result)
+	require.NoError(t, err)
+	assert.Equal(t, "workbrew devices.ListDevices", formatted)
+}
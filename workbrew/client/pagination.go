@@ -0,0 +1,196 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+)
+
+const (
+	// DefaultPageSize is the number of items requested per page when PageOptions.PageSize is zero
+	DefaultPageSize = 100
+
+	// DefaultPageParam is the query parameter carrying the page number
+	DefaultPageParam = "page"
+
+	// DefaultPageSizeParam is the query parameter carrying the page size
+	DefaultPageSizeParam = "limit"
+)
+
+// PageOptions configures Paginate. Passing options opts in to page-number pagination.
+type PageOptions struct {
+	// PageSize is the number of items requested per page. Defaults to DefaultPageSize.
+	// Only one page of items is held in memory at a time.
+	PageSize int
+
+	// PageParam names the page number query parameter. Defaults to DefaultPageParam.
+	PageParam string
+
+	// PageSizeParam names the page size query parameter. Defaults to DefaultPageSizeParam.
+	PageSizeParam string
+
+	// MaxPages stops iteration after this many pages. Zero fetches every page.
+	MaxPages int
+}
+
+// withDefaults returns a copy of the options with defaults applied
+func (o *PageOptions) withDefaults() PageOptions {
+	var options PageOptions
+	if o != nil {
+		options = *o
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = DefaultPageSize
+	}
+	if options.PageParam == "" {
+		options.PageParam = DefaultPageParam
+	}
+	if options.PageSizeParam == "" {
+		options.PageSizeParam = DefaultPageSizeParam
+	}
+	return options
+}
+
+// Paginate returns an iterator over every item of a JSON list endpoint.
+//
+// The Workbrew API documents no pagination parameters, so with nil options the list is
+// requested once, without page parameters, and the decoded items are yielded. Passing
+// PageOptions opts in to paging: the first page is requested with the page number and
+// page size query parameters, and the next page is found by incrementing the page number.
+// Iteration ends at a page shorter than the page size.
+//
+// With or without options, a response with a Link header is followed through its
+// rel="next" link, and iteration ends when there is none. Only the link's query parameters
+// are used; the request always goes to path, so credentials are never sent to another host.
+//
+// APIs that ignore the pagination parameters and return the full list are handled: a
+// first page longer than the page size ends iteration, and so does a page identical to
+// the previous one, which is not yielded again.
+//
+// Parameters:
+//   - ctx: Context for the requests
+//   - client: The HTTP client used for each page
+//   - path: API endpoint path
+//   - queryParams: Query parameters sent with every page; may be nil
+//   - headers: HTTP headers sent with every page; may be nil
+//   - options: Pagination options; nil makes one request for the whole list
+//
+// Returns:
+//   - iter.Seq2[T, error]: Yields each item with a nil error. A failed request or an
+//     undecodable page yields a zero item with the error, and ends iteration.
+//
+// Example:
+//
+//	for device, err := range client.Paginate[devices.Device](ctx, httpClient, "/devices.json", nil, nil, nil) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(device.SerialNumber)
+//	}
+func Paginate[T any](ctx context.Context, client interfaces.HTTPClient, path string, queryParams map[string]string, headers map[string]string, options *PageOptions) iter.Seq2[T, error] {
+	paged := options != nil
+	opts := options.withDefaults()
+
+	return func(yield func(T, error) bool) {
+		var zero T
+		params := maps.Clone(queryParams)
+		if params == nil {
+			params = make(map[string]string)
+		}
+		if paged {
+			params[opts.PageParam] = "1"
+			params[opts.PageSizeParam] = strconv.Itoa(opts.PageSize)
+		}
+
+		var previous []byte
+		for page := 1; ; page++ {
+			resp, body, err := client.GetBytes(ctx, path, params, headers)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if page > 1 && bytes.Equal(body, previous) {
+				return
+			}
+
+			var items []T
+			if err := json.Unmarshal(body, &items); err != nil {
+				yield(zero, fmt.Errorf("failed to decode page %d of %s: %w", page, path, err))
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if opts.MaxPages > 0 && page >= opts.MaxPages {
+				return
+			}
+
+			var header http.Header
+			if resp != nil {
+				header = resp.Headers
+			}
+			if links := header.Values("Link"); len(links) > 0 {
+				next, ok := nextLink(links)
+				if !ok {
+					return
+				}
+				params = maps.Clone(queryParams)
+				if params == nil {
+					params = make(map[string]string)
+				}
+				for key, values := range next.Query() {
+					if len(values) > 0 {
+						params[key] = values[0]
+					}
+				}
+			} else {
+				if !paged || len(items) != opts.PageSize {
+					return
+				}
+				params[opts.PageParam] = strconv.Itoa(page + 1)
+			}
+			previous = body
+		}
+	}
+}
+
+// nextLink returns the URL of the rel="next" link in Link header values (RFC 8288)
+func nextLink(values []string) (*url.URL, bool) {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			segments := strings.Split(link, ";")
+			target := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range segments[1:] {
+				name, rel, found := strings.Cut(strings.TrimSpace(param), "=")
+				if !found || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+				for _, relation := range strings.Fields(strings.Trim(rel, `"`)) {
+					if strings.EqualFold(relation, "next") {
+						u, err := url.Parse(strings.Trim(target, "<>"))
+						if err != nil {
+							return nil, false
+						}
+						return u, true
+					}
+				}
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,233 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type pageItem struct {
+	ID int `json:"id"`
+}
+
+// pageTestServer serves items 1 to total and records the query of every request
+type pageTestServer struct {
+	total int
+	mode  string // "pages", "links" or "full"
+
+	mu      sync.Mutex
+	queries []string
+}
+
+func (s *pageTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.queries = append(s.queries, r.URL.RawQuery)
+	s.mu.Unlock()
+
+	start, end := 0, s.total
+	switch s.mode {
+	case "pages":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		start, end = min((page-1)*limit, s.total), min(page*limit, s.total)
+	case "links":
+		start, _ = strconv.Atoi(r.URL.Query().Get("cursor"))
+		end = min(start+2, s.total)
+		links := `<https://elsewhere.example.com/devices.json?cursor=0>; rel="first"`
+		if end < s.total {
+			links += fmt.Sprintf(`, <https://elsewhere.example.com/devices.json?cursor=%d>; rel="next"`, end)
+		}
+		w.Header().Set("Link", links)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, "[")
+	for i := start; i < end; i++ {
+		if i > start {
+			fmt.Fprint(w, ",")
+		}
+		fmt.Fprintf(w, `{"id":%d}`, i+1)
+	}
+	fmt.Fprint(w, "]")
+}
+
+func newPageTestTransport(t *testing.T, server *pageTestServer) *Transport {
+	t.Helper()
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	transport, err := NewTransport("test-api-key", "test-workspace",
+		WithLogger(zaptest.NewLogger(t)),
+		WithBaseURL(httpServer.URL),
+	)
+	require.NoError(t, err)
+	return transport
+}
+
+// collect drains an iterator into item IDs
+func collect(t *testing.T, seq func(func(pageItem, error) bool)) []int {
+	t.Helper()
+	var ids []int
+	for item, err := range seq {
+		require.NoError(t, err)
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func TestPaginate_PageNumbers(t *testing.T) {
+	server := &pageTestServer{total: 5, mode: "pages"}
+	transport := newPageTestTransport(t, server)
+
+	seq := Paginate[pageItem](context.Background(), transport, "/devices.json", map[string]string{"filter": "all"}, nil, &PageOptions{PageSize: 2})
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collect(t, seq))
+	assert.Equal(t, []string{
+		"filter=all&limit=2&page=1",
+		"filter=all&limit=2&page=2",
+		"filter=all&limit=2&page=3",
+	}, server.queries)
+}
+
+func TestPaginate_ExactMultipleOfPageSize(t *testing.T) {
+	server := &pageTestServer{total: 4, mode: "pages"}
+	transport := newPageTestTransport(t, server)
+
+	seq := Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, &PageOptions{PageSize: 2})
+	assert.Equal(t, []int{1, 2, 3, 4}, collect(t, seq))
+	assert.Len(t, server.queries, 3, "the empty third page ends iteration")
+}
+
+func TestPaginate_LinkHeader(t *testing.T) {
+	server := &pageTestServer{total: 5, mode: "links"}
+	transport := newPageTestTransport(t, server)
+
+	seq := Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, &PageOptions{PageSize: 2})
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collect(t, seq))
+	// Only the link's query is used, so every request went to the test server
+	assert.Equal(t, []string{"limit=2&page=1", "cursor=2", "cursor=4"}, server.queries)
+}
+
+func TestPaginate_FullListFallback(t *testing.T) {
+	// The full list is longer than a page
+	server := &pageTestServer{total: 5, mode: "full"}
+	transport := newPageTestTransport(t, server)
+	seq := Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, &PageOptions{PageSize: 2})
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collect(t, seq))
+	assert.Len(t, server.queries, 1)
+
+	// The full list is exactly one page, so the second request returns it again
+	server = &pageTestServer{total: 2, mode: "full"}
+	transport = newPageTestTransport(t, server)
+	seq = Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, &PageOptions{PageSize: 2})
+	assert.Equal(t, []int{1, 2}, collect(t, seq))
+	assert.Len(t, server.queries, 2)
+}
+
+func TestPaginate_MaxPagesAndBreak(t *testing.T) {
+	server := &pageTestServer{total: 10, mode: "pages"}
+	transport := newPageTestTransport(t, server)
+
+	seq := Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, &PageOptions{PageSize: 2, MaxPages: 2})
+	assert.Equal(t, []int{1, 2, 3, 4}, collect(t, seq))
+	assert.Len(t, server.queries, 2)
+
+	server.queries = nil
+	for item, err := range Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, &PageOptions{PageSize: 2}) {
+		require.NoError(t, err)
+		if item.ID == 3 {
+			break
+		}
+	}
+	assert.Len(t, server.queries, 2, "no page is fetched after the loop breaks")
+}
+
+func TestPaginate_NilOptions(t *testing.T) {
+	// The full list is longer than DefaultPageSize, and is requested once without page parameters
+	server := &pageTestServer{total: DefaultPageSize + 50, mode: "full"}
+	transport := newPageTestTransport(t, server)
+
+	ids := collect(t, Paginate[pageItem](context.Background(), transport, "/devices.json", map[string]string{"filter": "all"}, nil, nil))
+	assert.Len(t, ids, DefaultPageSize+50)
+	assert.Equal(t, []string{"filter=all"}, server.queries)
+
+	// A short list is not mistaken for the first of several pages
+	server = &pageTestServer{total: 3, mode: "full"}
+	transport = newPageTestTransport(t, server)
+	assert.Equal(t, []int{1, 2, 3}, collect(t, Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, nil)))
+	assert.Equal(t, []string{""}, server.queries)
+
+	// Link headers are still followed
+	server = &pageTestServer{total: 5, mode: "links"}
+	transport = newPageTestTransport(t, server)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collect(t, Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, nil)))
+	assert.Equal(t, []string{"", "cursor=2", "cursor=4"}, server.queries)
+}
+
+func TestPaginate_Defaults(t *testing.T) {
+	server := &pageTestServer{total: 1, mode: "pages"}
+	transport := newPageTestTransport(t, server)
+
+	collect(t, Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, &PageOptions{}))
+	assert.Equal(t, []string{"limit=100&page=1"}, server.queries)
+
+	server.queries = nil
+	collect(t, Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, &PageOptions{PageParam: "p", PageSizeParam: "per_page"}))
+	assert.Equal(t, []string{"p=1&per_page=100"}, server.queries)
+}
+
+func TestPaginate_Errors(t *testing.T) {
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/workspaces/test-workspace/bad.json" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"not":"a list"}`)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"Forbidden","errors":["Please upgrade your plan to get access to this feature."]}`)
+	}))
+	t.Cleanup(httpServer.Close)
+	transport, err := NewTransport("test-api-key", "test-workspace",
+		WithLogger(zaptest.NewLogger(t)),
+		WithBaseURL(httpServer.URL),
+	)
+	require.NoError(t, err)
+
+	var errs []error
+	for _, err := range Paginate[pageItem](context.Background(), transport, "/devices.json", nil, nil, nil) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	var apiErr *APIError
+	assert.True(t, errors.As(errs[0], &apiErr))
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+	errs = nil
+	for _, err := range Paginate[pageItem](context.Background(), transport, "/bad.json", nil, nil, nil) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "failed to decode page 1 of /bad.json")
+}
+
+func TestNextLink(t *testing.T) {
+	next, ok := nextLink([]string{`<https://a.example.com/x?page=1>; rel="prev", <https://a.example.com/x?page=3>; rel="next last"`})
+	require.True(t, ok)
+	assert.Equal(t, "3", next.Query().Get("page"))
+
+	next, ok = nextLink([]string{`<https://a.example.com/x?page=1>; rel=first`, `</x?page=2>; REL=next`})
+	require.True(t, ok)
+	assert.Equal(t, "/x", next.Path)
+
+	_, ok = nextLink([]string{`<https://a.example.com/x?page=1>; rel="prev"`, `garbage`})
+	assert.False(t, ok)
+}
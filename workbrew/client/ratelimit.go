@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"resty.dev/v3"
+)
+
+// unixTimestampThreshold separates X-Api-Quota-Reset values expressed as a Unix
+// timestamp from values expressed as a number of seconds until the reset.
+const unixTimestampThreshold = 1_000_000_000
+
+// RateLimiter is a client-side token bucket that paces requests to the Workbrew API.
+// A single RateLimiter is safe for concurrent use and is shared by every request
+// (including retries) made through the Transport it is attached to. The same
+// RateLimiter may be passed to several clients with WithRateLimiter to share one budget.
+//
+// In addition to the steady-state rate, the limiter pauses all callers when the
+// API signals exhaustion: a Retry-After header, an X-Api-Quota-Remaining of 0,
+// or a 429 response with an X-Api-Quota-Reset time.
+type RateLimiter struct {
+	mu          sync.Mutex
+	rate        float64 // tokens added per second
+	burst       float64 // bucket capacity
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+
+	now func() time.Time
+}
+
+// NewRateLimiter creates a token bucket limiter.
+//
+// Parameters:
+//   - requestsPerSecond: Sustained request rate (must be greater than zero)
+//   - burst: Maximum number of requests that may be sent back-to-back (minimum 1)
+//
+// Returns:
+//   - *RateLimiter: A limiter with a full bucket
+//   - error: An error if requestsPerSecond is not positive
+//
+// Example:
+//
+//	limiter, err := client.NewRateLimiter(2, 5) // 2 req/s, bursts of 5
+func NewRateLimiter(requestsPerSecond float64, burst int) (*RateLimiter, error) {
+	if requestsPerSecond <= 0 {
+		return nil, fmt.Errorf("rate limit must be greater than zero, got %v", requestsPerSecond)
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}, nil
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+//
+// Parameters:
+//   - ctx: Context used to abandon the wait
+//
+// Returns:
+//   - time.Duration: How long the caller was delayed (zero if a token was immediately available)
+//   - error: ctx.Err() if the context ended before the request could proceed
+func (l *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	delay := l.reserve()
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		l.cancel()
+		return 0, ctx.Err()
+	}
+}
+
+// reserve takes a token, allowing the bucket to go negative, and returns how
+// long the caller must wait before the token becomes valid.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	start := now
+	if l.pausedUntil.After(start) {
+		start = l.pausedUntil
+	}
+
+	l.refill(start)
+	l.tokens--
+
+	delay := start.Sub(now)
+	if l.tokens < 0 {
+		delay += time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	return delay
+}
+
+// cancel returns a reserved token when the caller gave up waiting
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.tokens+1, l.burst)
+}
+
+// refill adds the tokens accrued up to at. Callers must hold l.mu.
+func (l *RateLimiter) refill(at time.Time) {
+	if l.last.IsZero() {
+		l.last = at
+		return
+	}
+	if elapsed := at.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.tokens+elapsed.Seconds()*l.rate, l.burst)
+		l.last = at
+	}
+}
+
+// Observe updates the limiter from a response's rate limit headers.
+// It pauses all callers until the time indicated by Retry-After, or until
+// X-Api-Quota-Reset when the quota is exhausted.
+//
+// Parameters:
+//   - statusCode: The HTTP status code of the response
+//   - headers: The response headers
+//
+// Returns:
+//   - time.Duration: The pause applied from now (zero if the response did not signal exhaustion)
+func (l *RateLimiter) Observe(statusCode int, headers http.Header) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	until, ok := pauseUntil(statusCode, headers, now)
+	if !ok || !until.After(now) {
+		return 0
+	}
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	// Drop any remaining burst so requests resume at the steady rate
+	l.refill(now)
+	l.tokens = min(l.tokens, 0)
+
+	return until.Sub(now)
+}
+
+// pauseUntil works out when requests may resume from rate limit headers
+func pauseUntil(statusCode int, headers http.Header, now time.Time) (time.Time, bool) {
+	if headers == nil {
+		return time.Time{}, false
+	}
+
+	if delay, ok := retryAfterDelay(headers, now); ok {
+		return now.Add(delay), true
+	}
+
+	exhausted := statusCode == StatusTooManyRequests || headers.Get(HeaderQuotaRemaining) == "0"
+	if !exhausted {
+		return time.Time{}, false
+	}
+
+	reset, err := strconv.ParseInt(headers.Get(HeaderQuotaReset), 10, 64)
+	if err != nil || reset < 0 {
+		return time.Time{}, false
+	}
+	if reset >= unixTimestampThreshold {
+		return time.Unix(reset, 0), true
+	}
+	return now.Add(time.Duration(reset) * time.Second), true
+}
+
+// retryAfterDelay parses a Retry-After header given in seconds or as an HTTP date.
+// Dates in the past produce a zero delay.
+func retryAfterDelay(headers http.Header, now time.Time) (time.Duration, bool) {
+	value := headers.Get(HeaderRetryAfter)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// enableRateLimiting registers resty middleware that waits on the limiter before
+// every attempt and feeds every response back into it
+func (t *Transport) enableRateLimiting() {
+	limiter := t.rateLimiter
+
+	t.client.AddRequestMiddleware(func(_ *resty.Client, req *resty.Request) error {
+		ctx := req.Context()
+		waited, err := limiter.Wait(ctx)
+		if err != nil {
+			return fmt.Errorf("rate limiter wait aborted: %w", err)
+		}
+		if waited > 0 {
+			t.metrics.recordRateLimitWait(ctx, req.Method, waited)
+			t.logger.Debug("Rate limiter delayed request",
+				zap.String("method", req.Method),
+				zap.String("url", req.URL),
+				zap.Int("attempt", req.Attempt),
+				zap.Duration("wait", waited))
+
+			trace.SpanFromContext(ctx).AddEvent("workbrew.rate_limit.wait",
+				trace.WithAttributes(
+					attribute.String("http.request.method", req.Method),
+					attribute.Int64("workbrew.rate_limit.wait_ms", waited.Milliseconds()),
+				))
+		}
+		return nil
+	})
+
+	t.client.AddResponseMiddleware(func(_ *resty.Client, resp *resty.Response) error {
+		if pause := limiter.Observe(resp.StatusCode(), resp.Header()); pause > 0 {
+			t.logger.Warn("Rate limit signalled by API, pausing requests",
+				zap.Int("status_code", resp.StatusCode()),
+				zap.String("retry_after", resp.Header().Get(HeaderRetryAfter)),
+				zap.String("quota_remaining", resp.Header().Get(HeaderQuotaRemaining)),
+				zap.String("quota_reset", resp.Header().Get(HeaderQuotaReset)),
+				zap.Duration("pause", pause))
+
+			trace.SpanFromContext(resp.Request.Context()).AddEvent("workbrew.rate_limit.pause",
+				trace.WithAttributes(
+					attribute.Int("http.response.status_code", resp.StatusCode()),
+					attribute.Int64("workbrew.rate_limit.pause_ms", pause.Milliseconds()),
+				))
+		}
+		return nil
+	})
+}
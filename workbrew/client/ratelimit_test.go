@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap/zaptest"
+)
+
+// newTestLimiter returns a limiter driven by a controllable clock
+func newTestLimiter(t *testing.T, rate float64, burst int) (*RateLimiter, *time.Time) {
+	t.Helper()
+	limiter, err := NewRateLimiter(rate, burst)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func TestNewRateLimiter_Validation(t *testing.T) {
+	_, err := NewRateLimiter(0, 1)
+	assert.Error(t, err)
+
+	_, err = NewRateLimiter(-1, 1)
+	assert.Error(t, err)
+
+	limiter, err := NewRateLimiter(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, limiter.burst)
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	limiter, now := newTestLimiter(t, 2, 2)
+
+	// Burst is available immediately
+	assert.Equal(t, time.Duration(0), limiter.reserve())
+	assert.Equal(t, time.Duration(0), limiter.reserve())
+
+	// Further requests queue behind each other at 2 req/s
+	assert.Equal(t, 500*time.Millisecond, limiter.reserve())
+	assert.Equal(t, time.Second, limiter.reserve())
+
+	// After the backlog drains the bucket refills up to burst
+	*now = now.Add(10 * time.Second)
+	assert.Equal(t, time.Duration(0), limiter.reserve())
+	assert.Equal(t, time.Duration(0), limiter.reserve())
+	assert.Equal(t, 500*time.Millisecond, limiter.reserve())
+}
+
+func TestRateLimiter_ObserveRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		want    time.Duration
+	}{
+		{
+			name:    "retry after seconds",
+			status:  http.StatusTooManyRequests,
+			headers: map[string]string{HeaderRetryAfter: "30"},
+			want:    30 * time.Second,
+		},
+		{
+			name:    "retry after http date",
+			status:  http.StatusServiceUnavailable,
+			headers: map[string]string{HeaderRetryAfter: "Wed, 01 Jan 2025 12:01:00 GMT"},
+			want:    time.Minute,
+		},
+		{
+			name:    "429 with reset as unix timestamp",
+			status:  http.StatusTooManyRequests,
+			headers: map[string]string{HeaderQuotaReset: "1735732815"},
+			want:    15 * time.Second,
+		},
+		{
+			name:    "quota exhausted with reset in seconds",
+			status:  http.StatusOK,
+			headers: map[string]string{HeaderQuotaRemaining: "0", HeaderQuotaReset: "5"},
+			want:    5 * time.Second,
+		},
+		{
+			name:    "quota remaining",
+			status:  http.StatusOK,
+			headers: map[string]string{HeaderQuotaRemaining: "10", HeaderQuotaReset: "5"},
+			want:    0,
+		},
+		{
+			name:    "retry after in the past",
+			status:  http.StatusTooManyRequests,
+			headers: map[string]string{HeaderRetryAfter: "Wed, 01 Jan 2025 11:00:00 GMT"},
+			want:    0,
+		},
+		{
+			name:    "no headers",
+			status:  http.StatusOK,
+			headers: nil,
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, _ := newTestLimiter(t, 10, 5)
+
+			headers := make(http.Header)
+			for k, v := range tt.headers {
+				headers.Set(k, v)
+			}
+
+			assert.Equal(t, tt.want, limiter.Observe(tt.status, headers))
+			assert.Equal(t, tt.want, limiter.reserve(), "next request should wait for the pause")
+		})
+	}
+}
+
+func TestRateLimiter_PauseOnlyExtends(t *testing.T) {
+	limiter, _ := newTestLimiter(t, 10, 1)
+
+	headers := http.Header{}
+	headers.Set(HeaderRetryAfter, "20")
+	limiter.Observe(http.StatusTooManyRequests, headers)
+
+	headers.Set(HeaderRetryAfter, "5")
+	limiter.Observe(http.StatusTooManyRequests, headers)
+
+	assert.Equal(t, 20*time.Second, limiter.reserve())
+}
+
+func TestRateLimiter_WaitHonoursContext(t *testing.T) {
+	limiter, err := NewRateLimiter(1, 1)
+	require.NoError(t, err)
+
+	_, err = limiter.Wait(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = limiter.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithRateLimit_SharedAcrossGoroutines(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	transport, err := NewTransport("test-api-key", "test-workspace",
+		WithLogger(zaptest.NewLogger(t)),
+		WithBaseURL(server.URL),
+		WithRateLimit(20, 1),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, transport.GetRateLimiter())
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			var result map[string]any
+			_, err := transport.Get(context.Background(), "/test", nil, nil, &result)
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	// 5 requests at 20 req/s with a burst of 1 take at least 4 intervals of 50ms
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+	assert.Equal(t, int32(5), requests.Load())
+}
+
+func TestWithRateLimit_PausesOnExhaustedQuota(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(HeaderQuotaRemaining, "0")
+		w.Header().Set(HeaderQuotaReset, strconv.Itoa(60))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	transport, err := NewTransport("test-api-key", "test-workspace",
+		WithLogger(zaptest.NewLogger(t)),
+		WithBaseURL(server.URL),
+		WithRateLimit(100, 10),
+	)
+	require.NoError(t, err)
+
+	ctx, span := tracer.Start(context.Background(), "caller")
+	var result map[string]any
+	_, err = transport.Get(ctx, "/test", nil, nil, &result)
+	require.NoError(t, err)
+
+	// The next request must wait for the quota reset and gives up with the context
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = transport.Get(waitCtx, "/test", nil, nil, &result)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	var names []string
+	for _, event := range spans[0].Events() {
+		names = append(names, event.Name)
+	}
+	assert.Contains(t, names, "workbrew.rate_limit.pause")
+}
+
+func TestWithRateLimiter_Nil(t *testing.T) {
+	_, err := NewTransport("test-api-key", "test-workspace", WithRateLimiter(nil))
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 	"go.uber.org/zap"
@@ -11,11 +12,33 @@ import (
 )
 
 // Get executes a GET request
+// When caching is enabled (WithCache) fresh responses are served from the cache
 func (t *Transport) Get(ctx context.Context, path string, queryParams map[string]string, headers map[string]string, result any) (*interfaces.Response, error) {
+	if t.cache == nil {
+		return t.get(ctx, path, queryParams, headers, result)
+	}
+
+	resp, body, fromCache, err := t.cachedGet(ctx, path, queryParams, headers, func(requestHeaders map[string]string) (*interfaces.Response, []byte, error) {
+		resp, err := t.get(ctx, path, queryParams, requestHeaders, result)
+		return resp, resp.Body, err
+	})
+	if err == nil && fromCache {
+		err = decodeCachedBody(body, result)
+	}
+	return resp, err
+}
+
+// get executes an uncached GET request
+func (t *Transport) get(ctx context.Context, path string, queryParams map[string]string, headers map[string]string, result any) (*interfaces.Response, error) {
 	req := t.client.R().
 		SetContext(ctx).
 		SetResult(result)
 
+	// Keep the raw body readable after unmarshaling so it can be cached
+	if t.cache != nil {
+		req.SetResponseBodyUnlimitedReads(true)
+	}
+
 	for k, v := range queryParams {
 		if v != "" {
 			req.SetQueryParam(k, v)
@@ -199,7 +222,34 @@ func (t *Transport) PostMultipart(ctx context.Context, path string, fileField st
 
 // GetBytes performs a GET request and returns raw bytes without unmarshaling
 // Use this for non-JSON responses like CSV, HTML, binary files, etc.
+// When caching is enabled (WithCache) fresh responses are served from the cache
 func (t *Transport) GetBytes(ctx context.Context, path string, queryParams map[string]string, headers map[string]string) (*interfaces.Response, []byte, error) {
+	if t.cache == nil {
+		return t.getBytes(ctx, path, queryParams, headers)
+	}
+
+	resp, body, _, err := t.cachedGet(ctx, path, queryParams, headers, func(requestHeaders map[string]string) (*interfaces.Response, []byte, error) {
+		return t.getBytes(ctx, path, queryParams, requestHeaders)
+	})
+	return resp, body, err
+}
+
+// getBytes performs an uncached GetBytes request with retries
+func (t *Transport) getBytes(ctx context.Context, path string, queryParams map[string]string, headers map[string]string) (*interfaces.Response, []byte, error) {
+	start := time.Now()
+	var body []byte
+	resp, err := t.withRetry(ctx, "GET", path, func() (*interfaces.Response, error) {
+		var resp *interfaces.Response
+		var err error
+		resp, body, err = t.getBytesOnce(ctx, path, queryParams, headers)
+		return resp, err
+	})
+	t.metrics.recordRequest(ctx, "GET", start, resp, err)
+	return resp, body, err
+}
+
+// getBytesOnce performs a single GetBytes attempt
+func (t *Transport) getBytesOnce(ctx context.Context, path string, queryParams map[string]string, headers map[string]string) (*interfaces.Response, []byte, error) {
 	var apiErr APIError
 	req := t.client.R().
 		SetContext(ctx).
@@ -246,9 +296,22 @@ func (t *Transport) GetBytes(ctx context.Context, path string, queryParams map[s
 	return ifaceResp, body, nil
 }
 
-// executeRequest is a centralized request executor that handles error processing
+// executeRequest is a centralized request executor that handles error processing and retries
 // Returns response metadata and error. Response is always non-nil for accessing headers.
 func (t *Transport) executeRequest(req *resty.Request, method, path string) (*interfaces.Response, error) {
+	start := time.Now()
+	resp, err := t.withRetry(req.Context(), method, path, func() (*interfaces.Response, error) {
+		return t.executeOnce(req, method, path)
+	})
+	t.metrics.recordRequest(req.Context(), method, start, resp, err)
+	if err == nil {
+		t.invalidateCache(method, path)
+	}
+	return resp, err
+}
+
+// executeOnce sends req a single time and converts the outcome into response metadata and error
+func (t *Transport) executeOnce(req *resty.Request, method, path string) (*interfaces.Response, error) {
 	t.logger.Debug("Executing API request",
 		zap.String("method", method),
 		zap.String("path", path))
@@ -123,10 +123,10 @@ func GetRateLimitHeaders(resp *interfaces.Response) (limit, remaining, reset, re
 	if resp == nil {
 		return
 	}
-	return resp.Headers.Get("X-Api-Quota-Limit"),
-		resp.Headers.Get("X-Api-Quota-Remaining"),
-		resp.Headers.Get("X-Api-Quota-Reset"),
-		resp.Headers.Get("Retry-After")
+	return resp.Headers.Get(HeaderQuotaLimit),
+		resp.Headers.Get(HeaderQuotaRemaining),
+		resp.Headers.Get(HeaderQuotaReset),
+		resp.Headers.Get(HeaderRetryAfter)
 }
 
 // validateResponse validates the HTTP response before processing.
@@ -0,0 +1,225 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"go.uber.org/zap"
+)
+
+// RetryAttempt describes a failed request attempt passed to a RetryPolicy.
+type RetryAttempt struct {
+	Method   string               // HTTP method of the request
+	Path     string               // API endpoint path
+	Attempt  int                  // 1-based number of the attempt that just failed
+	Response *interfaces.Response // Response metadata (status code is 0 for network errors)
+	Err      error                // The error returned by the attempt
+
+	// AllowNonIdempotent is true when the caller opted in to retrying this
+	// non-idempotent request with AllowNonIdempotentRetry
+	AllowNonIdempotent bool
+}
+
+// RetryPolicy decides whether a failed request is retried and how long to wait first.
+// Implementations must be safe for concurrent use.
+type RetryPolicy interface {
+	// NextDelay returns the wait before the next attempt and true to retry,
+	// or false to return the error to the caller.
+	NextDelay(attempt RetryAttempt) (time.Duration, bool)
+}
+
+// DefaultRetryPolicy retries transient, rate limited and server errors using
+// capped exponential backoff with full jitter.
+//
+// Requests are only retried when the method is idempotent (GET, HEAD, OPTIONS,
+// PUT, DELETE). POST and PATCH requests such as CreateBrewCommand are never
+// retried unless RetryNonIdempotent is set or the call's context was wrapped
+// with AllowNonIdempotentRetry.
+type DefaultRetryPolicy struct {
+	MaxRetries         int           // Maximum number of retries after the first attempt
+	BaseDelay          time.Duration // Backoff ceiling for the first retry
+	MaxDelay           time.Duration // Upper bound for any backoff ceiling and for Retry-After
+	RetryNonIdempotent bool          // Retry POST and PATCH requests as well
+
+	// random returns a value in [0, n); replaced in tests
+	random func(n int64) int64
+}
+
+// NewDefaultRetryPolicy returns the retry policy used when no WithRetryPolicy option is given.
+//
+// Returns:
+//   - *DefaultRetryPolicy: A policy with MaxRetries retries, RetryWaitTime base delay and RetryMaxWaitTime max delay
+func NewDefaultRetryPolicy() *DefaultRetryPolicy {
+	return &DefaultRetryPolicy{
+		MaxRetries: MaxRetries,
+		BaseDelay:  RetryWaitTime * time.Second,
+		MaxDelay:   RetryMaxWaitTime * time.Second,
+	}
+}
+
+// NextDelay implements RetryPolicy.
+func (p *DefaultRetryPolicy) NextDelay(attempt RetryAttempt) (time.Duration, bool) {
+	if attempt.Attempt > p.MaxRetries {
+		return 0, false
+	}
+	if !IsIdempotentMethod(attempt.Method) && !p.RetryNonIdempotent && !attempt.AllowNonIdempotent {
+		return 0, false
+	}
+	if !IsRetryableError(attempt.Err) {
+		return 0, false
+	}
+
+	if attempt.Response != nil && (IsRateLimited(attempt.Err) || IsTransient(attempt.Err)) {
+		if delay, ok := retryAfterDelay(attempt.Response.Headers, time.Now()); ok {
+			if p.MaxDelay > 0 && delay > p.MaxDelay {
+				delay = p.MaxDelay
+			}
+			return delay, true
+		}
+	}
+
+	return p.backoff(attempt.Attempt), true
+}
+
+// backoff returns a full jitter delay: a random duration between zero and
+// min(MaxDelay, BaseDelay * 2^(attempt-1))
+func (p *DefaultRetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay
+	for i := 1; i < attempt && ceiling < p.MaxDelay; i++ {
+		ceiling *= 2
+	}
+	if p.MaxDelay > 0 && ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+
+	random := p.random
+	if random == nil {
+		random = rand.Int64N
+	}
+	return time.Duration(random(int64(ceiling) + 1))
+}
+
+// IsIdempotentMethod reports whether repeating a request with this method is safe.
+//
+// Parameters:
+//   - method: The HTTP method
+//
+// Returns:
+//   - bool: True for GET, HEAD, OPTIONS, PUT and DELETE
+func IsIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// IsRetryableError reports whether an error may succeed if the request is repeated.
+// API errors are retryable when IsTransient, IsRateLimited or IsServerError is true.
+// Other errors are retryable only when they are network failures: a net.Error
+// (including timeouts before a response), an unexpected EOF, or a refused or reset
+// connection. Local errors, such as an unexpected response Content-Type, and the
+// caller's context being cancelled are never retryable.
+//
+// Parameters:
+//   - err: The error returned by a request
+//
+// Returns:
+//   - bool: True if the request should be retried
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return IsTransient(apiErr) || IsRateLimited(apiErr) || IsServerError(apiErr)
+	}
+	return isNetworkError(err)
+}
+
+// isNetworkError reports whether err is a connection or timeout failure
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// nonIdempotentRetryKey marks a context as allowing retries of POST/PATCH requests
+type nonIdempotentRetryKey struct{}
+
+// AllowNonIdempotentRetry returns a context that lets the retry policy retry
+// non-idempotent requests (POST, PATCH) made with it. Only use this when the
+// operation is safe to repeat, as a retried CreateBrewCommand may run twice.
+//
+// Example:
+//
+//	ctx := client.AllowNonIdempotentRetry(context.Background())
+//	result, resp, err := workbrewClient.BrewCommands.CreateBrewCommand(ctx, request)
+func AllowNonIdempotentRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonIdempotentRetryKey{}, true)
+}
+
+// nonIdempotentRetryAllowed reports whether ctx was created by AllowNonIdempotentRetry
+func nonIdempotentRetryAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(nonIdempotentRetryKey{}).(bool)
+	return allowed
+}
+
+// withRetry runs attempt until it succeeds or the retry policy gives up, and
+// records the number of attempts made on the returned response
+func (t *Transport) withRetry(ctx context.Context, method, path string, attempt func() (*interfaces.Response, error)) (*interfaces.Response, error) {
+	for n := 1; ; n++ {
+		resp, err := attempt()
+		if resp != nil {
+			resp.Attempts = n
+		}
+		if err == nil || t.retryPolicy == nil {
+			return resp, err
+		}
+
+		delay, retry := t.retryPolicy.NextDelay(RetryAttempt{
+			Method:             method,
+			Path:               path,
+			Attempt:            n,
+			Response:           resp,
+			Err:                err,
+			AllowNonIdempotent: nonIdempotentRetryAllowed(ctx),
+		})
+		if !retry {
+			return resp, err
+		}
+
+		t.metrics.recordRetry(ctx, method)
+		t.logger.Warn("Retrying request",
+			zap.String("method", method),
+			zap.String("path", path),
+			zap.Int("attempt", n),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "429", err: &APIError{StatusCode: StatusTooManyRequests}, want: true},
+		{name: "500", err: &APIError{StatusCode: StatusInternalServerError}, want: true},
+		{name: "503", err: &APIError{StatusCode: StatusServiceUnavailable}, want: true},
+		{name: "504", err: &APIError{StatusCode: StatusGatewayTimeout}, want: true},
+		{name: "400", err: &APIError{StatusCode: StatusBadRequest}, want: false},
+		{name: "404", err: &APIError{StatusCode: StatusNotFound}, want: false},
+		{name: "422", err: &APIError{StatusCode: StatusUnprocessableEntity}, want: false},
+		{name: "connection reset", err: &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, want: true},
+		{name: "wrapped reset", err: fmt.Errorf("request failed: %w", syscall.ECONNRESET), want: true},
+		{name: "unexpected EOF", err: fmt.Errorf("request failed: %w", io.ErrUnexpectedEOF), want: true},
+		{name: "timeout", err: os.ErrDeadlineExceeded, want: true},
+		{name: "wrapped API error", err: fmt.Errorf("list failed: %w", &APIError{StatusCode: StatusBadGateway}), want: true},
+		{name: "content type", err: errors.New(`unexpected response Content-Type from GET /devices.json: got "text/html", expected application/json`), want: false},
+		{name: "context cancelled", err: context.Canceled, want: false},
+		{name: "wrapped deadline", err: errors.Join(errors.New("request failed"), context.DeadlineExceeded), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryableError(tt.err))
+		})
+	}
+}
+
+func TestIsIdempotentMethod(t *testing.T) {
+	for _, method := range []string{"GET", "HEAD", "OPTIONS", "PUT", "DELETE"} {
+		assert.True(t, IsIdempotentMethod(method), method)
+	}
+	for _, method := range []string{"POST", "PATCH"} {
+		assert.False(t, IsIdempotentMethod(method), method)
+	}
+}
+
+func TestDefaultRetryPolicy_NextDelay(t *testing.T) {
+	serverError := &APIError{StatusCode: StatusInternalServerError}
+
+	tests := []struct {
+		name      string
+		policy    DefaultRetryPolicy
+		attempt   RetryAttempt
+		wantRetry bool
+	}{
+		{
+			name:      "GET server error retried",
+			policy:    DefaultRetryPolicy{MaxRetries: 3},
+			attempt:   RetryAttempt{Method: "GET", Attempt: 1, Err: serverError},
+			wantRetry: true,
+		},
+		{
+			name:      "retries exhausted",
+			policy:    DefaultRetryPolicy{MaxRetries: 3},
+			attempt:   RetryAttempt{Method: "GET", Attempt: 4, Err: serverError},
+			wantRetry: false,
+		},
+		{
+			name:      "POST never retried by default",
+			policy:    DefaultRetryPolicy{MaxRetries: 3},
+			attempt:   RetryAttempt{Method: "POST", Attempt: 1, Err: serverError},
+			wantRetry: false,
+		},
+		{
+			name:      "POST retried when policy allows",
+			policy:    DefaultRetryPolicy{MaxRetries: 3, RetryNonIdempotent: true},
+			attempt:   RetryAttempt{Method: "POST", Attempt: 1, Err: serverError},
+			wantRetry: true,
+		},
+		{
+			name:      "POST retried when context allows",
+			policy:    DefaultRetryPolicy{MaxRetries: 3},
+			attempt:   RetryAttempt{Method: "POST", Attempt: 1, Err: serverError, AllowNonIdempotent: true},
+			wantRetry: true,
+		},
+		{
+			name:      "client error not retried",
+			policy:    DefaultRetryPolicy{MaxRetries: 3},
+			attempt:   RetryAttempt{Method: "GET", Attempt: 1, Err: &APIError{StatusCode: StatusNotFound}},
+			wantRetry: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, retry := tt.policy.NextDelay(tt.attempt)
+			assert.Equal(t, tt.wantRetry, retry)
+		})
+	}
+}
+
+func TestDefaultRetryPolicy_FullJitterBackoff(t *testing.T) {
+	var ceilings []int64
+	policy := &DefaultRetryPolicy{
+		MaxRetries: 10,
+		BaseDelay:  time.Second,
+		MaxDelay:   5 * time.Second,
+		random: func(n int64) int64 {
+			ceilings = append(ceilings, n)
+			return n - 1
+		},
+	}
+
+	for attempt := 1; attempt <= 5; attempt++ {
+		delay, retry := policy.NextDelay(RetryAttempt{Method: "GET", Attempt: attempt, Err: &APIError{StatusCode: StatusBadGateway}})
+		require.True(t, retry)
+		assert.Equal(t, time.Duration(ceilings[attempt-1]-1), delay)
+	}
+
+	// Ceilings double from the base delay and are capped at the max delay (random is exclusive)
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, ceiling := range ceilings {
+		assert.Equal(t, int64(want[i])+1, ceiling)
+	}
+}
+
+func TestDefaultRetryPolicy_HonoursRetryAfter(t *testing.T) {
+	policy := NewDefaultRetryPolicy()
+
+	headers := http.Header{}
+	headers.Set(HeaderRetryAfter, "7")
+	delay, retry := policy.NextDelay(RetryAttempt{
+		Method:   "GET",
+		Attempt:  1,
+		Response: &interfaces.Response{StatusCode: StatusTooManyRequests, Headers: headers},
+		Err:      &APIError{StatusCode: StatusTooManyRequests},
+	})
+
+	require.True(t, retry)
+	assert.Equal(t, 7*time.Second, delay)
+}
+
+func TestDefaultRetryPolicy_CapsRetryAfter(t *testing.T) {
+	policy := &DefaultRetryPolicy{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	headers := http.Header{}
+	headers.Set(HeaderRetryAfter, "86400")
+	delay, retry := policy.NextDelay(RetryAttempt{
+		Method:   "GET",
+		Attempt:  1,
+		Response: &interfaces.Response{StatusCode: StatusTooManyRequests, Headers: headers},
+		Err:      &APIError{StatusCode: StatusTooManyRequests},
+	})
+
+	require.True(t, retry)
+	assert.Equal(t, 10*time.Second, delay)
+}
+
+// newRetryTestTransport returns a transport pointed at a server that fails the
+// first failures requests with status and then succeeds
+func newRetryTestTransport(t *testing.T, failures int32, status int, options ...ClientOption) (*Transport, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) <= failures {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"message":"try again"}`))
+			return
+		}
+		w.Write([]byte(`{"message":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	options = append([]ClientOption{
+		WithLogger(zaptest.NewLogger(t)),
+		WithBaseURL(server.URL),
+		WithRetryPolicy(&DefaultRetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}),
+	}, options...)
+
+	transport, err := NewTransport("test-api-key", "test-workspace", options...)
+	require.NoError(t, err)
+	return transport, &calls
+}
+
+func TestTransport_RetriesIdempotentRequests(t *testing.T) {
+	transport, calls := newRetryTestTransport(t, 2, http.StatusServiceUnavailable)
+
+	var result map[string]any
+	resp, err := transport.Get(context.Background(), "/test", nil, nil, &result)
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Attempts)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, "ok", result["message"])
+}
+
+func TestTransport_RetriesGetBytes(t *testing.T) {
+	transport, calls := newRetryTestTransport(t, 1, http.StatusBadGateway)
+
+	resp, body, err := transport.GetBytes(context.Background(), "/test.csv", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Attempts)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.JSONEq(t, `{"message":"ok"}`, string(body))
+}
+
+func TestTransport_DoesNotRetryPost(t *testing.T) {
+	transport, calls := newRetryTestTransport(t, 1, http.StatusServiceUnavailable)
+
+	var result map[string]any
+	resp, err := transport.Post(context.Background(), "/brew_commands.json", map[string]string{"arguments": "update"}, nil, &result)
+	require.Error(t, err)
+	assert.True(t, IsTransient(err))
+	assert.Equal(t, 1, resp.Attempts)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestTransport_RetriesPostWhenAllowed(t *testing.T) {
+	transport, calls := newRetryTestTransport(t, 1, http.StatusServiceUnavailable)
+
+	var result map[string]any
+	ctx := AllowNonIdempotentRetry(context.Background())
+	resp, err := transport.Post(ctx, "/brew_commands.json", map[string]string{"arguments": "update"}, nil, &result)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Attempts)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestTransport_DoesNotRetryClientErrors(t *testing.T) {
+	transport, calls := newRetryTestTransport(t, 1, http.StatusUnprocessableEntity)
+
+	var result map[string]any
+	resp, err := transport.Get(context.Background(), "/test", nil, nil, &result)
+	require.Error(t, err)
+	assert.Equal(t, 1, resp.Attempts)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestTransport_RetryPolicyDisabled(t *testing.T) {
+	transport, calls := newRetryTestTransport(t, 1, http.StatusServiceUnavailable, WithRetryPolicy(nil))
+
+	var result map[string]any
+	_, err := transport.Get(context.Background(), "/test", nil, nil, &result)
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+
+	_, err = NewTransport("test-api-key", "test-workspace", WithRetryPolicy(nil), WithRetryCount(2))
+	assert.Error(t, err, "retry options require the default policy")
+}
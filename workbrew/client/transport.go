@@ -20,6 +20,10 @@ type Transport struct {
 	BaseURL       string
 	globalHeaders map[string]string
 	userAgent     string
+	rateLimiter   *RateLimiter
+	retryPolicy   RetryPolicy
+	cache         *responseCache
+	metrics       *clientMetrics
 }
 
 // NewTransport creates a new Workbrew API transport with the provided API key and workspace.
@@ -36,7 +40,8 @@ type Transport struct {
 //
 // The transport is configured with:
 //   - Default timeout of 120 seconds
-//   - Automatic retry on transient failures (up to 3 retries)
+//   - Automatic retry of idempotent requests on transient failures (up to 3 retries, see WithRetryPolicy)
+//   - No client-side rate limiting (use WithRateLimit to enable)
 //   - Gzip compression support
 //   - Bearer token authentication
 //   - Production-ready logger (use WithLogger to customize)
@@ -59,9 +64,8 @@ func NewTransport(apiKey string, workspaceName string, options ...ClientOption)
 
 	restyClient := resty.New()
 	restyClient.SetTimeout(DefaultTimeout * time.Second)
-	restyClient.SetRetryCount(MaxRetries)
-	restyClient.SetRetryWaitTime(RetryWaitTime * time.Second)
-	restyClient.SetRetryMaxWaitTime(RetryMaxWaitTime * time.Second)
+	// Retries are handled by the transport's RetryPolicy rather than resty
+	restyClient.SetRetryCount(0)
 	restyClient.SetHeader("User-Agent", userAgent)
 	restyClient.SetHeader("Accept-Encoding", "gzip")
 
@@ -72,6 +76,7 @@ func NewTransport(apiKey string, workspaceName string, options ...ClientOption)
 		BaseURL:       DefaultBaseURL,
 		globalHeaders: make(map[string]string),
 		userAgent:     userAgent,
+		retryPolicy:   NewDefaultRetryPolicy(),
 	}
 
 	// Apply any additional options
@@ -81,6 +86,10 @@ func NewTransport(apiKey string, workspaceName string, options ...ClientOption)
 		}
 	}
 
+	if transport.rateLimiter != nil {
+		transport.enableRateLimiting()
+	}
+
 	if err := SetupAuthentication(restyClient, authConfig, logger); err != nil {
 		return nil, fmt.Errorf("failed to setup authentication: %w", err)
 	}
@@ -104,6 +113,14 @@ func (t *Transport) GetHTTPClient() *resty.Client {
 	return t.client
 }
 
+// GetRateLimiter returns the client-side rate limiter, or nil if rate limiting is disabled.
+//
+// Returns:
+//   - *RateLimiter: The limiter shared by all requests made through this transport
+func (t *Transport) GetRateLimiter() *RateLimiter {
+	return t.rateLimiter
+}
+
 // GetLogger returns the configured zap logger instance.
 // Use this to add custom logging within your application using the same logger.
 //
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"maps"
 	"net/http"
+	"path"
 	"time"
 
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
@@ -56,27 +58,135 @@ func WithTimeout(timeout time.Duration) ClientOption {
 // WithRetryCount sets the number of retries for failed requests
 func WithRetryCount(count int) ClientOption {
 	return func(t *Transport) error {
-		t.client.SetRetryCount(count)
+		policy, err := t.defaultRetryPolicy()
+		if err != nil {
+			return err
+		}
+		policy.MaxRetries = count
 		t.logger.Info("Retry count configured", zap.Int("retry_count", count))
 		return nil
 	}
 }
 
-// WithRetryWaitTime sets the default wait time between retry attempts
-// This is the initial/minimum wait time before the first retry
+// WithRateLimit enables a client-side token bucket rate limiter.
+// Requests (including retries) from all goroutines using the client share the budget,
+// and the limiter pauses when the API returns Retry-After or an exhausted quota.
+//
+// Example:
+//
+//	client.WithRateLimit(2, 5) // 2 requests per second, bursts of up to 5
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(t *Transport) error {
+		limiter, err := NewRateLimiter(requestsPerSecond, burst)
+		if err != nil {
+			return err
+		}
+		t.rateLimiter = limiter
+		t.logger.Info("Rate limit configured",
+			zap.Float64("requests_per_second", requestsPerSecond),
+			zap.Int("burst", burst))
+		return nil
+	}
+}
+
+// WithRateLimiter attaches an existing rate limiter to the client.
+// Pass the same limiter to several clients to share one request budget between them.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(t *Transport) error {
+		if limiter == nil {
+			return fmt.Errorf("rate limiter cannot be nil")
+		}
+		t.rateLimiter = limiter
+		t.logger.Info("Shared rate limiter configured")
+		return nil
+	}
+}
+
+// WithCache enables caching of GET responses.
+// Fresh responses are served without contacting the API; stale responses with an
+// ETag or Last-Modified header are revalidated with a conditional request.
+// Successful POST, PUT, PATCH and DELETE requests invalidate cached reads of the
+// same top-level resource. Pass nil to use an in-memory LRU with DefaultCacheTTL.
+//
+// Example:
+//
+//	client.WithCache(&client.CacheConfig{
+//	    Store:      client.NewLRUCache(512),
+//	    DefaultTTL: 30 * time.Second,
+//	    TTLs: map[string]time.Duration{
+//	        "/vulnerabilities.json": 5 * time.Minute,
+//	        "/events.json":          0, // never cache
+//	    },
+//	})
+func WithCache(config *CacheConfig) ClientOption {
+	return func(t *Transport) error {
+		if config == nil {
+			config = &CacheConfig{}
+		}
+		for pattern := range config.TTLs {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid cache TTL pattern %q: %w", pattern, err)
+			}
+		}
+		t.cache = newResponseCache(config)
+		t.logger.Info("Response cache configured",
+			zap.Duration("default_ttl", t.cache.config.DefaultTTL),
+			zap.Int("endpoint_ttls", len(config.TTLs)))
+		return nil
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy.
+// Pass nil to disable retries entirely.
+//
+// Example:
+//
+//	client.WithRetryPolicy(&client.DefaultRetryPolicy{
+//	    MaxRetries: 5,
+//	    BaseDelay:  500 * time.Millisecond,
+//	    MaxDelay:   30 * time.Second,
+//	})
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(t *Transport) error {
+		t.retryPolicy = policy
+		t.logger.Info("Retry policy configured", zap.Bool("enabled", policy != nil))
+		return nil
+	}
+}
+
+// defaultRetryPolicy returns the transport's policy for the WithRetry* options,
+// which only apply to DefaultRetryPolicy
+func (t *Transport) defaultRetryPolicy() (*DefaultRetryPolicy, error) {
+	policy, ok := t.retryPolicy.(*DefaultRetryPolicy)
+	if !ok {
+		return nil, fmt.Errorf("retry options require the default retry policy, got %T", t.retryPolicy)
+	}
+	return policy, nil
+}
+
+// WithRetryWaitTime sets the backoff ceiling for the first retry attempt
+// Each retry waits a random (full jitter) duration up to a ceiling that doubles per attempt
 func WithRetryWaitTime(waitTime time.Duration) ClientOption {
 	return func(t *Transport) error {
-		t.client.SetRetryWaitTime(waitTime)
+		policy, err := t.defaultRetryPolicy()
+		if err != nil {
+			return err
+		}
+		policy.BaseDelay = waitTime
 		t.logger.Info("Retry wait time configured", zap.Duration("wait_time", waitTime))
 		return nil
 	}
 }
 
 // WithRetryMaxWaitTime sets the maximum wait time between retry attempts
-// The wait time increases exponentially with each retry up to this maximum
+// The backoff ceiling increases exponentially with each retry up to this maximum
 func WithRetryMaxWaitTime(maxWaitTime time.Duration) ClientOption {
 	return func(t *Transport) error {
-		t.client.SetRetryMaxWaitTime(maxWaitTime)
+		policy, err := t.defaultRetryPolicy()
+		if err != nil {
+			return err
+		}
+		policy.MaxDelay = maxWaitTime
 		t.logger.Info("Retry max wait time configured", zap.Duration("max_wait_time", maxWaitTime))
 		return nil
 	}
@@ -254,7 +364,7 @@ func WithMinTLSVersion(minVersion uint16) ClientOption {
 	}
 }
 
-// WithTracing enables OpenTelemetry tracing for all HTTP requests.
+// WithTracing enables OpenTelemetry tracing and metrics for all HTTP requests.
 // This wraps the HTTP client transport with automatic instrumentation.
 //
 // Example usage:
@@ -267,6 +377,7 @@ func WithMinTLSVersion(minVersion uint16) ClientOption {
 //
 //	otelConfig := &client.OTelConfig{
 //	    TracerProvider: myTracerProvider,
+//	    MeterProvider:  myMeterProvider,
 //	    ServiceName:    "my-workbrew-client",
 //	}
 //	client, err := client.NewClient(apiKey, workspaceName,
@@ -278,8 +389,23 @@ func WithMinTLSVersion(minVersion uint16) ClientOption {
 // - Request/response timing
 // - Error details
 // - All OpenTelemetry semantic conventions for HTTP
+// - Request, latency, error, retry and rate limit metrics per SDK operation
 func WithTracing(config *OTelConfig) ClientOption {
 	return func(t *Transport) error {
 		return t.EnableTracing(config)
 	}
 }
+
+// WithMetrics records request, latency, error, retry and rate limit metrics per
+// SDK operation, without enabling tracing.
+//
+// Example usage:
+//
+//	client, err := client.NewClient(apiKey, workspaceName,
+//	    client.WithMetrics(myMeterProvider), // nil uses the global meter provider
+//	)
+func WithMetrics(provider metric.MeterProvider) ClientOption {
+	return func(t *Transport) error {
+		return t.EnableMetrics(provider)
+	}
+}
@@ -0,0 +1,354 @@
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+)
+
+// Outcome is the result of one rule on one device
+type Outcome string
+
+// Rule outcomes
+const (
+	OutcomePass Outcome = "pass"
+	OutcomeFail Outcome = "fail"
+
+	// OutcomeNotApplicable marks rules scoped away from the device
+	OutcomeNotApplicable Outcome = "not_applicable"
+)
+
+// RuleResult is the outcome of one rule on one device
+type RuleResult struct {
+	Rule    string  `json:"rule"`
+	Outcome Outcome `json:"outcome"`
+
+	// Reason explains a failure
+	Reason string `json:"reason,omitempty"`
+}
+
+// DeviceReport is the compliance of one device
+type DeviceReport struct {
+	SerialNumber string       `json:"serial_number"`
+	Name         string       `json:"name,omitempty"`
+	DeviceType   string       `json:"device_type"`
+	Groups       []string     `json:"groups"`
+	Compliant    bool         `json:"compliant"`
+	Results      []RuleResult `json:"results"`
+}
+
+// Failures returns the results of the rules the device fails
+func (d *DeviceReport) Failures() []RuleResult {
+	var failures []RuleResult
+	for _, result := range d.Results {
+		if result.Outcome == OutcomeFail {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// GroupReport aggregates the compliance of a device group's members
+type GroupReport struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Devices      int    `json:"devices"`
+	Compliant    int    `json:"compliant"`
+	NonCompliant int    `json:"non_compliant"`
+
+	// NonCompliantDevices are the serial numbers of failing members
+	NonCompliantDevices []string `json:"non_compliant_devices"`
+
+	// RuleFailures counts failing members per rule
+	RuleFailures map[string]int `json:"rule_failures"`
+}
+
+// Summary counts devices by compliance
+type Summary struct {
+	Devices      int `json:"devices"`
+	Compliant    int `json:"compliant"`
+	NonCompliant int `json:"non_compliant"`
+}
+
+// RuleInfo describes an evaluated rule
+type RuleInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Report is the compliance of every device in a workspace
+type Report struct {
+	Workspace   string     `json:"workspace,omitempty"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Rules       []RuleInfo `json:"rules"`
+	Summary     Summary    `json:"summary"`
+
+	// Devices are sorted by serial number
+	Devices []DeviceReport `json:"devices"`
+
+	// Groups are sorted by name
+	Groups []GroupReport `json:"groups"`
+}
+
+// Source holds the services a report is collected from.
+// Vulnerabilities is optional: without it, vulnerability rules see no vulnerabilities.
+type Source struct {
+	Workspace       string
+	Devices         devices.DevicesServiceInterface
+	DeviceGroups    devicegroups.DeviceGroupsServiceInterface
+	Vulnerabilities vulnerabilities.VulnerabilitiesServiceInterface
+}
+
+// NewSource creates a report source backed by a Workbrew client
+//
+// Parameters:
+//   - client: The Workbrew client to collect from
+//   - workspace: The workspace name recorded in the report
+func NewSource(client *workbrew.Client, workspace string) *Source {
+	return &Source{
+		Workspace:       workspace,
+		Devices:         client.Devices,
+		DeviceGroups:    client.DeviceGroups,
+		Vulnerabilities: client.Vulnerabilities,
+	}
+}
+
+// Evaluate lists devices, device groups and vulnerabilities and checks every device against the rules
+//
+// Parameters:
+//   - ctx: Context for the underlying API calls
+//   - src: The services to collect from
+//   - rules: The rules to apply
+//
+// Returns:
+//   - *Report: Per-device and per-group compliance
+//   - error: Any error returned by the API
+//
+// Example:
+//
+//	rules := []compliance.Rule{
+//	    compliance.MinHomebrewVersion("4.4.0"),
+//	    compliance.SeenWithin(7 * 24 * time.Hour),
+//	    compliance.MaxVulnerabilitySeverity(vulnerabilities.SeverityHigh),
+//	    compliance.MinGroups(1),
+//	}
+//	report, err := compliance.Evaluate(ctx, compliance.NewSource(client, "my-workspace"), rules)
+func Evaluate(ctx context.Context, src *Source, rules []Rule) (*Report, error) {
+	if src == nil || src.Devices == nil || src.DeviceGroups == nil {
+		return nil, fmt.Errorf("compliance source with devices and device groups services is required")
+	}
+
+	deviceList, _, err := src.Devices.ListDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	groupList, _, err := src.DeviceGroups.ListDeviceGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device groups: %w", err)
+	}
+
+	var vulnList vulnerabilities.VulnerabilitiesResponse
+	if src.Vulnerabilities != nil {
+		response, _, err := src.Vulnerabilities.ListVulnerabilities(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list vulnerabilities: %w", err)
+		}
+		vulnList = *response
+	}
+
+	return Build(*deviceList, *groupList, vulnList, rules, &Options{
+		Workspace: src.Workspace,
+		Now:       time.Now().UTC(),
+	})
+}
+
+// Options configures Build
+type Options struct {
+	// Workspace is recorded in the report
+	Workspace string
+
+	// Now is the evaluation time. Defaults to the current time.
+	Now time.Time
+}
+
+// Build checks devices from responses already fetched, e.g. from a snapshot
+//
+// Parameters:
+//   - deviceList: The devices to check
+//   - groups: Device groups, for group facts and per-group aggregation
+//   - vulns: Vulnerabilities, attributed to their outdated devices; may be nil
+//   - rules: The rules to apply
+//   - options: Build options; may be nil
+//
+// Returns:
+//   - *Report: Per-device and per-group compliance
+//   - error: If a rule has no name or check, or two rules share a name
+func Build(deviceList devices.DevicesResponse, groups devicegroups.DeviceGroupsResponse, vulns vulnerabilities.VulnerabilitiesResponse, rules []Rule, options *Options) (*Report, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	now := options.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	report := &Report{
+		Workspace:   options.Workspace,
+		GeneratedAt: now,
+		Rules:       []RuleInfo{},
+		Devices:     []DeviceReport{},
+		Groups:      []GroupReport{},
+	}
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Name == "" || rule.Check == nil {
+			return nil, fmt.Errorf("compliance rules must have a name and a check")
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("duplicate rule name %q", rule.Name)
+		}
+		seen[rule.Name] = true
+		report.Rules = append(report.Rules, RuleInfo{Name: rule.Name, Description: rule.Description})
+	}
+
+	deviceGroups := make(map[string][]string)
+	for _, group := range groups {
+		for _, serial := range group.Devices {
+			deviceGroups[serial] = append(deviceGroups[serial], group.Name)
+		}
+	}
+
+	deviceVulnerabilities := make(map[string][]Vulnerability)
+	for _, vuln := range vulns {
+		for _, serial := range vuln.OutdatedDevices {
+			for _, detail := range vuln.Vulnerabilities {
+				deviceVulnerabilities[serial] = append(deviceVulnerabilities[serial], Vulnerability{
+					Formula:   vuln.Formula,
+					ID:        detail.CleanID,
+					CVSSScore: detail.CVSSScore,
+				})
+			}
+		}
+	}
+
+	for _, device := range deviceList {
+		facts := &Facts{
+			Device:          device,
+			Groups:          deviceGroups[device.SerialNumber],
+			Vulnerabilities: deviceVulnerabilities[device.SerialNumber],
+			Now:             now,
+		}
+		slices.Sort(facts.Groups)
+
+		deviceReport := DeviceReport{
+			SerialNumber: device.SerialNumber,
+			DeviceType:   device.DeviceType,
+			Groups:       append([]string{}, facts.Groups...),
+			Compliant:    true,
+			Results:      []RuleResult{},
+		}
+		if device.MDMUserOrDeviceName != nil {
+			deviceReport.Name = *device.MDMUserOrDeviceName
+		}
+
+		for _, rule := range rules {
+			result := RuleResult{Rule: rule.Name, Outcome: OutcomePass}
+			if rule.AppliesTo != nil && !rule.AppliesTo(facts) {
+				result.Outcome = OutcomeNotApplicable
+			} else if err := rule.Check(facts); err != nil {
+				result.Outcome = OutcomeFail
+				result.Reason = err.Error()
+				deviceReport.Compliant = false
+			}
+			deviceReport.Results = append(deviceReport.Results, result)
+		}
+
+		if deviceReport.Compliant {
+			report.Summary.Compliant++
+		} else {
+			report.Summary.NonCompliant++
+		}
+		report.Devices = append(report.Devices, deviceReport)
+	}
+	report.Summary.Devices = len(report.Devices)
+	slices.SortFunc(report.Devices, func(a, b DeviceReport) int {
+		return strings.Compare(a.SerialNumber, b.SerialNumber)
+	})
+	bySerial := make(map[string]*DeviceReport, len(report.Devices))
+	for i := range report.Devices {
+		bySerial[report.Devices[i].SerialNumber] = &report.Devices[i]
+	}
+
+	for _, group := range groups {
+		groupReport := GroupReport{
+			ID:                  group.ID,
+			Name:                group.Name,
+			NonCompliantDevices: []string{},
+			RuleFailures:        map[string]int{},
+		}
+		for _, serial := range group.Devices {
+			deviceReport, ok := bySerial[serial]
+			if !ok {
+				continue
+			}
+			groupReport.Devices++
+			if deviceReport.Compliant {
+				groupReport.Compliant++
+				continue
+			}
+			groupReport.NonCompliant++
+			groupReport.NonCompliantDevices = append(groupReport.NonCompliantDevices, serial)
+			for _, failure := range deviceReport.Failures() {
+				groupReport.RuleFailures[failure.Rule]++
+			}
+		}
+		slices.Sort(groupReport.NonCompliantDevices)
+		report.Groups = append(report.Groups, groupReport)
+	}
+	slices.SortFunc(report.Groups, func(a, b GroupReport) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return report, nil
+}
+
+// Device returns the report for the device with the given serial number
+func (r *Report) Device(serial string) (*DeviceReport, bool) {
+	for i := range r.Devices {
+		if r.Devices[i].SerialNumber == serial {
+			return &r.Devices[i], true
+		}
+	}
+	return nil, false
+}
+
+// NonCompliant returns the reports of devices that fail at least one rule
+func (r *Report) NonCompliant() []DeviceReport {
+	var failing []DeviceReport
+	for _, device := range r.Devices {
+		if !device.Compliant {
+			failing = append(failing, device)
+		}
+	}
+	return failing
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
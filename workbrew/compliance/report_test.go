@@ -0,0 +1,151 @@
+package compliance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRules pass on the default fake workspace except for the Linux device,
+// which is in a single group, and the MacBook, which has a critical vulnerability
+func testRules() []Rule {
+	return []Rule{
+		MinHomebrewVersion("4.4.0"),
+		MaxVulnerabilitySeverity(vulnerabilities.SeverityHigh),
+		MinGroups(2),
+		MinOSVersion("15.0").ForDeviceTypes("Mac*"),
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	report, err := Evaluate(context.Background(), NewSource(wb, workbrewtest.DefaultWorkspace), testRules())
+	require.NoError(t, err)
+
+	assert.Equal(t, workbrewtest.DefaultWorkspace, report.Workspace)
+	assert.Len(t, report.Rules, 4)
+	assert.Equal(t, Summary{Devices: 2, Compliant: 0, NonCompliant: 2}, report.Summary)
+
+	linux, ok := report.Device(workbrewtest.FixtureDeviceLinux)
+	require.True(t, ok)
+	assert.Equal(t, []string{"All Devices"}, linux.Groups)
+	assert.False(t, linux.Compliant)
+	assert.Equal(t, []RuleResult{
+		{Rule: "min-homebrew-version", Outcome: OutcomePass},
+		{Rule: "max-vulnerability-severity", Outcome: OutcomePass},
+		{Rule: "min-groups", Outcome: OutcomeFail, Reason: "member of 1 device group(s), fewer than 2"},
+		{Rule: "min-os-version", Outcome: OutcomeNotApplicable},
+	}, linux.Results)
+
+	macbook, ok := report.Device(workbrewtest.FixtureDeviceMacBook)
+	require.True(t, ok)
+	assert.Equal(t, "Mike's MacBook Pro", macbook.Name)
+	failures := macbook.Failures()
+	require.Len(t, failures, 1)
+	assert.Equal(t, "max-vulnerability-severity", failures[0].Rule)
+	assert.Equal(t, "affected by CVE-2024-10524 in wget (Critical)", failures[0].Reason)
+
+	require.Len(t, report.Groups, 2)
+	admin := report.Groups[0]
+	assert.Equal(t, "Admin", admin.Name)
+	assert.Equal(t, 1, admin.Devices)
+	assert.Equal(t, 1, admin.NonCompliant)
+	assert.Equal(t, map[string]int{"max-vulnerability-severity": 1}, admin.RuleFailures)
+
+	all := report.Groups[1]
+	assert.Equal(t, "All Devices", all.Name)
+	assert.Equal(t, 2, all.Devices)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceLinux, workbrewtest.FixtureDeviceMacBook}, all.NonCompliantDevices)
+	assert.Equal(t, map[string]int{"max-vulnerability-severity": 1, "min-groups": 1}, all.RuleFailures)
+
+	assert.Len(t, report.NonCompliant(), 2)
+}
+
+func TestEvaluate_WithoutVulnerabilities(t *testing.T) {
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	src := NewSource(wb, workbrewtest.DefaultWorkspace)
+	src.Vulnerabilities = nil
+	report, err := Evaluate(context.Background(), src, testRules())
+	require.NoError(t, err)
+
+	macbook, _ := report.Device(workbrewtest.FixtureDeviceMacBook)
+	assert.True(t, macbook.Compliant)
+	assert.Equal(t, 1, report.Summary.Compliant)
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	_, err := Evaluate(context.Background(), nil, nil)
+	assert.Error(t, err)
+
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	server.InjectFault(workbrewtest.Fault{Path: "/vulnerabilities.json", StatusCode: 403})
+	_, err = Evaluate(context.Background(), NewSource(wb, workbrewtest.DefaultWorkspace), testRules())
+	assert.ErrorContains(t, err, "failed to list vulnerabilities")
+}
+
+func TestBuild_RuleValidation(t *testing.T) {
+	_, err := Build(nil, nil, nil, []Rule{{Name: "no-check"}}, nil)
+	assert.Error(t, err)
+
+	_, err = Build(nil, nil, nil, []Rule{MinGroups(1), MinGroups(2)}, nil)
+	assert.ErrorContains(t, err, "duplicate rule name")
+}
+
+func TestBuild_GoPredicate(t *testing.T) {
+	now := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	deviceList := devices.DevicesResponse{
+		{SerialNumber: "A", CasksCount: 0},
+		{SerialNumber: "B", CasksCount: 4},
+	}
+	rule := NewRule("no-casks", func(facts *Facts) error {
+		assert.Equal(t, now, facts.Now)
+		if facts.Device.CasksCount > 0 {
+			return fmt.Errorf("%d casks", facts.Device.CasksCount)
+		}
+		return nil
+	})
+
+	report, err := Build(deviceList, nil, nil, []Rule{rule}, &Options{Now: now})
+	require.NoError(t, err)
+	assert.Equal(t, now, report.GeneratedAt)
+	assert.True(t, report.Devices[0].Compliant)
+	assert.Equal(t, "4 casks", report.Devices[1].Results[0].Reason)
+	assert.Empty(t, report.Groups)
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	report, err := Build(devices.DevicesResponse{{SerialNumber: "A"}}, nil, nil, []Rule{MinGroups(1)}, nil)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	devicesJSON := document["devices"].([]any)
+	require.Len(t, devicesJSON, 1)
+	device := devicesJSON[0].(map[string]any)
+	assert.Equal(t, false, device["compliant"])
+	assert.Equal(t, "fail", device["results"].([]any)[0].(map[string]any)["outcome"])
+}
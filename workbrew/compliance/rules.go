@@ -0,0 +1,302 @@
+// Package compliance evaluates devices against fleet compliance rules.
+//
+// Rules are Go predicates over a device's Facts — its API record, device
+// groups and vulnerabilities — or are loaded from YAML or JSON rule files.
+// Evaluate checks every device in a workspace and returns a per-device report
+// with the reason for each failure, aggregated per device group.
+package compliance
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/duration"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+)
+
+// Facts is what is known about a device when rules are evaluated
+type Facts struct {
+	Device devices.Device
+
+	// Groups are the names of the device groups the device belongs to
+	Groups []string
+
+	// Vulnerabilities affect formulae the device runs an outdated version of.
+	// Empty when vulnerabilities were not collected.
+	Vulnerabilities []Vulnerability
+
+	// Now is the evaluation time
+	Now time.Time
+}
+
+// Vulnerability is a vulnerability affecting a device
+type Vulnerability struct {
+	Formula   string   `json:"formula"`
+	ID        string   `json:"id"`
+	CVSSScore *float64 `json:"cvss_score,omitempty"`
+}
+
+// Severity returns the CVSS rating of the vulnerability, or SeverityNone when unscored
+func (v *Vulnerability) Severity() vulnerabilities.Severity {
+	if v.CVSSScore == nil {
+		return vulnerabilities.SeverityNone
+	}
+	return vulnerabilities.SeverityForScore(*v.CVSSScore)
+}
+
+// Rule is a compliance check applied to each device
+type Rule struct {
+	// Name identifies the rule in reports
+	Name string
+
+	// Description explains the rule to readers of reports
+	Description string
+
+	// AppliesTo limits the rule to some devices; nil applies it to all
+	AppliesTo func(facts *Facts) bool
+
+	// Check returns nil if the device complies, or an error whose message is the reason it does not
+	Check func(facts *Facts) error
+}
+
+// NewRule creates a rule from a Go predicate
+//
+// Parameters:
+//   - name: The rule name shown in reports
+//   - check: Returns nil if the device complies, or the reason it does not
+//
+// Example:
+//
+//	rule := compliance.NewRule("has-casks", func(facts *compliance.Facts) error {
+//	    if facts.Device.CasksCount == 0 {
+//	        return fmt.Errorf("no casks installed")
+//	    }
+//	    return nil
+//	})
+func NewRule(name string, check func(facts *Facts) error) Rule {
+	return Rule{Name: name, Check: check}
+}
+
+// Named returns a copy of the rule with a different name and description
+func (r Rule) Named(name, description string) Rule {
+	r.Name = name
+	r.Description = description
+	return r
+}
+
+// ForDeviceTypes returns a copy of the rule that applies only to devices whose
+// type matches one of the path.Match patterns, e.g. "MacBook*" or "Linux"
+func (r Rule) ForDeviceTypes(patterns ...string) Rule {
+	return r.scoped(func(facts *Facts) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, facts.Device.DeviceType); ok {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ForGroups returns a copy of the rule that applies only to members of at least one of the named groups
+func (r Rule) ForGroups(names ...string) Rule {
+	return r.scoped(func(facts *Facts) bool {
+		for _, name := range names {
+			if slices.Contains(facts.Groups, name) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// scoped adds a condition to the rule's AppliesTo
+func (r Rule) scoped(condition func(facts *Facts) bool) Rule {
+	previous := r.AppliesTo
+	r.AppliesTo = func(facts *Facts) bool {
+		return (previous == nil || previous(facts)) && condition(facts)
+	}
+	return r
+}
+
+// MinHomebrewVersion requires Homebrew at or above a version, e.g. "4.4.0"
+func MinHomebrewVersion(version string) Rule {
+	return minVersion("min-homebrew-version", "Homebrew", version, func(d *devices.Device) string { return d.HomebrewVersion })
+}
+
+// MinWorkbrewVersion requires the Workbrew agent at or above a version
+func MinWorkbrewVersion(version string) Rule {
+	return minVersion("min-workbrew-version", "Workbrew", version, func(d *devices.Device) string { return d.WorkbrewVersion })
+}
+
+// MinOSVersion requires an operating system version at or above a version. The
+// first dotted number in the OS version is compared, so "macOS 15.2 (24C101)"
+// is 15.2. Scope the rule with ForDeviceTypes when the fleet mixes platforms.
+func MinOSVersion(version string) Rule {
+	return minVersion("min-os-version", "OS", version, func(d *devices.Device) string { return d.OSVersion })
+}
+
+// minVersion requires a device version field at or above a version
+func minVersion(name, label, minimum string, field func(*devices.Device) string) Rule {
+	return Rule{
+		Name:        name,
+		Description: fmt.Sprintf("%s %s or later", label, minimum),
+		Check: func(facts *Facts) error {
+			current := field(&facts.Device)
+			comparison, ok := compareVersions(current, minimum)
+			if !ok {
+				return fmt.Errorf("%s version %q cannot be compared with %s", label, current, minimum)
+			}
+			if comparison < 0 {
+				return fmt.Errorf("%s %s is older than %s", label, extractVersion(current), minimum)
+			}
+			return nil
+		},
+	}
+}
+
+// SeenWithin requires the device to have checked in within a duration of the evaluation time
+func SeenWithin(within time.Duration) Rule {
+	return Rule{
+		Name:        "seen-within",
+		Description: "Seen within " + duration.Format(within),
+		Check: func(facts *Facts) error {
+			seen := facts.Device.LastSeenAt
+			if seen.Never || seen.Time == nil {
+				return fmt.Errorf("never seen")
+			}
+			if age := facts.Now.Sub(*seen.Time); age > within {
+				return fmt.Errorf("last seen %s ago, more than %s", duration.Format(age.Truncate(time.Hour)), duration.Format(within))
+			}
+			return nil
+		},
+	}
+}
+
+// MaxVulnerabilitySeverity fails devices affected by a vulnerability rated above
+// a severity; MaxVulnerabilitySeverity(vulnerabilities.SeverityHigh) forbids critical
+// vulnerabilities. With SeverityNone, any vulnerability fails, including
+// unscored ones; otherwise unscored vulnerabilities are ignored.
+func MaxVulnerabilitySeverity(maximum vulnerabilities.Severity) Rule {
+	description := "No vulnerabilities"
+	if maximum > vulnerabilities.SeverityNone {
+		description = "No vulnerabilities above " + strings.ToLower(maximum.String())
+	}
+	return Rule{
+		Name:        "max-vulnerability-severity",
+		Description: description,
+		Check: func(facts *Facts) error {
+			var found []string
+			for _, vulnerability := range facts.Vulnerabilities {
+				if maximum == vulnerabilities.SeverityNone || vulnerability.Severity() > maximum {
+					found = append(found, fmt.Sprintf("%s in %s (%s)", vulnerability.ID, vulnerability.Formula, vulnerability.Severity()))
+				}
+			}
+			if len(found) > 0 {
+				return fmt.Errorf("affected by %s", strings.Join(found, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// MinGroups requires membership of at least a number of device groups
+func MinGroups(minimum int) Rule {
+	return Rule{
+		Name:        "min-groups",
+		Description: fmt.Sprintf("Member of at least %d device group(s)", minimum),
+		Check: func(facts *Facts) error {
+			if len(facts.Groups) < minimum {
+				return fmt.Errorf("member of %d device group(s), fewer than %d", len(facts.Groups), minimum)
+			}
+			return nil
+		},
+	}
+}
+
+// RequiredGroups requires membership of every named device group
+func RequiredGroups(names ...string) Rule {
+	return Rule{
+		Name:        "required-groups",
+		Description: "Member of " + strings.Join(names, ", "),
+		Check: func(facts *Facts) error {
+			var missing []string
+			for _, name := range names {
+				if !slices.Contains(facts.Groups, name) {
+					missing = append(missing, name)
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("not a member of %s", strings.Join(missing, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// MaxFormulae limits the number of installed formulae
+func MaxFormulae(maximum int) Rule {
+	return Rule{
+		Name:        "max-formulae",
+		Description: fmt.Sprintf("At most %d formulae", maximum),
+		Check: func(facts *Facts) error {
+			if facts.Device.FormulaeCount > maximum {
+				return fmt.Errorf("%d formulae installed, more than %d", facts.Device.FormulaeCount, maximum)
+			}
+			return nil
+		},
+	}
+}
+
+// MaxCasks limits the number of installed casks
+func MaxCasks(maximum int) Rule {
+	return Rule{
+		Name:        "max-casks",
+		Description: fmt.Sprintf("At most %d casks", maximum),
+		Check: func(facts *Facts) error {
+			if facts.Device.CasksCount > maximum {
+				return fmt.Errorf("%d casks installed, more than %d", facts.Device.CasksCount, maximum)
+			}
+			return nil
+		},
+	}
+}
+
+// versionPattern matches the first dotted number in a version string
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// extractVersion returns the first dotted number in a version string
+func extractVersion(version string) string {
+	return versionPattern.FindString(version)
+}
+
+// compareVersions compares the first dotted numbers in two version strings
+// numerically, treating missing components as zero
+func compareVersions(a, b string) (int, bool) {
+	left, right := extractVersion(a), extractVersion(b)
+	if left == "" || right == "" {
+		return 0, false
+	}
+	leftParts, rightParts := strings.Split(left, "."), strings.Split(right, ".")
+	for i := range max(len(leftParts), len(rightParts)) {
+		var l, r int
+		if i < len(leftParts) {
+			l, _ = strconv.Atoi(leftParts[i])
+		}
+		if i < len(rightParts) {
+			r, _ = strconv.Atoi(rightParts[i])
+		}
+		if l != r {
+			if l < r {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
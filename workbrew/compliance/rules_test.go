@@ -0,0 +1,118 @@
+package compliance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func testFacts() *Facts {
+	seen := time.Date(2025, 1, 6, 9, 30, 0, 0, time.UTC)
+	return &Facts{
+		Device: devices.Device{
+			SerialNumber:    "TC6R2DHVHG",
+			LastSeenAt:      devices.TimeOrNever{Time: &seen},
+			DeviceType:      "MacBook Pro",
+			OSVersion:       "macOS 15.2 (24C101)",
+			HomebrewVersion: "4.4.15",
+			WorkbrewVersion: "1.1.4",
+			FormulaeCount:   3,
+			CasksCount:      1,
+		},
+		Groups: []string{"Admin", "All Devices"},
+		Vulnerabilities: []Vulnerability{
+			{Formula: "curl", ID: "CVE-2024-2466", CVSSScore: ptr(6.5)},
+			{Formula: "jq", ID: "OSV-1"},
+		},
+		Now: seen.Add(36 * time.Hour),
+	}
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		rule   Rule
+		reason string
+	}{
+		{MinHomebrewVersion("4.4"), ""},
+		{MinHomebrewVersion("4.4.16"), "Homebrew 4.4.15 is older than 4.4.16"},
+		{MinWorkbrewVersion("1.1.4"), ""},
+		{MinWorkbrewVersion("1.10"), "Workbrew 1.1.4 is older than 1.10"},
+		{MinOSVersion("15.0"), ""},
+		{MinOSVersion("15.3"), "OS 15.2 is older than 15.3"},
+		{MinOSVersion("fifteen"), `OS version "macOS 15.2 (24C101)" cannot be compared with fifteen`},
+		{SeenWithin(7 * 24 * time.Hour), ""},
+		{SeenWithin(24 * time.Hour), "last seen 36h0m0s ago, more than 1d"},
+		{MaxVulnerabilitySeverity(vulnerabilities.SeverityHigh), ""},
+		{MaxVulnerabilitySeverity(vulnerabilities.SeverityLow), "affected by CVE-2024-2466 in curl (Medium)"},
+		{MaxVulnerabilitySeverity(vulnerabilities.SeverityNone), "affected by CVE-2024-2466 in curl (Medium), OSV-1 in jq (None)"},
+		{MinGroups(1), ""},
+		{MinGroups(3), "member of 2 device group(s), fewer than 3"},
+		{RequiredGroups("Admin"), ""},
+		{RequiredGroups("Admin", "Engineering", "Finance"), "not a member of Engineering, Finance"},
+		{MaxFormulae(3), ""},
+		{MaxFormulae(2), "3 formulae installed, more than 2"},
+		{MaxCasks(0), "1 casks installed, more than 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule.Name+"/"+tt.rule.Description, func(t *testing.T) {
+			err := tt.rule.Check(testFacts())
+			if tt.reason == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestSeenWithin_Never(t *testing.T) {
+	facts := testFacts()
+	facts.Device.LastSeenAt = devices.TimeOrNever{Never: true}
+
+	assert.EqualError(t, SeenWithin(time.Hour).Check(facts), "never seen")
+}
+
+func TestRule_Scopes(t *testing.T) {
+	facts := testFacts()
+
+	rule := NewRule("custom", func(*Facts) error { return fmt.Errorf("always fails") })
+	assert.Nil(t, rule.AppliesTo)
+
+	assert.True(t, rule.ForDeviceTypes("MacBook*").AppliesTo(facts))
+	assert.False(t, rule.ForDeviceTypes("Linux").AppliesTo(facts))
+	assert.True(t, rule.ForGroups("Finance", "Admin").AppliesTo(facts))
+	assert.False(t, rule.ForGroups("Finance").AppliesTo(facts))
+	assert.False(t, rule.ForDeviceTypes("MacBook*").ForGroups("Finance").AppliesTo(facts), "scopes combine")
+
+	named := rule.Named("renamed", "A description")
+	assert.Equal(t, "renamed", named.Name)
+	assert.Equal(t, "custom", rule.Name, "the original is unchanged")
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+		ok       bool
+	}{
+		{"4.4.15", "4.4.15", 0, true},
+		{"4.4", "4.4.0", 0, true},
+		{"4.10.0", "4.9.9", 1, true},
+		{"Ubuntu 22.04.5 LTS", "22.10", -1, true},
+		{"4.4.15-25-gabc", "4.4.15", 0, true},
+		{"unknown", "1.0", 0, false},
+	}
+	for _, tt := range tests {
+		comparison, ok := compareVersions(tt.a, tt.b)
+		assert.Equal(t, tt.ok, ok, "%s vs %s", tt.a, tt.b)
+		assert.Equal(t, tt.expected, comparison, "%s vs %s", tt.a, tt.b)
+	}
+}
@@ -0,0 +1,213 @@
+package compliance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/duration"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleFile is a set of declarative rules, as read from a YAML or JSON file
+//
+// Example YAML:
+//
+//	rules:
+//	  - name: homebrew-current
+//	    min_homebrew_version: "4.4.0"
+//	  - name: checked-in
+//	    seen_within: 7d
+//	  - name: no-critical-vulnerabilities
+//	    max_vulnerability_severity: high
+//	  - name: grouped
+//	    min_groups: 1
+//	  - name: macos-15
+//	    device_types: ["Mac*"]
+//	    min_os_version: "15.0"
+type RuleFile struct {
+	Rules []RuleSpec `json:"rules" yaml:"rules"`
+}
+
+// RuleSpec declares one rule. Exactly one check field must be set; DeviceTypes
+// and Groups optionally limit the devices the rule applies to.
+type RuleSpec struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// DeviceTypes are path.Match patterns the device type must match one of
+	DeviceTypes []string `json:"device_types,omitempty" yaml:"device_types,omitempty"`
+
+	// Groups limit the rule to members of at least one of these device groups
+	Groups []string `json:"groups,omitempty" yaml:"groups,omitempty"`
+
+	MinHomebrewVersion       string    `json:"min_homebrew_version,omitempty" yaml:"min_homebrew_version,omitempty"`
+	MinWorkbrewVersion       string    `json:"min_workbrew_version,omitempty" yaml:"min_workbrew_version,omitempty"`
+	MinOSVersion             string    `json:"min_os_version,omitempty" yaml:"min_os_version,omitempty"`
+	SeenWithin               *Duration `json:"seen_within,omitempty" yaml:"seen_within,omitempty"`
+	MaxVulnerabilitySeverity string    `json:"max_vulnerability_severity,omitempty" yaml:"max_vulnerability_severity,omitempty"`
+	MinGroups                *int      `json:"min_groups,omitempty" yaml:"min_groups,omitempty"`
+	RequiredGroups           []string  `json:"required_groups,omitempty" yaml:"required_groups,omitempty"`
+	MaxFormulae              *int      `json:"max_formulae,omitempty" yaml:"max_formulae,omitempty"`
+	MaxCasks                 *int      `json:"max_casks,omitempty" yaml:"max_casks,omitempty"`
+}
+
+// Compile converts the declaration to a Rule
+func (s *RuleSpec) Compile() (Rule, error) {
+	if s.Name == "" {
+		return Rule{}, fmt.Errorf("rule name is required")
+	}
+
+	var rules []Rule
+	if s.MinHomebrewVersion != "" {
+		rules = append(rules, MinHomebrewVersion(s.MinHomebrewVersion))
+	}
+	if s.MinWorkbrewVersion != "" {
+		rules = append(rules, MinWorkbrewVersion(s.MinWorkbrewVersion))
+	}
+	if s.MinOSVersion != "" {
+		rules = append(rules, MinOSVersion(s.MinOSVersion))
+	}
+	if s.SeenWithin != nil {
+		rules = append(rules, SeenWithin(time.Duration(*s.SeenWithin)))
+	}
+	if s.MaxVulnerabilitySeverity != "" {
+		severity, err := vulnerabilities.ParseSeverity(s.MaxVulnerabilitySeverity)
+		if err != nil {
+			return Rule{}, fmt.Errorf("rule %q: %w", s.Name, err)
+		}
+		rules = append(rules, MaxVulnerabilitySeverity(severity))
+	}
+	if s.MinGroups != nil {
+		rules = append(rules, MinGroups(*s.MinGroups))
+	}
+	if len(s.RequiredGroups) > 0 {
+		rules = append(rules, RequiredGroups(s.RequiredGroups...))
+	}
+	if s.MaxFormulae != nil {
+		rules = append(rules, MaxFormulae(*s.MaxFormulae))
+	}
+	if s.MaxCasks != nil {
+		rules = append(rules, MaxCasks(*s.MaxCasks))
+	}
+	if len(rules) != 1 {
+		return Rule{}, fmt.Errorf("rule %q: exactly one check must be set, found %d", s.Name, len(rules))
+	}
+
+	for _, pattern := range s.DeviceTypes {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return Rule{}, fmt.Errorf("rule %q: invalid device type pattern %q: %w", s.Name, pattern, err)
+		}
+	}
+
+	rule := rules[0]
+	rule.Name = s.Name
+	if s.Description != "" {
+		rule.Description = s.Description
+	}
+	if len(s.DeviceTypes) > 0 {
+		rule = rule.ForDeviceTypes(s.DeviceTypes...)
+	}
+	if len(s.Groups) > 0 {
+		rule = rule.ForGroups(s.Groups...)
+	}
+	return rule, nil
+}
+
+// Compile converts every declaration to a Rule, rejecting duplicate names
+func (f *RuleFile) Compile() ([]Rule, error) {
+	rules := make([]Rule, 0, len(f.Rules))
+	seen := make(map[string]bool)
+	for i := range f.Rules {
+		rule, err := f.Rules[i].Compile()
+		if err != nil {
+			return nil, err
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("duplicate rule name %q", rule.Name)
+		}
+		seen[rule.Name] = true
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ParseRules decodes and compiles a YAML or JSON rule file. JSON is a subset of
+// YAML, so YAML decoding is used for both; unknown fields are rejected.
+//
+// Parameters:
+//   - data: The rule file contents
+//
+// Returns:
+//   - []Rule: The compiled rules, in file order
+//   - error: Any decoding or validation error
+func ParseRules(data []byte) ([]Rule, error) {
+	var file RuleFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to decode compliance rules: %w", err)
+	}
+	return file.Compile()
+}
+
+// LoadRules reads a YAML or JSON rule file
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compliance rules: %w", err)
+	}
+	rules, err := ParseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return rules, nil
+}
+
+// Duration is a time.Duration that also accepts whole days and weeks, e.g. "7d" or "2w"
+type Duration time.Duration
+
+// ParseDuration parses a Go duration, or a whole number of days ("7d") or weeks ("2w")
+func ParseDuration(value string) (Duration, error) {
+	d, err := duration.Parse(value)
+	return Duration(d), err
+}
+
+// String formats the duration as days when whole, e.g. "7d"
+func (d Duration) String() string {
+	return duration.Format(time.Duration(d))
+}
+
+// UnmarshalYAML parses a duration string
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := ParseDuration(node.Value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	parsed, err := ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON formats the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
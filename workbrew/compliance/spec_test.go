@@ -0,0 +1,118 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRulesYAML = `
+rules:
+  - name: homebrew-current
+    description: Homebrew must be current
+    min_homebrew_version: "4.4.0"
+  - name: checked-in
+    seen_within: 7d
+  - name: no-critical-vulnerabilities
+    max_vulnerability_severity: high
+  - name: grouped
+    min_groups: 1
+  - name: macos-15
+    device_types: ["Mac*"]
+    min_os_version: "15.3"
+  - name: admins-limited
+    groups: [Admin]
+    max_casks: 0
+`
+
+func TestParseRules_YAML(t *testing.T) {
+	rules, err := ParseRules([]byte(testRulesYAML))
+	require.NoError(t, err)
+	require.Len(t, rules, 6)
+
+	assert.Equal(t, "homebrew-current", rules[0].Name)
+	assert.Equal(t, "Homebrew must be current", rules[0].Description)
+	assert.Equal(t, "Seen within 7d", rules[1].Description)
+	assert.Equal(t, "No vulnerabilities above high", rules[2].Description)
+
+	facts := testFacts()
+	assert.NoError(t, rules[0].Check(facts))
+	assert.NoError(t, rules[1].Check(facts))
+	assert.True(t, rules[4].AppliesTo(facts))
+	assert.Error(t, rules[4].Check(facts))
+	assert.True(t, rules[5].AppliesTo(facts))
+
+	facts.Device.DeviceType = "Linux"
+	facts.Groups = nil
+	assert.False(t, rules[4].AppliesTo(facts))
+	assert.False(t, rules[5].AppliesTo(facts))
+}
+
+func TestParseRules_JSON(t *testing.T) {
+	rules, err := ParseRules([]byte(`{"rules": [{"name": "seen", "seen_within": "12h"}, {"name": "grouped", "required_groups": ["Admin"]}]}`))
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "Seen within 12h0m0s", rules[0].Description)
+}
+
+func TestParseRules_Errors(t *testing.T) {
+	tests := map[string]string{
+		"no check":           "rules: [{name: empty}]",
+		"two checks":         "rules: [{name: both, min_groups: 1, max_casks: 2}]",
+		"no name":            "rules: [{min_groups: 1}]",
+		"duplicate name":     "rules: [{name: a, min_groups: 1}, {name: a, max_casks: 1}]",
+		"unknown field":      "rules: [{name: a, min_group: 1}]",
+		"bad severity":       "rules: [{name: a, max_vulnerability_severity: extreme}]",
+		"bad duration":       "rules: [{name: a, seen_within: soon}]",
+		"bad device pattern": "rules: [{name: a, device_types: ['[Mac'], min_groups: 1}]",
+		"empty":              "",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseRules([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testRulesYAML), 0o644))
+
+	rules, err := LoadRules(path)
+	require.NoError(t, err)
+	assert.Len(t, rules, 6)
+
+	require.NoError(t, os.WriteFile(path, []byte("rules: [{name: a}]"), 0o644))
+	_, err = LoadRules(path)
+	assert.ErrorContains(t, err, "rules.yaml: rule \"a\"")
+
+	_, err = LoadRules(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"7d":    7 * 24 * time.Hour,
+		"2w":    14 * 24 * time.Hour,
+		"36h":   36 * time.Hour,
+		"1h30m": 90 * time.Minute,
+	}
+	for input, expected := range tests {
+		d, err := ParseDuration(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, Duration(expected), d, input)
+	}
+
+	for _, input := range []string{"", "d", "-1d", "1.5d", "-1h", "soon"} {
+		_, err := ParseDuration(input)
+		assert.Error(t, err, input)
+	}
+
+	assert.Equal(t, "7d", Duration(7*24*time.Hour).String())
+	assert.Equal(t, "1h30m0s", Duration(90*time.Minute).String())
+}
@@ -0,0 +1,351 @@
+package csvdecode
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unmarshaler is implemented by types that can decode themselves from a single CSV cell.
+// Model types with non-trivial CSV representations (e.g. devices.TimeOrNever) implement this.
+type Unmarshaler interface {
+	UnmarshalCSV(value string) error
+}
+
+// TimeLayouts are the timestamp layouts accepted when decoding time.Time cells.
+// The Workbrew CSV exports mix RFC3339 ("2024-01-01T12:34:56Z") with a
+// human readable UTC layout ("2023-08-25 00:00:00 UTC").
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02T15:04",
+}
+
+// ParseTime parses a CSV timestamp using the first matching layout in TimeLayouts.
+//
+// Parameters:
+//   - value: The raw cell value
+//
+// Returns:
+//   - time.Time: The parsed time
+//   - error: An error if no layout matched
+func ParseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range TimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised time format %q", value)
+}
+
+// SplitList splits a CSV list cell into its items.
+// Cells may be encoded as a JSON array (`["a","b"]`) or as a comma separated
+// string ("a, b"). Empty cells produce a nil slice.
+//
+// Parameters:
+//   - value: The raw cell value
+//
+// Returns:
+//   - []string: The individual list items with surrounding whitespace trimmed
+func SplitList(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "[]" {
+		return nil
+	}
+
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		var items []string
+		if err := json.Unmarshal([]byte(value), &items); err == nil {
+			return items
+		}
+	}
+
+	parts := strings.Split(value, ",")
+	items := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// Unmarshal decodes a complete CSV document into a slice of T.
+// The first record is treated as the header row; columns are matched to struct
+// fields by their `csv` tag, falling back to the `json` tag name.
+// Columns without a matching field are ignored.
+//
+// Parameters:
+//   - data: Raw CSV bytes, as returned by the service List*CSV methods
+//
+// Returns:
+//   - []T: The decoded rows
+//   - error: Any error encountered while reading or decoding a row
+//
+// Example:
+//
+//	csvData, _, _ := client.Devices.ListDevicesCSV(ctx)
+//	rows, err := csvdecode.Unmarshal[devices.Device](csvData)
+func Unmarshal[T any](data []byte) ([]T, error) {
+	reader, err := NewReader[T](bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return []T{}, nil
+		}
+		return nil, err
+	}
+
+	rows := make([]T, 0)
+	for row, err := range reader.All() {
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Reader streams typed rows from a CSV document without loading it into memory.
+type Reader[T any] struct {
+	csv    *csv.Reader
+	header []string
+	fields []fieldPlan
+	line   int
+}
+
+// fieldPlan maps a CSV column to a struct field index.
+type fieldPlan struct {
+	column string
+	index  []int
+}
+
+// NewReader creates a streaming reader that decodes rows of r into T.
+// The header row is consumed immediately; io.EOF is returned for empty input.
+//
+// Parameters:
+//   - r: The CSV source
+//
+// Returns:
+//   - *Reader[T]: A reader positioned at the first data row
+//   - error: An error if T is not a struct or the header could not be read
+func NewReader[T any](r io.Reader) (*Reader[T], error) {
+	var zero T
+	rt := reflect.TypeOf(zero)
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csvdecode: target type %T must be a struct", zero)
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+
+	columns := columnIndex(rt)
+	plans := make([]fieldPlan, len(header))
+	for i, name := range header {
+		plans[i] = fieldPlan{column: name, index: columns[name]}
+	}
+
+	return &Reader[T]{
+		csv:    cr,
+		header: header,
+		fields: plans,
+		line:   1,
+	}, nil
+}
+
+// Header returns the column names read from the first CSV record.
+func (r *Reader[T]) Header() []string {
+	return append([]string(nil), r.header...)
+}
+
+// Read decodes the next row. It returns io.EOF when no rows remain.
+func (r *Reader[T]) Read() (T, error) {
+	var row T
+
+	record, err := r.csv.Read()
+	if err != nil {
+		return row, err
+	}
+	r.line++
+
+	rv := reflect.ValueOf(&row).Elem()
+	for i, cell := range record {
+		if i >= len(r.fields) || r.fields[i].index == nil {
+			continue
+		}
+		field := rv.FieldByIndex(r.fields[i].index)
+		if err := setField(field, cell); err != nil {
+			return row, fmt.Errorf("csvdecode: line %d, column %q: %w", r.line, r.fields[i].column, err)
+		}
+	}
+
+	return row, nil
+}
+
+// All returns an iterator over the remaining rows.
+// Iteration stops after the first error is yielded.
+func (r *Reader[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			row, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(row, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// columnIndex builds a lookup of column name to struct field index for rt.
+func columnIndex(rt reflect.Type) map[string][]int {
+	columns := make(map[string][]int, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := tagName(sf.Tag.Get("csv"))
+		if name == "" {
+			name = tagName(sf.Tag.Get("json"))
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		columns[name] = sf.Index
+	}
+	return columns
+}
+
+// tagName returns the name portion of a struct tag value.
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
+
+var (
+	unmarshalerType = reflect.TypeFor[Unmarshaler]()
+	timeType        = reflect.TypeFor[time.Time]()
+)
+
+// setField decodes a single cell into the given field.
+func setField(field reflect.Value, cell string) error {
+	if field.CanAddr() && field.Addr().Type().Implements(unmarshalerType) {
+		return field.Addr().Interface().(Unmarshaler).UnmarshalCSV(cell)
+	}
+
+	if field.Type() == timeType {
+		if strings.TrimSpace(cell) == "" {
+			return nil
+		}
+		parsed, err := ParseTime(cell)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(parsed))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Pointer:
+		if strings.TrimSpace(cell) == "" {
+			field.SetZero()
+			return nil
+		}
+		value := reflect.New(field.Type().Elem())
+		if err := setField(value.Elem(), cell); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+
+	case reflect.String:
+		field.SetString(cell)
+		return nil
+
+	case reflect.Bool:
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			field.SetBool(false)
+			return nil
+		}
+		parsed, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			field.SetInt(0)
+			return nil
+		}
+		parsed, err := strconv.ParseInt(cell, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			field.SetFloat(0)
+			return nil
+		}
+		parsed, err := strconv.ParseFloat(cell, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+		return nil
+
+	case reflect.Slice:
+		items := SplitList(cell)
+		if items == nil {
+			field.SetZero()
+			return nil
+		}
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setField(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+
+	case reflect.Map, reflect.Struct, reflect.Interface:
+		if strings.TrimSpace(cell) == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(cell), field.Addr().Interface())
+	}
+
+	return fmt.Errorf("unsupported field type %s", field.Type())
+}
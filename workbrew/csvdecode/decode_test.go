@@ -0,0 +1,106 @@
+package csvdecode
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRow struct {
+	Name     string    `json:"name"`
+	Count    int       `json:"count"`
+	Score    *float64  `json:"score"`
+	Enabled  bool      `json:"enabled"`
+	Tags     []string  `json:"tags"`
+	Seen     time.Time `json:"seen"`
+	Renamed  string    `json:"ignored" csv:"alias"`
+	Optional *string   `json:"optional,omitempty"`
+	Skipped  string    `json:"-"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	data := "name,count,score,enabled,tags,seen,alias,optional,unknown\n" +
+		`a,1,2.5,true,"[""x"",""y""]",2024-01-01 10:00:00 UTC,aliased,,whatever` + "\n" +
+		`b,,,false,"x, y",2024-01-01T10:00:00Z,,set,` + "\n"
+
+	rows, err := Unmarshal[testRow]([]byte(data))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, "a", rows[0].Name)
+	assert.Equal(t, 1, rows[0].Count)
+	require.NotNil(t, rows[0].Score)
+	assert.Equal(t, 2.5, *rows[0].Score)
+	assert.True(t, rows[0].Enabled)
+	assert.Equal(t, []string{"x", "y"}, rows[0].Tags)
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), rows[0].Seen.UTC())
+	assert.Equal(t, "aliased", rows[0].Renamed)
+	assert.Nil(t, rows[0].Optional)
+
+	assert.Nil(t, rows[1].Score)
+	assert.Equal(t, []string{"x", "y"}, rows[1].Tags)
+	require.NotNil(t, rows[1].Optional)
+	assert.Equal(t, "set", *rows[1].Optional)
+}
+
+func TestUnmarshal_Empty(t *testing.T) {
+	rows, err := Unmarshal[testRow](nil)
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
+func TestUnmarshal_InvalidCell(t *testing.T) {
+	_, err := Unmarshal[testRow]([]byte("name,count\na,notanumber\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `line 2, column "count"`)
+}
+
+func TestNewReader_NonStruct(t *testing.T) {
+	_, err := NewReader[string](strings.NewReader("a\nb\n"))
+	assert.Error(t, err)
+}
+
+func TestReader_Read(t *testing.T) {
+	reader, err := NewReader[testRow](strings.NewReader("name\none\ntwo\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name"}, reader.Header())
+
+	row, err := reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "one", row.Name)
+
+	row, err = reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "two", row.Name)
+
+	_, err = reader.Read()
+	assert.True(t, errors.Is(err, io.EOF))
+}
+
+func TestSplitList(t *testing.T) {
+	assert.Nil(t, SplitList(""))
+	assert.Nil(t, SplitList("[]"))
+	assert.Equal(t, []string{"a"}, SplitList("a"))
+	assert.Equal(t, []string{"a", "b"}, SplitList("a, b"))
+	assert.Equal(t, []string{"a, b"}, SplitList(`["a, b"]`))
+}
+
+func TestParseTime(t *testing.T) {
+	for _, value := range []string{
+		"2023-08-25 00:00:00 UTC",
+		"2023-08-25T00:00:00Z",
+		"2023-08-25T00:00:00.000Z",
+	} {
+		parsed, err := ParseTime(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, 2023, parsed.Year())
+	}
+
+	_, err := ParseTime("not a time")
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+// Package cyclonedx defines the subset of the CycloneDX 1.5 JSON format written
+// by the SDK's SBOM and VEX generators.
+package cyclonedx
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/google/uuid"
+)
+
+// Document constants
+const (
+	BOMFormat   = "CycloneDX"
+	SpecVersion = "1.5"
+)
+
+// Component types used by the SDK
+const (
+	ComponentApplication = "application"
+	ComponentDevice      = "device"
+	ComponentLibrary     = "library"
+)
+
+// BOM is a CycloneDX document: a software bill of materials, a VEX document, or both
+type BOM struct {
+	BOMFormat       string          `json:"bomFormat"`
+	SpecVersion     string          `json:"specVersion"`
+	SerialNumber    string          `json:"serialNumber"`
+	Version         int             `json:"version"`
+	Metadata        Metadata        `json:"metadata"`
+	Components      []Component     `json:"components"`
+	Dependencies    []Dependency    `json:"dependencies,omitempty"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// Metadata describes when, by what and about what the document was produced
+type Metadata struct {
+	Timestamp  string     `json:"timestamp"`
+	Tools      Tools      `json:"tools"`
+	Component  *Component `json:"component,omitempty"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+// Tools lists the tools that produced the document
+type Tools struct {
+	Components []Component `json:"components"`
+}
+
+// Component is a package, device or tool
+type Component struct {
+	Type        string          `json:"type"`
+	BOMRef      string          `json:"bom-ref,omitempty"`
+	Name        string          `json:"name"`
+	Version     string          `json:"version,omitempty"`
+	Description string          `json:"description,omitempty"`
+	PURL        string          `json:"purl,omitempty"`
+	Licenses    []LicenseChoice `json:"licenses,omitempty"`
+	Properties  []Property      `json:"properties,omitempty"`
+}
+
+// LicenseChoice is either a single license or an SPDX license expression
+type LicenseChoice struct {
+	License    *License `json:"license,omitempty"`
+	Expression string   `json:"expression,omitempty"`
+}
+
+// License identifies a license by SPDX ID or by name
+type License struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// Property is a name-value pair
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Dependency lists the components a component depends on or contains
+type Dependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// Vulnerability is a VEX statement about one vulnerability
+type Vulnerability struct {
+	BOMRef         string     `json:"bom-ref"`
+	ID             string     `json:"id"`
+	Source         Source     `json:"source"`
+	Ratings        []Rating   `json:"ratings,omitempty"`
+	Recommendation string     `json:"recommendation,omitempty"`
+	Analysis       Analysis   `json:"analysis"`
+	Affects        []Affects  `json:"affects"`
+	Properties     []Property `json:"properties,omitempty"`
+}
+
+// Source identifies an advisory database
+type Source struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Rating is a score and its severity
+type Rating struct {
+	Score    float64 `json:"score"`
+	Severity string  `json:"severity"`
+	Method   string  `json:"method"`
+}
+
+// Analysis is the VEX impact analysis
+type Analysis struct {
+	State  string `json:"state"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Affects references an affected component
+type Affects struct {
+	Ref string `json:"ref"`
+}
+
+// NewBOM creates an empty document with a random serial number, naming the SDK as the producing tool
+//
+// Parameters:
+//   - timestamp: When the document's contents were collected
+func NewBOM(timestamp time.Time) *BOM {
+	return &BOM{
+		BOMFormat:    BOMFormat,
+		SpecVersion:  SpecVersion,
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Version:      1,
+		Metadata: Metadata{
+			Timestamp: timestamp.UTC().Format(time.RFC3339),
+			Tools: Tools{Components: []Component{{
+				Type:    ComponentApplication,
+				Name:    "go-api-sdk-workbrew",
+				Version: client.Version,
+			}}},
+		},
+		Components: []Component{},
+	}
+}
+
+// Write writes the document as indented JSON
+func (b *BOM) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(b); err != nil {
+		return fmt.Errorf("failed to write CycloneDX document: %w", err)
+	}
+	return nil
+}
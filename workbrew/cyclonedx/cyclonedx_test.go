@@ -0,0 +1,152 @@
+package cyclonedx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decode writes a document and decodes it into a generic map
+func decode(t *testing.T, bom *BOM) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, bom.Write(&buf))
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	return doc
+}
+
+func TestNewBOM(t *testing.T) {
+	timestamp := time.Date(2025, 1, 6, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	bom := NewBOM(timestamp)
+
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "1.5", bom.SpecVersion)
+	assert.Equal(t, 1, bom.Version)
+	assert.Equal(t, "2025-01-06T11:00:00Z", bom.Metadata.Timestamp)
+	require.True(t, strings.HasPrefix(bom.SerialNumber, "urn:uuid:"))
+	_, err := uuid.Parse(strings.TrimPrefix(bom.SerialNumber, "urn:uuid:"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, bom.SerialNumber, NewBOM(timestamp).SerialNumber)
+
+	require.Len(t, bom.Metadata.Tools.Components, 1)
+	tool := bom.Metadata.Tools.Components[0]
+	assert.Equal(t, ComponentApplication, tool.Type)
+	assert.Equal(t, "go-api-sdk-workbrew", tool.Name)
+	assert.Equal(t, client.Version, tool.Version)
+}
+
+func TestWrite_EmptyDocument(t *testing.T) {
+	doc := decode(t, NewBOM(time.Now()))
+
+	assert.Equal(t, []any{}, doc["components"], "components is always present")
+	assert.NotContains(t, doc, "dependencies")
+	assert.NotContains(t, doc, "vulnerabilities")
+
+	metadata := doc["metadata"].(map[string]any)
+	assert.NotContains(t, metadata, "component")
+	assert.NotContains(t, metadata, "properties")
+
+	tool := metadata["tools"].(map[string]any)["components"].([]any)[0].(map[string]any)
+	for _, key := range []string{"bom-ref", "description", "purl", "licenses", "properties"} {
+		assert.NotContains(t, tool, key)
+	}
+}
+
+func TestWrite_Structure(t *testing.T) {
+	bom := NewBOM(time.Now())
+	bom.Metadata.Component = &Component{Type: ComponentDevice, BOMRef: "device:TC6R2DHVHG", Name: "TC6R2DHVHG"}
+	bom.Components = append(bom.Components, Component{
+		Type:     ComponentLibrary,
+		BOMRef:   "pkg:brew/wget",
+		Name:     "wget",
+		Version:  "1.25.0",
+		PURL:     "pkg:brew/wget",
+		Licenses: []LicenseChoice{{License: &License{ID: "GPL-3.0-or-later"}}, {Expression: "MIT OR Apache-2.0"}},
+	})
+	bom.Dependencies = []Dependency{{Ref: "device:TC6R2DHVHG", DependsOn: []string{"pkg:brew/wget"}}}
+
+	doc := decode(t, bom)
+
+	assert.Equal(t, "device", doc["metadata"].(map[string]any)["component"].(map[string]any)["type"])
+
+	component := doc["components"].([]any)[0].(map[string]any)
+	assert.Equal(t, "pkg:brew/wget", component["bom-ref"])
+	assert.Equal(t, "1.25.0", component["version"])
+	licenses := component["licenses"].([]any)
+	assert.Equal(t, map[string]any{"license": map[string]any{"id": "GPL-3.0-or-later"}}, licenses[0])
+	assert.Equal(t, map[string]any{"expression": "MIT OR Apache-2.0"}, licenses[1])
+
+	assert.Equal(t, []any{map[string]any{"ref": "device:TC6R2DHVHG", "dependsOn": []any{"pkg:brew/wget"}}}, doc["dependencies"])
+}
+
+func TestWrite_VEXAnalysis(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis Analysis
+		want     map[string]any
+	}{
+		{"in triage", Analysis{State: "in_triage", Detail: "2 device(s) run an affected version"}, map[string]any{"state": "in_triage", "detail": "2 device(s) run an affected version"}},
+		{"resolved", Analysis{State: "resolved", Detail: "No devices run an affected version"}, map[string]any{"state": "resolved", "detail": "No devices run an affected version"}},
+		{"detail omitted", Analysis{State: "not_affected"}, map[string]any{"state": "not_affected"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bom := NewBOM(time.Now())
+			bom.Vulnerabilities = []Vulnerability{{
+				BOMRef:   "CVE-2024-2466/curl",
+				ID:       "CVE-2024-2466",
+				Source:   Source{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/CVE-2024-2466"},
+				Analysis: tt.analysis,
+				Affects:  []Affects{{Ref: "pkg:brew/curl"}},
+			}}
+
+			vulnerability := decode(t, bom)["vulnerabilities"].([]any)[0].(map[string]any)
+			assert.Equal(t, tt.want, vulnerability["analysis"])
+			assert.Equal(t, []any{map[string]any{"ref": "pkg:brew/curl"}}, vulnerability["affects"])
+			assert.Equal(t, "NVD", vulnerability["source"].(map[string]any)["name"])
+			for _, key := range []string{"ratings", "recommendation", "properties"} {
+				assert.NotContains(t, vulnerability, key)
+			}
+		})
+	}
+}
+
+func TestWrite_Ratings(t *testing.T) {
+	bom := NewBOM(time.Now())
+	bom.Vulnerabilities = []Vulnerability{{
+		ID:             "CVE-2024-10524",
+		Ratings:        []Rating{{Score: 9.1, Severity: "critical", Method: "other"}},
+		Recommendation: "Upgrade wget to 1.25.0 or later",
+		Analysis:       Analysis{State: "in_triage"},
+		Affects:        []Affects{},
+		Properties:     []Property{{Name: "workbrew:outdated_device", Value: "TC6R2DHVHG"}},
+	}}
+
+	vulnerability := decode(t, bom)["vulnerabilities"].([]any)[0].(map[string]any)
+	assert.Equal(t, []any{map[string]any{"score": 9.1, "severity": "critical", "method": "other"}}, vulnerability["ratings"])
+	assert.Equal(t, "Upgrade wget to 1.25.0 or later", vulnerability["recommendation"])
+	assert.Equal(t, []any{map[string]any{"name": "workbrew:outdated_device", "value": "TC6R2DHVHG"}}, vulnerability["properties"])
+	assert.Equal(t, []any{}, vulnerability["affects"], "affects is always present")
+	assert.Equal(t, "", vulnerability["bom-ref"], "bom-ref is always present")
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWrite_Error(t *testing.T) {
+	err := NewBOM(time.Now()).Write(failingWriter{})
+	assert.ErrorContains(t, err, "failed to write CycloneDX document: disk full")
+}
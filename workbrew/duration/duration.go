@@ -0,0 +1,56 @@
+// Package duration parses and formats the day and week durations accepted by
+// the SDK's specs, flags and queries, e.g. "7d" or "2w".
+package duration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Units beyond those of time.ParseDuration
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// Parse parses a Go duration, or a whole number of days ("7d") or weeks ("2w")
+//
+// Parameters:
+//   - value: The duration, e.g. "30d", "2w" or "36h"
+//
+// Returns:
+//   - time.Duration: The parsed duration
+//   - error: An error if the value is not a duration or is negative
+func Parse(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": Day, "w": Week} {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			count, err := strconv.Atoi(number)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// Format formats whole days as "7d" and other durations as time.Duration does
+//
+// Parameters:
+//   - d: The duration to format
+//
+// Returns:
+//   - string: e.g. "30d" or "36h0m0s"
+func Format(d time.Duration) string {
+	if d >= Day && d%Day == 0 {
+		return strconv.FormatInt(int64(d/Day), 10) + "d"
+	}
+	return d.String()
+}
@@ -0,0 +1,43 @@
+package duration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]time.Duration{
+		"7d":    7 * Day,
+		" 30d ": 30 * Day,
+		"2w":    2 * Week,
+		"0d":    0,
+		"36h":   36 * time.Hour,
+		"1h30m": 90 * time.Minute,
+	}
+	for input, expected := range tests {
+		d, err := Parse(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, d, input)
+	}
+
+	for _, input := range []string{"", "d", "-1d", "1.5d", "-1h", "soon"} {
+		_, err := Parse(input)
+		assert.ErrorContains(t, err, "invalid duration", input)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := map[time.Duration]string{
+		7 * Day:          "7d",
+		2 * Week:         "14d",
+		36 * time.Hour:   "36h0m0s",
+		90 * time.Minute: "1h30m0s",
+		0:                "0s",
+	}
+	for d, expected := range tests {
+		assert.Equal(t, expected, Format(d), d)
+	}
+}
@@ -0,0 +1,170 @@
+package eventstream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CheckpointVersion is the checkpoint file format version written by this package
+const CheckpointVersion = 1
+
+// Checkpoint records how far a Tailer has followed the audit log. It can track
+// any stream of items identified by an ID and ordered by time, e.g. vulnerability changes.
+type Checkpoint struct {
+	Version int `json:"version"`
+
+	// HighWater is the latest OccurredAt of any delivered event
+	HighWater time.Time `json:"high_water"`
+
+	// Seen holds the IDs of delivered events that occurred within the tailer's
+	// lookback window of HighWater, with their OccurredAt. Events with these IDs
+	// are not delivered again.
+	Seen map[string]time.Time `json:"seen"`
+
+	// UpdatedAt is when the checkpoint was last advanced
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewCheckpoint returns an empty checkpoint that accepts every item
+func NewCheckpoint() *Checkpoint {
+	return &Checkpoint{
+		Version: CheckpointVersion,
+		Seen:    make(map[string]time.Time),
+	}
+}
+
+// Accepts reports whether an item has not been recorded and occurred no earlier
+// than lookback before the high-water mark
+//
+// Parameters:
+//   - id: The item's unique ID
+//   - occurredAt: When the item occurred
+//   - lookback: How far before the high-water mark items are still accepted
+func (c *Checkpoint) Accepts(id string, occurredAt time.Time, lookback time.Duration) bool {
+	if _, seen := c.Seen[id]; seen {
+		return false
+	}
+	if c.HighWater.IsZero() {
+		return true
+	}
+	return !occurredAt.Before(c.HighWater.Add(-lookback))
+}
+
+// Record marks an item as delivered, advances the high-water mark, and forgets
+// IDs of items that occurred more than lookback before it
+//
+// Parameters:
+//   - id: The item's unique ID
+//   - occurredAt: When the item occurred
+//   - lookback: The lookback window passed to Accepts
+func (c *Checkpoint) Record(id string, occurredAt time.Time, lookback time.Duration) {
+	if c.Seen == nil {
+		c.Seen = make(map[string]time.Time)
+	}
+	c.Seen[id] = occurredAt
+	c.UpdatedAt = time.Now().UTC()
+	if !occurredAt.After(c.HighWater) {
+		return
+	}
+
+	c.HighWater = occurredAt
+	cutoff := c.HighWater.Add(-lookback)
+	for seenID, seenAt := range c.Seen {
+		if seenAt.Before(cutoff) {
+			delete(c.Seen, seenID)
+		}
+	}
+}
+
+// clone returns a deep copy of the checkpoint
+func (c *Checkpoint) clone() *Checkpoint {
+	copied := *c
+	copied.Seen = make(map[string]time.Time, len(c.Seen))
+	for id, occurredAt := range c.Seen {
+		copied.Seen[id] = occurredAt
+	}
+	return &copied
+}
+
+// CheckpointStore persists checkpoints between runs of a Tailer
+type CheckpointStore interface {
+	// Load returns the saved checkpoint, or nil if none has been saved yet
+	Load() (*Checkpoint, error)
+
+	// Save replaces the saved checkpoint
+	Save(checkpoint *Checkpoint) error
+}
+
+// FileCheckpointStore stores a checkpoint as a JSON file.
+// Saves write a temporary file and rename it, so a crash never leaves a partial checkpoint.
+type FileCheckpointStore struct {
+	Path string
+}
+
+// NewFileCheckpointStore creates a checkpoint store backed by the named file
+//
+// Parameters:
+//   - path: The checkpoint file; it is created on the first save
+//
+// Example:
+//
+//	store := eventstream.NewFileCheckpointStore("/var/lib/shipper/events.checkpoint.json")
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{Path: path}
+}
+
+// Load reads the checkpoint file. A missing file returns a nil checkpoint.
+func (s *FileCheckpointStore) Load() (*Checkpoint, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint file: %w", err)
+	}
+	if checkpoint.Version < 1 || checkpoint.Version > CheckpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %d (supported: 1-%d)", checkpoint.Version, CheckpointVersion)
+	}
+	if checkpoint.Seen == nil {
+		checkpoint.Seen = make(map[string]time.Time)
+	}
+
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint file atomically
+func (s *FileCheckpointStore) Save(checkpoint *Checkpoint) error {
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(append(data, '\n')); err != nil {
+		temp.Close()
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+
+	if err := os.Rename(temp.Name(), s.Path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint file: %w", err)
+	}
+	return nil
+}
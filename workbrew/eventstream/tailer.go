@@ -0,0 +1,305 @@
+package eventstream
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"go.uber.org/zap"
+)
+
+// Defaults used when Options leaves a field unset
+const (
+	DefaultInterval = 30 * time.Second
+	DefaultLookback = 5 * time.Minute
+)
+
+// Options configures a Tailer
+type Options struct {
+	// Filter restricts events by actor type: events.FilterUser, events.FilterSystem
+	// or events.FilterAll. Empty uses the API default.
+	Filter string
+
+	// Interval is the wait between polls. Defaults to DefaultInterval.
+	Interval time.Duration
+
+	// Lookback is how far before the high-water mark events are still accepted,
+	// to catch events that appear in the audit log out of order. Events in the
+	// window are de-duplicated by ID. Defaults to DefaultLookback; set a negative
+	// value to accept only events at or after the high-water mark.
+	Lookback time.Duration
+
+	// Checkpoints persists progress so a restarted tailer resumes where it stopped.
+	// Nil keeps progress in memory only.
+	Checkpoints CheckpointStore
+
+	// StartTime skips events that occurred before it. Zero delivers the whole
+	// audit log on the first poll without a saved checkpoint.
+	StartTime time.Time
+
+	// Logger receives progress messages. Defaults to a no-op logger.
+	Logger *zap.Logger
+}
+
+// Tailer follows the workspace audit log, delivering each event once
+type Tailer struct {
+	service events.EventsServiceInterface
+	options Options
+
+	mu         sync.Mutex
+	checkpoint *Checkpoint
+	err        error
+}
+
+// NewTailer creates a tailer using the given events service
+//
+// Parameters:
+//   - service: The events service (e.g. client.Events)
+//   - options: Tailer options; nil uses defaults with an in-memory checkpoint
+//
+// Example:
+//
+//	tailer := eventstream.NewTailer(client.Events, &eventstream.Options{
+//	    Checkpoints: eventstream.NewFileCheckpointStore("events.checkpoint.json"),
+//	})
+func NewTailer(service events.EventsServiceInterface, options *Options) *Tailer {
+	t := &Tailer{service: service}
+	if options != nil {
+		t.options = *options
+	}
+	if t.options.Interval <= 0 {
+		t.options.Interval = DefaultInterval
+	}
+	if t.options.Lookback == 0 {
+		t.options.Lookback = DefaultLookback
+	}
+	t.options.Lookback = max(t.options.Lookback, 0)
+	if t.options.Logger == nil {
+		t.options.Logger = zap.NewNop()
+	}
+	return t
+}
+
+// Poll fetches the audit log once and returns the events not delivered before,
+// oldest first. The events are marked as delivered in memory; call Save to
+// persist the checkpoint once they have been processed.
+//
+// Parameters:
+//   - ctx: Context for the API call
+//
+// Returns:
+//   - []events.Event: The new events
+//   - error: Any error loading the checkpoint or calling the API
+func (t *Tailer) Poll(ctx context.Context) ([]events.Event, error) {
+	fresh, err := t.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, event := range fresh {
+		t.markDelivered(event)
+	}
+	return fresh, nil
+}
+
+// Save persists the current checkpoint. It is a no-op without a CheckpointStore.
+func (t *Tailer) Save() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.options.Checkpoints == nil || t.checkpoint == nil {
+		return nil
+	}
+	if err := t.options.Checkpoints.Save(t.checkpoint); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Checkpoint returns a copy of the current checkpoint, or nil before the first poll
+func (t *Tailer) Checkpoint() *Checkpoint {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.checkpoint == nil {
+		return nil
+	}
+	return t.checkpoint.clone()
+}
+
+// Tail polls the audit log until ctx is done and delivers new events on the
+// returned channel, oldest first. The channel is closed when tailing stops; Err
+// then reports why.
+//
+// Each event is marked as delivered once it has been received from the channel,
+// and the checkpoint is saved after every poll and when tailing stops. A restarted
+// tailer may therefore redeliver events received after the last save, but never
+// skips one. Transient API errors (429, 5xx and network failures) are logged and
+// retried at the next interval; other errors stop tailing.
+//
+// Tail must not be called again until the previous channel is closed.
+//
+// Parameters:
+//   - ctx: Controls how long to tail
+//
+// Returns:
+//   - <-chan events.Event: The new events
+//
+// Example:
+//
+//	for event := range tailer.Tail(ctx) {
+//	    ship(event)
+//	}
+//	if err := tailer.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (t *Tailer) Tail(ctx context.Context) <-chan events.Event {
+	out := make(chan events.Event)
+
+	t.mu.Lock()
+	t.err = nil
+	t.mu.Unlock()
+
+	go func() {
+		defer close(out)
+		err := t.run(ctx, out)
+		if saveErr := t.Save(); err == nil {
+			err = saveErr
+		}
+
+		t.mu.Lock()
+		t.err = err
+		t.mu.Unlock()
+	}()
+
+	return out
+}
+
+// Err returns the error that stopped the last Tail, or nil if it stopped because
+// its context was done
+func (t *Tailer) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// run is the polling loop behind Tail
+func (t *Tailer) run(ctx context.Context, out chan<- events.Event) error {
+	if err := t.load(); err != nil {
+		return err
+	}
+
+	for {
+		fresh, err := t.fetch(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && !client.IsRetryableError(err):
+			return err
+		case err != nil:
+			t.options.Logger.Warn("Polling events failed, will retry", zap.Error(err))
+		default:
+			for _, event := range fresh {
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return nil
+				}
+				t.mu.Lock()
+				t.markDelivered(event)
+				t.mu.Unlock()
+			}
+			if len(fresh) > 0 {
+				t.options.Logger.Debug("Delivered events", zap.Int("count", len(fresh)))
+				if err := t.Save(); err != nil {
+					return err
+				}
+			}
+		}
+
+		timer := time.NewTimer(t.options.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// fetch lists events and returns those not yet delivered, oldest first
+func (t *Tailer) fetch(ctx context.Context) ([]events.Event, error) {
+	if err := t.load(); err != nil {
+		return nil, err
+	}
+
+	response, _, err := t.service.ListEvents(ctx, &events.RequestQueryOptions{Filter: t.options.Filter})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var fresh []events.Event
+	for _, event := range *response {
+		if t.isNew(event) {
+			fresh = append(fresh, event)
+		}
+	}
+
+	slices.SortStableFunc(fresh, func(a, b events.Event) int {
+		if c := a.OccurredAt.Compare(b.OccurredAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return slices.CompactFunc(fresh, func(a, b events.Event) bool { return a.ID == b.ID }), nil
+}
+
+// load reads the saved checkpoint on first use
+func (t *Tailer) load() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.checkpoint != nil {
+		return nil
+	}
+
+	var checkpoint *Checkpoint
+	if t.options.Checkpoints != nil {
+		var err error
+		if checkpoint, err = t.options.Checkpoints.Load(); err != nil {
+			return fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+	}
+	if checkpoint == nil {
+		checkpoint = NewCheckpoint()
+	} else {
+		t.options.Logger.Info("Resuming from checkpoint", zap.Time("high_water", checkpoint.HighWater))
+	}
+
+	t.checkpoint = checkpoint
+	return nil
+}
+
+// isNew reports whether an event has not been delivered, is not older than the
+// lookback window and did not occur before StartTime. Callers must hold t.mu.
+func (t *Tailer) isNew(event events.Event) bool {
+	if event.OccurredAt.Before(t.options.StartTime) {
+		return false
+	}
+	return t.checkpoint.Accepts(event.ID, event.OccurredAt, t.options.Lookback)
+}
+
+// markDelivered records an event in the checkpoint. Callers must hold t.mu.
+func (t *Tailer) markDelivered(event events.Event) {
+	t.checkpoint.Record(event.ID, event.OccurredAt, t.options.Lookback)
+}
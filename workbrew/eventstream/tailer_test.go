@@ -0,0 +1,191 @@
+package eventstream
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureEventTime is when the default fixture's only event occurred
+var fixtureEventTime = time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC)
+
+func newTestServer(t *testing.T) (*workbrewtest.Server, *workbrew.Client) {
+	t.Helper()
+
+	server := workbrewtest.NewServer()
+	t.Cleanup(server.Close)
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	return server, wb
+}
+
+// addEvent appends an event to the fake audit log
+func addEvent(t *testing.T, server *workbrewtest.Server, id string, occurredAt time.Time) {
+	t.Helper()
+	err := server.Update(workbrewtest.DefaultWorkspace, func(f *workbrewtest.Fixtures) {
+		f.Events = append(f.Events, events.Event{ID: id, EventType: "device.updated", OccurredAt: occurredAt})
+	})
+	require.NoError(t, err)
+}
+
+func eventIDs(list []events.Event) []string {
+	ids := make([]string, 0, len(list))
+	for _, event := range list {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestTailer_Poll(t *testing.T) {
+	server, wb := newTestServer(t)
+	ctx := context.Background()
+	tailer := NewTailer(wb.Events, &Options{Lookback: time.Minute})
+
+	first, err := tailer.Poll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, first, 1)
+
+	again, err := tailer.Poll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, again, "delivered events are not repeated")
+
+	// Delivered oldest first, regardless of the order in the audit log
+	addEvent(t, server, "b", fixtureEventTime.Add(2*time.Hour))
+	addEvent(t, server, "a", fixtureEventTime.Add(time.Hour))
+	next, err := tailer.Poll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, eventIDs(next))
+	assert.Equal(t, fixtureEventTime.Add(2*time.Hour), tailer.Checkpoint().HighWater)
+
+	// A late event within the lookback window is delivered; an older one is not
+	addEvent(t, server, "late", fixtureEventTime.Add(2*time.Hour-30*time.Second))
+	addEvent(t, server, "too-late", fixtureEventTime.Add(time.Hour+30*time.Minute))
+	late, err := tailer.Poll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"late"}, eventIDs(late))
+
+	// IDs outside the window are forgotten
+	assert.ElementsMatch(t, []string{"b", "late"}, keys(tailer.Checkpoint().Seen))
+}
+
+func TestTailer_StartTime(t *testing.T) {
+	server, wb := newTestServer(t)
+	addEvent(t, server, "recent", fixtureEventTime.Add(24*time.Hour))
+
+	tailer := NewTailer(wb.Events, &Options{StartTime: fixtureEventTime.Add(time.Hour)})
+	got, err := tailer.Poll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"recent"}, eventIDs(got))
+}
+
+func TestTailer_TailResumesFromCheckpoint(t *testing.T) {
+	server, wb := newTestServer(t)
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "events.checkpoint.json"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tailer := NewTailer(wb.Events, &Options{Checkpoints: store, Interval: 5 * time.Millisecond})
+	stream := tailer.Tail(ctx)
+
+	first := <-stream
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", first.ID)
+
+	addEvent(t, server, "second", fixtureEventTime.Add(time.Hour))
+	second := <-stream
+	assert.Equal(t, "second", second.ID)
+
+	cancel()
+	for range stream {
+	}
+	require.NoError(t, tailer.Err())
+
+	saved, err := store.Load()
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, fixtureEventTime.Add(time.Hour), saved.HighWater)
+	assert.Contains(t, saved.Seen, "second")
+
+	// A new tailer resumes after the delivered events
+	addEvent(t, server, "third", fixtureEventTime.Add(2*time.Hour))
+	resumed := NewTailer(wb.Events, &Options{Checkpoints: store})
+	got, err := resumed.Poll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"third"}, eventIDs(got))
+}
+
+func TestTailer_TailStopsOnAuthError(t *testing.T) {
+	server, wb := newTestServer(t)
+	server.InjectFault(workbrewtest.Fault{Path: "/events.json", StatusCode: 401})
+
+	tailer := NewTailer(wb.Events, &Options{Interval: 5 * time.Millisecond})
+	for range tailer.Tail(context.Background()) {
+		t.Fatal("no events expected")
+	}
+
+	require.Error(t, tailer.Err())
+	assert.ErrorContains(t, tailer.Err(), "failed to list events")
+}
+
+func TestTailer_TailRetriesServerErrors(t *testing.T) {
+	server, _ := newTestServer(t)
+	wb, err := server.NewClient(client.WithRetryCount(0))
+	require.NoError(t, err)
+	server.InjectFault(workbrewtest.Fault{Path: "/events.json", StatusCode: 502, Times: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tailer := NewTailer(wb.Events, &Options{Interval: 5 * time.Millisecond})
+	stream := tailer.Tail(ctx)
+
+	first := <-stream
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", first.ID)
+	cancel()
+	for range stream {
+	}
+	require.NoError(t, tailer.Err())
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	store := NewFileCheckpointStore(path)
+
+	missing, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	checkpoint := &Checkpoint{
+		Version:   CheckpointVersion,
+		HighWater: fixtureEventTime,
+		Seen:      map[string]time.Time{"a": fixtureEventTime},
+	}
+	require.NoError(t, store.Save(checkpoint))
+
+	loaded, err := store.Load()
+	require.NoError(t, err)
+	assert.True(t, checkpoint.HighWater.Equal(loaded.HighWater))
+	assert.Contains(t, loaded.Seen, "a")
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files are cleaned up")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 99}`), 0o600))
+	_, err = store.Load()
+	assert.ErrorContains(t, err, "unsupported checkpoint version 99")
+}
+
+func keys(m map[string]time.Time) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
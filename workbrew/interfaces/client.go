@@ -19,6 +19,22 @@ type Response struct {
 	Duration   time.Duration // Time taken for the request
 	ReceivedAt time.Time     // When the response was received
 	Size       int64         // Response body size in bytes
+	Attempts   int           // Number of attempts made, including retries
+
+	CacheStatus string        // CacheStatusHit, CacheStatusRevalidated, CacheStatusMiss, or "" when caching is disabled
+	CacheAge    time.Duration // Age of the cached response when served from the cache
+}
+
+// Cache statuses reported in Response.CacheStatus
+const (
+	CacheStatusHit         = "hit"         // Served from cache without contacting the API
+	CacheStatusRevalidated = "revalidated" // API answered 304 Not Modified and the cached body was used
+	CacheStatusMiss        = "miss"        // Fetched from the API and stored in the cache
+)
+
+// FromCache reports whether the response body was served from the response cache
+func (r *Response) FromCache() bool {
+	return r != nil && (r.CacheStatus == CacheStatusHit || r.CacheStatus == CacheStatusRevalidated)
 }
 
 // MultipartProgressCallback is a callback function for multipart upload progress
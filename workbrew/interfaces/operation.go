@@ -0,0 +1,19 @@
+package interfaces
+
+import "context"
+
+// operationKey is the context key for the name of the SDK operation being performed
+type operationKey struct{}
+
+// WithOperation annotates ctx with the SDK operation making the request, in
+// "<service>.<Method>" form (e.g. "brewfiles.UpdateBrewfile"). The transport
+// uses it to name trace spans and label metrics.
+func WithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// OperationFromContext returns the operation set by WithOperation, or "" if none was set
+func OperationFromContext(ctx context.Context) string {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	return operation
+}
@@ -0,0 +1,237 @@
+// Package licensepolicy enforces a license policy across the formulae installed in a workspace.
+//
+// A Policy lists SPDX license expressions to allow, review and deny. Each
+// installed formula's declared licenses are parsed as SPDX expressions and
+// evaluated against the policy: a conjunction (AND) is as restrictive as its
+// most restrictive license, and a disjunction (OR) as permissive as its most
+// permissive one, since the licensee may choose. The resulting Report lists the
+// formulae that violate the policy with their devices and device groups, and
+// can be written as JSON or JUnit XML to gate CI pipelines.
+package licensepolicy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operator joins the operands of a compound expression
+type Operator string
+
+// SPDX expression operators
+const (
+	OperatorAnd Operator = "AND"
+	OperatorOr  Operator = "OR"
+)
+
+// Expression is a parsed SPDX license expression: a Term or a Compound
+type Expression interface {
+	// String formats the expression in canonical SPDX syntax
+	String() string
+
+	// Terms returns every license term in the expression, in order
+	Terms() []Term
+}
+
+// Term is a single license, optionally with an exception, e.g. "GPL-2.0-only WITH Classpath-exception-2.0"
+type Term struct {
+	License   string
+	Exception string
+}
+
+// String formats the term in SPDX syntax
+func (t Term) String() string {
+	if t.Exception != "" {
+		return t.License + " WITH " + t.Exception
+	}
+	return t.License
+}
+
+// Terms returns the term itself
+func (t Term) Terms() []Term {
+	return []Term{t}
+}
+
+// Compound is two or more expressions joined by the same operator
+type Compound struct {
+	Operator Operator
+	Operands []Expression
+}
+
+// String formats the compound in SPDX syntax, parenthesising nested disjunctions
+func (c Compound) String() string {
+	parts := make([]string, len(c.Operands))
+	for i, operand := range c.Operands {
+		parts[i] = operand.String()
+		if nested, ok := operand.(Compound); ok && nested.Operator == OperatorOr && c.Operator == OperatorAnd {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+string(c.Operator)+" ")
+}
+
+// Terms returns the terms of every operand, in order
+func (c Compound) Terms() []Term {
+	var terms []Term
+	for _, operand := range c.Operands {
+		terms = append(terms, operand.Terms()...)
+	}
+	return terms
+}
+
+// ParseExpression parses an SPDX license expression such as
+// "(MIT OR Apache-2.0) AND GPL-2.0-only WITH Classpath-exception-2.0".
+// WITH binds tighter than AND, which binds tighter than OR. Operators are
+// matched case-insensitively.
+//
+// Parameters:
+//   - expression: The expression to parse
+//
+// Returns:
+//   - Expression: The parsed expression
+//   - error: If the expression is empty or malformed
+func ParseExpression(expression string) (Expression, error) {
+	p := &parser{tokens: tokenize(expression)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	parsed, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression %q: %w", expression, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid license expression %q: unexpected %q", expression, p.tokens[p.pos])
+	}
+	return parsed, nil
+}
+
+// parser is a recursive-descent parser over expression tokens
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+// parseOr parses a disjunction of conjunctions
+func (p *parser) parseOr() (Expression, error) {
+	return p.parseCompound(OperatorOr, p.parseAnd)
+}
+
+// parseAnd parses a conjunction of terms
+func (p *parser) parseAnd() (Expression, error) {
+	return p.parseCompound(OperatorAnd, p.parseTerm)
+}
+
+// parseCompound parses operands joined by operator, flattening them into one Compound
+func (p *parser) parseCompound(operator Operator, operand func() (Expression, error)) (Expression, error) {
+	var operands []Expression
+	for {
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if nested, ok := next.(Compound); ok && nested.Operator == operator {
+			operands = append(operands, nested.Operands...)
+		} else {
+			operands = append(operands, next)
+		}
+		if !p.accept(string(operator)) {
+			break
+		}
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return Compound{Operator: operator, Operands: operands}, nil
+}
+
+// parseTerm parses a parenthesised expression or a license with an optional exception
+func (p *parser) parseTerm() (Expression, error) {
+	token, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	if token == "(" {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return inner, nil
+	}
+	if !isIdentifier(token) {
+		return nil, fmt.Errorf("expected a license identifier, got %q", token)
+	}
+
+	term := Term{License: token}
+	if p.accept("WITH") {
+		exception, ok := p.next()
+		if !ok || !isIdentifier(exception) {
+			return nil, fmt.Errorf("expected an exception identifier after WITH")
+		}
+		term.Exception = exception
+	}
+	return term, nil
+}
+
+// next consumes and returns the next token
+func (p *parser) next() (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	p.pos++
+	return p.tokens[p.pos-1], true
+}
+
+// accept consumes the next token if it is the given operator or parenthesis
+func (p *parser) accept(token string) bool {
+	if p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], token) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// tokenize splits an expression into parentheses and words
+func tokenize(expression string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range expression {
+		switch {
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// isIdentifier reports whether a token is a license or exception identifier.
+// Besides SPDX ID characters, "*" and "?" are allowed for policy patterns and
+// ":" for DocumentRef prefixes.
+func isIdentifier(token string) bool {
+	switch strings.ToUpper(token) {
+	case "AND", "OR", "WITH":
+		return false
+	}
+	for _, r := range token {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '.' || r == '+' || r == ':' || r == '*' || r == '?':
+		default:
+			return false
+		}
+	}
+	return token != ""
+}
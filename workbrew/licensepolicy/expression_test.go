@@ -0,0 +1,72 @@
+package licensepolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		terms    int
+	}{
+		{"MIT", "MIT", 1},
+		{"MIT OR Apache-2.0", "MIT OR Apache-2.0", 2},
+		{"mit or apache-2.0 and isc", "mit OR apache-2.0 AND isc", 3},
+		{"(MIT OR Apache-2.0) AND BSD-3-Clause", "(MIT OR Apache-2.0) AND BSD-3-Clause", 3},
+		{"((MIT))", "MIT", 1},
+		{"MIT OR (ISC OR 0BSD)", "MIT OR ISC OR 0BSD", 3},
+		{"GPL-2.0-only WITH Classpath-exception-2.0 OR MIT", "GPL-2.0-only WITH Classpath-exception-2.0 OR MIT", 2},
+		{"GPL-2.0+", "GPL-2.0+", 1},
+		{"DocumentRef-spdx:LicenseRef-custom", "DocumentRef-spdx:LicenseRef-custom", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expression, err := ParseExpression(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, expression.String())
+			assert.Len(t, expression.Terms(), tt.terms)
+		})
+	}
+}
+
+func TestParseExpression_Precedence(t *testing.T) {
+	expression, err := ParseExpression("MIT OR Apache-2.0 AND GPL-2.0-only WITH Classpath-exception-2.0")
+	require.NoError(t, err)
+
+	or, ok := expression.(Compound)
+	require.True(t, ok)
+	assert.Equal(t, OperatorOr, or.Operator)
+	require.Len(t, or.Operands, 2)
+	assert.Equal(t, Term{License: "MIT"}, or.Operands[0])
+
+	and, ok := or.Operands[1].(Compound)
+	require.True(t, ok)
+	assert.Equal(t, OperatorAnd, and.Operator)
+	assert.Equal(t, []Expression{
+		Term{License: "Apache-2.0"},
+		Term{License: "GPL-2.0-only", Exception: "Classpath-exception-2.0"},
+	}, and.Operands)
+}
+
+func TestParseExpression_Errors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"   ",
+		"MIT OR",
+		"AND MIT",
+		"(MIT",
+		"MIT)",
+		"MIT Apache-2.0",
+		"MIT WITH",
+		"MIT/X11",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseExpression(input)
+			assert.Error(t, err)
+		})
+	}
+}
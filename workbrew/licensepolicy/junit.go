@@ -0,0 +1,145 @@
+package licensepolicy
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// JUnitSuiteName names the test suite in JUnit output
+const JUnitSuiteName = "workbrew-license-policy"
+
+// JUnitTestSuites is the root of a JUnit XML report
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite holds a test case per formula
+type JUnitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []JUnitProperty `xml:"properties>property,omitempty"`
+	TestCases  []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitProperty is a suite property
+type JUnitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// JUnitTestCase is the evaluation of one formula
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// JUnitFailure marks a formula that fails the check
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitSkipped marks a formula that requires review but does not fail the check
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// JUnit converts the report to a JUnit XML report with a test case per formula.
+// Failing formulae are failures, formulae requiring review that do not fail
+// the check are skipped, and allowed formulae pass.
+//
+// Returns:
+//   - *JUnitTestSuites: The JUnit report
+func (r *Report) JUnit() *JUnitTestSuites {
+	suite := JUnitTestSuite{
+		Name:      JUnitSuiteName,
+		Tests:     len(r.Results),
+		TestCases: []JUnitTestCase{},
+	}
+	if !r.GeneratedAt.IsZero() {
+		suite.Timestamp = r.GeneratedAt.UTC().Format(time.RFC3339)
+	}
+	if r.Workspace != "" {
+		suite.Properties = []JUnitProperty{{Name: "workspace", Value: r.Workspace}}
+	}
+
+	for _, result := range r.Results {
+		testCase := JUnitTestCase{
+			Name:      result.Formula,
+			ClassName: "workbrew.licenses." + string(result.Decision),
+			SystemOut: resultDetails(&result),
+		}
+		switch {
+		case r.Fails(&result):
+			suite.Failures++
+			testCase.Failure = &JUnitFailure{
+				Message: result.Reason,
+				Type:    string(result.Decision),
+				Text:    testCase.SystemOut,
+			}
+		case result.Decision == DecisionReview:
+			suite.Skipped++
+			testCase.Skipped = &JUnitSkipped{Message: result.Reason}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	return &JUnitTestSuites{
+		Name:     JUnitSuiteName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Suites:   []JUnitTestSuite{suite},
+	}
+}
+
+// WriteJUnit writes the report as indented JUnit XML
+func (r *Report) WriteJUnit(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(r.JUnit()); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// resultDetails describes a result's license, devices and groups
+func resultDetails(result *Result) string {
+	var b strings.Builder
+	license := result.Expression
+	if license == "" {
+		license = strings.Join(result.Licenses, ", ")
+	}
+	if license == "" {
+		license = "none declared"
+	}
+	fmt.Fprintf(&b, "License: %s\n", license)
+	fmt.Fprintf(&b, "Decision: %s (%s)\n", result.Decision, result.Reason)
+	fmt.Fprintf(&b, "Devices: %s\n", strings.Join(result.Devices, ", "))
+	if len(result.Groups) > 0 {
+		fmt.Fprintf(&b, "Groups: %s\n", strings.Join(result.Groups, ", "))
+	}
+	return b.String()
+}
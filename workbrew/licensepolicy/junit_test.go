@@ -0,0 +1,64 @@
+package licensepolicy
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_JUnit(t *testing.T) {
+	junit := testReport(t, &Options{Workspace: "test-workspace"}).JUnit()
+
+	assert.Equal(t, JUnitSuiteName, junit.Name)
+	assert.Equal(t, 3, junit.Tests)
+	assert.Equal(t, 1, junit.Failures)
+	assert.Equal(t, 1, junit.Skipped)
+
+	require.Len(t, junit.Suites, 1)
+	suite := junit.Suites[0]
+	assert.Equal(t, []JUnitProperty{{Name: "workspace", Value: "test-workspace"}}, suite.Properties)
+	assert.NotEmpty(t, suite.Timestamp)
+	require.Len(t, suite.TestCases, 3)
+
+	wget := suite.TestCases[0]
+	assert.Equal(t, "wget", wget.Name)
+	assert.Equal(t, "workbrew.licenses.deny", wget.ClassName)
+	require.NotNil(t, wget.Failure)
+	assert.Equal(t, "denied: GPL-3.0-or-later", wget.Failure.Message)
+	assert.Equal(t, "deny", wget.Failure.Type)
+	assert.Contains(t, wget.Failure.Text, "Devices: TC6R2DHVHG\n")
+	assert.Contains(t, wget.Failure.Text, "Groups: Admin, All Devices\n")
+
+	curl := suite.TestCases[1]
+	assert.Nil(t, curl.Failure)
+	require.NotNil(t, curl.Skipped)
+
+	actionlint := suite.TestCases[2]
+	assert.Nil(t, actionlint.Failure)
+	assert.Nil(t, actionlint.Skipped)
+}
+
+func TestReport_JUnit_FailOnReview(t *testing.T) {
+	junit := testReport(t, &Options{FailOnReview: true}).JUnit()
+
+	assert.Equal(t, 2, junit.Failures)
+	assert.Equal(t, 0, junit.Skipped)
+	assert.Equal(t, "review", junit.Suites[0].TestCases[1].Failure.Type)
+}
+
+func TestReport_WriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testReport(t, nil).WriteJUnit(&buf))
+
+	assert.True(t, strings.HasPrefix(buf.String(), xml.Header))
+	assert.Contains(t, buf.String(), `<testcase name="wget" classname="workbrew.licenses.deny">`)
+
+	var decoded JUnitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, 3, decoded.Tests)
+	assert.Len(t, decoded.Suites[0].TestCases, 3)
+}
@@ -0,0 +1,360 @@
+package licensepolicy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Decision is the outcome of evaluating a license against a policy
+type Decision string
+
+// Decisions, from most to least permissive
+const (
+	DecisionAllow  Decision = "allow"
+	DecisionReview Decision = "review"
+	DecisionDeny   Decision = "deny"
+)
+
+// rank orders decisions from most (0) to least permissive
+func (d Decision) rank() int {
+	switch d {
+	case DecisionAllow:
+		return 0
+	case DecisionReview:
+		return 1
+	}
+	return 2
+}
+
+// ParseDecision parses a decision name: "allow", "review" or "deny"
+func ParseDecision(name string) (Decision, error) {
+	switch decision := Decision(strings.ToLower(name)); decision {
+	case DecisionAllow, DecisionReview, DecisionDeny:
+		return decision, nil
+	}
+	return "", fmt.Errorf("unknown decision %q (expected allow, review or deny)", name)
+}
+
+// Policy lists the licenses to allow, review and deny.
+//
+// Entries are SPDX license expressions; every license named in an entry is
+// added to the list, so "MIT OR ISC" is equivalent to separate "MIT" and "ISC"
+// entries. License identifiers match case-insensitively and may use the
+// wildcards "*" and "?", e.g. "AGPL-*". An entry with an exception, such as
+// "GPL-2.0-only WITH Classpath-exception-2.0", matches only that combination
+// and takes precedence over entries for the bare license. When a license
+// matches several lists, deny wins over review, and review over allow.
+type Policy struct {
+	Allow  []string `json:"allow,omitempty"`
+	Review []string `json:"review,omitempty"`
+	Deny   []string `json:"deny,omitempty"`
+
+	// Unlisted is the decision for licenses in no list. Defaults to review;
+	// set it to deny to treat Allow as an allowlist.
+	Unlisted Decision `json:"unlisted,omitempty"`
+
+	// Unlicensed is the decision for formulae that declare no license. Defaults to review.
+	Unlicensed Decision `json:"unlicensed,omitempty"`
+
+	// Exempt lists formula names, which may use path.Match wildcards, that are
+	// allowed whatever their license, e.g. for approved waivers
+	Exempt []string `json:"exempt,omitempty"`
+}
+
+// ReadPolicy decodes a JSON policy
+//
+// Example policy:
+//
+//	{
+//	  "allow": ["MIT", "Apache-2.0", "BSD-*"],
+//	  "review": ["LGPL-*", "MPL-2.0"],
+//	  "deny": ["AGPL-*", "GPL-3.0-only OR GPL-3.0-or-later"],
+//	  "unlisted": "review",
+//	  "exempt": ["git"]
+//	}
+func ReadPolicy(r io.Reader) (*Policy, error) {
+	var policy Policy
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("failed to decode license policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// LoadPolicy reads a JSON policy from the named file
+func LoadPolicy(path string) (*Policy, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open license policy: %w", err)
+	}
+	defer file.Close()
+
+	return ReadPolicy(file)
+}
+
+// Validate checks every entry parses and every pattern and decision is valid
+func (p *Policy) Validate() error {
+	_, err := NewEngine(p)
+	return err
+}
+
+// Verdict is the evaluation of one formula's licenses
+type Verdict struct {
+	Decision Decision `json:"decision"`
+
+	// Reason explains the decision, naming the licenses that determined it
+	Reason string `json:"reason"`
+
+	// Expression is the canonical SPDX expression evaluated, if any
+	Expression string `json:"expression,omitempty"`
+
+	// Terms are the decisions for each license in the expression
+	Terms []TermVerdict `json:"terms,omitempty"`
+}
+
+// TermVerdict is the decision for one license term
+type TermVerdict struct {
+	License  string   `json:"license"`
+	Decision Decision `json:"decision"`
+
+	// Rule is the policy entry that matched, or empty for unlisted licenses
+	Rule string `json:"rule,omitempty"`
+}
+
+// Engine evaluates licenses against a compiled policy
+type Engine struct {
+	rules      []rule
+	unlisted   Decision
+	unlicensed Decision
+	exempt     []string
+}
+
+// rule is a single license pattern from a policy list
+type rule struct {
+	decision  Decision
+	license   string
+	exception string
+	entry     string
+}
+
+// matches reports whether the rule matches a term, and whether it matched on its exception
+func (r *rule) matches(term Term) (bool, bool) {
+	if !globMatch(r.license, term.License) {
+		return false, false
+	}
+	if r.exception == "" {
+		return true, false
+	}
+	return term.Exception != "" && globMatch(r.exception, term.Exception), true
+}
+
+// NewEngine compiles a policy
+//
+// Parameters:
+//   - policy: The policy to enforce
+//
+// Returns:
+//   - *Engine: The compiled policy
+//   - error: If an entry does not parse, or a pattern or decision is invalid
+func NewEngine(policy *Policy) (*Engine, error) {
+	if policy == nil {
+		return nil, fmt.Errorf("license policy is required")
+	}
+
+	engine := &Engine{
+		unlisted:   DecisionReview,
+		unlicensed: DecisionReview,
+		exempt:     policy.Exempt,
+	}
+	for _, setting := range []struct {
+		name  string
+		value Decision
+		into  *Decision
+	}{
+		{"unlisted", policy.Unlisted, &engine.unlisted},
+		{"unlicensed", policy.Unlicensed, &engine.unlicensed},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		decision, err := ParseDecision(string(setting.value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s decision: %w", setting.name, err)
+		}
+		*setting.into = decision
+	}
+
+	for _, list := range []struct {
+		decision Decision
+		entries  []string
+	}{
+		{DecisionDeny, policy.Deny},
+		{DecisionReview, policy.Review},
+		{DecisionAllow, policy.Allow},
+	} {
+		for _, entry := range list.entries {
+			expression, err := parseLicense(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s entry: %w", list.decision, err)
+			}
+			for _, term := range expression.Terms() {
+				r := rule{
+					decision:  list.decision,
+					license:   strings.ToLower(term.License),
+					exception: strings.ToLower(term.Exception),
+					entry:     entry,
+				}
+				if _, err := path.Match(r.license, ""); err != nil {
+					return nil, fmt.Errorf("invalid %s pattern %q: %w", list.decision, term.License, err)
+				}
+				if _, err := path.Match(r.exception, ""); err != nil {
+					return nil, fmt.Errorf("invalid %s pattern %q: %w", list.decision, term.Exception, err)
+				}
+				engine.rules = append(engine.rules, r)
+			}
+		}
+	}
+
+	for _, pattern := range policy.Exempt {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exempt pattern %q: %w", pattern, err)
+		}
+	}
+
+	return engine, nil
+}
+
+// Decide evaluates a formula's declared licenses. Multiple licenses are
+// combined with AND, as the API does not say whether they are alternatives.
+// Licenses that do not parse as SPDX expressions are sent for review.
+//
+// Parameters:
+//   - formula: The formula name, checked against the exempt list
+//   - licenses: The formula's declared licenses
+//
+// Returns:
+//   - Verdict: The decision and the licenses that determined it
+func (e *Engine) Decide(formula string, licenses []string) Verdict {
+	for _, pattern := range e.exempt {
+		if ok, _ := path.Match(pattern, formula); ok {
+			return Verdict{Decision: DecisionAllow, Reason: fmt.Sprintf("%s is exempt from the license policy", formula)}
+		}
+	}
+
+	var operands []Expression
+	for _, license := range licenses {
+		if strings.TrimSpace(license) == "" {
+			continue
+		}
+		expression, err := parseLicense(license)
+		if err != nil {
+			return Verdict{Decision: DecisionReview, Reason: err.Error(), Expression: license}
+		}
+		operands = append(operands, expression)
+	}
+	if len(operands) == 0 {
+		return Verdict{Decision: e.unlicensed, Reason: "no license is declared"}
+	}
+
+	expression := operands[0]
+	if len(operands) > 1 {
+		expression = Compound{Operator: OperatorAnd, Operands: operands}
+	}
+
+	verdict := Verdict{Expression: expression.String()}
+	verdict.Decision = e.evaluate(expression, &verdict.Terms)
+
+	var deciding []string
+	for _, term := range verdict.Terms {
+		if term.Decision == verdict.Decision && !slices.Contains(deciding, term.License) {
+			deciding = append(deciding, term.License)
+		}
+	}
+	switch verdict.Decision {
+	case DecisionAllow:
+		verdict.Reason = "allowed: " + strings.Join(deciding, ", ")
+	case DecisionReview:
+		verdict.Reason = "review required: " + strings.Join(deciding, ", ")
+	case DecisionDeny:
+		verdict.Reason = "denied: " + strings.Join(deciding, ", ")
+	}
+	return verdict
+}
+
+// evaluate decides an expression, recording the decision for each term
+func (e *Engine) evaluate(expression Expression, terms *[]TermVerdict) Decision {
+	switch expr := expression.(type) {
+	case Term:
+		verdict := e.decideTerm(expr)
+		*terms = append(*terms, verdict)
+		return verdict.Decision
+	case Compound:
+		var result Decision
+		for i, operand := range expr.Operands {
+			decision := e.evaluate(operand, terms)
+			if i == 0 ||
+				(expr.Operator == OperatorAnd && decision.rank() > result.rank()) ||
+				(expr.Operator == OperatorOr && decision.rank() < result.rank()) {
+				result = decision
+			}
+		}
+		return result
+	}
+	return DecisionReview
+}
+
+// decideTerm finds the most specific, most restrictive rule matching a term
+func (e *Engine) decideTerm(term Term) TermVerdict {
+	lower := Term{License: strings.ToLower(term.License), Exception: strings.ToLower(term.Exception)}
+
+	var general *rule
+	for i := range e.rules {
+		r := &e.rules[i]
+		matched, specific := r.matches(lower)
+		if !matched {
+			continue
+		}
+		// Rules are ordered deny, review, allow, so the first match of each kind is the most restrictive
+		if specific {
+			return TermVerdict{License: term.String(), Decision: r.decision, Rule: r.entry}
+		}
+		if general == nil {
+			general = r
+		}
+	}
+	if general != nil {
+		return TermVerdict{License: term.String(), Decision: general.decision, Rule: general.entry}
+	}
+	return TermVerdict{License: term.String(), Decision: e.unlisted}
+}
+
+// parseLicense parses an SPDX expression. A license name with no operators,
+// such as "Public Domain", is accepted as a single term.
+func parseLicense(license string) (Expression, error) {
+	expression, err := ParseExpression(license)
+	if err == nil {
+		return expression, nil
+	}
+	for _, token := range tokenize(license) {
+		switch strings.ToUpper(token) {
+		case "AND", "OR", "WITH", "(", ")":
+			return nil, err
+		}
+	}
+	return Term{License: strings.Join(tokenize(license), " ")}, nil
+}
+
+// globMatch matches a lower-case license pattern against a license identifier
+func globMatch(pattern, license string) bool {
+	ok, _ := path.Match(pattern, license)
+	return ok
+}
@@ -0,0 +1,127 @@
+package licensepolicy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEngine(t *testing.T) *Engine {
+	t.Helper()
+
+	engine, err := NewEngine(&Policy{
+		Allow:  []string{"MIT", "Apache-2.0", "BSD-*", "GPL-2.0-only WITH Classpath-exception-2.0"},
+		Review: []string{"LGPL-* OR MPL-2.0", "curl"},
+		Deny:   []string{"AGPL-*", "GPL-*"},
+		Exempt: []string{"git*"},
+	})
+	require.NoError(t, err)
+	return engine
+}
+
+func TestEngine_Decide(t *testing.T) {
+	engine := testEngine(t)
+
+	tests := []struct {
+		name     string
+		licenses []string
+		decision Decision
+		reason   string
+	}{
+		{"allowed", []string{"MIT"}, DecisionAllow, "allowed: MIT"},
+		{"case insensitive", []string{"apache-2.0"}, DecisionAllow, "allowed: apache-2.0"},
+		{"wildcard", []string{"BSD-3-Clause"}, DecisionAllow, "allowed: BSD-3-Clause"},
+		{"review", []string{"curl"}, DecisionReview, "review required: curl"},
+		{"denied", []string{"GPL-3.0-or-later"}, DecisionDeny, "denied: GPL-3.0-or-later"},
+		{"unlisted", []string{"Zlib"}, DecisionReview, "review required: Zlib"},
+		{"or picks most permissive", []string{"GPL-3.0-only OR MIT"}, DecisionAllow, "allowed: MIT"},
+		{"and picks least permissive", []string{"MIT AND LGPL-2.1-only"}, DecisionReview, "review required: LGPL-2.1-only"},
+		{"multiple licenses are combined with and", []string{"MIT", "AGPL-3.0-only"}, DecisionDeny, "denied: AGPL-3.0-only"},
+		{"exception entry is more specific", []string{"GPL-2.0-only WITH Classpath-exception-2.0"}, DecisionAllow, "allowed: GPL-2.0-only WITH Classpath-exception-2.0"},
+		{"other exceptions fall back to the license", []string{"GPL-2.0-only WITH GCC-exception-3.1"}, DecisionDeny, "denied: GPL-2.0-only WITH GCC-exception-3.1"},
+		{"unlicensed", nil, DecisionReview, "no license is declared"},
+		{"non-SPDX name", []string{"Public Domain"}, DecisionReview, "review required: Public Domain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := engine.Decide("jq", tt.licenses)
+			assert.Equal(t, tt.decision, verdict.Decision)
+			assert.Equal(t, tt.reason, verdict.Reason)
+		})
+	}
+
+	verdict := engine.Decide("jq", []string{"MIT OR", "MIT"})
+	assert.Equal(t, DecisionReview, verdict.Decision)
+	assert.Contains(t, verdict.Reason, "invalid license expression")
+
+	verdict = engine.Decide("git-lfs", []string{"AGPL-3.0-only"})
+	assert.Equal(t, DecisionAllow, verdict.Decision)
+	assert.Equal(t, "git-lfs is exempt from the license policy", verdict.Reason)
+}
+
+func TestEngine_Decide_Terms(t *testing.T) {
+	verdict := testEngine(t).Decide("jq", []string{"(MIT OR GPL-3.0-only) AND Zlib"})
+
+	assert.Equal(t, "(MIT OR GPL-3.0-only) AND Zlib", verdict.Expression)
+	assert.Equal(t, []TermVerdict{
+		{License: "MIT", Decision: DecisionAllow, Rule: "MIT"},
+		{License: "GPL-3.0-only", Decision: DecisionDeny, Rule: "GPL-*"},
+		{License: "Zlib", Decision: DecisionReview},
+	}, verdict.Terms)
+	assert.Equal(t, DecisionReview, verdict.Decision)
+}
+
+func TestEngine_Defaults(t *testing.T) {
+	engine, err := NewEngine(&Policy{Allow: []string{"MIT"}, Unlisted: "deny", Unlicensed: "allow"})
+	require.NoError(t, err)
+
+	assert.Equal(t, DecisionDeny, engine.Decide("jq", []string{"ISC"}).Decision)
+	assert.Equal(t, DecisionAllow, engine.Decide("jq", nil).Decision)
+}
+
+func TestNewEngine_Errors(t *testing.T) {
+	for name, policy := range map[string]*Policy{
+		"nil":        nil,
+		"entry":      {Deny: []string{"MIT OR"}},
+		"unlisted":   {Unlisted: "block"},
+		"unlicensed": {Unlicensed: "maybe"},
+		"exempt":     {Exempt: []string{"[git"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewEngine(policy)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"allow": ["MIT"], "deny": ["AGPL-*"], "unlisted": "deny"}`), 0o644))
+
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MIT"}, policy.Allow)
+	assert.Equal(t, DecisionDeny, policy.Unlisted)
+
+	_, err = ReadPolicy(strings.NewReader(`{"allowed": ["MIT"]}`))
+	assert.ErrorContains(t, err, "unknown field")
+
+	_, err = ReadPolicy(strings.NewReader(`{"deny": ["(MIT"]}`))
+	assert.ErrorContains(t, err, "invalid deny entry")
+
+	_, err = LoadPolicy(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestParseDecision(t *testing.T) {
+	decision, err := ParseDecision("DENY")
+	require.NoError(t, err)
+	assert.Equal(t, DecisionDeny, decision)
+
+	_, err = ParseDecision("block")
+	assert.Error(t, err)
+}
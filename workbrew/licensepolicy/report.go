@@ -0,0 +1,192 @@
+package licensepolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+)
+
+// Options configures evaluation
+type Options struct {
+	// Workspace is recorded in the report
+	Workspace string
+
+	// FailOnReview makes formulae that require review fail the check, as well as denied ones
+	FailOnReview bool
+}
+
+// Result is the evaluation of one installed formula
+type Result struct {
+	Formula  string   `json:"formula"`
+	Licenses []string `json:"licenses"`
+	Verdict
+
+	// Devices are the serial numbers of the devices the formula is installed on
+	Devices []string `json:"devices"`
+
+	// Groups are the device groups containing those devices
+	Groups []string `json:"groups,omitempty"`
+}
+
+// Summary counts results by decision
+type Summary struct {
+	Formulae int `json:"formulae"`
+	Allowed  int `json:"allowed"`
+	Review   int `json:"review"`
+	Denied   int `json:"denied"`
+}
+
+// Report is the evaluation of every installed formula against a policy
+type Report struct {
+	Workspace    string    `json:"workspace,omitempty"`
+	GeneratedAt  time.Time `json:"generated_at"`
+	FailOnReview bool      `json:"fail_on_review"`
+	Passed       bool      `json:"passed"`
+	Summary      Summary   `json:"summary"`
+
+	// Results are sorted by decision, least permissive first, then by formula
+	Results []Result `json:"results"`
+}
+
+// Evaluate lists installed formulae and evaluates their licenses against a policy
+//
+// Parameters:
+//   - ctx: Context for the underlying API calls
+//   - formulaeService: The formulae service
+//   - groupsService: The device groups service used to report affected groups; may be nil
+//   - engine: The compiled policy
+//   - options: Evaluation options; may be nil
+//
+// Returns:
+//   - *Report: The evaluation of every formula
+//   - error: Any error returned by the API
+//
+// Example:
+//
+//	engine, err := licensepolicy.NewEngine(policy)
+//	report, err := licensepolicy.Evaluate(ctx, client.Formulae, client.DeviceGroups, engine, nil)
+//	if !report.Passed {
+//	    for _, result := range report.Violations() { ... }
+//	}
+func Evaluate(ctx context.Context, formulaeService formulae.FormulaeServiceInterface, groupsService devicegroups.DeviceGroupsServiceInterface, engine *Engine, options *Options) (*Report, error) {
+	if formulaeService == nil || engine == nil {
+		return nil, fmt.Errorf("formulae service and policy engine are required")
+	}
+
+	formulaList, _, err := formulaeService.ListFormulae(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list formulae: %w", err)
+	}
+
+	var groups devicegroups.DeviceGroupsResponse
+	if groupsService != nil {
+		groupList, _, err := groupsService.ListDeviceGroups(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list device groups: %w", err)
+		}
+		groups = *groupList
+	}
+
+	report := Build(*formulaList, groups, engine, options)
+	report.GeneratedAt = time.Now().UTC()
+	return report, nil
+}
+
+// Build evaluates formulae from responses already fetched, e.g. from a snapshot.
+// GeneratedAt is left zero.
+//
+// Parameters:
+//   - formulaList: The installed formulae
+//   - groups: Device groups used to report affected groups; may be nil
+//   - engine: The compiled policy
+//   - options: Evaluation options; may be nil
+func Build(formulaList formulae.FormulaeResponse, groups devicegroups.DeviceGroupsResponse, engine *Engine, options *Options) *Report {
+	if options == nil {
+		options = &Options{}
+	}
+
+	deviceGroups := make(map[string][]string)
+	for _, group := range groups {
+		for _, serial := range group.Devices {
+			deviceGroups[serial] = append(deviceGroups[serial], group.Name)
+		}
+	}
+
+	report := &Report{
+		Workspace:    options.Workspace,
+		FailOnReview: options.FailOnReview,
+		Results:      []Result{},
+	}
+	for _, formula := range formulaList {
+		result := Result{
+			Formula:  formula.Name,
+			Licenses: []string{},
+			Devices:  slices.Clone(formula.Devices),
+		}
+		if formula.License != nil {
+			result.Licenses = *formula.License
+		}
+		result.Verdict = engine.Decide(formula.Name, result.Licenses)
+		slices.Sort(result.Devices)
+		for _, serial := range result.Devices {
+			result.Groups = append(result.Groups, deviceGroups[serial]...)
+		}
+		slices.Sort(result.Groups)
+		result.Groups = slices.Compact(result.Groups)
+
+		switch result.Decision {
+		case DecisionAllow:
+			report.Summary.Allowed++
+		case DecisionReview:
+			report.Summary.Review++
+		case DecisionDeny:
+			report.Summary.Denied++
+		}
+		report.Results = append(report.Results, result)
+	}
+	report.Summary.Formulae = len(report.Results)
+
+	slices.SortStableFunc(report.Results, func(a, b Result) int {
+		if a.Decision != b.Decision {
+			return b.Decision.rank() - a.Decision.rank()
+		}
+		return strings.Compare(a.Formula, b.Formula)
+	})
+	report.Passed = len(report.Violations()) == 0
+
+	return report
+}
+
+// Fails reports whether a result fails the check
+func (r *Report) Fails(result *Result) bool {
+	return result.Decision == DecisionDeny || (r.FailOnReview && result.Decision == DecisionReview)
+}
+
+// Violations returns the results that fail the check: denied formulae, and
+// formulae requiring review when FailOnReview is set
+func (r *Report) Violations() []Result {
+	var violations []Result
+	for _, result := range r.Results {
+		if r.Fails(&result) {
+			violations = append(violations, result)
+		}
+	}
+	return violations
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package licensepolicy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testReport evaluates the default fake workspace against a policy that
+// allows MIT, denies GPL licenses and leaves curl's license for review
+func testReport(t *testing.T, options *Options) *Report {
+	t.Helper()
+
+	server := workbrewtest.NewServer()
+	t.Cleanup(server.Close)
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	engine, err := NewEngine(&Policy{Allow: []string{"MIT"}, Deny: []string{"GPL-*"}})
+	require.NoError(t, err)
+
+	report, err := Evaluate(context.Background(), wb.Formulae, wb.DeviceGroups, engine, options)
+	require.NoError(t, err)
+	return report
+}
+
+func TestEvaluate(t *testing.T) {
+	report := testReport(t, &Options{Workspace: workbrewtest.DefaultWorkspace})
+
+	assert.Equal(t, workbrewtest.DefaultWorkspace, report.Workspace)
+	assert.False(t, report.GeneratedAt.IsZero())
+	assert.False(t, report.Passed)
+	assert.Equal(t, Summary{Formulae: 3, Allowed: 1, Review: 1, Denied: 1}, report.Summary)
+
+	// Least permissive first
+	require.Len(t, report.Results, 3)
+	wget := report.Results[0]
+	assert.Equal(t, "wget", wget.Formula)
+	assert.Equal(t, DecisionDeny, wget.Decision)
+	assert.Equal(t, []string{"GPL-3.0-or-later"}, wget.Licenses)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceMacBook}, wget.Devices)
+	assert.Equal(t, []string{"Admin", "All Devices"}, wget.Groups)
+
+	curl := report.Results[1]
+	assert.Equal(t, "curl", curl.Formula)
+	assert.Equal(t, DecisionReview, curl.Decision)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceLinux, workbrewtest.FixtureDeviceMacBook}, curl.Devices)
+
+	assert.Equal(t, "actionlint", report.Results[2].Formula)
+	assert.Equal(t, DecisionAllow, report.Results[2].Decision)
+
+	violations := report.Violations()
+	require.Len(t, violations, 1)
+	assert.Equal(t, "wget", violations[0].Formula)
+}
+
+func TestEvaluate_FailOnReview(t *testing.T) {
+	report := testReport(t, &Options{FailOnReview: true})
+
+	assert.False(t, report.Passed)
+	assert.Len(t, report.Violations(), 2)
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	_, err := Evaluate(context.Background(), nil, nil, nil, nil)
+	assert.Error(t, err)
+
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	engine, err := NewEngine(&Policy{})
+	require.NoError(t, err)
+
+	server.InjectFault(workbrewtest.Fault{Path: "/device_groups.json", StatusCode: 403})
+	_, err = Evaluate(context.Background(), wb.Formulae, wb.DeviceGroups, engine, nil)
+	assert.ErrorContains(t, err, "failed to list device groups")
+}
+
+func TestBuild_Passed(t *testing.T) {
+	engine, err := NewEngine(&Policy{Allow: []string{"*"}})
+	require.NoError(t, err)
+
+	report := Build(nil, devicegroups.DeviceGroupsResponse{}, engine, nil)
+	assert.True(t, report.Passed)
+	assert.Empty(t, report.Results)
+	assert.Empty(t, report.Violations())
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testReport(t, nil).WriteJSON(&buf))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Equal(t, false, document["passed"])
+
+	results := document["results"].([]any)
+	require.Len(t, results, 3)
+	wget := results[0].(map[string]any)
+	assert.Equal(t, "wget", wget["formula"])
+	assert.Equal(t, "deny", wget["decision"])
+	assert.Equal(t, "denied: GPL-3.0-or-later", wget["reason"])
+	assert.Equal(t, "GPL-3.0-or-later", wget["expression"])
+}
@@ -126,6 +126,10 @@ func NewClientFromEnv(options ...client.ClientOption) (*Client, error) {
 // SetWorkspace changes the active workspace for all subsequent API calls.
 // This updates the base URL to target the specified workspace.
 //
+// The change affects every goroutine using the client, including calls already
+// in flight. To work with several workspaces at once, use a WorkspaceSet, which
+// gives each workspace its own client.
+//
 // Parameters:
 //   - workspaceName: The name of the workspace to switch to
 //
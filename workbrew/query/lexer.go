@@ -0,0 +1,137 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenType classifies a token of an expression
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token is a lexed token and its byte offset in the expression
+type token struct {
+	typ  tokenType
+	text string
+	num  float64
+	pos  int
+}
+
+// String describes the token in error messages
+func (t token) String() string {
+	if t.typ == tokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// Error is a syntax or type error in an expression
+type Error struct {
+	// Expr is the expression being compiled
+	Expr string
+
+	// Pos is the byte offset of the error in Expr
+	Pos int
+
+	// Message describes the error
+	Message string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid query %q: %s at position %d", e.Expr, e.Message, e.Pos+1)
+}
+
+// operators are matched longest first
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "!~", "<", ">", "!", "~"}
+
+// lex splits an expression into tokens, ending with a tokenEOF
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	for pos := 0; pos < len(expr); {
+		c := expr[pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pos++
+
+		case c == '(':
+			tokens = append(tokens, token{typ: tokenLParen, text: "(", pos: pos})
+			pos++
+		case c == ')':
+			tokens = append(tokens, token{typ: tokenRParen, text: ")", pos: pos})
+			pos++
+		case c == ',':
+			tokens = append(tokens, token{typ: tokenComma, text: ",", pos: pos})
+			pos++
+
+		case c == '"' || c == '\'':
+			end := pos + 1
+			for end < len(expr) && expr[end] != c {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, &Error{Expr: expr, Pos: pos, Message: "unterminated string"}
+			}
+			raw := expr[pos+1 : end]
+			if c == '\'' {
+				raw = strings.ReplaceAll(strings.ReplaceAll(raw, `\'`, `'`), `"`, `\"`)
+			}
+			text, err := strconv.Unquote(`"` + raw + `"`)
+			if err != nil {
+				return nil, &Error{Expr: expr, Pos: pos, Message: "invalid escape in string"}
+			}
+			tokens = append(tokens, token{typ: tokenString, text: text, pos: pos})
+			pos = end + 1
+
+		case c >= '0' && c <= '9' || c == '-' && pos+1 < len(expr) && expr[pos+1] >= '0' && expr[pos+1] <= '9':
+			end := pos + 1
+			for end < len(expr) && (expr[end] >= '0' && expr[end] <= '9' || expr[end] == '.') {
+				end++
+			}
+			num, err := strconv.ParseFloat(expr[pos:end], 64)
+			if err != nil {
+				return nil, &Error{Expr: expr, Pos: pos, Message: fmt.Sprintf("invalid number %q", expr[pos:end])}
+			}
+			tokens = append(tokens, token{typ: tokenNumber, text: expr[pos:end], num: num, pos: pos})
+			pos = end
+
+		case c == '_' || unicode.IsLetter(rune(c)):
+			end := pos + 1
+			for end < len(expr) && (expr[end] == '_' || expr[end] == '.' || unicode.IsLetter(rune(expr[end])) || unicode.IsDigit(rune(expr[end]))) {
+				end++
+			}
+			tokens = append(tokens, token{typ: tokenIdent, text: expr[pos:end], pos: pos})
+			pos = end
+
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(expr[pos:], op) {
+					tokens = append(tokens, token{typ: tokenOperator, text: op, pos: pos})
+					pos += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &Error{Expr: expr, Pos: pos, Message: fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	return append(tokens, token{typ: tokenEOF, pos: len(expr)}), nil
+}
@@ -0,0 +1,507 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/duration"
+)
+
+// node is a type-checked expression
+type node interface {
+	typ() valueType
+	eval(item reflect.Value) value
+}
+
+// compareMode selects how a comparison treats a list on its left
+type compareMode int
+
+const (
+	// compareDirect compares the two values
+	compareDirect compareMode = iota
+
+	// compareLength compares the list's length with a number
+	compareLength
+
+	// compareAny is true if the comparison is true for any element; != is true if == is false
+	compareAny
+)
+
+type literalNode struct {
+	v value
+}
+
+func (n *literalNode) typ() valueType           { return valueType{kind: n.v.kind} }
+func (n *literalNode) eval(reflect.Value) value { return n.v }
+
+type fieldNode struct {
+	field *field
+}
+
+func (n *fieldNode) typ() valueType                { return n.field.typ }
+func (n *fieldNode) eval(item reflect.Value) value { return n.field.get(item) }
+
+type notNode struct {
+	x node
+}
+
+func (n *notNode) typ() valueType { return valueType{kind: kindBool} }
+func (n *notNode) eval(item reflect.Value) value {
+	return value{kind: kindBool, b: !n.x.eval(item).truthy()}
+}
+
+type logicalNode struct {
+	and  bool
+	l, r node
+}
+
+func (n *logicalNode) typ() valueType { return valueType{kind: kindBool} }
+func (n *logicalNode) eval(item reflect.Value) value {
+	l := n.l.eval(item).truthy()
+	if l != n.and {
+		return value{kind: kindBool, b: l}
+	}
+	return value{kind: kindBool, b: n.r.eval(item).truthy()}
+}
+
+type compareNode struct {
+	op   string
+	mode compareMode
+	l, r node
+}
+
+func (n *compareNode) typ() valueType { return valueType{kind: kindBool} }
+func (n *compareNode) eval(item reflect.Value) value {
+	l, r := n.l.eval(item), n.r.eval(item)
+	if l.kind == kindList {
+		switch n.mode {
+		case compareLength:
+			l = value{kind: kindNumber, n: float64(len(l.list))}
+		case compareAny:
+			op := n.op
+			if op == "!=" {
+				op = "=="
+			}
+			found := slices.ContainsFunc(l.list, func(element value) bool { return compare(op, element, r) })
+			return value{kind: kindBool, b: found != (n.op == "!=")}
+		}
+	}
+	return value{kind: kindBool, b: compare(n.op, l, r)}
+}
+
+// compare applies a comparison operator. Null equals only null and is not ordered.
+func compare(op string, l, r value) bool {
+	if l.kind == kindNull || r.kind == kindNull {
+		bothNull := l.kind == r.kind
+		switch op {
+		case "==":
+			return bothNull
+		case "!=":
+			return !bothNull
+		}
+		return false
+	}
+	switch op {
+	case "==":
+		return equalValues(l, r)
+	case "!=":
+		return !equalValues(l, r)
+	case "<":
+		return compareValues(l, r) < 0
+	case "<=":
+		return compareValues(l, r) <= 0
+	case ">":
+		return compareValues(l, r) > 0
+	case ">=":
+		return compareValues(l, r) >= 0
+	}
+	return false
+}
+
+type matchNode struct {
+	x      node
+	re     *regexp.Regexp
+	negate bool
+}
+
+func (n *matchNode) typ() valueType { return valueType{kind: kindBool} }
+func (n *matchNode) eval(item reflect.Value) value {
+	x := n.x.eval(item)
+	matched := false
+	switch x.kind {
+	case kindString:
+		matched = n.re.MatchString(x.s)
+	case kindList:
+		matched = slices.ContainsFunc(x.list, func(element value) bool {
+			return element.kind == kindString && n.re.MatchString(element.s)
+		})
+	}
+	return value{kind: kindBool, b: matched != n.negate}
+}
+
+type callNode struct {
+	result valueType
+	fn     func(args []value) value
+	args   []node
+}
+
+func (n *callNode) typ() valueType { return n.result }
+func (n *callNode) eval(item reflect.Value) value {
+	args := make([]value, 0, len(n.args))
+	for _, arg := range n.args {
+		args = append(args, arg.eval(item))
+	}
+	return n.fn(args)
+}
+
+// parser is a recursive descent parser that type-checks as it builds nodes
+type parser struct {
+	expr   string
+	tokens []token
+	pos    int
+	fields map[string]*field
+}
+
+// parse compiles an expression against the fields of an item type
+func parse(expr string, fields map[string]*field) (node, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{expr: expr, tokens: tokens, fields: fields}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.typ != tokenEOF {
+		return nil, p.errorf(next, "unexpected %s", next)
+	}
+	return root, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// acceptOperator consumes the next token if it is one of the operators
+func (p *parser) acceptOperator(ops ...string) (token, bool) {
+	t := p.peek()
+	if t.typ == tokenOperator && slices.Contains(ops, t.text) {
+		p.pos++
+		return t, true
+	}
+	return t, false
+}
+
+func (p *parser) errorf(at token, format string, args ...any) error {
+	return &Error{Expr: p.expr, Pos: at.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// parseOr parses: and ("||" and)*
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("||"); !ok {
+			return l, nil
+		}
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = &logicalNode{l: l, r: r}
+	}
+}
+
+// parseAnd parses: unary ("&&" unary)*
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOperator("&&"); !ok {
+			return l, nil
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = &logicalNode{and: true, l: l, r: r}
+	}
+}
+
+// parseUnary parses: "!" unary | comparison
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.acceptOperator("!"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses: primary (operator primary)?
+func (p *parser) parseComparison() (node, error) {
+	l, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if op, ok := p.acceptOperator("~", "!~"); ok {
+		pattern := p.next()
+		if pattern.typ != tokenString {
+			return nil, p.errorf(pattern, "%s must be followed by a regular expression string", op.text)
+		}
+		if t := l.typ(); t.kind != kindString && (t.kind != kindList || t.elem != kindString) {
+			return nil, p.errorf(op, "cannot match %s against a regular expression", t)
+		}
+		re, err := regexp.Compile(pattern.text)
+		if err != nil {
+			return nil, p.errorf(pattern, "invalid regular expression: %v", err)
+		}
+		return &matchNode{x: l, re: re, negate: op.text == "!~"}, nil
+	}
+
+	op, ok := p.acceptOperator("==", "!=", "<", "<=", ">", ">=")
+	if !ok {
+		return l, nil
+	}
+	r, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return p.checkComparison(op, l, r)
+}
+
+// flipped maps an operator to its equivalent with the operands swapped
+var flipped = map[string]string{"==": "==", "!=": "!=", "<": ">", "<=": ">=", ">": "<", ">=": "<="}
+
+// checkComparison type-checks a comparison and chooses how lists are compared
+func (p *parser) checkComparison(op token, l, r node) (node, error) {
+	lt, rt := l.typ(), r.typ()
+	if lt.kind != kindList && rt.kind == kindList {
+		return p.checkComparison(token{typ: op.typ, text: flipped[op.text], pos: op.pos}, r, l)
+	}
+	ordering := op.text != "==" && op.text != "!="
+
+	if lt.kind == kindNull || rt.kind == kindNull {
+		if ordering {
+			return nil, p.errorf(op, "cannot order null with %s", op.text)
+		}
+		return &compareNode{op: op.text, l: l, r: r}, nil
+	}
+
+	// Compare times with date strings
+	if lt.kind == kindTime || lt.kind == kindList && lt.elem == kindTime {
+		if converted, err := p.timeLiteral(r, op); err != nil {
+			return nil, err
+		} else if converted != nil {
+			r, rt = converted, converted.typ()
+		}
+	}
+	if rt.kind == kindTime {
+		if converted, err := p.timeLiteral(l, op); err != nil {
+			return nil, err
+		} else if converted != nil {
+			l, lt = converted, converted.typ()
+		}
+	}
+
+	switch {
+	case lt.kind == kindList && rt.kind == lt.elem && rt.kind != kindObject:
+		return &compareNode{op: op.text, mode: compareAny, l: l, r: r}, nil
+	case lt.kind == kindList && rt.kind == kindNumber:
+		return &compareNode{op: op.text, mode: compareLength, l: l, r: r}, nil
+	case lt.kind == kindList && rt.kind == kindList && !ordering:
+		return &compareNode{op: op.text, l: l, r: r}, nil
+	case lt.kind == rt.kind && lt.kind != kindList && lt.kind != kindObject:
+		if lt.kind == kindBool && ordering {
+			return nil, p.errorf(op, "cannot order bool with %s", op.text)
+		}
+		return &compareNode{op: op.text, l: l, r: r}, nil
+	}
+	return nil, p.errorf(op, "cannot compare %s with %s", lt, rt)
+}
+
+// timeLayouts are the layouts accepted when a string is compared with a time
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+
+// timeLiteral converts a string literal to a time literal; it returns nil for other nodes
+func (p *parser) timeLiteral(n node, at token) (node, error) {
+	literal, ok := n.(*literalNode)
+	if !ok || literal.v.kind != kindString {
+		return nil, nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, literal.v.s); err == nil {
+			return &literalNode{v: value{kind: kindTime, t: t}}, nil
+		}
+	}
+	return nil, p.errorf(at, "invalid time %q, use RFC 3339 or YYYY-MM-DD", literal.v.s)
+}
+
+// parsePrimary parses a literal, field, function call or parenthesized expression
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.typ {
+	case tokenNumber:
+		return &literalNode{v: value{kind: kindNumber, n: t.num}}, nil
+	case tokenString:
+		return &literalNode{v: value{kind: kindString, s: t.text}}, nil
+	case tokenLParen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.typ != tokenRParen {
+			return nil, p.errorf(closing, "expected \")\", found %s", closing)
+		}
+		return x, nil
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &literalNode{v: value{kind: kindBool, b: t.text == "true"}}, nil
+		case "null":
+			return &literalNode{v: nullValue}, nil
+		}
+		if p.peek().typ == tokenLParen {
+			return p.parseCall(t)
+		}
+		f, ok := p.fields[t.text]
+		if !ok {
+			return nil, p.errorf(t, "unknown field %q, fields are %s", t.text, strings.Join(fieldNames(p.fields), ", "))
+		}
+		return &fieldNode{field: f}, nil
+	}
+	return nil, p.errorf(t, "unexpected %s", t)
+}
+
+// parseCall parses the arguments of a function call and type-checks them
+func (p *parser) parseCall(name token) (node, error) {
+	p.next()
+	var args []node
+	if p.peek().typ != tokenRParen {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().typ != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if closing := p.next(); closing.typ != tokenRParen {
+		return nil, p.errorf(closing, "expected \")\", found %s", closing)
+	}
+
+	argType := func() valueType {
+		if len(args) != 1 {
+			return valueType{}
+		}
+		return args[0].typ()
+	}()
+	if name.text != "now" && len(args) != 1 {
+		return nil, p.errorf(name, "%s takes one argument", name.text)
+	}
+
+	switch name.text {
+	case "len":
+		if argType.kind != kindList && argType.kind != kindString {
+			return nil, p.errorf(name, "len needs a list or string, got %s", argType)
+		}
+		return &callNode{result: valueType{kind: kindNumber}, args: args, fn: func(args []value) value {
+			return value{kind: kindNumber, n: float64(max(len(args[0].list), len(args[0].s)))}
+		}}, nil
+
+	case "lower":
+		if argType.kind != kindString && (argType.kind != kindList || argType.elem != kindString) {
+			return nil, p.errorf(name, "lower needs a string or list of strings, got %s", argType)
+		}
+		return &callNode{result: argType, args: args, fn: func(args []value) value { return lower(args[0]) }}, nil
+
+	case "min", "max":
+		if argType.kind != kindList || argType.elem != kindNumber && argType.elem != kindTime {
+			return nil, p.errorf(name, "%s needs a list of numbers or times, got %s", name.text, argType)
+		}
+		sign := 1
+		if name.text == "min" {
+			sign = -1
+		}
+		return &callNode{result: valueType{kind: argType.elem}, args: args, fn: func(args []value) value {
+			best := nullValue
+			for _, element := range args[0].list {
+				if element.kind != kindNull && (best.kind == kindNull || sign*compareValues(element, best) > 0) {
+					best = element
+				}
+			}
+			return best
+		}}, nil
+
+	case "ago":
+		literal, ok := args[0].(*literalNode)
+		if !ok || literal.v.kind != kindString {
+			return nil, p.errorf(name, "ago needs a duration string such as \"30d\"")
+		}
+		d, err := duration.Parse(literal.v.s)
+		if err != nil {
+			return nil, p.errorf(name, "%v", err)
+		}
+		return &callNode{result: valueType{kind: kindTime}, fn: func([]value) value {
+			return value{kind: kindTime, t: time.Now().Add(-d)}
+		}}, nil
+
+	case "now":
+		if len(args) != 0 {
+			return nil, p.errorf(name, "now takes no arguments")
+		}
+		return &callNode{result: valueType{kind: kindTime}, fn: func([]value) value {
+			return value{kind: kindTime, t: time.Now()}
+		}}, nil
+	}
+	return nil, p.errorf(name, "unknown function %q, functions are ago, len, lower, max, min, now", name.text)
+}
+
+// lower lower-cases a string or each string of a list
+func lower(v value) value {
+	switch v.kind {
+	case kindString:
+		return value{kind: kindString, s: strings.ToLower(v.s)}
+	case kindList:
+		list := value{kind: kindList, list: make([]value, 0, len(v.list))}
+		for _, element := range v.list {
+			list.list = append(list.list, lower(element))
+		}
+		return list
+	}
+	return v
+}
+
+// fieldNames returns the sorted names of fields
+func fieldNames(fields map[string]*field) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,156 @@
+// Package query filters, sorts, projects and groups list results on the client,
+// for the many Workbrew endpoints that cannot filter on the server.
+//
+// Expressions are compiled against a model type, such as formulae.Formula,
+// casks.Cask, devices.Device or vulnerabilities.Vulnerability, and refer to its
+// fields by their JSON names:
+//
+//	outdated && devices > 10 && name ~ "^python"
+//
+// The language has:
+//   - Logical operators &&, || and !, and parentheses. ! negates the comparison that follows it.
+//   - Comparisons ==, !=, <, <=, > and >=, and regular expression matches ~ and !~ (RE2 syntax).
+//   - Number, "string" (or 'string'), true, false and null literals.
+//   - Nested fields through lists of objects, e.g. vulnerabilities.cvss_score.
+//   - Functions len(x), lower(x), min(list), max(list), now() and ago("30d").
+//
+// Fields are converted as follows. Nil pointers are null, which equals only null
+// and is never ordered. Device times ("never" and statuses are null) compare with
+// other times, ago() and "2006-01-02" or RFC 3339 strings. A list compared with a
+// value of its element type is true if the comparison holds for any element
+// (!= is true if no element is equal); any other list compared with a number
+// compares its length, so devices > 10 counts devices. A field on its own is true
+// when it is true, non-zero, non-empty and not null.
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldCache holds the fields of each model type
+var fieldCache sync.Map
+
+// fieldsFor returns the fields of T
+func fieldsFor[T any]() (map[string]*field, error) {
+	t := reflect.TypeFor[T]()
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.(map[string]*field), nil
+	}
+	fields, err := fieldsOf(t)
+	if err != nil {
+		return nil, err
+	}
+	fieldCache.Store(t, fields)
+	return fields, nil
+}
+
+// lookupField returns the field of T with the given JSON name
+func lookupField[T any](name string) (*field, error) {
+	fields, err := fieldsFor[T]()
+	if err != nil {
+		return nil, err
+	}
+	f, ok := fields[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q, fields are %s", name, strings.Join(fieldNames(fields), ", "))
+	}
+	return f, nil
+}
+
+// Fields returns the names of the fields of T that expressions, sort keys,
+// projections and groups can use, sorted
+func Fields[T any]() []string {
+	fields, err := fieldsFor[T]()
+	if err != nil {
+		return nil
+	}
+	return fieldNames(fields)
+}
+
+// Expr is a compiled expression over items of type T
+type Expr[T any] struct {
+	source string
+	root   node
+}
+
+// Compile parses and type-checks an expression against the fields of T.
+// An empty expression matches every item.
+//
+// Parameters:
+//   - expr: The expression, e.g. `outdated && devices > 10 && name ~ "^python"`
+//
+// Returns:
+//   - *Expr[T]: The compiled expression, safe for concurrent use
+//   - error: An *Error for syntax and type errors, such as unknown fields
+//
+// Example:
+//
+//	expr, err := query.Compile[formulae.Formula](`outdated && devices > 10`)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, formula := range expr.Filter(*allFormulae) {
+//	    fmt.Println(formula.Name)
+//	}
+func Compile[T any](expr string) (*Expr[T], error) {
+	fields, err := fieldsFor[T]()
+	if err != nil {
+		return nil, err
+	}
+	e := &Expr[T]{source: expr}
+	if isBlank(expr) {
+		return e, nil
+	}
+	root, err := parse(expr, fields)
+	if err != nil {
+		return nil, err
+	}
+	e.root = root
+	return e, nil
+}
+
+// MustCompile is like Compile but panics if the expression is invalid
+func MustCompile[T any](expr string) *Expr[T] {
+	e, err := Compile[T](expr)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// String returns the source of the expression
+func (e *Expr[T]) String() string {
+	return e.source
+}
+
+// Match reports whether an item satisfies the expression
+func (e *Expr[T]) Match(item T) bool {
+	if e.root == nil {
+		return true
+	}
+	return e.root.eval(reflect.ValueOf(item)).truthy()
+}
+
+// Filter returns the items that satisfy the expression, in order
+func (e *Expr[T]) Filter(items []T) []T {
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		if e.Match(item) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// isBlank reports whether s is only whitespace
+func isBlank(s string) bool {
+	for _, c := range s {
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			return false
+		}
+	}
+	return true
+}
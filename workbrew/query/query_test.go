@@ -0,0 +1,188 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/casks"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// names returns the names of formulae
+func names(items []formulae.Formula) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.Name)
+	}
+	return result
+}
+
+func TestCompile_Formulae(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures().Formulae
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"", []string{"curl", "wget", "actionlint"}},
+		{"outdated", []string{"curl", "wget"}},
+		{"!outdated", []string{"actionlint"}},
+		{`outdated && devices > 1 && name ~ "^cu"`, []string{"curl"}},
+		{`name == "wget" || name == 'actionlint'`, []string{"wget", "actionlint"}},
+		{`(installed_on_request || installed_as_dependency) && !outdated`, []string{"actionlint"}},
+		{`devices == "1234567890"`, []string{"curl"}},
+		{`devices != "1234567890"`, []string{"wget", "actionlint"}},
+		{`len(devices) >= 2`, []string{"curl"}},
+		{`1 < devices`, []string{"curl"}},
+		{`vulnerabilities`, []string{"curl", "wget"}},
+		{`vulnerabilities ~ "^CVE-2024-1"`, []string{"wget"}},
+		{`license !~ "GPL"`, []string{"curl", "actionlint"}},
+		{`deprecated == null`, []string{"curl", "wget", "actionlint"}},
+		{`homebrew_core_version >= "8"`, []string{"curl"}},
+		{`lower(name) == "CURL"`, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Compile[formulae.Formula](tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(expr.Filter(fixtures)))
+		})
+	}
+}
+
+func TestCompile_Devices(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures().Devices
+	fixtures = append(fixtures, devices.Device{
+		SerialNumber: "NEVERSEEN1",
+		LastSeenAt:   devices.TimeOrNever{Never: true},
+	})
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`groups == "Admin"`, []string{workbrewtest.FixtureDeviceMacBook}},
+		{`formulae_count > 1 && device_type ~ "(?i)macbook"`, []string{workbrewtest.FixtureDeviceMacBook}},
+		{`last_seen_at == null`, []string{"NEVERSEEN1"}},
+		{`last_seen_at < "2025-01-07"`, []string{workbrewtest.FixtureDeviceMacBook, workbrewtest.FixtureDeviceLinux}},
+		{`last_seen_at >= "2025-01-07T00:00:00Z"`, []string{}},
+		{`last_seen_at < ago("1d")`, []string{workbrewtest.FixtureDeviceMacBook, workbrewtest.FixtureDeviceLinux}},
+		{`last_seen_at > now()`, []string{}},
+		{`mdm_user_or_device_name`, []string{workbrewtest.FixtureDeviceMacBook}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Compile[devices.Device](tt.expr)
+			require.NoError(t, err)
+			serials := []string{}
+			for _, device := range expr.Filter(fixtures) {
+				serials = append(serials, device.SerialNumber)
+			}
+			assert.Equal(t, tt.want, serials)
+		})
+	}
+}
+
+func TestCompile_VulnerabilitiesNestedFields(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures().Vulnerabilities
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{`vulnerabilities.cvss_score >= 9`, []string{"wget"}},
+		{`max(vulnerabilities.cvss_score) < 7 && supported`, []string{"curl"}},
+		{`vulnerabilities.clean_id == "CVE-2024-2466"`, []string{"curl"}},
+		{`outdated_devices > 1`, []string{"curl"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := Compile[vulnerabilities.Vulnerability](tt.expr)
+			require.NoError(t, err)
+			formulas := []string{}
+			for _, vuln := range expr.Filter(fixtures) {
+				formulas = append(formulas, vuln.Formula)
+			}
+			assert.Equal(t, tt.want, formulas)
+		})
+	}
+}
+
+func TestCompile_Casks(t *testing.T) {
+	expr, err := Compile[casks.Cask](`outdated && display_name ~ "Visual Studio"`)
+	require.NoError(t, err)
+	assert.Len(t, expr.Filter(workbrewtest.DefaultFixtures().Casks), 1)
+	assert.Equal(t, `outdated && display_name ~ "Visual Studio"`, expr.String())
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		message string
+		pos     int
+	}{
+		{`nme == "curl"`, `unknown field "nme", fields are deprecated, devices,`, 0},
+		{`name == `, "unexpected end of expression", 8},
+		{`name == "curl`, "unterminated string", 8},
+		{`(outdated`, `expected ")", found end of expression`, 9},
+		{`outdated == 1`, "cannot compare bool with number", 9},
+		{`name > 3`, "cannot compare string with number", 5},
+		{`outdated < true`, "cannot order bool with <", 9},
+		{`devices ~ 3`, "~ must be followed by a regular expression string", 10},
+		{`outdated ~ "x"`, "cannot match bool against a regular expression", 9},
+		{`name ~ "("`, "invalid regular expression", 7},
+		{`len(outdated)`, "len needs a list or string, got bool", 0},
+		{`ago("soon")`, "invalid duration", 0},
+		{`first(devices)`, `unknown function "first"`, 0},
+		{`name @ "x"`, `unexpected character '@'`, 5},
+		{`name == "x" name`, `unexpected "name"`, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := Compile[formulae.Formula](tt.expr)
+			require.Error(t, err)
+			var queryErr *Error
+			require.True(t, errors.As(err, &queryErr))
+			assert.Contains(t, queryErr.Message, tt.message)
+			assert.Equal(t, tt.pos, queryErr.Pos)
+		})
+	}
+}
+
+func TestCompile_TimeLiteral(t *testing.T) {
+	_, err := Compile[devices.Device](`last_seen_at < "last week"`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid time "last week"`)
+}
+
+func TestCompile_NotAStruct(t *testing.T) {
+	_, err := Compile[string](`x`)
+	require.Error(t, err)
+	assert.Nil(t, Fields[string]())
+}
+
+func TestMustCompile(t *testing.T) {
+	assert.True(t, MustCompile[formulae.Formula]("outdated").Match(formulae.Formula{Outdated: true}))
+	assert.Panics(t, func() { MustCompile[formulae.Formula]("outdated ==") })
+}
+
+func TestMatch_Pointer(t *testing.T) {
+	expr := MustCompile[*devices.Device](`last_seen_at > "2025-01-01"`)
+	seen := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	assert.True(t, expr.Match(&devices.Device{LastSeenAt: devices.TimeOrNever{Time: &seen}}))
+	assert.False(t, expr.Match(nil))
+}
+
+func TestFields(t *testing.T) {
+	fields := Fields[vulnerabilities.Vulnerability]()
+	assert.Contains(t, fields, "formula")
+	assert.Contains(t, fields, "vulnerabilities")
+	assert.Contains(t, fields, "vulnerabilities.cvss_score")
+	assert.Contains(t, fields, "vulnerabilities.clean_id")
+}
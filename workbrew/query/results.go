@@ -0,0 +1,312 @@
+package query
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SortKey is a field to sort by
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSortKeys parses field names to sort by, each optionally prefixed with
+// "-" for descending order, e.g. "-devices"
+func ParseSortKeys(keys ...string) []SortKey {
+	sortKeys := make([]SortKey, 0, len(keys))
+	for _, key := range keys {
+		if field, ok := strings.CutPrefix(key, "-"); ok {
+			sortKeys = append(sortKeys, SortKey{Field: field, Descending: true})
+		} else {
+			sortKeys = append(sortKeys, SortKey{Field: strings.TrimPrefix(key, "+")})
+		}
+	}
+	return sortKeys
+}
+
+// Sort stably sorts items in place by each key in turn. Null sorts before other
+// values, false before true, and lists by their length.
+//
+// Parameters:
+//   - items: The items to sort
+//   - keys: Field names, each optionally prefixed with "-" for descending order
+//
+// Returns:
+//   - error: If a field does not exist
+//
+// Example:
+//
+//	err := query.Sort(formulae, "-devices", "name")
+func Sort[T any](items []T, keys ...string) error {
+	sortKeys := ParseSortKeys(keys...)
+	fields := make([]*field, 0, len(sortKeys))
+	for _, key := range sortKeys {
+		f, err := lookupField[T](key.Field)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, f)
+	}
+
+	slices.SortStableFunc(items, func(a, b T) int {
+		va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+		for i, f := range fields {
+			c := compareValues(f.get(va), f.get(vb))
+			if sortKeys[i].Descending {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return nil
+}
+
+// Row is a projected item: the values of selected fields, in order
+type Row struct {
+	Fields []string
+	Values []any
+}
+
+// Get returns the value of a field, or nil if the row does not have it
+func (r Row) Get(field string) any {
+	if i := slices.Index(r.Fields, field); i >= 0 {
+		return r.Values[i]
+	}
+	return nil
+}
+
+// MarshalJSON renders the row as an object with its fields in order
+func (r Row) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range r.Fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.Values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Rows is a list of projected items
+type Rows []Row
+
+// Columns returns the projected field names, in order
+func (r Rows) Columns() []string {
+	if len(r) == 0 {
+		return nil
+	}
+	return r[0].Fields
+}
+
+// Project selects fields from each item. Values are strings, float64 numbers,
+// bools, time.Time, nil for null, and []any for lists.
+//
+// Parameters:
+//   - items: The items to project
+//   - fields: The field names to select, e.g. "name", "devices"
+//
+// Returns:
+//   - Rows: One row per item
+//   - error: If a field does not exist
+func Project[T any](items []T, fields ...string) (Rows, error) {
+	selected := make([]*field, 0, len(fields))
+	for _, name := range fields {
+		f, err := lookupField[T](name)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, f)
+	}
+
+	rows := make(Rows, 0, len(items))
+	for _, item := range items {
+		v := reflect.ValueOf(item)
+		values := make([]any, 0, len(selected))
+		for _, f := range selected {
+			values = append(values, f.get(v).native())
+		}
+		rows = append(rows, Row{Fields: slices.Clone(fields), Values: values})
+	}
+	return rows, nil
+}
+
+// Group is the number of items with a value of the grouped field
+type Group struct {
+	Value any `json:"value"`
+	Count int `json:"count"`
+}
+
+// GroupCount counts items by the value of a field. For list fields each element
+// is counted, so grouping devices by "groups" counts the devices in each group,
+// and grouping formulae by "devices" counts the formulae on each device.
+//
+// Parameters:
+//   - items: The items to group
+//   - field: The field name to group by
+//
+// Returns:
+//   - []Group: Groups by descending count, then ascending value
+//   - error: If the field does not exist
+func GroupCount[T any](items []T, field string) ([]Group, error) {
+	f, err := lookupField[T](field)
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		value value
+		count int
+	}
+	entries := make(map[string]*entry)
+	add := func(v value) {
+		key := fmt.Sprint(v.kind, v.native())
+		if entries[key] == nil {
+			entries[key] = &entry{value: v}
+		}
+		entries[key].count++
+	}
+	for _, item := range items {
+		v := f.get(reflect.ValueOf(item))
+		if v.kind == kindList {
+			for _, element := range v.list {
+				add(element)
+			}
+			continue
+		}
+		add(v)
+	}
+
+	sorted := make([]*entry, 0, len(entries))
+	for _, e := range entries {
+		sorted = append(sorted, e)
+	}
+	slices.SortFunc(sorted, func(a, b *entry) int {
+		if c := cmp.Compare(b.count, a.count); c != 0 {
+			return c
+		}
+		return compareValues(a.value, b.value)
+	})
+
+	groups := make([]Group, 0, len(sorted))
+	for _, e := range sorted {
+		groups = append(groups, Group{Value: e.value.native(), Count: e.count})
+	}
+	return groups, nil
+}
+
+// Query combines a filter, sort, limit, and a projection or grouping
+type Query struct {
+	// Where is the filter expression; empty matches every item
+	Where string
+
+	// Sort lists field names to sort by, each optionally prefixed with "-" for descending order
+	Sort []string
+
+	// Limit keeps the first items after sorting, or the largest groups with GroupBy.
+	// Zero keeps every item.
+	Limit int
+
+	// Fields selects fields to project. Empty keeps whole items.
+	Fields []string
+
+	// GroupBy counts items by a field instead of returning them
+	GroupBy string
+}
+
+// Result is the outcome of running a query
+type Result[T any] struct {
+	// Matched is the number of items that satisfied Where, before Limit
+	Matched int `json:"matched"`
+
+	// Items are the matching items; nil when Fields or GroupBy is set
+	Items []T `json:"items,omitempty"`
+
+	// Rows are the projected items when Fields is set
+	Rows Rows `json:"rows,omitempty"`
+
+	// Groups are the counts when GroupBy is set
+	Groups []Group `json:"groups,omitempty"`
+}
+
+// Run filters, sorts and limits items, then projects or groups them
+//
+// Parameters:
+//   - items: The items to query; not modified
+//   - q: The query
+//
+// Returns:
+//   - *Result[T]: The matching items, rows or groups
+//   - error: If the expression is invalid, a field does not exist, or both Fields and GroupBy are set
+//
+// Example:
+//
+//	result, err := query.Run(*allDevices, query.Query{
+//	    Where:   `last_seen_at < ago("30d")`,
+//	    GroupBy: "groups",
+//	})
+func Run[T any](items []T, q Query) (*Result[T], error) {
+	if len(q.Fields) > 0 && q.GroupBy != "" {
+		return nil, fmt.Errorf("fields and group by cannot be combined")
+	}
+	expr, err := Compile[T](q.Where)
+	if err != nil {
+		return nil, err
+	}
+	matched := expr.Filter(items)
+	if err := Sort(matched, q.Sort...); err != nil {
+		return nil, err
+	}
+
+	result := &Result[T]{Matched: len(matched)}
+	if q.GroupBy != "" {
+		if result.Groups, err = GroupCount(matched, q.GroupBy); err != nil {
+			return nil, err
+		}
+		if q.Limit > 0 && len(result.Groups) > q.Limit {
+			result.Groups = result.Groups[:q.Limit]
+		}
+		return result, nil
+	}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+
+	switch {
+	case len(q.Fields) > 0:
+		if result.Rows, err = Project(matched, q.Fields...); err != nil {
+			return nil, err
+		}
+	default:
+		result.Items = matched
+	}
+	return result, nil
+}
+
+// WriteJSON writes the result as indented JSON
+func (r *Result[T]) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,113 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSort(t *testing.T) {
+	items := workbrewtest.DefaultFixtures().Formulae
+
+	require.NoError(t, Sort(items, "name"))
+	assert.Equal(t, []string{"actionlint", "curl", "wget"}, names(items))
+
+	require.NoError(t, Sort(items, "-devices", "-name"))
+	assert.Equal(t, []string{"curl", "wget", "actionlint"}, names(items))
+
+	require.NoError(t, Sort(items, "outdated", "+name"))
+	assert.Equal(t, []string{"actionlint", "curl", "wget"}, names(items))
+
+	assert.ErrorContains(t, Sort(items, "-size"), `unknown field "size"`)
+}
+
+func TestSort_NullFirst(t *testing.T) {
+	items := workbrewtest.DefaultFixtures().Devices
+	items = append(items, devices.Device{SerialNumber: "NEVERSEEN1", LastSeenAt: devices.TimeOrNever{Never: true}})
+
+	require.NoError(t, Sort(items, "last_seen_at", "serial_number"))
+	assert.Equal(t, "NEVERSEEN1", items[0].SerialNumber)
+	assert.Equal(t, workbrewtest.FixtureDeviceLinux, items[1].SerialNumber)
+}
+
+func TestProject(t *testing.T) {
+	rows, err := Project(workbrewtest.DefaultFixtures().Formulae[:2], "name", "devices", "deprecated")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"name", "devices", "deprecated"}, rows.Columns())
+	assert.Equal(t, "curl", rows[0].Get("name"))
+	assert.Equal(t, []any{workbrewtest.FixtureDeviceMacBook, workbrewtest.FixtureDeviceLinux}, rows[0].Get("devices"))
+	assert.Nil(t, rows[0].Get("deprecated"))
+	assert.Nil(t, rows[0].Get("missing"))
+
+	data, err := json.Marshal(rows[1])
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"wget","devices":["TC6R2DHVHG"],"deprecated":null}`, string(data))
+
+	_, err = Project(workbrewtest.DefaultFixtures().Formulae, "size")
+	assert.ErrorContains(t, err, `unknown field "size"`)
+	assert.Nil(t, Rows{}.Columns())
+}
+
+func TestGroupCount(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures()
+
+	groups, err := GroupCount(fixtures.Devices, "groups")
+	require.NoError(t, err)
+	assert.Equal(t, []Group{{Value: "All Devices", Count: 2}, {Value: "Admin", Count: 1}}, groups)
+
+	groups, err = GroupCount(fixtures.Formulae, "outdated")
+	require.NoError(t, err)
+	assert.Equal(t, []Group{{Value: true, Count: 2}, {Value: false, Count: 1}}, groups)
+
+	groups, err = GroupCount(fixtures.Devices, "formulae_count")
+	require.NoError(t, err)
+	assert.Equal(t, []Group{{Value: 1.0, Count: 1}, {Value: 3.0, Count: 1}}, groups)
+
+	_, err = GroupCount(fixtures.Devices, "tap")
+	assert.ErrorContains(t, err, `unknown field "tap"`)
+}
+
+func TestRun(t *testing.T) {
+	items := workbrewtest.DefaultFixtures().Formulae
+
+	result, err := Run(items, Query{Where: "outdated", Sort: []string{"name"}, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Matched)
+	assert.Equal(t, []string{"curl"}, names(result.Items))
+	assert.Equal(t, "curl", items[0].Name, "input is not sorted")
+
+	result, err = Run(items, Query{Sort: []string{"-name"}, Fields: []string{"name", "outdated"}})
+	require.NoError(t, err)
+	assert.Nil(t, result.Items)
+	require.Len(t, result.Rows, 3)
+	assert.Equal(t, "wget", result.Rows[0].Get("name"))
+
+	result, err = Run(items, Query{GroupBy: "devices", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Matched)
+	assert.Equal(t, []Group{{Value: workbrewtest.FixtureDeviceMacBook, Count: 3}}, result.Groups)
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteJSON(&buf))
+	assert.JSONEq(t, `{"matched":3,"groups":[{"value":"TC6R2DHVHG","count":3}]}`, buf.String())
+}
+
+func TestRun_Errors(t *testing.T) {
+	items := []formulae.Formula{}
+
+	_, err := Run(items, Query{Fields: []string{"name"}, GroupBy: "name"})
+	assert.ErrorContains(t, err, "cannot be combined")
+
+	_, err = Run(items, Query{Where: "outdated &&"})
+	assert.ErrorContains(t, err, "unexpected end of expression")
+
+	_, err = Run(items, Query{Sort: []string{"size"}})
+	assert.ErrorContains(t, err, `unknown field "size"`)
+}
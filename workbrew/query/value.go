@@ -0,0 +1,327 @@
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+)
+
+// kind is the type of a value in an expression
+type kind int
+
+const (
+	kindNull kind = iota
+	kindBool
+	kindNumber
+	kindString
+	kindTime
+	kindList
+	kindObject
+)
+
+// String returns the kind's name as used in error messages
+func (k kind) String() string {
+	switch k {
+	case kindBool:
+		return "bool"
+	case kindNumber:
+		return "number"
+	case kindString:
+		return "string"
+	case kindTime:
+		return "time"
+	case kindList:
+		return "list"
+	case kindObject:
+		return "object"
+	}
+	return "null"
+}
+
+// valueType is the static type of an expression. Elem is the element kind of lists.
+type valueType struct {
+	kind kind
+	elem kind
+}
+
+// String returns the type's name as used in error messages
+func (t valueType) String() string {
+	if t.kind == kindList {
+		return "list of " + t.elem.String()
+	}
+	return t.kind.String()
+}
+
+// value is the result of evaluating an expression
+type value struct {
+	kind kind
+	b    bool
+	n    float64
+	s    string
+	t    time.Time
+	list []value
+}
+
+var nullValue = value{kind: kindNull}
+
+// truthy converts a value to a condition: null, false, zero, "" and empty lists are false
+func (v value) truthy() bool {
+	switch v.kind {
+	case kindBool:
+		return v.b
+	case kindNumber:
+		return v.n != 0
+	case kindString:
+		return v.s != ""
+	case kindTime:
+		return !v.t.IsZero()
+	case kindList:
+		return len(v.list) > 0
+	case kindObject:
+		return true
+	}
+	return false
+}
+
+// native converts a value to the Go value used in projections and groups
+func (v value) native() any {
+	switch v.kind {
+	case kindBool:
+		return v.b
+	case kindNumber:
+		return v.n
+	case kindString:
+		return v.s
+	case kindTime:
+		return v.t
+	case kindList:
+		items := make([]any, 0, len(v.list))
+		for _, item := range v.list {
+			items = append(items, item.native())
+		}
+		return items
+	}
+	return nil
+}
+
+// compareValues orders two values of the same kind; lists are ordered by length and null first
+func compareValues(a, b value) int {
+	if a.kind == kindNull || b.kind == kindNull {
+		return cmp.Compare(boolRank(a.kind != kindNull), boolRank(b.kind != kindNull))
+	}
+	switch {
+	case a.kind == kindList && b.kind == kindNumber:
+		return cmp.Compare(float64(len(a.list)), b.n)
+	case a.kind == kindNumber && b.kind == kindList:
+		return cmp.Compare(a.n, float64(len(b.list)))
+	}
+	switch a.kind {
+	case kindBool:
+		return cmp.Compare(boolRank(a.b), boolRank(b.b))
+	case kindNumber:
+		return cmp.Compare(a.n, b.n)
+	case kindString:
+		return strings.Compare(a.s, b.s)
+	case kindTime:
+		return a.t.Compare(b.t)
+	case kindList:
+		return cmp.Compare(len(a.list), len(b.list))
+	}
+	return 0
+}
+
+// boolRank orders false before true
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// equalValues reports whether two values of the same kind are equal
+func equalValues(a, b value) bool {
+	if a.kind != b.kind {
+		return false
+	}
+	if a.kind == kindList {
+		return slices.EqualFunc(a.list, b.list, equalValues)
+	}
+	return compareValues(a, b) == 0
+}
+
+var (
+	timeType         = reflect.TypeFor[time.Time]()
+	timeOrNeverType  = reflect.TypeFor[devices.TimeOrNever]()
+	timeOrStatusType = reflect.TypeFor[devices.TimeOrStatus]()
+)
+
+// field is a queryable field of a model type, named by its json path (e.g. "vulnerabilities.cvss_score")
+type field struct {
+	name  string
+	typ   valueType
+	steps []int
+}
+
+// get extracts the field from an item. Steps through slices are applied to
+// every element, giving a flattened list.
+func (f *field) get(v reflect.Value) value {
+	return extract(v, f.steps)
+}
+
+// extract follows struct field indexes from v
+func extract(v reflect.Value, steps []int) value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nullValue
+		}
+		v = v.Elem()
+	}
+	if len(steps) == 0 {
+		return toValue(v)
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		list := value{kind: kindList, list: make([]value, 0, v.Len())}
+		for i := 0; i < v.Len(); i++ {
+			item := extract(v.Index(i), steps)
+			if item.kind == kindList {
+				list.list = append(list.list, item.list...)
+			} else {
+				list.list = append(list.list, item)
+			}
+		}
+		return list
+	case reflect.Struct:
+		return extract(v.Field(steps[0]), steps[1:])
+	}
+	return nullValue
+}
+
+// toValue converts a dereferenced reflect value
+func toValue(v reflect.Value) value {
+	switch v.Type() {
+	case timeType:
+		return value{kind: kindTime, t: v.Interface().(time.Time)}
+	case timeOrNeverType:
+		if t := v.Interface().(devices.TimeOrNever).Time; t != nil {
+			return value{kind: kindTime, t: *t}
+		}
+		return nullValue
+	case timeOrStatusType:
+		if t := v.Interface().(devices.TimeOrStatus).Time; t != nil {
+			return value{kind: kindTime, t: *t}
+		}
+		return nullValue
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return value{kind: kindBool, b: v.Bool()}
+	case reflect.String:
+		return value{kind: kindString, s: v.String()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value{kind: kindNumber, n: float64(v.Int())}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value{kind: kindNumber, n: float64(v.Uint())}
+	case reflect.Float32, reflect.Float64:
+		return value{kind: kindNumber, n: v.Float()}
+	case reflect.Slice, reflect.Array:
+		list := value{kind: kindList, list: make([]value, 0, v.Len())}
+		for i := 0; i < v.Len(); i++ {
+			list.list = append(list.list, extract(v.Index(i), nil))
+		}
+		return list
+	case reflect.Struct:
+		return value{kind: kindObject}
+	}
+	return nullValue
+}
+
+// kindOfType returns the kind of values of a reflect type, after dereferencing pointers
+func kindOfType(t reflect.Type) kind {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType, timeOrNeverType, timeOrStatusType:
+		return kindTime
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return kindBool
+	case reflect.String:
+		return kindString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return kindNumber
+	case reflect.Slice, reflect.Array:
+		return kindList
+	case reflect.Struct:
+		return kindObject
+	}
+	return kindNull
+}
+
+// fieldsOf returns the queryable fields of a struct type, keyed by json path
+func fieldsOf(t reflect.Type) (map[string]*field, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query items must be structs, got %s", t)
+	}
+	fields := make(map[string]*field)
+	collectFields(t, "", nil, false, fields)
+	return fields, nil
+}
+
+// collectFields adds the fields of struct type t under a json path prefix
+func collectFields(t reflect.Type, prefix string, steps []int, inList bool, fields map[string]*field) {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = structField.Name
+		}
+		path := prefix + name
+		fieldSteps := append(slices.Clone(steps), i)
+
+		fieldType := structField.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		typ := valueType{kind: kindOfType(fieldType)}
+		elemType := fieldType
+		if typ.kind == kindList {
+			elemType = fieldType.Elem()
+			for elemType.Kind() == reflect.Pointer {
+				elemType = elemType.Elem()
+			}
+			typ.elem = kindOfType(elemType)
+		}
+		if inList {
+			if typ.kind == kindList {
+				typ = valueType{kind: kindList, elem: typ.elem}
+			} else {
+				typ = valueType{kind: kindList, elem: typ.kind}
+			}
+		}
+		fields[path] = &field{name: path, typ: typ, steps: fieldSteps}
+
+		if kindOfType(elemType) == kindObject {
+			collectFields(elemType, path+".", fieldSteps, inList || typ.kind == kindList, fields)
+		}
+	}
+}
@@ -0,0 +1,504 @@
+package remediation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/rollout"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/waiter"
+	"go.uber.org/zap"
+)
+
+// DefaultVerifyInterval is the wait between fix checks when Options.VerifyInterval is zero
+const DefaultVerifyInterval = time.Minute
+
+// DefaultClockSkew is how long before Report.StartedAt a fix may have occurred
+// and still count, when Options.ClockSkew is zero
+const DefaultClockSkew = 5 * time.Minute
+
+// Audit actions
+const (
+	ActionPlanned      = "planned"
+	ActionScheduled    = "scheduled"
+	ActionRunSucceeded = "run_succeeded"
+	ActionRunFailed    = "run_failed"
+	ActionRunPending   = "run_pending"
+	ActionFixed        = "fixed"
+	ActionOutstanding  = "outstanding"
+)
+
+// AuditEntry records one step of a remediation
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Formula string    `json:"formula"`
+	Device  string    `json:"device,omitempty"`
+	Label   string    `json:"label,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+}
+
+// DeviceReport is the remediation state of one formula on one device
+type DeviceReport struct {
+	Device string `json:"device"`
+
+	// RunStatus is the state of the device's brew command run; empty in dry runs
+	RunStatus waiter.Status `json:"run_status,omitempty"`
+
+	// Fixed is true once the device is no longer outdated and every finding has a "fixed" change
+	Fixed bool `json:"fixed"`
+
+	// FixedAt is the time of the latest "fixed" change for the device
+	FixedAt *time.Time `json:"fixed_at,omitempty"`
+
+	// FixedVulnerabilities are the findings with a "fixed" change for the device
+	FixedVulnerabilities []string `json:"fixed_vulnerabilities"`
+}
+
+// TargetReport is the remediation state of one formula
+type TargetReport struct {
+	Formula   string    `json:"formula"`
+	Arguments string    `json:"arguments"`
+	Findings  []Finding `json:"findings"`
+
+	// Label is the label Workbrew gave the brew command; empty in dry runs
+	Label string `json:"label,omitempty"`
+
+	Devices []DeviceReport `json:"devices"`
+}
+
+// Summary counts device upgrades across every target
+type Summary struct {
+	Targets       int `json:"targets"`
+	Devices       int `json:"devices"`
+	RunsSucceeded int `json:"runs_succeeded"`
+	RunsFailed    int `json:"runs_failed"`
+	Fixed         int `json:"fixed"`
+	Outstanding   int `json:"outstanding"`
+}
+
+// Report is the outcome and audit trail of a remediation
+type Report struct {
+	MinCVSSScore float64        `json:"min_cvss_score"`
+	StartedAt    time.Time      `json:"started_at"`
+	FinishedAt   time.Time      `json:"finished_at"`
+	DryRun       bool           `json:"dry_run"`
+	Targets      []TargetReport `json:"targets"`
+	Skipped      []Skipped      `json:"skipped"`
+	Summary      Summary        `json:"summary"`
+
+	// Audit lists every step in the order it happened
+	Audit []AuditEntry `json:"audit"`
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// Source holds the services a remediation uses
+type Source struct {
+	Vulnerabilities      vulnerabilities.VulnerabilitiesServiceInterface
+	VulnerabilityChanges vulnerabilitychanges.VulnerabilityChangesServiceInterface
+	BrewCommands         brewcommands.BrewCommandsServiceInterface
+}
+
+// NewSource creates a remediation source backed by a Workbrew client
+func NewSource(client *workbrew.Client) *Source {
+	return &Source{
+		Vulnerabilities:      client.Vulnerabilities,
+		VulnerabilityChanges: client.VulnerabilityChanges,
+		BrewCommands:         client.BrewCommands,
+	}
+}
+
+// Options configures an Orchestrator
+type Options struct {
+	// RunAfter schedules the brew commands to run after this time. Zero runs them immediately.
+	RunAfter time.Time
+
+	// RunTimeout bounds the wait for the brew command runs. Zero waits until the context is done.
+	RunTimeout time.Duration
+
+	// DeviceID converts a device serial number to the device UUID sent in a
+	// brew command's device_ids, e.g. rollout.DeviceIDs.Lookup. Required unless
+	// DryRun is set.
+	DeviceID func(serial string) (string, error)
+
+	// Wait configures polling of runs
+	Wait *waiter.Options
+
+	// VerifyTimeout bounds the wait for the fixes to be reported after the runs.
+	// Zero checks once.
+	VerifyTimeout time.Duration
+
+	// VerifyInterval is the wait between fix checks. Defaults to DefaultVerifyInterval.
+	VerifyInterval time.Duration
+
+	// ClockSkew is how long before Report.StartedAt, which is taken from the
+	// local clock, a "fixed" change may have occurred on the server's clock and
+	// still count. Defaults to DefaultClockSkew.
+	ClockSkew time.Duration
+
+	// DryRun plans and audits the upgrades without creating brew commands
+	DryRun bool
+
+	// Logger receives progress messages. Defaults to a no-op logger.
+	Logger *zap.Logger
+}
+
+// Orchestrator schedules the upgrades of a plan and verifies the fixes
+type Orchestrator struct {
+	src     *Source
+	options Options
+}
+
+// NewOrchestrator creates an orchestrator using the given services
+//
+// Parameters:
+//   - src: The services to remediate with
+//   - options: Orchestrator options; may be nil
+func NewOrchestrator(src *Source, options *Options) *Orchestrator {
+	o := &Orchestrator{src: src}
+	if options != nil {
+		o.options = *options
+	}
+	if o.options.VerifyInterval <= 0 {
+		o.options.VerifyInterval = DefaultVerifyInterval
+	}
+	if o.options.ClockSkew <= 0 {
+		o.options.ClockSkew = DefaultClockSkew
+	}
+	if o.options.Logger == nil {
+		o.options.Logger = zap.NewNop()
+	}
+	return o
+}
+
+// Execute creates one brew command per target, waits for the runs, then checks
+// ListVulnerabilities and ListVulnerabilityChanges until every device whose run
+// did not fail is reported fixed or the verify timeout passes.
+//
+// Parameters:
+//   - ctx: Context for the API calls
+//   - plan: A plan produced by NewPlan or BuildPlan
+//
+// Returns:
+//   - *Report: The remediation outcome and audit trail, also returned with an error
+//   - error: The first API error encountered, if any, or an error if DeviceID is missing
+//     outside a dry run. Failed runs and outstanding fixes are not errors.
+//
+// Example:
+//
+//	orchestrator := remediation.NewOrchestrator(remediation.NewSource(client), &remediation.Options{
+//	    DeviceID:      deviceIDs.Lookup,
+//	    RunTimeout:    time.Hour,
+//	    VerifyTimeout: 2 * time.Hour,
+//	})
+//	report, err := orchestrator.Execute(ctx, plan)
+//	if err == nil {
+//	    fmt.Printf("%d fixed, %d outstanding\n", report.Summary.Fixed, report.Summary.Outstanding)
+//	}
+func (o *Orchestrator) Execute(ctx context.Context, plan *Plan) (*Report, error) {
+	report := newReport(plan, o.options.DryRun)
+	defer func() { report.FinishedAt = time.Now().UTC() }()
+	if len(plan.Targets) == 0 {
+		return report, nil
+	}
+	if !o.options.DryRun && o.options.DeviceID == nil {
+		return report, fmt.Errorf("Options.DeviceID is required to create brew commands: device_ids takes device UUIDs, not serial numbers")
+	}
+
+	wave := rollout.Wave{Number: 1, Commands: make([]rollout.Command, 0, len(plan.Targets))}
+	for _, target := range plan.Targets {
+		wave.Commands = append(wave.Commands, rollout.Command{
+			Arguments: target.Arguments,
+			Type:      rollout.PackageFormula,
+			Packages:  []string{target.Formula},
+			Devices:   target.Devices,
+		})
+		wave.Devices = append(wave.Devices, target.Devices...)
+	}
+	slices.Sort(wave.Devices)
+	wave.Devices = slices.Compact(wave.Devices)
+
+	executor := rollout.NewExecutor(o.src.BrewCommands, &rollout.ExecuteOptions{
+		WaveTimeout: o.options.RunTimeout,
+		RunAfter:    o.options.RunAfter,
+		DeviceID:    o.options.DeviceID,
+		Wait:        o.options.Wait,
+		DryRun:      o.options.DryRun,
+		Logger:      o.options.Logger,
+	})
+	result, err := executor.Execute(ctx, &rollout.Plan{Waves: []rollout.Wave{wave}})
+	if len(result.Waves) > 0 {
+		report.recordRuns(result.Waves[0].Commands)
+	}
+	if err != nil {
+		report.summarize()
+		return report, fmt.Errorf("failed to schedule upgrades: %w", err)
+	}
+	if o.options.DryRun {
+		report.summarize()
+		return report, nil
+	}
+
+	if err := o.verify(ctx, report); err != nil {
+		return report, err
+	}
+	for i := range report.Targets {
+		target := &report.Targets[i]
+		for _, device := range target.Devices {
+			if !device.Fixed {
+				report.audit(ActionOutstanding, target.Formula, device.Device, target.Label, "fix not reported by Workbrew")
+			}
+		}
+	}
+	o.options.Logger.Info("Remediation finished",
+		zap.Int("fixed", report.Summary.Fixed),
+		zap.Int("outstanding", report.Summary.Outstanding))
+	return report, nil
+}
+
+// verify checks for fixes until every device whose run did not fail is fixed,
+// or the verify timeout passes
+func (o *Orchestrator) verify(ctx context.Context, report *Report) error {
+	deadline := time.Now().Add(o.options.VerifyTimeout)
+	for {
+		if err := o.Verify(ctx, report); err != nil {
+			return err
+		}
+		if report.awaiting() == 0 || !time.Now().Add(o.options.VerifyInterval).Before(deadline) {
+			return nil
+		}
+		o.options.Logger.Info("Waiting for fixes",
+			zap.Int("awaiting", report.awaiting()),
+			zap.Duration("interval", o.options.VerifyInterval))
+
+		timer := time.NewTimer(o.options.VerifyInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Verify checks ListVulnerabilities and ListVulnerabilityChanges once and marks
+// the report's devices that are fixed. A device is fixed when it is no longer
+// among the formula's outdated devices and every finding has a "fixed" change
+// for the device that occurred after the report started, less Options.ClockSkew.
+// Verify can be called again later to re-check a report.
+//
+// Parameters:
+//   - ctx: Context for the API calls
+//   - report: A report returned by Execute
+//
+// Returns:
+//   - error: Any error returned by the API
+func (o *Orchestrator) Verify(ctx context.Context, report *Report) error {
+	vulns, _, err := o.src.Vulnerabilities.ListVulnerabilities(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list vulnerabilities: %w", err)
+	}
+	changes, _, err := o.src.VulnerabilityChanges.ListVulnerabilityChanges(ctx, &vulnerabilitychanges.RequestQueryOptions{
+		Status: vulnerabilitychanges.StatusFixed,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list vulnerability changes: %w", err)
+	}
+
+	since := report.StartedAt.Add(-o.options.ClockSkew)
+	outdated := make(map[string][]string, len(*vulns))
+	for _, vuln := range *vulns {
+		outdated[vuln.Formula] = append(outdated[vuln.Formula], vuln.OutdatedDevices...)
+	}
+
+	for i := range report.Targets {
+		target := &report.Targets[i]
+		for j := range target.Devices {
+			device := &target.Devices[j]
+			if device.Fixed {
+				continue
+			}
+
+			device.FixedVulnerabilities = []string{}
+			device.FixedAt = nil
+			for _, change := range *changes {
+				if change.Status != vulnerabilitychanges.StatusFixed ||
+					change.FormulaName != target.Formula ||
+					change.DeviceSerialNumber == nil || *change.DeviceSerialNumber != device.Device ||
+					change.OccurredAt.Before(since) ||
+					!target.hasFinding(change.VulnerabilityID) {
+					continue
+				}
+				if !slices.Contains(device.FixedVulnerabilities, change.VulnerabilityID) {
+					device.FixedVulnerabilities = append(device.FixedVulnerabilities, change.VulnerabilityID)
+				}
+				if device.FixedAt == nil || change.OccurredAt.After(*device.FixedAt) {
+					occurredAt := change.OccurredAt
+					device.FixedAt = &occurredAt
+				}
+			}
+			slices.Sort(device.FixedVulnerabilities)
+
+			if slices.Contains(outdated[target.Formula], device.Device) || len(device.FixedVulnerabilities) < len(target.Findings) {
+				continue
+			}
+			device.Fixed = true
+			report.audit(ActionFixed, target.Formula, device.Device, target.Label, strings.Join(device.FixedVulnerabilities, ", "))
+			o.options.Logger.Info("Vulnerability fixed",
+				zap.String("formula", target.Formula),
+				zap.String("device", device.Device))
+		}
+	}
+
+	report.summarize()
+	return nil
+}
+
+// newReport creates a report for a plan and audits its targets
+func newReport(plan *Plan, dryRun bool) *Report {
+	report := &Report{
+		MinCVSSScore: plan.MinCVSSScore,
+		StartedAt:    time.Now().UTC(),
+		DryRun:       dryRun,
+		Targets:      make([]TargetReport, 0, len(plan.Targets)),
+		Skipped:      plan.Skipped,
+		Audit:        []AuditEntry{},
+	}
+	for _, target := range plan.Targets {
+		targetReport := TargetReport{
+			Formula:   target.Formula,
+			Arguments: target.Arguments,
+			Findings:  target.Findings,
+			Devices:   make([]DeviceReport, 0, len(target.Devices)),
+		}
+		ids := make([]string, 0, len(target.Findings))
+		for _, finding := range target.Findings {
+			ids = append(ids, finding.ID)
+		}
+		for _, device := range target.Devices {
+			targetReport.Devices = append(targetReport.Devices, DeviceReport{Device: device, FixedVulnerabilities: []string{}})
+			report.audit(ActionPlanned, target.Formula, device, "", strings.Join(ids, ", "))
+		}
+		report.Targets = append(report.Targets, targetReport)
+	}
+	report.summarize()
+	return report
+}
+
+// recordRuns copies the labels and run states of the created brew commands into the report
+func (r *Report) recordRuns(commands []rollout.CommandResult) {
+	for _, command := range commands {
+		target := r.target(command.Command.Packages[0])
+		if target == nil {
+			continue
+		}
+		target.Label = command.Label
+		if r.DryRun {
+			r.audit(ActionScheduled, target.Formula, "", "", "dry run: brew "+target.Arguments)
+			continue
+		}
+		r.audit(ActionScheduled, target.Formula, "", target.Label, "brew "+target.Arguments)
+		if command.Runs == nil {
+			continue
+		}
+		for _, outcome := range command.Runs.Devices {
+			for i := range target.Devices {
+				if target.Devices[i].Device != outcome.Device {
+					continue
+				}
+				target.Devices[i].RunStatus = outcome.Status
+				switch outcome.Status {
+				case waiter.StatusSucceeded:
+					r.audit(ActionRunSucceeded, target.Formula, outcome.Device, target.Label, "")
+				case waiter.StatusFailed:
+					r.audit(ActionRunFailed, target.Formula, outcome.Device, target.Label, outcome.Output)
+				default:
+					r.audit(ActionRunPending, target.Formula, outcome.Device, target.Label, string(outcome.Status))
+				}
+			}
+		}
+	}
+}
+
+// target returns the report of a formula
+func (r *Report) target(formula string) *TargetReport {
+	for i := range r.Targets {
+		if r.Targets[i].Formula == formula {
+			return &r.Targets[i]
+		}
+	}
+	return nil
+}
+
+// awaiting counts devices that are not fixed and whose run did not fail
+func (r *Report) awaiting() int {
+	count := 0
+	for _, target := range r.Targets {
+		for _, device := range target.Devices {
+			if !device.Fixed && device.RunStatus != waiter.StatusFailed {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// summarize recounts the summary
+func (r *Report) summarize() {
+	r.Summary = Summary{Targets: len(r.Targets)}
+	for _, target := range r.Targets {
+		for _, device := range target.Devices {
+			r.Summary.Devices++
+			switch device.RunStatus {
+			case waiter.StatusSucceeded:
+				r.Summary.RunsSucceeded++
+			case waiter.StatusFailed:
+				r.Summary.RunsFailed++
+			}
+			if device.Fixed {
+				r.Summary.Fixed++
+			} else {
+				r.Summary.Outstanding++
+			}
+		}
+	}
+}
+
+// audit appends an audit entry
+func (r *Report) audit(action, formula, device, label, detail string) {
+	r.Audit = append(r.Audit, AuditEntry{
+		Time:    time.Now().UTC(),
+		Action:  action,
+		Formula: formula,
+		Device:  device,
+		Label:   label,
+		Detail:  detail,
+	})
+}
+
+// hasFinding reports whether the target includes a vulnerability
+func (t *TargetReport) hasFinding(id string) bool {
+	for _, finding := range t.Findings {
+		if finding.ID == id {
+			return true
+		}
+	}
+	return false
+}
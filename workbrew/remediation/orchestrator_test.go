@@ -0,0 +1,329 @@
+package remediation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/waiter"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves runs that fail on the Linux device
+func newTestServer(t *testing.T) (*workbrewtest.Server, *workbrew.Client) {
+	t.Helper()
+
+	server := workbrewtest.NewServer(
+		workbrewtest.WithRunResult(func(device, command string) (string, bool) {
+			if device == workbrewtest.FixtureDeviceLinux {
+				return "Error: No space left on device", false
+			}
+			return "==> Upgrading", true
+		}),
+	)
+	t.Cleanup(server.Close)
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	return server, wb
+}
+
+// deviceUUID resolves serial numbers to the UUIDs the fake accepts in device_ids
+func deviceUUID(serial string) (string, error) {
+	return workbrewtest.DeviceUUID(serial), nil
+}
+
+// fix reports a formula's vulnerability as fixed on a device
+func fix(t *testing.T, server *workbrewtest.Server, formula, vulnerabilityID, device string) {
+	t.Helper()
+	fixAt(t, server, formula, vulnerabilityID, device, time.Now().UTC())
+}
+
+// fixAt reports a formula's vulnerability as fixed on a device at a time on the server's clock
+func fixAt(t *testing.T, server *workbrewtest.Server, formula, vulnerabilityID, device string, occurredAt time.Time) {
+	t.Helper()
+	err := server.Update(workbrewtest.DefaultWorkspace, func(f *workbrewtest.Fixtures) {
+		for i := range f.Vulnerabilities {
+			if f.Vulnerabilities[i].Formula != formula {
+				continue
+			}
+			var outdated []string
+			for _, d := range f.Vulnerabilities[i].OutdatedDevices {
+				if d != device {
+					outdated = append(outdated, d)
+				}
+			}
+			f.Vulnerabilities[i].OutdatedDevices = outdated
+		}
+		f.VulnerabilityChanges = append(f.VulnerabilityChanges, vulnerabilitychanges.VulnerabilityChange{
+			ID:                 formula + "-" + device + "-fixed",
+			EventType:          "vulnerability.fixed",
+			OccurredAt:         occurredAt,
+			Status:             vulnerabilitychanges.StatusFixed,
+			DeviceSerialNumber: &device,
+			FormulaName:        formula,
+			VulnerabilityID:    vulnerabilityID,
+		})
+	})
+	require.NoError(t, err)
+}
+
+// actions returns the audit actions in order
+func actions(report *Report) []string {
+	var result []string
+	for _, entry := range report.Audit {
+		result = append(result, entry.Action)
+	}
+	return result
+}
+
+func TestExecute_VerifiesFixes(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, nil)
+	require.NoError(t, err)
+
+	// Workbrew reports the fix once the run has finished
+	var once sync.Once
+	report, err := NewOrchestrator(NewSource(wb), &Options{
+		DeviceID: deviceUUID,
+		Wait: &waiter.Options{
+			InitialInterval: time.Millisecond,
+			OnProgress: func(p waiter.Progress) {
+				if p.Finished == p.Total {
+					once.Do(func() { fix(t, server, "wget", "CVE-2024-10524", workbrewtest.FixtureDeviceMacBook) })
+				}
+			},
+		},
+		VerifyTimeout:  time.Second,
+		VerifyInterval: time.Millisecond,
+	}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+
+	require.Len(t, report.Targets, 1)
+	target := report.Targets[0]
+	assert.Equal(t, "upgrade-formula-wget", target.Label)
+	require.Len(t, target.Devices, 1)
+	device := target.Devices[0]
+	assert.Equal(t, waiter.StatusSucceeded, device.RunStatus)
+	assert.True(t, device.Fixed)
+	assert.NotNil(t, device.FixedAt)
+	assert.Equal(t, []string{"CVE-2024-10524"}, device.FixedVulnerabilities)
+	assert.Equal(t, Summary{Targets: 1, Devices: 1, RunsSucceeded: 1, Fixed: 1}, report.Summary)
+	assert.Equal(t, []string{ActionPlanned, ActionScheduled, ActionRunSucceeded, ActionFixed}, actions(report))
+	assert.False(t, report.FinishedAt.Before(report.StartedAt))
+
+	commands := server.Snapshot(workbrewtest.DefaultWorkspace).BrewCommands
+	assert.Equal(t, "brew upgrade --formula wget", commands[len(commands)-1].Command)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceMacBook}, commands[len(commands)-1].Devices)
+}
+
+func TestExecute_Outstanding(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, &PlanOptions{MinCVSSScore: 6})
+	require.NoError(t, err)
+
+	orchestrator := NewOrchestrator(NewSource(wb), &Options{DeviceID: deviceUUID, Wait: &waiter.Options{InitialInterval: time.Millisecond}})
+	report, err := orchestrator.Execute(context.Background(), plan)
+	require.NoError(t, err)
+
+	require.Len(t, report.Targets, 2)
+	curl := report.Targets[1]
+	assert.Equal(t, "curl", curl.Formula)
+	assert.Equal(t, waiter.StatusFailed, curl.Devices[0].RunStatus)
+	assert.Equal(t, Summary{Targets: 2, Devices: 3, RunsSucceeded: 2, RunsFailed: 1, Outstanding: 3}, report.Summary)
+	failed := report.Audit[6]
+	failed.Time = time.Time{}
+	assert.Equal(t, AuditEntry{
+		Action:  ActionRunFailed,
+		Formula: "curl",
+		Device:  workbrewtest.FixtureDeviceLinux,
+		Label:   "upgrade-formula-curl",
+		Detail:  "Error: No space left on device",
+	}, failed)
+
+	// A fix of a vulnerability outside the plan, or with the device still outdated, does not count
+	fix(t, server, "curl", "CVE-0000-0000", workbrewtest.FixtureDeviceMacBook)
+	require.NoError(t, server.Update(workbrewtest.DefaultWorkspace, func(f *workbrewtest.Fixtures) {
+		f.VulnerabilityChanges = append(f.VulnerabilityChanges, vulnerabilitychanges.VulnerabilityChange{
+			ID:                 "wget-fixed",
+			OccurredAt:         time.Now().UTC(),
+			Status:             vulnerabilitychanges.StatusFixed,
+			DeviceSerialNumber: ptr(workbrewtest.FixtureDeviceMacBook),
+			FormulaName:        "wget",
+			VulnerabilityID:    "CVE-2024-10524",
+		})
+	}))
+	require.NoError(t, orchestrator.Verify(context.Background(), report))
+	assert.Equal(t, 0, report.Summary.Fixed)
+	assert.Equal(t, []string{"CVE-2024-10524"}, report.Targets[0].Devices[0].FixedVulnerabilities)
+
+	// A later re-check picks up fixes
+	fix(t, server, "wget", "CVE-2024-10524", workbrewtest.FixtureDeviceMacBook)
+	fix(t, server, "curl", "CVE-2024-2466", workbrewtest.FixtureDeviceMacBook)
+	require.NoError(t, orchestrator.Verify(context.Background(), report))
+	assert.Equal(t, 2, report.Summary.Fixed)
+	assert.Equal(t, 1, report.Summary.Outstanding)
+	assert.Equal(t, ActionFixed, report.Audit[len(report.Audit)-1].Action)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+	var decoded Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, report.Summary, decoded.Summary)
+}
+
+func TestExecute_OldFixIgnored(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, nil)
+	require.NoError(t, err)
+
+	// Fixed before, then detected again
+	require.NoError(t, server.Update(workbrewtest.DefaultWorkspace, func(f *workbrewtest.Fixtures) {
+		f.Vulnerabilities[1].OutdatedDevices = nil
+		f.VulnerabilityChanges = append(f.VulnerabilityChanges, vulnerabilitychanges.VulnerabilityChange{
+			ID:                 "old",
+			OccurredAt:         time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Status:             vulnerabilitychanges.StatusFixed,
+			DeviceSerialNumber: ptr(workbrewtest.FixtureDeviceMacBook),
+			FormulaName:        "wget",
+			VulnerabilityID:    "CVE-2024-10524",
+		})
+	}))
+
+	report, err := NewOrchestrator(NewSource(wb), &Options{DeviceID: deviceUUID, Wait: &waiter.Options{InitialInterval: time.Millisecond}}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Summary.Outstanding)
+	assert.Equal(t, ActionOutstanding, report.Audit[len(report.Audit)-1].Action)
+}
+
+func TestVerify_ClockSkew(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, nil)
+	require.NoError(t, err)
+
+	orchestrator := NewOrchestrator(NewSource(wb), &Options{
+		DeviceID:  deviceUUID,
+		ClockSkew: time.Minute,
+		Wait:      &waiter.Options{InitialInterval: time.Millisecond},
+	})
+	report, err := orchestrator.Execute(context.Background(), plan)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Summary.Outstanding)
+
+	// The server's clock is two minutes behind the local clock
+	fixAt(t, server, "wget", "CVE-2024-10524", workbrewtest.FixtureDeviceMacBook, report.StartedAt.Add(-2*time.Minute))
+	require.NoError(t, orchestrator.Verify(context.Background(), report))
+	assert.Equal(t, 0, report.Summary.Fixed, "outside a one minute tolerance")
+
+	require.NoError(t, NewOrchestrator(NewSource(wb), &Options{DeviceID: deviceUUID}).Verify(context.Background(), report))
+	assert.Equal(t, 1, report.Summary.Fixed, "within DefaultClockSkew")
+}
+
+func TestExecute_DeviceIDRequired(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, nil)
+	require.NoError(t, err)
+
+	report, err := NewOrchestrator(NewSource(wb), nil).Execute(context.Background(), plan)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Options.DeviceID is required")
+	assert.Equal(t, []string{ActionPlanned}, actions(report))
+	assert.Len(t, server.Snapshot(workbrewtest.DefaultWorkspace).BrewCommands, 1, "only the fixture command exists")
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestReport_WriteJSONError(t *testing.T) {
+	err := (&Report{}).WriteJSON(failingWriter{})
+	assert.EqualError(t, err, "failed to write report: disk full")
+}
+
+func TestExecute_DryRun(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, nil)
+	require.NoError(t, err)
+
+	report, err := NewOrchestrator(NewSource(wb), &Options{DryRun: true}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Empty(t, report.Targets[0].Label)
+	assert.Equal(t, []string{ActionPlanned, ActionScheduled}, actions(report))
+	assert.Len(t, server.Snapshot(workbrewtest.DefaultWorkspace).BrewCommands, 1, "only the fixture command exists")
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	_, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, nil)
+	require.NoError(t, err)
+	report, err := NewOrchestrator(NewSource(wb), &Options{DryRun: true}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+	var decoded struct {
+		Targets []struct {
+			Findings []map[string]any `json:"findings"`
+		} `json:"targets"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded.Targets, 1)
+	assert.Equal(t, []map[string]any{{"id": "CVE-2024-10524", "cvss_score": 9.1, "severity": "critical"}}, decoded.Targets[0].Findings)
+
+	// A written report reads back into the same findings
+	var roundTrip Report
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &roundTrip))
+	assert.Equal(t, report.Targets[0].Findings, roundTrip.Targets[0].Findings)
+}
+
+func TestExecute_RunAfter(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, nil)
+	require.NoError(t, err)
+
+	report, err := NewOrchestrator(NewSource(wb), &Options{
+		RunAfter:   time.Now().Add(time.Hour),
+		RunTimeout: 20 * time.Millisecond,
+		DeviceID:   deviceUUID,
+		Wait:       &waiter.Options{InitialInterval: time.Millisecond},
+	}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Equal(t, waiter.StatusPending, report.Targets[0].Devices[0].RunStatus)
+	assert.Contains(t, actions(report), ActionRunPending)
+	assert.Len(t, server.Snapshot(workbrewtest.DefaultWorkspace).BrewCommands, 2)
+}
+
+func TestExecute_CreateError(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, nil)
+	require.NoError(t, err)
+	server.InjectFault(workbrewtest.Fault{Method: "POST", Path: "/brew_commands.json", StatusCode: 403})
+
+	report, err := NewOrchestrator(NewSource(wb), &Options{DeviceID: deviceUUID}).Execute(context.Background(), plan)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to schedule upgrades: wave 1: failed to create brew command "upgrade --formula wget"`)
+	assert.Equal(t, []string{ActionPlanned}, actions(report))
+}
+
+func TestExecute_NothingToDo(t *testing.T) {
+	_, wb := newTestServer(t)
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, &PlanOptions{MinCVSSScore: 10})
+	require.NoError(t, err)
+
+	report, err := NewOrchestrator(NewSource(wb), nil).Execute(context.Background(), plan)
+	require.NoError(t, err)
+	assert.Empty(t, report.Targets)
+	assert.Empty(t, report.Audit)
+}
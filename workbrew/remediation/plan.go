@@ -0,0 +1,202 @@
+// Package remediation upgrades vulnerable formulae on exactly the devices that
+// need them and confirms the fix.
+//
+// A plan takes each vulnerability at or above a CVSS threshold from
+// ListVulnerabilities and targets a "brew upgrade --formula" at the formula's
+// outdated devices. Executing the plan creates the brew commands, waits for their
+// runs, then re-checks ListVulnerabilities and ListVulnerabilityChanges until every
+// device's vulnerabilities are reported fixed. Every step is recorded in an audit trail.
+package remediation
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+)
+
+// DefaultMinCVSSScore is the lowest CVSS score remediated when PlanOptions.MinCVSSScore is zero: high and critical
+const DefaultMinCVSSScore = 7.0
+
+// Finding is a vulnerability of a targeted formula
+type Finding struct {
+	ID        string                   `json:"id"`
+	CVSSScore *float64                 `json:"cvss_score,omitempty"`
+	Severity  vulnerabilities.Severity `json:"severity"`
+}
+
+// Target is one formula to upgrade on its vulnerable devices
+type Target struct {
+	Formula string `json:"formula"`
+
+	// Arguments are the brew arguments, e.g. "upgrade --formula curl"
+	Arguments string `json:"arguments"`
+
+	// Findings are the formula's vulnerabilities at or above the threshold, highest score first
+	Findings []Finding `json:"findings"`
+
+	// MaxCVSSScore is the highest score among the findings
+	MaxCVSSScore float64 `json:"max_cvss_score"`
+
+	// Devices are the formula's outdated devices, sorted
+	Devices []string `json:"devices"`
+}
+
+// Skipped is a vulnerable formula the plan does not upgrade
+type Skipped struct {
+	Formula string `json:"formula"`
+	Reason  string `json:"reason"`
+}
+
+// Plan lists the formulae to upgrade
+type Plan struct {
+	MinCVSSScore float64 `json:"min_cvss_score"`
+
+	// Targets are sorted by highest CVSS score, then formula
+	Targets []Target `json:"targets"`
+
+	// Skipped are formulae with vulnerabilities at or above the threshold that are not upgraded
+	Skipped []Skipped `json:"skipped"`
+}
+
+// Devices returns the number of formula upgrades the plan makes, counting a device once per formula
+func (p *Plan) Devices() int {
+	count := 0
+	for _, target := range p.Targets {
+		count += len(target.Devices)
+	}
+	return count
+}
+
+// String renders a human readable summary of the plan
+func (p *Plan) String() string {
+	var sb strings.Builder
+	for _, target := range p.Targets {
+		ids := make([]string, 0, len(target.Findings))
+		for _, finding := range target.Findings {
+			ids = append(ids, finding.ID)
+		}
+		fmt.Fprintf(&sb, "brew %s on %s (%s, CVSS %.1f)\n", target.Arguments, strings.Join(target.Devices, ", "), strings.Join(ids, ", "), target.MaxCVSSScore)
+	}
+	for _, skipped := range p.Skipped {
+		fmt.Fprintf(&sb, "skip %s: %s\n", skipped.Formula, skipped.Reason)
+	}
+	fmt.Fprintf(&sb, "Plan: %d formula(e) to upgrade on %d device(s), CVSS %.1f or higher.\n", len(p.Targets), p.Devices(), p.MinCVSSScore)
+	return sb.String()
+}
+
+// PlanOptions configures a plan
+type PlanOptions struct {
+	// MinCVSSScore is the lowest CVSS score remediated. Defaults to DefaultMinCVSSScore.
+	// Vulnerabilities without a score are never remediated.
+	MinCVSSScore float64
+
+	// Exclude names formulae that are never upgraded
+	Exclude []string
+}
+
+// BuildPlan plans upgrades from a ListVulnerabilities response
+//
+// Parameters:
+//   - vulns: The vulnerabilities to remediate
+//   - options: Plan options; may be nil
+//
+// Returns:
+//   - *Plan: The formulae to upgrade
+//   - error: If the threshold is outside 0 to 10
+func BuildPlan(vulns vulnerabilities.VulnerabilitiesResponse, options *PlanOptions) (*Plan, error) {
+	if options == nil {
+		options = &PlanOptions{}
+	}
+	threshold := options.MinCVSSScore
+	if threshold < 0 || threshold > 10 {
+		return nil, fmt.Errorf("minimum CVSS score %.1f must be between 0 and 10", threshold)
+	}
+	if threshold == 0 {
+		threshold = DefaultMinCVSSScore
+	}
+
+	plan := &Plan{MinCVSSScore: threshold, Targets: []Target{}, Skipped: []Skipped{}}
+	for _, vuln := range vulns {
+		var findings []Finding
+		for _, detail := range vuln.Vulnerabilities {
+			if detail.CVSSScore != nil && *detail.CVSSScore >= threshold {
+				findings = append(findings, Finding{
+					ID:        detail.CleanID,
+					CVSSScore: detail.CVSSScore,
+					Severity:  vulnerabilities.SeverityForScore(*detail.CVSSScore),
+				})
+			}
+		}
+		if len(findings) == 0 {
+			continue
+		}
+
+		switch {
+		case slices.Contains(options.Exclude, vuln.Formula):
+			plan.Skipped = append(plan.Skipped, Skipped{Formula: vuln.Formula, Reason: "excluded"})
+			continue
+		case !vuln.Supported:
+			plan.Skipped = append(plan.Skipped, Skipped{Formula: vuln.Formula, Reason: "not supported by Workbrew"})
+			continue
+		case len(vuln.OutdatedDevices) == 0:
+			plan.Skipped = append(plan.Skipped, Skipped{Formula: vuln.Formula, Reason: "no outdated devices"})
+			continue
+		}
+
+		arguments, err := brewcommands.Upgrade(vuln.Formula).WithFlags(brewcommands.FlagFormula).Arguments()
+		if err != nil {
+			plan.Skipped = append(plan.Skipped, Skipped{Formula: vuln.Formula, Reason: err.Error()})
+			continue
+		}
+
+		slices.SortFunc(findings, func(a, b Finding) int {
+			if c := cmp.Compare(*b.CVSSScore, *a.CVSSScore); c != 0 {
+				return c
+			}
+			return strings.Compare(a.ID, b.ID)
+		})
+		devices := slices.Clone(vuln.OutdatedDevices)
+		slices.Sort(devices)
+		plan.Targets = append(plan.Targets, Target{
+			Formula:      vuln.Formula,
+			Arguments:    arguments,
+			Findings:     findings,
+			MaxCVSSScore: *findings[0].CVSSScore,
+			Devices:      slices.Compact(devices),
+		})
+	}
+
+	slices.SortFunc(plan.Targets, func(a, b Target) int {
+		if c := cmp.Compare(b.MaxCVSSScore, a.MaxCVSSScore); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Formula, b.Formula)
+	})
+	slices.SortFunc(plan.Skipped, func(a, b Skipped) int {
+		return strings.Compare(a.Formula, b.Formula)
+	})
+	return plan, nil
+}
+
+// NewPlan lists vulnerabilities and plans upgrades
+//
+// Parameters:
+//   - ctx: Context for the API call
+//   - service: The vulnerabilities service (e.g. client.Vulnerabilities)
+//   - options: Plan options; may be nil
+//
+// Returns:
+//   - *Plan: The formulae to upgrade
+//   - error: Any error returned by the API, or an invalid threshold
+func NewPlan(ctx context.Context, service vulnerabilities.VulnerabilitiesServiceInterface, options *PlanOptions) (*Plan, error) {
+	vulns, _, err := service.ListVulnerabilities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vulnerabilities: %w", err)
+	}
+	return BuildPlan(*vulns, options)
+}
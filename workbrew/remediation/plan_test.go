@@ -0,0 +1,73 @@
+package remediation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlan(t *testing.T) {
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	plan, err := NewPlan(context.Background(), wb.Vulnerabilities, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultMinCVSSScore, plan.MinCVSSScore)
+	require.Len(t, plan.Targets, 1)
+	target := plan.Targets[0]
+	assert.Equal(t, "wget", target.Formula)
+	assert.Equal(t, "upgrade --formula wget", target.Arguments)
+	assert.Equal(t, 9.1, target.MaxCVSSScore)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceMacBook}, target.Devices)
+	require.Len(t, target.Findings, 1)
+	assert.Equal(t, "CVE-2024-10524", target.Findings[0].ID)
+	assert.Equal(t, vulnerabilities.SeverityCritical, target.Findings[0].Severity)
+	assert.Empty(t, plan.Skipped)
+	assert.Equal(t, "brew upgrade --formula wget on TC6R2DHVHG (CVE-2024-10524, CVSS 9.1)\n"+
+		"Plan: 1 formula(e) to upgrade on 1 device(s), CVSS 7.0 or higher.\n", plan.String())
+}
+
+func TestBuildPlan_Threshold(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures()
+
+	plan, err := BuildPlan(fixtures.Vulnerabilities, &PlanOptions{MinCVSSScore: 6.5})
+	require.NoError(t, err)
+	require.Len(t, plan.Targets, 2)
+	assert.Equal(t, "wget", plan.Targets[0].Formula, "highest score first")
+	assert.Equal(t, "curl", plan.Targets[1].Formula)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceLinux, workbrewtest.FixtureDeviceMacBook}, plan.Targets[1].Devices)
+	assert.Equal(t, 3, plan.Devices())
+
+	_, err = BuildPlan(fixtures.Vulnerabilities, &PlanOptions{MinCVSSScore: 11})
+	assert.EqualError(t, err, "minimum CVSS score 11.0 must be between 0 and 10")
+}
+
+func TestBuildPlan_Skipped(t *testing.T) {
+	vulns := vulnerabilities.VulnerabilitiesResponse{
+		{Formula: "curl", Supported: true, OutdatedDevices: []string{"A"}, Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-1", CVSSScore: ptr(8.0)}}},
+		{Formula: "openssl@3", Supported: false, OutdatedDevices: []string{"A"}, Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-2", CVSSScore: ptr(9.8)}}},
+		{Formula: "git", Supported: true, Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-3", CVSSScore: ptr(7.5)}}},
+		{Formula: "jq", Supported: true, OutdatedDevices: []string{"A"}, Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-4"}}},
+	}
+
+	plan, err := BuildPlan(vulns, &PlanOptions{Exclude: []string{"curl"}})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Targets)
+	assert.Equal(t, []Skipped{
+		{Formula: "curl", Reason: "excluded"},
+		{Formula: "git", Reason: "no outdated devices"},
+		{Formula: "openssl@3", Reason: "not supported by Workbrew"},
+	}, plan.Skipped, "unscored vulnerabilities are ignored")
+}
+
+// ptr returns a pointer to v
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,70 @@
+package rollout
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// DeviceIDs maps device serial numbers to the device UUIDs a brew command's
+// device_ids takes. The API lists devices by serial number only, so the mapping
+// comes from outside the SDK, e.g. a Workbrew Console export.
+//
+// Example:
+//
+//	ids, err := rollout.LoadDeviceIDs("device-ids.json")
+//	if err != nil {
+//	    return err
+//	}
+//	executor := rollout.NewExecutor(client.BrewCommands, &rollout.ExecuteOptions{
+//	    DeviceID: ids.Lookup,
+//	})
+type DeviceIDs map[string]string
+
+// Lookup returns the UUID of a device serial number. It has the signature of
+// ExecuteOptions.DeviceID.
+func (ids DeviceIDs) Lookup(serial string) (string, error) {
+	id, ok := ids[serial]
+	if !ok {
+		return "", fmt.Errorf("no device UUID for serial number %s", serial)
+	}
+	return id, nil
+}
+
+// ParseDeviceIDs decodes a JSON object mapping serial numbers to device UUIDs,
+// e.g. {"TC6R2DHVHG": "5e4f2a3b-..."}
+//
+// Parameters:
+//   - data: The JSON object
+//
+// Returns:
+//   - DeviceIDs: The mapping
+//   - error: A decoding error, or an error if a value is not a UUID
+func ParseDeviceIDs(data []byte) (DeviceIDs, error) {
+	var ids DeviceIDs
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("failed to decode device IDs: %w", err)
+	}
+	for serial, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("device ID %q for serial number %s is not a UUID", id, serial)
+		}
+	}
+	return ids, nil
+}
+
+// LoadDeviceIDs reads a JSON file mapping serial numbers to device UUIDs
+func LoadDeviceIDs(path string) (DeviceIDs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device IDs: %w", err)
+	}
+	ids, err := ParseDeviceIDs(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,41 @@
+package rollout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeviceIDs(t *testing.T) {
+	ids, err := ParseDeviceIDs([]byte(`{"TC6R2DHVHG": "7c1e8f0a-3b2d-4e5f-9a6b-1c2d3e4f5a6b"}`))
+	require.NoError(t, err)
+
+	id, err := ids.Lookup("TC6R2DHVHG")
+	require.NoError(t, err)
+	assert.Equal(t, "7c1e8f0a-3b2d-4e5f-9a6b-1c2d3e4f5a6b", id)
+
+	_, err = ids.Lookup("1234567890")
+	assert.EqualError(t, err, "no device UUID for serial number 1234567890")
+}
+
+func TestParseDeviceIDs_Errors(t *testing.T) {
+	_, err := ParseDeviceIDs([]byte(`{"TC6R2DHVHG": "TC6R2DHVHG"}`))
+	assert.EqualError(t, err, `device ID "TC6R2DHVHG" for serial number TC6R2DHVHG is not a UUID`)
+
+	_, err = ParseDeviceIDs([]byte(`["TC6R2DHVHG"]`))
+	assert.ErrorContains(t, err, "failed to decode device IDs")
+}
+
+func TestLoadDeviceIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "device-ids.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"TC6R2DHVHG": "not-a-uuid"}`), 0o600))
+
+	_, err := LoadDeviceIDs(path)
+	assert.ErrorContains(t, err, "device-ids.json: device ID")
+
+	_, err = LoadDeviceIDs(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "failed to read device IDs")
+}
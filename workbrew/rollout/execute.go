@@ -0,0 +1,288 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/waiter"
+	"go.uber.org/zap"
+)
+
+// DefaultMinSuccessRate is the success rate a wave needs when ExecuteOptions.MinSuccessRate is zero
+const DefaultMinSuccessRate = 1.0
+
+// CommandResult is the outcome of one brew command created for a wave
+type CommandResult struct {
+	Command Command `json:"command"`
+
+	// Label is the label Workbrew gave the brew command; empty in dry runs
+	Label string `json:"label,omitempty"`
+
+	// Runs holds each targeted device's run; nil in dry runs
+	Runs *waiter.Result `json:"runs,omitempty"`
+}
+
+// WaveResult is the outcome of one wave
+type WaveResult struct {
+	Wave     int             `json:"wave"`
+	Commands []CommandResult `json:"commands"`
+
+	// Succeeded and Total count runs; a device upgrading formulae and casks has two
+	Succeeded int `json:"succeeded"`
+	Total     int `json:"total"`
+
+	// SuccessRate is Succeeded divided by Total. Runs that had not finished count as unsuccessful.
+	SuccessRate float64 `json:"success_rate"`
+
+	// Passed reports whether the success rate met the gate
+	Passed bool `json:"passed"`
+}
+
+// Result records the waves executed
+type Result struct {
+	Waves []WaveResult `json:"waves"`
+
+	// Completed is true when every wave was executed and passed its gate
+	Completed bool `json:"completed"`
+
+	// HaltedAfter is the number of the wave whose gate stopped the rollout, or 0
+	HaltedAfter int `json:"halted_after,omitempty"`
+
+	// DryRun is true when no brew commands were created
+	DryRun bool `json:"dry_run"`
+}
+
+// ExecuteOptions configures an Executor
+type ExecuteOptions struct {
+	// MinSuccessRate is the fraction of a wave's runs, from 0 to 1, that must
+	// succeed before the next wave starts. Defaults to DefaultMinSuccessRate.
+	MinSuccessRate float64
+
+	// WaveTimeout bounds the wait for each wave's runs. Zero waits until the
+	// context is done. Runs that have not finished by then count as unsuccessful.
+	WaveTimeout time.Duration
+
+	// RunAfter schedules every brew command to run after this time. Zero runs them immediately.
+	RunAfter time.Time
+
+	// DeviceID converts a device serial number to the device UUID sent in a
+	// brew command's device_ids, e.g. DeviceIDs.Lookup. Required unless DryRun
+	// is set: device_ids takes UUIDs, and a serial number would not match a device.
+	DeviceID func(serial string) (string, error)
+
+	// Wait configures polling of runs. Devices is set per command.
+	Wait *waiter.Options
+
+	// DryRun logs the brew commands without creating them
+	DryRun bool
+
+	// OnWave is called after each wave, from the executing goroutine
+	OnWave func(WaveResult)
+
+	// Logger receives progress messages. Defaults to a no-op logger.
+	Logger *zap.Logger
+}
+
+// Executor creates the brew commands of a plan one wave at a time
+type Executor struct {
+	service brewcommands.BrewCommandsServiceInterface
+	options ExecuteOptions
+}
+
+// NewExecutor creates an executor using the given brew commands service
+//
+// Parameters:
+//   - service: The brew commands service (e.g. client.BrewCommands)
+//   - options: Executor options; nil requires every run to succeed
+func NewExecutor(service brewcommands.BrewCommandsServiceInterface, options *ExecuteOptions) *Executor {
+	e := &Executor{service: service}
+	if options != nil {
+		e.options = *options
+	}
+	if e.options.MinSuccessRate <= 0 {
+		e.options.MinSuccessRate = DefaultMinSuccessRate
+	}
+	if e.options.Logger == nil {
+		e.options.Logger = zap.NewNop()
+	}
+	return e
+}
+
+// Execute runs the plan's waves in order. For each wave it creates the brew
+// commands with CreateBrewCommand, waits for their runs with ListBrewCommandRuns,
+// and stops before the next wave if the wave's success rate is below the gate.
+//
+// Parameters:
+//   - ctx: Context for the API calls; its deadline bounds the whole rollout
+//   - plan: A plan produced by NewPlan or BuildPlan
+//
+// Returns:
+//   - *Result: The waves executed, also returned with an error
+//   - error: The first API error encountered, if any, or an error if DeviceID is
+//     missing outside a dry run. A wave failing its gate is not an error.
+//
+// Example:
+//
+//	executor := rollout.NewExecutor(client.BrewCommands, &rollout.ExecuteOptions{
+//	    DeviceID:       deviceIDs.Lookup,
+//	    MinSuccessRate: 0.95,
+//	    WaveTimeout:    time.Hour,
+//	})
+//	result, err := executor.Execute(ctx, plan)
+//	if err == nil && !result.Completed {
+//	    fmt.Printf("rollout halted after wave %d\n", result.HaltedAfter)
+//	}
+func (e *Executor) Execute(ctx context.Context, plan *Plan) (*Result, error) {
+	result := &Result{Waves: []WaveResult{}, DryRun: e.options.DryRun}
+	logger := e.options.Logger
+	if !e.options.DryRun && e.options.DeviceID == nil {
+		return result, fmt.Errorf("ExecuteOptions.DeviceID is required to create brew commands: device_ids takes device UUIDs, not serial numbers")
+	}
+
+	for _, wave := range plan.Waves {
+		waveResult, err := e.executeWave(ctx, wave)
+		if waveResult != nil {
+			result.Waves = append(result.Waves, *waveResult)
+		}
+		if err != nil {
+			return result, fmt.Errorf("wave %d: %w", wave.Number, err)
+		}
+		if e.options.OnWave != nil {
+			e.options.OnWave(*waveResult)
+		}
+
+		if !waveResult.Passed {
+			logger.Warn("Rollout halted, wave success rate below gate",
+				zap.Int("wave", wave.Number),
+				zap.Float64("success_rate", waveResult.SuccessRate),
+				zap.Float64("min_success_rate", e.options.MinSuccessRate))
+			result.HaltedAfter = wave.Number
+			return result, nil
+		}
+		logger.Info("Wave passed",
+			zap.Int("wave", wave.Number),
+			zap.Float64("success_rate", waveResult.SuccessRate))
+	}
+
+	result.Completed = true
+	return result, nil
+}
+
+// executeWave creates a wave's brew commands and waits for their runs
+func (e *Executor) executeWave(ctx context.Context, wave Wave) (*WaveResult, error) {
+	waveResult := &WaveResult{Wave: wave.Number, Commands: []CommandResult{}}
+
+	if e.options.DryRun {
+		for _, command := range wave.Commands {
+			e.options.Logger.Info("Dry run: would create brew command",
+				zap.Int("wave", wave.Number),
+				zap.String("arguments", command.Arguments),
+				zap.Strings("devices", command.Devices))
+			waveResult.Commands = append(waveResult.Commands, CommandResult{Command: command})
+		}
+		waveResult.SuccessRate = 1
+		waveResult.Passed = true
+		return waveResult, nil
+	}
+
+	for _, command := range wave.Commands {
+		label, err := e.create(ctx, command)
+		if err != nil {
+			return waveResult, err
+		}
+		e.options.Logger.Info("Created brew command",
+			zap.Int("wave", wave.Number),
+			zap.String("label", label),
+			zap.Strings("devices", command.Devices))
+		waveResult.Commands = append(waveResult.Commands, CommandResult{Command: command, Label: label})
+	}
+
+	waitCtx := ctx
+	if e.options.WaveTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, e.options.WaveTimeout)
+		defer cancel()
+	}
+
+	for i := range waveResult.Commands {
+		commandResult := &waveResult.Commands[i]
+		var opts waiter.Options
+		if e.options.Wait != nil {
+			opts = *e.options.Wait
+		}
+		opts.Devices = commandResult.Command.Devices
+		if opts.Logger == nil {
+			opts.Logger = e.options.Logger
+		}
+
+		runs, err := waiter.WaitForBrewCommand(waitCtx, e.service, commandResult.Label, &opts)
+		commandResult.Runs = runs
+		if err != nil && (ctx.Err() != nil || waitCtx.Err() == nil) {
+			// Only the wave timeout is tolerated; unfinished runs count against the gate
+			return waveResult, err
+		}
+		if runs != nil {
+			waveResult.Succeeded += runs.Count(waiter.StatusSucceeded)
+			waveResult.Total += len(runs.Devices)
+		}
+	}
+
+	if waveResult.Total > 0 {
+		waveResult.SuccessRate = float64(waveResult.Succeeded) / float64(waveResult.Total)
+	}
+	waveResult.Passed = waveResult.Total > 0 && waveResult.SuccessRate >= e.options.MinSuccessRate
+	return waveResult, nil
+}
+
+// create creates a brew command and returns its label. CreateBrewCommand does
+// not return the label, so it is the label that appears in ListBrewCommands for
+// the same command after the create but not before it.
+func (e *Executor) create(ctx context.Context, command Command) (string, error) {
+	deviceIDs := make([]string, 0, len(command.Devices))
+	for _, serial := range command.Devices {
+		id, err := e.options.DeviceID(serial)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve device %s: %w", serial, err)
+		}
+		deviceIDs = append(deviceIDs, id)
+	}
+	request, err := brewcommands.Upgrade(command.Packages...).
+		WithFlags(command.Type.flag()).
+		OnDevices(deviceIDs...).
+		RunAfter(e.options.RunAfter).
+		Build()
+	if err != nil {
+		return "", err
+	}
+
+	before, _, err := e.service.ListBrewCommands(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list brew commands: %w", err)
+	}
+	existing := make(map[string]bool, len(*before))
+	for _, existingCommand := range *before {
+		existing[existingCommand.Label] = true
+	}
+
+	if _, _, err := e.service.CreateBrewCommand(ctx, request); err != nil {
+		return "", fmt.Errorf("failed to create brew command %q: %w", request.Arguments, err)
+	}
+
+	after, _, err := e.service.ListBrewCommands(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list brew commands: %w", err)
+	}
+	var labels []string
+	for _, created := range *after {
+		if !existing[created.Label] && strings.TrimPrefix(created.Command, "brew ") == request.Arguments {
+			labels = append(labels, created.Label)
+		}
+	}
+	if len(labels) != 1 {
+		return "", fmt.Errorf("failed to identify the label of brew command %q: %d new matching commands", request.Arguments, len(labels))
+	}
+	return labels[0], nil
+}
@@ -0,0 +1,215 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/waiter"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves runs that fail for cask upgrades
+func newTestServer(t *testing.T) (*workbrewtest.Server, *workbrew.Client) {
+	t.Helper()
+
+	server := workbrewtest.NewServer(
+		workbrewtest.WithRunResult(func(device, command string) (string, bool) {
+			if strings.Contains(command, "--cask") {
+				return "Error: Cask is in use", false
+			}
+			return "==> Upgrading", true
+		}),
+	)
+	t.Cleanup(server.Close)
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	return server, wb
+}
+
+// testPlan upgrades the Admin group first, then the Linux device in All Devices
+func testPlan(t *testing.T, wb *workbrew.Client, options *Options) *Plan {
+	t.Helper()
+	if options == nil {
+		options = &Options{Waves: [][]string{{"Admin"}}}
+	}
+	plan, err := NewPlan(context.Background(), NewSource(wb), options)
+	require.NoError(t, err)
+	return plan
+}
+
+// deviceUUID resolves serial numbers to the UUIDs the fake accepts in device_ids
+func deviceUUID(serial string) (string, error) {
+	return workbrewtest.DeviceUUID(serial), nil
+}
+
+// fastWait polls runs without delay
+var fastWait = &waiter.Options{InitialInterval: time.Millisecond}
+
+func TestExecute_Completed(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan := testPlan(t, wb, &Options{Waves: [][]string{{"Admin"}}, Exclude: []string{"visual-studio-code"}})
+
+	var waves []int
+	result, err := NewExecutor(wb.BrewCommands, &ExecuteOptions{
+		Wait:     fastWait,
+		DeviceID: deviceUUID,
+		OnWave:   func(w WaveResult) { waves = append(waves, w.Wave) },
+	}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+
+	assert.True(t, result.Completed)
+	assert.Zero(t, result.HaltedAfter)
+	assert.Equal(t, []int{1, 2}, waves)
+	require.Len(t, result.Waves, 2)
+
+	canary := result.Waves[0]
+	require.Len(t, canary.Commands, 1)
+	assert.Equal(t, "upgrade-formula-curl-wget", canary.Commands[0].Label)
+	assert.Equal(t, 1, canary.Succeeded)
+	assert.Equal(t, 1, canary.Total)
+	assert.Equal(t, 1.0, canary.SuccessRate)
+	assert.True(t, canary.Passed)
+
+	rest := result.Waves[1]
+	require.Len(t, rest.Commands, 1)
+	assert.Equal(t, "upgrade-formula-curl", rest.Commands[0].Label)
+	commands := server.Snapshot(workbrewtest.DefaultWorkspace).BrewCommands
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceLinux}, commands[len(commands)-1].Devices)
+}
+
+func TestExecute_HaltsBelowGate(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan := testPlan(t, wb, nil)
+
+	result, err := NewExecutor(wb.BrewCommands, &ExecuteOptions{Wait: fastWait, DeviceID: deviceUUID}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+
+	assert.False(t, result.Completed)
+	assert.Equal(t, 1, result.HaltedAfter)
+	require.Len(t, result.Waves, 1)
+	canary := result.Waves[0]
+	assert.Equal(t, 1, canary.Succeeded)
+	assert.Equal(t, 2, canary.Total)
+	assert.Equal(t, 0.5, canary.SuccessRate)
+	assert.False(t, canary.Passed)
+	require.Len(t, canary.Commands, 2)
+	assert.Equal(t, "Error: Cask is in use", canary.Commands[1].Runs.Failed()[0].Output)
+
+	// The second wave was never created
+	for _, command := range server.Snapshot(workbrewtest.DefaultWorkspace).BrewCommands {
+		assert.NotEqual(t, "brew upgrade --formula curl", command.Command)
+	}
+}
+
+func TestExecute_LowerGate(t *testing.T) {
+	_, wb := newTestServer(t)
+	plan := testPlan(t, wb, nil)
+
+	result, err := NewExecutor(wb.BrewCommands, &ExecuteOptions{Wait: fastWait, MinSuccessRate: 0.5, DeviceID: deviceUUID}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+	assert.True(t, result.Completed)
+	assert.Len(t, result.Waves, 2)
+}
+
+func TestExecute_DeviceID(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan := testPlan(t, wb, nil)
+
+	_, err := NewExecutor(wb.BrewCommands, &ExecuteOptions{
+		Wait: fastWait,
+		DeviceID: func(serial string) (string, error) {
+			return "", fmt.Errorf("no ID for %s", serial)
+		},
+	}).Execute(context.Background(), plan)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "wave 1: failed to resolve device TC6R2DHVHG: no ID for TC6R2DHVHG")
+	assert.Len(t, server.Snapshot(workbrewtest.DefaultWorkspace).BrewCommands, 1, "only the fixture command exists")
+}
+
+func TestExecute_SendsDeviceUUIDs(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan := testPlan(t, wb, &Options{Waves: [][]string{{"Admin"}}, Exclude: []string{"visual-studio-code"}})
+
+	_, err := NewExecutor(wb.BrewCommands, &ExecuteOptions{Wait: fastWait, DeviceID: deviceUUID}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+
+	var sent []string
+	for _, request := range server.Requests() {
+		if request.Method != "POST" {
+			continue
+		}
+		var body struct {
+			DeviceIDs string `json:"device_ids"`
+		}
+		require.NoError(t, json.Unmarshal(request.Body, &body))
+		sent = append(sent, strings.Split(body.DeviceIDs, ",")...)
+	}
+	assert.Equal(t, []string{workbrewtest.DeviceUUID(workbrewtest.FixtureDeviceMacBook), workbrewtest.DeviceUUID(workbrewtest.FixtureDeviceLinux)}, sent)
+	assert.NotContains(t, sent, workbrewtest.FixtureDeviceMacBook)
+	assert.NotContains(t, sent, workbrewtest.FixtureDeviceLinux)
+}
+
+func TestExecute_DeviceIDRequired(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan := testPlan(t, wb, nil)
+
+	result, err := NewExecutor(wb.BrewCommands, &ExecuteOptions{Wait: fastWait}).Execute(context.Background(), plan)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ExecuteOptions.DeviceID is required")
+	assert.Empty(t, result.Waves)
+	assert.Len(t, server.Snapshot(workbrewtest.DefaultWorkspace).BrewCommands, 1, "only the fixture command exists")
+}
+
+func TestExecute_DryRun(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan := testPlan(t, wb, nil)
+
+	result, err := NewExecutor(wb.BrewCommands, &ExecuteOptions{DryRun: true}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+
+	assert.True(t, result.DryRun)
+	assert.True(t, result.Completed)
+	require.Len(t, result.Waves, 2)
+	assert.Len(t, result.Waves[0].Commands, 2)
+	assert.Empty(t, result.Waves[0].Commands[0].Label)
+	assert.Len(t, server.Snapshot(workbrewtest.DefaultWorkspace).BrewCommands, 1, "only the fixture command exists")
+}
+
+func TestExecute_CreateError(t *testing.T) {
+	server, wb := newTestServer(t)
+	plan := testPlan(t, wb, nil)
+	server.InjectFault(workbrewtest.Fault{Method: "POST", Path: "/brew_commands.json", StatusCode: 403})
+
+	result, err := NewExecutor(wb.BrewCommands, &ExecuteOptions{Wait: fastWait, DeviceID: deviceUUID}).Execute(context.Background(), plan)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to create brew command "upgrade --formula curl wget"`)
+	require.Len(t, result.Waves, 1)
+	assert.False(t, result.Completed)
+}
+
+func TestExecute_WaveTimeout(t *testing.T) {
+	server := workbrewtest.NewServer(workbrewtest.WithRunDuration(time.Hour))
+	t.Cleanup(server.Close)
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	plan := testPlan(t, wb, nil)
+
+	result, err := NewExecutor(wb.BrewCommands, &ExecuteOptions{
+		Wait:        fastWait,
+		WaveTimeout: 20 * time.Millisecond,
+		DeviceID:    deviceUUID,
+	}).Execute(context.Background(), plan)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.HaltedAfter)
+	assert.Equal(t, 0, result.Waves[0].Succeeded)
+	assert.Equal(t, 2, result.Waves[0].Total)
+}
@@ -0,0 +1,366 @@
+// Package rollout plans and executes staged upgrades of outdated formulae and casks.
+//
+// A plan groups the outdated packages installed on each device by device group
+// and orders the groups into waves, canary groups first. Executing the plan
+// creates "brew upgrade" commands for one wave at a time and only starts the
+// next wave when enough of the previous wave's runs succeeded.
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/casks"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+)
+
+// PackageType distinguishes formulae from casks
+type PackageType string
+
+const (
+	PackageFormula PackageType = "formula"
+	PackageCask    PackageType = "cask"
+)
+
+// flag returns the brew flag limiting an upgrade to the package type
+func (t PackageType) flag() brewcommands.Flag {
+	if t == PackageCask {
+		return brewcommands.FlagCask
+	}
+	return brewcommands.FlagFormula
+}
+
+// GroupUpgrades lists the outdated packages on a device group's members
+type GroupUpgrades struct {
+	GroupID string `json:"group_id"`
+	Group   string `json:"group"`
+
+	// Devices are the members with at least one outdated package, sorted
+	Devices  []string `json:"devices"`
+	Formulae []string `json:"formulae"`
+	Casks    []string `json:"casks"`
+}
+
+// Command is one brew upgrade to create. Devices with the same outdated
+// packages share a command, so every package named is installed on every device targeted.
+type Command struct {
+	// Arguments are the brew arguments, e.g. "upgrade --formula curl wget"
+	Arguments string      `json:"arguments"`
+	Type      PackageType `json:"type"`
+	Packages  []string    `json:"packages"`
+	Devices   []string    `json:"devices"`
+}
+
+// Wave is a set of device groups upgraded together
+type Wave struct {
+	// Number counts waves from 1
+	Number int      `json:"number"`
+	Groups []string `json:"groups"`
+
+	// Devices are the devices upgraded in this wave. A device in groups of
+	// several waves is upgraded in the earliest.
+	Devices  []string  `json:"devices"`
+	Commands []Command `json:"commands"`
+}
+
+// Plan is a staged upgrade of every outdated package
+type Plan struct {
+	// Groups lists the outdated packages per device group, sorted by name
+	Groups []GroupUpgrades `json:"groups"`
+
+	// Waves are executed in order
+	Waves []Wave `json:"waves"`
+
+	// Unassigned are devices with outdated packages in no device group. They are
+	// only upgraded, in the last wave, when Options.IncludeUngrouped is set.
+	Unassigned []string `json:"unassigned"`
+}
+
+// Devices returns the number of devices the plan upgrades
+func (p *Plan) Devices() int {
+	count := 0
+	for _, wave := range p.Waves {
+		count += len(wave.Devices)
+	}
+	return count
+}
+
+// String renders a human readable summary of the plan
+func (p *Plan) String() string {
+	var sb strings.Builder
+	for _, wave := range p.Waves {
+		fmt.Fprintf(&sb, "wave %d: %s (%d devices)\n", wave.Number, strings.Join(wave.Groups, ", "), len(wave.Devices))
+		for _, command := range wave.Commands {
+			fmt.Fprintf(&sb, "  brew %s on %s\n", command.Arguments, strings.Join(command.Devices, ", "))
+		}
+	}
+	if len(p.Unassigned) > 0 {
+		fmt.Fprintf(&sb, "not in any device group: %s\n", strings.Join(p.Unassigned, ", "))
+	}
+	fmt.Fprintf(&sb, "Plan: %d wave(s) upgrading %d device(s).\n", len(p.Waves), p.Devices())
+	return sb.String()
+}
+
+// Options configures a plan
+type Options struct {
+	// Waves lists the device group names upgraded in each wave, canary groups
+	// first. Groups not listed form a final wave. With no waves, every group is
+	// upgraded in a single wave.
+	Waves [][]string
+
+	// Exclude names formulae and casks that are never upgraded
+	Exclude []string
+
+	// IncludeUngrouped upgrades devices in no device group in the last wave
+	IncludeUngrouped bool
+}
+
+// Source holds the services a plan is built from
+type Source struct {
+	Formulae     formulae.FormulaeServiceInterface
+	Casks        casks.CasksServiceInterface
+	DeviceGroups devicegroups.DeviceGroupsServiceInterface
+}
+
+// NewSource creates a plan source backed by a Workbrew client
+func NewSource(client *workbrew.Client) *Source {
+	return &Source{
+		Formulae:     client.Formulae,
+		Casks:        client.Casks,
+		DeviceGroups: client.DeviceGroups,
+	}
+}
+
+// NewPlan lists formulae, casks and device groups and plans upgrade waves
+//
+// Parameters:
+//   - ctx: Context for the underlying API calls
+//   - src: The services to plan from. Casks may be nil to upgrade formulae only.
+//   - options: Plan options; may be nil
+//
+// Returns:
+//   - *Plan: The upgrade waves
+//   - error: Any error returned by the API, or invalid options
+//
+// Example:
+//
+//	plan, err := rollout.NewPlan(ctx, rollout.NewSource(client), &rollout.Options{
+//	    Waves: [][]string{{"Canary"}},
+//	})
+//	fmt.Print(plan)
+func NewPlan(ctx context.Context, src *Source, options *Options) (*Plan, error) {
+	if src == nil || src.Formulae == nil || src.DeviceGroups == nil {
+		return nil, fmt.Errorf("rollout source with formulae and device groups services is required")
+	}
+
+	formulaList, _, err := src.Formulae.ListFormulae(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list formulae: %w", err)
+	}
+
+	var caskList casks.CasksResponse
+	if src.Casks != nil {
+		response, _, err := src.Casks.ListCasks(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list casks: %w", err)
+		}
+		caskList = *response
+	}
+
+	groupList, _, err := src.DeviceGroups.ListDeviceGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device groups: %w", err)
+	}
+
+	return BuildPlan(*formulaList, caskList, *groupList, options)
+}
+
+// BuildPlan plans upgrade waves from responses already fetched, e.g. from a snapshot
+//
+// Parameters:
+//   - formulaList: Formulae; outdated ones are upgraded on the devices listed
+//   - caskList: Casks; outdated ones are upgraded on the devices listed
+//   - groups: Device groups, ordered into waves
+//   - options: Plan options; may be nil
+//
+// Returns:
+//   - *Plan: The upgrade waves
+//   - error: If a wave names an unknown group or a group is in two waves
+func BuildPlan(formulaList formulae.FormulaeResponse, caskList casks.CasksResponse, groups devicegroups.DeviceGroupsResponse, options *Options) (*Plan, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	// outdated maps each device to its outdated packages by type
+	outdated := make(map[string]map[PackageType][]string)
+	add := func(device string, packageType PackageType, name string) {
+		if outdated[device] == nil {
+			outdated[device] = make(map[PackageType][]string)
+		}
+		outdated[device][packageType] = append(outdated[device][packageType], name)
+	}
+	for _, formula := range formulaList {
+		if formula.Outdated && !slices.Contains(options.Exclude, formula.Name) {
+			for _, device := range formula.Devices {
+				add(device, PackageFormula, formula.Name)
+			}
+		}
+	}
+	for _, cask := range caskList {
+		if cask.Outdated && !slices.Contains(options.Exclude, cask.Name) {
+			for _, device := range cask.Devices {
+				add(device, PackageCask, cask.Name)
+			}
+		}
+	}
+	for _, packages := range outdated {
+		for _, names := range packages {
+			slices.Sort(names)
+		}
+	}
+
+	plan := &Plan{Groups: []GroupUpgrades{}, Waves: []Wave{}, Unassigned: []string{}}
+	byName := make(map[string]*devicegroups.DeviceGroup, len(groups))
+	grouped := make(map[string]bool)
+	for i := range groups {
+		group := &groups[i]
+		byName[group.Name] = group
+		upgrades := GroupUpgrades{GroupID: group.ID, Group: group.Name, Devices: []string{}, Formulae: []string{}, Casks: []string{}}
+		for _, device := range group.Devices {
+			grouped[device] = true
+			packages, ok := outdated[device]
+			if !ok {
+				continue
+			}
+			upgrades.Devices = append(upgrades.Devices, device)
+			upgrades.Formulae = append(upgrades.Formulae, packages[PackageFormula]...)
+			upgrades.Casks = append(upgrades.Casks, packages[PackageCask]...)
+		}
+		slices.Sort(upgrades.Devices)
+		upgrades.Devices = slices.Compact(upgrades.Devices)
+		slices.Sort(upgrades.Formulae)
+		upgrades.Formulae = slices.Compact(upgrades.Formulae)
+		slices.Sort(upgrades.Casks)
+		upgrades.Casks = slices.Compact(upgrades.Casks)
+		plan.Groups = append(plan.Groups, upgrades)
+	}
+	slices.SortFunc(plan.Groups, func(a, b GroupUpgrades) int {
+		return strings.Compare(a.Group, b.Group)
+	})
+	for device := range outdated {
+		if !grouped[device] {
+			plan.Unassigned = append(plan.Unassigned, device)
+		}
+	}
+	slices.Sort(plan.Unassigned)
+
+	// Order the groups into waves, with unlisted groups last
+	var waveGroups [][]string
+	listed := make(map[string]bool)
+	for _, names := range options.Waves {
+		for _, name := range names {
+			if _, ok := byName[name]; !ok {
+				return nil, fmt.Errorf("device group %q not found", name)
+			}
+			if listed[name] {
+				return nil, fmt.Errorf("device group %q is in more than one wave", name)
+			}
+			listed[name] = true
+		}
+		if len(names) > 0 {
+			waveGroups = append(waveGroups, names)
+		}
+	}
+	var remaining []string
+	for _, upgrades := range plan.Groups {
+		if !listed[upgrades.Group] {
+			remaining = append(remaining, upgrades.Group)
+		}
+	}
+	if len(remaining) > 0 {
+		waveGroups = append(waveGroups, remaining)
+	}
+
+	assigned := make(map[string]bool)
+	for _, names := range waveGroups {
+		var waveDevices []string
+		for _, name := range names {
+			for _, device := range byName[name].Devices {
+				if _, ok := outdated[device]; ok && !assigned[device] {
+					assigned[device] = true
+					waveDevices = append(waveDevices, device)
+				}
+			}
+		}
+		if err := plan.addWave(names, waveDevices, outdated); err != nil {
+			return nil, err
+		}
+	}
+	if options.IncludeUngrouped && len(plan.Unassigned) > 0 {
+		if last := len(plan.Waves) - 1; last >= 0 {
+			wave := plan.Waves[last]
+			plan.Waves = plan.Waves[:last]
+			if err := plan.addWave(wave.Groups, append(wave.Devices, plan.Unassigned...), outdated); err != nil {
+				return nil, err
+			}
+		} else if err := plan.addWave([]string{}, plan.Unassigned, outdated); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// addWave appends a wave upgrading devices, skipping waves with no devices
+func (p *Plan) addWave(groups, waveDevices []string, outdated map[string]map[PackageType][]string) error {
+	if len(waveDevices) == 0 {
+		return nil
+	}
+	wave := Wave{
+		Number:   len(p.Waves) + 1,
+		Groups:   slices.Clone(groups),
+		Devices:  slices.Sorted(slices.Values(waveDevices)),
+		Commands: []Command{},
+	}
+
+	// Devices with identical outdated packages share a command
+	index := make(map[string]int)
+	for _, device := range wave.Devices {
+		for _, packageType := range []PackageType{PackageFormula, PackageCask} {
+			names := outdated[device][packageType]
+			if len(names) == 0 {
+				continue
+			}
+			key := string(packageType) + " " + strings.Join(names, " ")
+			i, ok := index[key]
+			if !ok {
+				arguments, err := brewcommands.Upgrade(names...).WithFlags(packageType.flag()).Arguments()
+				if err != nil {
+					return fmt.Errorf("failed to build upgrade of %s: %w", strings.Join(names, ", "), err)
+				}
+				i = len(wave.Commands)
+				index[key] = i
+				wave.Commands = append(wave.Commands, Command{
+					Arguments: arguments,
+					Type:      packageType,
+					Packages:  names,
+				})
+			}
+			wave.Commands[i].Devices = append(wave.Commands[i].Devices, device)
+		}
+	}
+	slices.SortFunc(wave.Commands, func(a, b Command) int {
+		if a.Type != b.Type {
+			// Formulae before casks
+			return strings.Compare(string(b.Type), string(a.Type))
+		}
+		return strings.Compare(a.Arguments, b.Arguments)
+	})
+	p.Waves = append(p.Waves, wave)
+	return nil
+}
@@ -0,0 +1,126 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlan(t *testing.T) {
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	plan, err := NewPlan(context.Background(), NewSource(wb), &Options{Waves: [][]string{{"Admin"}}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []GroupUpgrades{
+		{
+			GroupID:  workbrewtest.FixtureGroupAdmin,
+			Group:    "Admin",
+			Devices:  []string{workbrewtest.FixtureDeviceMacBook},
+			Formulae: []string{"curl", "wget"},
+			Casks:    []string{"visual-studio-code"},
+		},
+		{
+			GroupID:  workbrewtest.FixtureGroupAllDevices,
+			Group:    "All Devices",
+			Devices:  []string{workbrewtest.FixtureDeviceLinux, workbrewtest.FixtureDeviceMacBook},
+			Formulae: []string{"curl", "wget"},
+			Casks:    []string{"visual-studio-code"},
+		},
+	}, plan.Groups)
+
+	require.Len(t, plan.Waves, 2)
+	canary := plan.Waves[0]
+	assert.Equal(t, 1, canary.Number)
+	assert.Equal(t, []string{"Admin"}, canary.Groups)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceMacBook}, canary.Devices)
+	assert.Equal(t, []Command{
+		{Arguments: "upgrade --formula curl wget", Type: PackageFormula, Packages: []string{"curl", "wget"}, Devices: []string{workbrewtest.FixtureDeviceMacBook}},
+		{Arguments: "upgrade --cask visual-studio-code", Type: PackageCask, Packages: []string{"visual-studio-code"}, Devices: []string{workbrewtest.FixtureDeviceMacBook}},
+	}, canary.Commands)
+
+	// The MacBook is also in All Devices but was upgraded in the canary wave
+	rest := plan.Waves[1]
+	assert.Equal(t, []string{"All Devices"}, rest.Groups)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceLinux}, rest.Devices)
+	assert.Equal(t, []Command{
+		{Arguments: "upgrade --formula curl", Type: PackageFormula, Packages: []string{"curl"}, Devices: []string{workbrewtest.FixtureDeviceLinux}},
+	}, rest.Commands)
+
+	assert.Equal(t, 2, plan.Devices())
+	assert.Empty(t, plan.Unassigned)
+	assert.Equal(t, "wave 1: Admin (1 devices)\n"+
+		"  brew upgrade --formula curl wget on TC6R2DHVHG\n"+
+		"  brew upgrade --cask visual-studio-code on TC6R2DHVHG\n"+
+		"wave 2: All Devices (1 devices)\n"+
+		"  brew upgrade --formula curl on 1234567890\n"+
+		"Plan: 2 wave(s) upgrading 2 device(s).\n", plan.String())
+}
+
+func TestNewPlan_FormulaeOnly(t *testing.T) {
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	src := NewSource(wb)
+	src.Casks = nil
+	plan, err := NewPlan(context.Background(), src, &Options{Exclude: []string{"wget"}})
+	require.NoError(t, err)
+
+	require.Len(t, plan.Waves, 1)
+	assert.Equal(t, []string{"Admin", "All Devices"}, plan.Waves[0].Groups)
+	assert.Equal(t, []Command{
+		{Arguments: "upgrade --formula curl", Type: PackageFormula, Packages: []string{"curl"}, Devices: []string{workbrewtest.FixtureDeviceLinux, workbrewtest.FixtureDeviceMacBook}},
+	}, plan.Waves[0].Commands)
+}
+
+func TestBuildPlan_Ungrouped(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures()
+	groups := devicegroups.DeviceGroupsResponse{fixtures.DeviceGroups[0]}
+
+	plan, err := BuildPlan(fixtures.Formulae, fixtures.Casks, groups, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceLinux}, plan.Unassigned)
+	assert.Equal(t, 1, plan.Devices())
+
+	plan, err = BuildPlan(fixtures.Formulae, fixtures.Casks, groups, &Options{IncludeUngrouped: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Waves, 1)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceLinux, workbrewtest.FixtureDeviceMacBook}, plan.Waves[0].Devices)
+	assert.Len(t, plan.Waves[0].Commands, 3)
+
+	plan, err = BuildPlan(fixtures.Formulae, fixtures.Casks, nil, &Options{IncludeUngrouped: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Waves, 1)
+	assert.Empty(t, plan.Waves[0].Groups)
+	assert.Len(t, plan.Waves[0].Devices, 2)
+}
+
+func TestBuildPlan_NothingOutdated(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures()
+
+	plan, err := BuildPlan(fixtures.Formulae, fixtures.Casks, fixtures.DeviceGroups, &Options{
+		Exclude: []string{"curl", "wget", "visual-studio-code"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Waves)
+	assert.Equal(t, 0, plan.Devices())
+}
+
+func TestBuildPlan_InvalidWaves(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures()
+
+	_, err := BuildPlan(fixtures.Formulae, fixtures.Casks, fixtures.DeviceGroups, &Options{Waves: [][]string{{"Canary"}}})
+	assert.EqualError(t, err, `device group "Canary" not found`)
+
+	_, err = BuildPlan(fixtures.Formulae, fixtures.Casks, fixtures.DeviceGroups, &Options{Waves: [][]string{{"Admin"}, {"Admin"}}})
+	assert.EqualError(t, err, `device group "Admin" is in more than one wave`)
+}
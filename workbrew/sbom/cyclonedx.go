@@ -0,0 +1,127 @@
+package sbom
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/cyclonedx"
+)
+
+// CycloneDX converts the inventory of one device to a CycloneDX 1.5 SBOM. The
+// device is the metadata component and depends on every installed package.
+//
+// Parameters:
+//   - serial: The serial number of the device
+//
+// Returns:
+//   - *cyclonedx.BOM: The SBOM, with a new random serial number
+//   - error: If the device is not in the inventory
+func (inv *Inventory) CycloneDX(serial string) (*cyclonedx.BOM, error) {
+	device, ok := inv.Device(serial)
+	if !ok {
+		return nil, fmt.Errorf("device %s not found in inventory", serial)
+	}
+
+	bom := cyclonedx.NewBOM(inv.CollectedAt)
+	if inv.Workspace != "" {
+		bom.Metadata.Properties = []cyclonedx.Property{{Name: "workbrew:workspace", Value: inv.Workspace}}
+	}
+
+	deviceComponent := deviceComponent(device)
+	bom.Metadata.Component = &deviceComponent
+
+	dependsOn := []string{}
+	for _, pkg := range device.Packages {
+		component := packageComponent(&pkg)
+		bom.Components = append(bom.Components, component)
+		dependsOn = append(dependsOn, component.BOMRef)
+	}
+	bom.Dependencies = []cyclonedx.Dependency{{Ref: deviceComponent.BOMRef, DependsOn: dependsOn}}
+
+	return bom, nil
+}
+
+// WriteCycloneDX writes the inventory of one device as an indented CycloneDX 1.5 SBOM
+func (inv *Inventory) WriteCycloneDX(w io.Writer, serial string) error {
+	bom, err := inv.CycloneDX(serial)
+	if err != nil {
+		return err
+	}
+	return bom.Write(w)
+}
+
+// PackageURL returns the package URL of a formula or cask, e.g. "pkg:brew/wget@1.25.0".
+// Casks carry a type=cask qualifier, and the version is omitted when unknown.
+func PackageURL(pkg *Package) string {
+	purl := "pkg:brew/" + pkg.Name
+	if pkg.Version != "" {
+		purl += "@" + pkg.Version
+	}
+	if pkg.Type == PackageCask {
+		purl += "?type=cask"
+	}
+	return purl
+}
+
+// deviceComponent describes the device itself
+func deviceComponent(device *Device) cyclonedx.Component {
+	component := cyclonedx.Component{
+		Type:    cyclonedx.ComponentDevice,
+		BOMRef:  "device:" + device.SerialNumber,
+		Name:    device.SerialNumber,
+		Version: device.OSVersion,
+		Properties: []cyclonedx.Property{
+			{Name: "workbrew:serial_number", Value: device.SerialNumber},
+		},
+	}
+	if device.Name != "" {
+		component.Name = device.Name
+	}
+	for _, property := range []cyclonedx.Property{
+		{Name: "workbrew:device_type", Value: device.DeviceType},
+		{Name: "workbrew:homebrew_version", Value: device.HomebrewVersion},
+		{Name: "workbrew:workbrew_version", Value: device.WorkbrewVersion},
+	} {
+		if property.Value != "" {
+			component.Properties = append(component.Properties, property)
+		}
+	}
+	for _, group := range device.Groups {
+		component.Properties = append(component.Properties, cyclonedx.Property{Name: "workbrew:group", Value: group})
+	}
+	for _, tap := range device.Taps {
+		component.Properties = append(component.Properties, cyclonedx.Property{Name: "workbrew:tap", Value: tap})
+	}
+	return component
+}
+
+// packageComponent describes an installed formula or cask
+func packageComponent(pkg *Package) cyclonedx.Component {
+	component := cyclonedx.Component{
+		Type:        cyclonedx.ComponentApplication,
+		BOMRef:      string(pkg.Type) + ":" + pkg.Name,
+		Name:        pkg.Name,
+		Version:     pkg.Version,
+		Description: pkg.DisplayName,
+		PURL:        PackageURL(pkg),
+		Properties: []cyclonedx.Property{
+			{Name: "workbrew:package_type", Value: string(pkg.Type)},
+			{Name: "workbrew:tap", Value: pkg.Tap},
+			{Name: "workbrew:outdated", Value: strconv.FormatBool(pkg.Outdated)},
+		},
+	}
+	if pkg.Type == PackageFormula && !pkg.InstalledOnRequest {
+		component.Type = cyclonedx.ComponentLibrary
+	}
+	for _, license := range pkg.Licenses {
+		component.Licenses = append(component.Licenses, cyclonedx.LicenseChoice{License: &cyclonedx.License{Name: license}})
+	}
+	if pkg.LatestVersion != "" {
+		component.Properties = append(component.Properties, cyclonedx.Property{Name: "workbrew:latest_version", Value: pkg.LatestVersion})
+	}
+	if pkg.Deprecated != "" {
+		component.Properties = append(component.Properties, cyclonedx.Property{Name: "workbrew:deprecated", Value: pkg.Deprecated})
+	}
+	return component
+}
@@ -0,0 +1,72 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/cyclonedx"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventory_CycloneDX(t *testing.T) {
+	bom, err := testInventory(t).CycloneDX(workbrewtest.FixtureDeviceMacBook)
+	require.NoError(t, err)
+
+	assert.Equal(t, cyclonedx.BOMFormat, bom.BOMFormat)
+	assert.Equal(t, cyclonedx.SpecVersion, bom.SpecVersion)
+	assert.Regexp(t, `^urn:uuid:[0-9a-f-]{36}$`, bom.SerialNumber)
+	assert.Equal(t, []cyclonedx.Property{{Name: "workbrew:workspace", Value: "test-workspace"}}, bom.Metadata.Properties)
+
+	device := bom.Metadata.Component
+	require.NotNil(t, device)
+	assert.Equal(t, cyclonedx.ComponentDevice, device.Type)
+	assert.Equal(t, "device:TC6R2DHVHG", device.BOMRef)
+	assert.Equal(t, "Mike's MacBook Pro", device.Name)
+	assert.Equal(t, "macOS 15.2 (24C101)", device.Version)
+	assert.Contains(t, device.Properties, cyclonedx.Property{Name: "workbrew:homebrew_version", Value: "4.4.15"})
+	assert.Contains(t, device.Properties, cyclonedx.Property{Name: "workbrew:tap", Value: "homebrew/cask"})
+
+	require.Len(t, bom.Components, 4)
+	wget := bom.Components[2]
+	assert.Equal(t, cyclonedx.ComponentApplication, wget.Type)
+	assert.Equal(t, "formula:wget", wget.BOMRef)
+	assert.Empty(t, wget.Version)
+	assert.Equal(t, "pkg:brew/wget", wget.PURL)
+	assert.Equal(t, []cyclonedx.LicenseChoice{{License: &cyclonedx.License{Name: "GPL-3.0-or-later"}}}, wget.Licenses)
+	assert.Equal(t, []cyclonedx.Property{
+		{Name: "workbrew:package_type", Value: "formula"},
+		{Name: "workbrew:tap", Value: "homebrew/core"},
+		{Name: "workbrew:outdated", Value: "true"},
+		{Name: "workbrew:latest_version", Value: "1.25.0"},
+	}, wget.Properties)
+
+	curl := bom.Components[1]
+	assert.Equal(t, cyclonedx.ComponentLibrary, curl.Type)
+
+	require.Len(t, bom.Dependencies, 1)
+	assert.Equal(t, "device:TC6R2DHVHG", bom.Dependencies[0].Ref)
+	assert.Equal(t, []string{"formula:actionlint", "formula:curl", "formula:wget", "cask:visual-studio-code"}, bom.Dependencies[0].DependsOn)
+
+	_, err = testInventory(t).CycloneDX("UNKNOWN")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestPackageURL(t *testing.T) {
+	assert.Equal(t, "pkg:brew/wget@1.25.0", PackageURL(&Package{Type: PackageFormula, Name: "wget", Version: "1.25.0"}))
+	assert.Equal(t, "pkg:brew/acme/tools/deployer", PackageURL(&Package{Type: PackageFormula, Name: "acme/tools/deployer"}))
+	assert.Equal(t, "pkg:brew/firefox@133.0?type=cask", PackageURL(&Package{Type: PackageCask, Name: "firefox", Version: "133.0"}))
+}
+
+func TestInventory_WriteCycloneDX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testInventory(t).WriteCycloneDX(&buf, workbrewtest.FixtureDeviceLinux))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Equal(t, "CycloneDX", document["bomFormat"])
+	assert.Len(t, document["components"], 1)
+	assert.NotContains(t, document, "vulnerabilities")
+}
@@ -0,0 +1,314 @@
+// Package sbom generates per-device software bills of materials.
+//
+// The Workbrew API reports installed software per package, each with the
+// devices it is installed on. This package inverts that mapping into a
+// per-device inventory of formulae and casks and writes it as SPDX 2.3 or
+// CycloneDX 1.5 JSON, one document per device.
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewtaps"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/casks"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/snapshot"
+)
+
+// PackageType distinguishes formulae from casks
+type PackageType string
+
+// Package types
+const (
+	PackageFormula PackageType = "formula"
+	PackageCask    PackageType = "cask"
+)
+
+// Default taps of packages without a fully qualified name
+const (
+	CoreTap = "homebrew/core"
+	CaskTap = "homebrew/cask"
+)
+
+// Package is a formula or cask installed on a device
+type Package struct {
+	Type        PackageType `json:"type"`
+	Name        string      `json:"name"`
+	DisplayName string      `json:"display_name,omitempty"`
+
+	// Version is the installed version. The API reports only the latest
+	// version and whether any device is behind it, so Version is set only
+	// when the package is up to date on every device.
+	Version string `json:"version,omitempty"`
+
+	// LatestVersion is the latest version available from the tap
+	LatestVersion string `json:"latest_version,omitempty"`
+
+	// Outdated reports whether at least one device runs an older version
+	Outdated bool `json:"outdated"`
+
+	// Licenses are the declared licenses, usually SPDX license identifiers.
+	// Only formulae have licenses.
+	Licenses []string `json:"licenses,omitempty"`
+
+	// Tap is the tap the package was installed from
+	Tap string `json:"tap"`
+
+	Deprecated         string `json:"deprecated,omitempty"`
+	InstalledOnRequest bool   `json:"installed_on_request,omitempty"`
+}
+
+// Device is the software inventory of one device
+type Device struct {
+	SerialNumber    string   `json:"serial_number"`
+	Name            string   `json:"name,omitempty"`
+	DeviceType      string   `json:"device_type,omitempty"`
+	OSVersion       string   `json:"os_version,omitempty"`
+	HomebrewVersion string   `json:"homebrew_version,omitempty"`
+	WorkbrewVersion string   `json:"workbrew_version,omitempty"`
+	Groups          []string `json:"groups,omitempty"`
+
+	// Taps are the taps configured on the device
+	Taps []string `json:"taps"`
+
+	// Packages are sorted formulae first, then casks, each by name
+	Packages []Package `json:"packages"`
+}
+
+// Inventory is the per-device software inventory of a workspace
+type Inventory struct {
+	Workspace   string    `json:"workspace,omitempty"`
+	CollectedAt time.Time `json:"collected_at"`
+
+	// Devices are sorted by serial number
+	Devices []Device `json:"devices"`
+}
+
+// Source holds the services an inventory is collected from.
+// Devices is optional: without it devices have no metadata beyond their serial number.
+type Source struct {
+	Workspace string
+	Devices   devices.DevicesServiceInterface
+	Formulae  formulae.FormulaeServiceInterface
+	Casks     casks.CasksServiceInterface
+	BrewTaps  brewtaps.BrewTapsServiceInterface
+}
+
+// NewSource creates an inventory source backed by a Workbrew client
+//
+// Parameters:
+//   - client: The Workbrew client to collect from
+//   - workspace: The workspace name recorded in the inventory
+func NewSource(client *workbrew.Client, workspace string) *Source {
+	return &Source{
+		Workspace: workspace,
+		Devices:   client.Devices,
+		Formulae:  client.Formulae,
+		Casks:     client.Casks,
+		BrewTaps:  client.BrewTaps,
+	}
+}
+
+// Collect lists devices, formulae, casks and taps and builds the per-device inventory.
+// The first failing call aborts the collection.
+//
+// Parameters:
+//   - ctx: Context for the underlying API calls
+//   - src: The services to collect from
+//
+// Returns:
+//   - *Inventory: The per-device inventory
+//   - error: Any error returned by the API
+//
+// Example:
+//
+//	inventory, err := sbom.Collect(ctx, sbom.NewSource(client, "my-workspace"))
+func Collect(ctx context.Context, src *Source) (*Inventory, error) {
+	if src == nil || src.Formulae == nil || src.Casks == nil || src.BrewTaps == nil {
+		return nil, fmt.Errorf("sbom source with formulae, casks and brew taps services is required")
+	}
+
+	snap := &snapshot.Snapshot{
+		Version:   snapshot.CurrentVersion,
+		Workspace: src.Workspace,
+	}
+
+	if src.Devices != nil {
+		deviceList, _, err := src.Devices.ListDevices(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list devices: %w", err)
+		}
+		snap.Devices = *deviceList
+	}
+
+	formulaList, _, err := src.Formulae.ListFormulae(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list formulae: %w", err)
+	}
+	snap.Formulae = *formulaList
+
+	caskList, _, err := src.Casks.ListCasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list casks: %w", err)
+	}
+	snap.Casks = *caskList
+
+	tapList, _, err := src.BrewTaps.ListBrewTaps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list brew taps: %w", err)
+	}
+	snap.BrewTaps = *tapList
+
+	snap.CapturedAt = time.Now().UTC()
+
+	return Build(snap), nil
+}
+
+// Build inverts the package-to-devices mapping of a snapshot into a per-device inventory.
+// Every device in the device list is included, as is any device that only appears
+// in a package or tap list.
+//
+// Parameters:
+//   - snap: The snapshot to build from, e.g. one captured earlier with snapshot.Capture
+//
+// Returns:
+//   - *Inventory: The per-device inventory
+func Build(snap *snapshot.Snapshot) *Inventory {
+	inventory := &Inventory{
+		Workspace:   snap.Workspace,
+		CollectedAt: snap.CapturedAt,
+		Devices:     []Device{},
+	}
+
+	index := make(map[string]int)
+	device := func(serial string) *Device {
+		if i, ok := index[serial]; ok {
+			return &inventory.Devices[i]
+		}
+		index[serial] = len(inventory.Devices)
+		inventory.Devices = append(inventory.Devices, Device{
+			SerialNumber: serial,
+			Taps:         []string{},
+			Packages:     []Package{},
+		})
+		return &inventory.Devices[len(inventory.Devices)-1]
+	}
+
+	for _, d := range snap.Devices {
+		entry := device(d.SerialNumber)
+		if d.MDMUserOrDeviceName != nil {
+			entry.Name = *d.MDMUserOrDeviceName
+		}
+		entry.DeviceType = d.DeviceType
+		entry.OSVersion = d.OSVersion
+		entry.HomebrewVersion = d.HomebrewVersion
+		entry.WorkbrewVersion = d.WorkbrewVersion
+		entry.Groups = d.Groups
+	}
+
+	for _, tap := range snap.BrewTaps {
+		for _, serial := range tap.Devices {
+			entry := device(serial)
+			entry.Taps = append(entry.Taps, tap.Tap)
+		}
+	}
+
+	for _, formula := range snap.Formulae {
+		pkg := Package{
+			Type:               PackageFormula,
+			Name:               formula.Name,
+			Outdated:           formula.Outdated,
+			Tap:                TapOf(formula.Name, PackageFormula),
+			InstalledOnRequest: formula.InstalledOnRequest,
+		}
+		if formula.HomebrewCoreVersion != nil {
+			pkg.LatestVersion = *formula.HomebrewCoreVersion
+		}
+		if formula.License != nil {
+			pkg.Licenses = *formula.License
+		}
+		if formula.Deprecated != nil {
+			pkg.Deprecated = *formula.Deprecated
+		}
+		addPackage(device, formula.Devices, pkg)
+	}
+
+	for _, cask := range snap.Casks {
+		pkg := Package{
+			Type:     PackageCask,
+			Name:     cask.Name,
+			Outdated: cask.Outdated,
+			Tap:      TapOf(cask.Name, PackageCask),
+		}
+		if cask.DisplayName != nil {
+			pkg.DisplayName = *cask.DisplayName
+		}
+		if cask.HomebrewCaskVersion != nil {
+			pkg.LatestVersion = *cask.HomebrewCaskVersion
+		}
+		if cask.Deprecated != nil {
+			pkg.Deprecated = *cask.Deprecated
+		}
+		addPackage(device, cask.Devices, pkg)
+	}
+
+	slices.SortFunc(inventory.Devices, func(a, b Device) int {
+		return strings.Compare(a.SerialNumber, b.SerialNumber)
+	})
+	for i := range inventory.Devices {
+		entry := &inventory.Devices[i]
+		slices.Sort(entry.Taps)
+		entry.Taps = slices.Compact(entry.Taps)
+		slices.SortStableFunc(entry.Packages, func(a, b Package) int {
+			if a.Type != b.Type {
+				if a.Type == PackageFormula {
+					return -1
+				}
+				return 1
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+
+	return inventory
+}
+
+// Device returns the inventory of the device with the given serial number
+func (inv *Inventory) Device(serial string) (*Device, bool) {
+	for i := range inv.Devices {
+		if inv.Devices[i].SerialNumber == serial {
+			return &inv.Devices[i], true
+		}
+	}
+	return nil, false
+}
+
+// TapOf returns the tap a package was installed from. Packages from third-party
+// taps have fully qualified names such as "user/repo/name"; other formulae come
+// from homebrew/core and other casks from homebrew/cask.
+func TapOf(name string, packageType PackageType) string {
+	if i := strings.LastIndex(name, "/"); i > 0 && strings.Count(name, "/") == 2 {
+		return name[:i]
+	}
+	if packageType == PackageCask {
+		return CaskTap
+	}
+	return CoreTap
+}
+
+// addPackage adds a package to each device it is installed on
+func addPackage(device func(string) *Device, serials []string, pkg Package) {
+	if !pkg.Outdated {
+		pkg.Version = pkg.LatestVersion
+	}
+	for _, serial := range serials {
+		entry := device(serial)
+		entry.Packages = append(entry.Packages, pkg)
+	}
+}
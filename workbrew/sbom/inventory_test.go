@@ -0,0 +1,140 @@
+package sbom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewtaps"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/snapshot"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// testInventory collects an inventory from the default fake workspace
+func testInventory(t *testing.T) *Inventory {
+	t.Helper()
+
+	server := workbrewtest.NewServer()
+	t.Cleanup(server.Close)
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	inventory, err := Collect(context.Background(), NewSource(wb, workbrewtest.DefaultWorkspace))
+	require.NoError(t, err)
+	return inventory
+}
+
+func TestCollect(t *testing.T) {
+	inventory := testInventory(t)
+
+	assert.Equal(t, workbrewtest.DefaultWorkspace, inventory.Workspace)
+	assert.False(t, inventory.CollectedAt.IsZero())
+	require.Len(t, inventory.Devices, 2)
+
+	// Sorted by serial number
+	linux := inventory.Devices[0]
+	assert.Equal(t, workbrewtest.FixtureDeviceLinux, linux.SerialNumber)
+	assert.Equal(t, "Ubuntu 22.04.5 LTS", linux.OSVersion)
+	assert.Equal(t, []string{"homebrew/core"}, linux.Taps)
+	require.Len(t, linux.Packages, 1)
+	assert.Equal(t, "curl", linux.Packages[0].Name)
+
+	macbook, ok := inventory.Device(workbrewtest.FixtureDeviceMacBook)
+	require.True(t, ok)
+	assert.Equal(t, "Mike's MacBook Pro", macbook.Name)
+	assert.Equal(t, "MacBook Pro", macbook.DeviceType)
+	assert.Equal(t, []string{"Admin", "All Devices"}, macbook.Groups)
+	assert.Equal(t, []string{"homebrew/cask", "homebrew/core"}, macbook.Taps)
+
+	var names []string
+	for _, pkg := range macbook.Packages {
+		names = append(names, pkg.Name)
+	}
+	assert.Equal(t, []string{"actionlint", "curl", "wget", "visual-studio-code"}, names, "formulae first, then casks")
+
+	actionlint := macbook.Packages[0]
+	assert.Equal(t, PackageFormula, actionlint.Type)
+	assert.Equal(t, "1.7.7", actionlint.Version, "up-to-date packages run the latest version")
+	assert.Equal(t, []string{"MIT"}, actionlint.Licenses)
+	assert.Equal(t, CoreTap, actionlint.Tap)
+	assert.True(t, actionlint.InstalledOnRequest)
+
+	wget := macbook.Packages[2]
+	assert.Empty(t, wget.Version, "the installed version of an outdated package is unknown")
+	assert.Equal(t, "1.25.0", wget.LatestVersion)
+	assert.True(t, wget.Outdated)
+
+	vscode := macbook.Packages[3]
+	assert.Equal(t, PackageCask, vscode.Type)
+	assert.Equal(t, "Microsoft Visual Studio Code", vscode.DisplayName)
+	assert.Equal(t, CaskTap, vscode.Tap)
+
+	_, ok = inventory.Device("UNKNOWN")
+	assert.False(t, ok)
+}
+
+func TestCollect_Errors(t *testing.T) {
+	_, err := Collect(context.Background(), nil)
+	assert.Error(t, err)
+
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	server.InjectFault(workbrewtest.Fault{Path: "/casks.json", StatusCode: 403})
+	_, err = Collect(context.Background(), NewSource(wb, workbrewtest.DefaultWorkspace))
+	assert.ErrorContains(t, err, "failed to list casks")
+}
+
+func TestCollect_WithoutDevices(t *testing.T) {
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	src := NewSource(wb, workbrewtest.DefaultWorkspace)
+	src.Devices = nil
+	inventory, err := Collect(context.Background(), src)
+	require.NoError(t, err)
+
+	require.Len(t, inventory.Devices, 2, "devices are found from package and tap lists")
+	assert.Empty(t, inventory.Devices[1].Name)
+	assert.Len(t, inventory.Devices[1].Packages, 4)
+}
+
+func TestBuild_ThirdPartyTap(t *testing.T) {
+	snap := &snapshot.Snapshot{
+		CapturedAt: time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC),
+		Formulae: formulae.FormulaeResponse{
+			{Name: "acme/tools/deployer", Devices: []string{"C02XYZ"}, HomebrewCoreVersion: ptr("2.0.0"), Deprecated: ptr("unmaintained")},
+		},
+		BrewTaps: brewtaps.BrewTapsResponse{
+			{Tap: "acme/tools", Devices: []string{"C02XYZ"}},
+		},
+	}
+	inventory := Build(snap)
+
+	require.Len(t, inventory.Devices, 1)
+	device := inventory.Devices[0]
+	assert.Equal(t, "C02XYZ", device.SerialNumber)
+	assert.Equal(t, []string{"acme/tools"}, device.Taps)
+	require.Len(t, device.Packages, 1)
+	assert.Equal(t, "acme/tools", device.Packages[0].Tap)
+	assert.Equal(t, "2.0.0", device.Packages[0].Version)
+	assert.Equal(t, "unmaintained", device.Packages[0].Deprecated)
+}
+
+func TestTapOf(t *testing.T) {
+	assert.Equal(t, "homebrew/core", TapOf("wget", PackageFormula))
+	assert.Equal(t, "homebrew/cask", TapOf("firefox", PackageCask))
+	assert.Equal(t, "acme/tools", TapOf("acme/tools/deployer", PackageFormula))
+	assert.Equal(t, "homebrew/core", TapOf("odd/name", PackageFormula))
+}
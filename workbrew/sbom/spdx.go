@@ -0,0 +1,341 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/licensepolicy"
+	"github.com/google/uuid"
+)
+
+// SPDX document constants
+const (
+	SPDXVersion     = "SPDX-2.3"
+	SPDXDataLicense = "CC0-1.0"
+
+	// SPDXNoAssertion marks a field whose value is unknown
+	SPDXNoAssertion = "NOASSERTION"
+
+	// SPDXNamespacePrefix prefixes every document namespace. Namespaces are
+	// unique identifiers and need not resolve.
+	SPDXNamespacePrefix = "https://github.com/deploymenttheory/go-api-sdk-workbrew/spdx/"
+)
+
+// SPDXDocument is an SPDX 2.3 document describing one device
+type SPDXDocument struct {
+	SPDXVersion          string                 `json:"spdxVersion"`
+	DataLicense          string                 `json:"dataLicense"`
+	SPDXID               string                 `json:"SPDXID"`
+	Name                 string                 `json:"name"`
+	DocumentNamespace    string                 `json:"documentNamespace"`
+	CreationInfo         SPDXCreationInfo       `json:"creationInfo"`
+	Packages             []SPDXPackage          `json:"packages"`
+	Relationships        []SPDXRelationship     `json:"relationships"`
+	ExtractedLicenseInfo []SPDXExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+// SPDXCreationInfo records when and by what the document was created
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+// SPDXPackage is the device, or a formula or cask installed on it
+type SPDXPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	SourceInfo            string            `json:"sourceInfo,omitempty"`
+	Description           string            `json:"description,omitempty"`
+	Comment               string            `json:"comment,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs          []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+// SPDXExternalRef is an external reference such as a package URL
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// SPDXRelationship relates two SPDX elements
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// SPDXExtractedLicense defines a LicenseRef for a license that is not an SPDX license identifier
+type SPDXExtractedLicense struct {
+	LicenseID     string `json:"licenseId"`
+	Name          string `json:"name"`
+	ExtractedText string `json:"extractedText"`
+}
+
+// spdxIDChars matches the characters not allowed in SPDX element IDs
+var spdxIDChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// SPDX converts the inventory of one device to an SPDX 2.3 document. The
+// document describes the device, which contains each installed package.
+// Unknown versions are omitted and unknown licenses are NOASSERTION.
+//
+// Parameters:
+//   - serial: The serial number of the device
+//
+// Returns:
+//   - *SPDXDocument: The document, with a new random namespace
+//   - error: If the device is not in the inventory
+func (inv *Inventory) SPDX(serial string) (*SPDXDocument, error) {
+	device, ok := inv.Device(serial)
+	if !ok {
+		return nil, fmt.Errorf("device %s not found in inventory", serial)
+	}
+
+	deviceID := spdxID("device", device.SerialNumber)
+	document := &SPDXDocument{
+		SPDXVersion:       SPDXVersion,
+		DataLicense:       SPDXDataLicense,
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "workbrew-device-" + device.SerialNumber,
+		DocumentNamespace: spdxNamespace(inv.Workspace, device.SerialNumber),
+		CreationInfo: SPDXCreationInfo{
+			Created:  inv.CollectedAt.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: go-api-sdk-workbrew-" + client.Version},
+		},
+		Packages: []SPDXPackage{spdxDevicePackage(device, deviceID)},
+		Relationships: []SPDXRelationship{{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: deviceID,
+		}},
+	}
+	if inv.Workspace != "" {
+		document.CreationInfo.Comment = "Workbrew workspace " + inv.Workspace
+	}
+
+	extracted := make(map[string]bool)
+	for _, pkg := range device.Packages {
+		id := spdxID(string(pkg.Type), pkg.Name)
+		declared, refs := spdxLicenseExpression(pkg.Licenses)
+		for _, ref := range refs {
+			if !extracted[ref.LicenseID] {
+				extracted[ref.LicenseID] = true
+				document.ExtractedLicenseInfo = append(document.ExtractedLicenseInfo, ref)
+			}
+		}
+
+		spdxPackage := SPDXPackage{
+			Name:             pkg.Name,
+			SPDXID:           id,
+			VersionInfo:      pkg.Version,
+			DownloadLocation: tapLocation(pkg.Tap),
+			LicenseConcluded: SPDXNoAssertion,
+			LicenseDeclared:  declared,
+			CopyrightText:    SPDXNoAssertion,
+			SourceInfo:       "Installed from the " + pkg.Tap + " tap",
+			Description:      pkg.DisplayName,
+			Comment:          packageComment(&pkg),
+			ExternalRefs: []SPDXExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  PackageURL(&pkg),
+			}},
+			PrimaryPackagePurpose: "APPLICATION",
+		}
+		if pkg.Type == PackageFormula && !pkg.InstalledOnRequest {
+			spdxPackage.PrimaryPackagePurpose = "LIBRARY"
+		}
+
+		document.Packages = append(document.Packages, spdxPackage)
+		document.Relationships = append(document.Relationships, SPDXRelationship{
+			SPDXElementID:      deviceID,
+			RelationshipType:   "CONTAINS",
+			RelatedSPDXElement: id,
+		})
+	}
+
+	return document, nil
+}
+
+// WriteSPDX writes the inventory of one device as an indented SPDX 2.3 JSON document
+func (inv *Inventory) WriteSPDX(w io.Writer, serial string) error {
+	document, err := inv.SPDX(serial)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to write SPDX document: %w", err)
+	}
+	return nil
+}
+
+// spdxDevicePackage describes the device itself
+func spdxDevicePackage(device *Device, id string) SPDXPackage {
+	name := device.Name
+	if name == "" {
+		name = device.SerialNumber
+	}
+
+	var details []string
+	if device.DeviceType != "" {
+		details = append(details, "Device type "+device.DeviceType)
+	}
+	if device.HomebrewVersion != "" {
+		details = append(details, "Homebrew "+device.HomebrewVersion)
+	}
+	if device.WorkbrewVersion != "" {
+		details = append(details, "Workbrew "+device.WorkbrewVersion)
+	}
+	if len(device.Taps) > 0 {
+		details = append(details, "Taps "+strings.Join(device.Taps, ", "))
+	}
+
+	pkg := SPDXPackage{
+		Name:                  name,
+		SPDXID:                id,
+		VersionInfo:           device.OSVersion,
+		DownloadLocation:      SPDXNoAssertion,
+		LicenseConcluded:      SPDXNoAssertion,
+		LicenseDeclared:       SPDXNoAssertion,
+		CopyrightText:         SPDXNoAssertion,
+		Description:           "Device with serial number " + device.SerialNumber,
+		PrimaryPackagePurpose: "DEVICE",
+	}
+	if len(details) > 0 {
+		pkg.Comment = strings.Join(details, "; ")
+	}
+	return pkg
+}
+
+// spdxLicenseExpression combines declared licenses into an SPDX license expression.
+// Each license is parsed as an SPDX expression, so "MIT OR Apache-2.0" keeps its
+// operator. Terms that are not on the SPDX License List, and licenses that do not
+// parse, become LicenseRefs, returned for the document's extracted licensing
+// information. The API does not say whether multiple licenses apply together or
+// as alternatives, so they are combined conservatively with AND.
+func spdxLicenseExpression(licenses []string) (string, []SPDXExtractedLicense) {
+	var operands []licensepolicy.Expression
+	var refs []SPDXExtractedLicense
+	for _, license := range licenses {
+		license = strings.TrimSpace(license)
+		if license == "" {
+			continue
+		}
+		expression, err := licensepolicy.ParseExpression(license)
+		if err != nil {
+			ref := spdxLicenseRef(license)
+			operands = append(operands, licensepolicy.Term{License: ref.LicenseID})
+			refs = append(refs, ref)
+			continue
+		}
+		operands = append(operands, spdxListedTerms(expression, &refs))
+	}
+	switch len(operands) {
+	case 0:
+		return SPDXNoAssertion, nil
+	case 1:
+		return operands[0].String(), refs
+	}
+	return licensepolicy.Compound{Operator: licensepolicy.OperatorAnd, Operands: operands}.String(), refs
+}
+
+// spdxListedTerms rewrites each term of an expression in the canonical case of the
+// SPDX License List, replacing terms that are not on it with LicenseRefs
+func spdxListedTerms(expression licensepolicy.Expression, refs *[]SPDXExtractedLicense) licensepolicy.Expression {
+	if compound, ok := expression.(licensepolicy.Compound); ok {
+		operands := make([]licensepolicy.Expression, 0, len(compound.Operands))
+		for _, operand := range compound.Operands {
+			operands = append(operands, spdxListedTerms(operand, refs))
+		}
+		return licensepolicy.Compound{Operator: compound.Operator, Operands: operands}
+	}
+
+	term := expression.(licensepolicy.Term)
+	license, listed := spdxListedLicense(term.License)
+	exception := ""
+	if listed && term.Exception != "" {
+		exception, listed = spdxExceptionIDs[strings.ToLower(term.Exception)]
+	}
+	if !listed {
+		ref := spdxLicenseRef(term.String())
+		*refs = append(*refs, ref)
+		return licensepolicy.Term{License: ref.LicenseID}
+	}
+	return licensepolicy.Term{License: license, Exception: exception}
+}
+
+// spdxListedLicense returns the canonical form of a license identifier on the
+// SPDX License List, with an optional "+" for "or later"
+func spdxListedLicense(license string) (string, bool) {
+	if id, ok := spdxLicenseIDs[strings.ToLower(license)]; ok {
+		return id, true
+	}
+	if base, ok := strings.CutSuffix(license, "+"); ok {
+		if id, ok := spdxLicenseIDs[strings.ToLower(base)]; ok {
+			return id + "+", true
+		}
+	}
+	return "", false
+}
+
+// spdxLicenseRef defines a LicenseRef for a license that is not on the SPDX License List
+func spdxLicenseRef(license string) SPDXExtractedLicense {
+	return SPDXExtractedLicense{
+		LicenseID:     "LicenseRef-" + strings.Trim(spdxIDChars.ReplaceAllString(license, "-"), "-"),
+		Name:          license,
+		ExtractedText: license,
+	}
+}
+
+// spdxID builds an SPDX element ID from a prefix and a name
+func spdxID(prefix, name string) string {
+	return "SPDXRef-" + prefix + "-" + strings.Trim(spdxIDChars.ReplaceAllString(name, "-"), "-")
+}
+
+// spdxNamespace builds a unique document namespace for a device
+func spdxNamespace(workspace, serial string) string {
+	if workspace == "" {
+		workspace = "default"
+	}
+	return SPDXNamespacePrefix + url.PathEscape(workspace) + "/" + url.PathEscape(serial) + "-" + uuid.NewString()
+}
+
+// tapLocation returns the download location of an official Homebrew tap.
+// Third-party taps may be cloned from any remote, so their location is not asserted.
+func tapLocation(tap string) string {
+	if repo, ok := strings.CutPrefix(tap, "homebrew/"); ok {
+		return "git+https://github.com/Homebrew/homebrew-" + repo + ".git"
+	}
+	return SPDXNoAssertion
+}
+
+// packageComment notes what is known about a package's version and status
+func packageComment(pkg *Package) string {
+	var notes []string
+	if pkg.Outdated {
+		note := "Outdated on at least one device"
+		if pkg.LatestVersion != "" {
+			note += "; latest version " + pkg.LatestVersion
+		}
+		notes = append(notes, note)
+	}
+	if pkg.Deprecated != "" {
+		notes = append(notes, "Deprecated: "+strings.TrimSuffix(pkg.Deprecated, "."))
+	}
+	return strings.Join(notes, ". ")
+}
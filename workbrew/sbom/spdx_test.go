@@ -0,0 +1,123 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventory_SPDX(t *testing.T) {
+	document, err := testInventory(t).SPDX(workbrewtest.FixtureDeviceMacBook)
+	require.NoError(t, err)
+
+	assert.Equal(t, SPDXVersion, document.SPDXVersion)
+	assert.Equal(t, "CC0-1.0", document.DataLicense)
+	assert.Equal(t, "workbrew-device-TC6R2DHVHG", document.Name)
+	assert.Regexp(t, `^https://github.com/deploymenttheory/go-api-sdk-workbrew/spdx/test-workspace/TC6R2DHVHG-[0-9a-f-]{36}$`, document.DocumentNamespace)
+	assert.Equal(t, []string{"Tool: go-api-sdk-workbrew-1.0.0"}, document.CreationInfo.Creators)
+
+	require.Len(t, document.Packages, 5)
+	device := document.Packages[0]
+	assert.Equal(t, "SPDXRef-device-TC6R2DHVHG", device.SPDXID)
+	assert.Equal(t, "Mike's MacBook Pro", device.Name)
+	assert.Equal(t, "macOS 15.2 (24C101)", device.VersionInfo)
+	assert.Equal(t, "DEVICE", device.PrimaryPackagePurpose)
+	assert.Contains(t, device.Comment, "Taps homebrew/cask, homebrew/core")
+
+	actionlint := document.Packages[1]
+	assert.Equal(t, "SPDXRef-formula-actionlint", actionlint.SPDXID)
+	assert.Equal(t, "1.7.7", actionlint.VersionInfo)
+	assert.Equal(t, "MIT", actionlint.LicenseDeclared)
+	assert.Equal(t, SPDXNoAssertion, actionlint.LicenseConcluded)
+	assert.Equal(t, "git+https://github.com/Homebrew/homebrew-core.git", actionlint.DownloadLocation)
+	assert.Equal(t, "Installed from the homebrew/core tap", actionlint.SourceInfo)
+	assert.Equal(t, []SPDXExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:brew/actionlint@1.7.7"}}, actionlint.ExternalRefs)
+	assert.Equal(t, "APPLICATION", actionlint.PrimaryPackagePurpose)
+
+	curl := document.Packages[2]
+	assert.Empty(t, curl.VersionInfo)
+	assert.Equal(t, "LIBRARY", curl.PrimaryPackagePurpose, "dependencies are libraries")
+	assert.Equal(t, "Outdated on at least one device; latest version 8.11.1", curl.Comment)
+
+	vscode := document.Packages[4]
+	assert.Equal(t, "SPDXRef-cask-visual-studio-code", vscode.SPDXID)
+	assert.Equal(t, SPDXNoAssertion, vscode.LicenseDeclared)
+	assert.Equal(t, "Microsoft Visual Studio Code", vscode.Description)
+	assert.Equal(t, "pkg:brew/visual-studio-code?type=cask", vscode.ExternalRefs[0].ReferenceLocator)
+
+	require.Len(t, document.Relationships, 5)
+	assert.Equal(t, SPDXRelationship{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-device-TC6R2DHVHG"}, document.Relationships[0])
+	assert.Equal(t, SPDXRelationship{SPDXElementID: "SPDXRef-device-TC6R2DHVHG", RelationshipType: "CONTAINS", RelatedSPDXElement: "SPDXRef-cask-visual-studio-code"}, document.Relationships[4])
+
+	_, err = testInventory(t).SPDX("UNKNOWN")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestSPDXLicenseExpression(t *testing.T) {
+	expression, refs := spdxLicenseExpression(nil)
+	assert.Equal(t, SPDXNoAssertion, expression)
+	assert.Empty(t, refs)
+
+	expression, refs = spdxLicenseExpression([]string{"MIT", "Apache-2.0 WITH LLVM-exception"})
+	assert.Equal(t, "MIT AND Apache-2.0 WITH LLVM-exception", expression)
+	assert.Empty(t, refs)
+
+	expression, refs = spdxLicenseExpression([]string{"Public Domain", "BSD-3-Clause"})
+	assert.Equal(t, "LicenseRef-Public-Domain AND BSD-3-Clause", expression)
+	assert.Equal(t, []SPDXExtractedLicense{{LicenseID: "LicenseRef-Public-Domain", Name: "Public Domain", ExtractedText: "Public Domain"}}, refs)
+}
+
+func TestSPDXLicenseExpression_Compound(t *testing.T) {
+	expression, refs := spdxLicenseExpression([]string{"MIT OR Apache-2.0"})
+	assert.Equal(t, "MIT OR Apache-2.0", expression)
+	assert.Empty(t, refs)
+
+	// Alternatives keep their grouping when combined with other licenses
+	expression, refs = spdxLicenseExpression([]string{"mit or apache-2.0", "GPL-2.0-or-later"})
+	assert.Equal(t, "(MIT OR Apache-2.0) AND GPL-2.0-or-later", expression)
+	assert.Empty(t, refs)
+
+	expression, refs = spdxLicenseExpression([]string{"(GPL-2.0-only WITH classpath-exception-2.0 OR LGPL-2.1+) AND Zlib"})
+	assert.Equal(t, "(GPL-2.0-only WITH Classpath-exception-2.0 OR LGPL-2.1+) AND Zlib", expression)
+	assert.Empty(t, refs)
+}
+
+func TestSPDXLicenseExpression_UnlistedTerms(t *testing.T) {
+	// Names shaped like identifiers are not SPDX licenses unless they are on the list
+	expression, refs := spdxLicenseExpression([]string{"BSD"})
+	assert.Equal(t, "LicenseRef-BSD", expression)
+	assert.Equal(t, []SPDXExtractedLicense{{LicenseID: "LicenseRef-BSD", Name: "BSD", ExtractedText: "BSD"}}, refs)
+
+	// Only the unlisted term of a compound expression becomes a LicenseRef
+	expression, refs = spdxLicenseExpression([]string{"MIT OR Public-Domain"})
+	assert.Equal(t, "MIT OR LicenseRef-Public-Domain", expression)
+	assert.Equal(t, []SPDXExtractedLicense{{LicenseID: "LicenseRef-Public-Domain", Name: "Public-Domain", ExtractedText: "Public-Domain"}}, refs)
+
+	// An unlisted exception makes the whole term a LicenseRef
+	expression, refs = spdxLicenseExpression([]string{"Apache-2.0 WITH Custom-exception"})
+	assert.Equal(t, "LicenseRef-Apache-2.0-WITH-Custom-exception", expression)
+	require.Len(t, refs, 1)
+	assert.Equal(t, "Apache-2.0 WITH Custom-exception", refs[0].Name)
+
+	// Malformed expressions are kept whole
+	expression, refs = spdxLicenseExpression([]string{"MIT OR"})
+	assert.Equal(t, "LicenseRef-MIT-OR", expression)
+	require.Len(t, refs, 1)
+	assert.Equal(t, "MIT OR", refs[0].Name)
+}
+
+func TestInventory_WriteSPDX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testInventory(t).WriteSPDX(&buf, workbrewtest.FixtureDeviceLinux))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Equal(t, "SPDX-2.3", document["spdxVersion"])
+	assert.Equal(t, "SPDXRef-DOCUMENT", document["SPDXID"])
+	assert.Len(t, document["packages"], 2)
+	assert.NotContains(t, document, "hasExtractedLicensingInfos")
+}
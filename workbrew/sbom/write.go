@@ -0,0 +1,100 @@
+package sbom
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Format is an SBOM document format
+type Format string
+
+// Supported formats
+const (
+	FormatSPDX      Format = "spdx"
+	FormatCycloneDX Format = "cyclonedx"
+)
+
+// ParseFormat parses a format name: "spdx" or "cyclonedx"
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case FormatSPDX, FormatCycloneDX:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown SBOM format %q (expected spdx or cyclonedx)", name)
+}
+
+// Extension returns the conventional file extension of the format
+func (f Format) Extension() string {
+	if f == FormatCycloneDX {
+		return ".cdx.json"
+	}
+	return ".spdx.json"
+}
+
+// Write writes the SBOM of one device in the given format
+//
+// Parameters:
+//   - w: Destination for the document
+//   - serial: The serial number of the device
+//   - format: FormatSPDX or FormatCycloneDX
+func (inv *Inventory) Write(w io.Writer, serial string, format Format) error {
+	switch format {
+	case FormatSPDX:
+		return inv.WriteSPDX(w, serial)
+	case FormatCycloneDX:
+		return inv.WriteCycloneDX(w, serial)
+	}
+	return fmt.Errorf("unknown SBOM format %q", format)
+}
+
+// WriteDir writes one SBOM per device to a directory, naming each file after the
+// device serial number and the format's extension, e.g. "TC6R2DHVHG.spdx.json".
+// The directory is created if needed and existing files are replaced.
+//
+// Parameters:
+//   - dir: The output directory
+//   - format: FormatSPDX or FormatCycloneDX
+//
+// Returns:
+//   - []string: The paths of the files written, in device order
+//   - error: Any error creating or writing a file
+//
+// Example:
+//
+//	paths, err := inventory.WriteDir("sboms", sbom.FormatSPDX)
+func (inv *Inventory) WriteDir(dir string, format Format) ([]string, error) {
+	if _, err := ParseFormat(string(format)); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create SBOM directory: %w", err)
+	}
+
+	paths := make([]string, 0, len(inv.Devices))
+	for _, device := range inv.Devices {
+		path := filepath.Join(dir, filepath.Base(device.SerialNumber)+format.Extension())
+		if err := inv.writeFile(path, device.SerialNumber, format); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writeFile writes the SBOM of one device to the named file
+func (inv *Inventory) writeFile(path, serial string, format Format) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create SBOM file: %w", err)
+	}
+	if err := inv.Write(file, serial, format); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close SBOM file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package sbom
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("spdx")
+	require.NoError(t, err)
+	assert.Equal(t, FormatSPDX, format)
+	assert.Equal(t, ".spdx.json", format.Extension())
+
+	format, err = ParseFormat("cyclonedx")
+	require.NoError(t, err)
+	assert.Equal(t, ".cdx.json", format.Extension())
+
+	_, err = ParseFormat("swid")
+	assert.ErrorContains(t, err, "unknown SBOM format")
+}
+
+func TestInventory_Write(t *testing.T) {
+	inventory := testInventory(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, inventory.Write(&buf, workbrewtest.FixtureDeviceLinux, FormatCycloneDX))
+	assert.Contains(t, buf.String(), `"bomFormat": "CycloneDX"`)
+
+	assert.Error(t, inventory.Write(&buf, workbrewtest.FixtureDeviceLinux, Format("swid")))
+}
+
+func TestInventory_WriteDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sboms")
+
+	paths, err := testInventory(t).WriteDir(dir, FormatSPDX)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "1234567890.spdx.json"),
+		filepath.Join(dir, "TC6R2DHVHG.spdx.json"),
+	}, paths)
+
+	data, err := os.ReadFile(paths[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"name": "workbrew-device-TC6R2DHVHG"`)
+
+	_, err = testInventory(t).WriteDir(dir, Format("swid"))
+	assert.Error(t, err)
+}
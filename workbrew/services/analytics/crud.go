@@ -2,7 +2,9 @@ package analytics
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -16,6 +18,9 @@ type (
 		// Returns analytics records with device, command, last run timestamp, and count information
 		ListAnalytics(ctx context.Context) (*AnalyticsResponse, *interfaces.Response, error)
 
+		// ListAnalyticsSeq returns an iterator over analytics
+		ListAnalyticsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Analytic, error]
+
 		// ListAnalyticsCSV returns a list of analytics data in CSV format
 		//
 		// Returns the same analytics data as ListAnalytics but formatted as CSV
@@ -44,6 +49,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // ListAnalytics retrieves all analytics in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/analytics.json
 func (s *Service) ListAnalytics(ctx context.Context) (*AnalyticsResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "analytics.ListAnalytics")
+
 	endpoint := EndpointAnalyticsJSON
 
 	headers := map[string]string{
@@ -62,9 +69,31 @@ func (s *Service) ListAnalytics(ctx context.Context) (*AnalyticsResponse, *inter
 	return &result, resp, nil
 }
 
+// ListAnalyticsSeq iterates over analytics in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/analytics.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListAnalyticsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Analytic, error] {
+	ctx = interfaces.WithOperation(ctx, "analytics.ListAnalyticsSeq")
+
+	endpoint := EndpointAnalyticsJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[Analytic](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListAnalyticsCSV retrieves all analytics in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/analytics.csv
 func (s *Service) ListAnalyticsCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "analytics.ListAnalyticsCSV")
+
 	endpoint := EndpointAnalyticsCSV
 
 	headers := map[string]string{
@@ -223,3 +223,24 @@ func TestAnalytics_MultipleSequentialCalls(t *testing.T) {
 	// Verify both results have same length
 	assert.Equal(t, len(*result1), len(*result2), "Sequential calls should return consistent data")
 }
+
+func TestListAnalyticsSeq_Success(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.AnalyticsMock{}
+	mockHandler.RegisterMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	ctx := context.Background()
+	expected, _, err := service.ListAnalytics(ctx)
+	require.NoError(t, err)
+
+	var items []Analytic
+	for item, err := range service.ListAnalyticsSeq(ctx, nil) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	// The mock ignores page and limit and returns the full list, so one page is fetched
+	assert.ElementsMatch(t, *expected, items)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
@@ -0,0 +1,25 @@
+package analytics
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeAnalyticsCSV decodes the output of ListAnalyticsCSV into typed analytics
+// The result matches the shape returned by the JSON endpoint.
+func DecodeAnalyticsCSV(data []byte) (*AnalyticsResponse, error) {
+	rows, err := csvdecode.Unmarshal[Analytic](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := AnalyticsResponse(rows)
+	return &result, nil
+}
+
+// NewAnalyticsCSVReader returns a streaming reader over a analytics CSV export
+// Use this instead of DecodeAnalyticsCSV for large exports.
+func NewAnalyticsCSVReader(r io.Reader) (*csvdecode.Reader[Analytic], error) {
+	return csvdecode.NewReader[Analytic](r)
+}
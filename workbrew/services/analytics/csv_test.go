@@ -0,0 +1,25 @@
+package analytics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeAnalyticsCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_analytics.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeAnalyticsCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 4)
+
+	row := (*result)[0]
+	assert.Equal(t, "TC6R2DHVHG", row.Device)
+	assert.Equal(t, "brew install curl", row.Command)
+	assert.Equal(t, 2, row.Count)
+	assert.Equal(t, 2024, row.LastRun.Year())
+}
@@ -0,0 +1,352 @@
+package brewcommands
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Subcommand is a brew subcommand supported by CommandBuilder
+type Subcommand string
+
+// Subcommands supported by CommandBuilder
+const (
+	SubcommandInstall    Subcommand = "install"
+	SubcommandReinstall  Subcommand = "reinstall"
+	SubcommandUpgrade    Subcommand = "upgrade"
+	SubcommandUninstall  Subcommand = "uninstall"
+	SubcommandUpdate     Subcommand = "update"
+	SubcommandTap        Subcommand = "tap"
+	SubcommandUntap      Subcommand = "untap"
+	SubcommandCleanup    Subcommand = "cleanup"
+	SubcommandAutoremove Subcommand = "autoremove"
+	SubcommandPin        Subcommand = "pin"
+	SubcommandUnpin      Subcommand = "unpin"
+	SubcommandOutdated   Subcommand = "outdated"
+	SubcommandList       Subcommand = "list"
+	SubcommandDoctor     Subcommand = "doctor"
+	SubcommandConfig     Subcommand = "config"
+)
+
+// Flag is a brew command line flag
+type Flag string
+
+// Flags supported by CommandBuilder. Each subcommand accepts a subset of them.
+const (
+	FlagCask               Flag = "--cask"
+	FlagFormula            Flag = "--formula"
+	FlagForce              Flag = "--force"
+	FlagDryRun             Flag = "--dry-run"
+	FlagGreedy             Flag = "--greedy"
+	FlagZap                Flag = "--zap"
+	FlagIgnoreDependencies Flag = "--ignore-dependencies"
+	FlagOverwrite          Flag = "--overwrite"
+	FlagFetchHEAD          Flag = "--fetch-HEAD"
+	FlagPruneAll           Flag = "--prune=all"
+	FlagVersions           Flag = "--versions"
+	FlagJSON               Flag = "--json"
+	FlagVerbose            Flag = "--verbose"
+	FlagQuiet              Flag = "--quiet"
+)
+
+// operandKind describes the operands a subcommand takes
+type operandKind int
+
+const (
+	operandsNone operandKind = iota
+	operandsOptional
+	operandsRequired
+	operandsOneTap
+	operandsTaps
+)
+
+// subcommandSpec describes the operands and flags a subcommand accepts
+type subcommandSpec struct {
+	operands operandKind
+	flags    []Flag
+}
+
+// subcommandSpecs lists the subcommands supported by CommandBuilder
+var subcommandSpecs = map[Subcommand]subcommandSpec{
+	SubcommandInstall:    {operandsRequired, []Flag{FlagCask, FlagFormula, FlagForce, FlagDryRun, FlagOverwrite, FlagFetchHEAD, FlagVerbose, FlagQuiet}},
+	SubcommandReinstall:  {operandsRequired, []Flag{FlagCask, FlagFormula, FlagForce, FlagVerbose, FlagQuiet}},
+	SubcommandUpgrade:    {operandsOptional, []Flag{FlagCask, FlagFormula, FlagForce, FlagDryRun, FlagGreedy, FlagFetchHEAD, FlagVerbose, FlagQuiet}},
+	SubcommandUninstall:  {operandsRequired, []Flag{FlagCask, FlagFormula, FlagForce, FlagZap, FlagIgnoreDependencies, FlagVerbose, FlagQuiet}},
+	SubcommandUpdate:     {operandsNone, []Flag{FlagForce, FlagVerbose, FlagQuiet}},
+	SubcommandTap:        {operandsOneTap, []Flag{FlagForce, FlagVerbose, FlagQuiet}},
+	SubcommandUntap:      {operandsTaps, []Flag{FlagForce}},
+	SubcommandCleanup:    {operandsOptional, []Flag{FlagDryRun, FlagPruneAll, FlagVerbose, FlagQuiet}},
+	SubcommandAutoremove: {operandsNone, []Flag{FlagDryRun}},
+	SubcommandPin:        {operandsRequired, nil},
+	SubcommandUnpin:      {operandsRequired, nil},
+	SubcommandOutdated:   {operandsOptional, []Flag{FlagCask, FlagFormula, FlagGreedy, FlagJSON, FlagVerbose, FlagQuiet}},
+	SubcommandList:       {operandsOptional, []Flag{FlagCask, FlagFormula, FlagVersions}},
+	SubcommandDoctor:     {operandsNone, []Flag{FlagVerbose, FlagQuiet}},
+	SubcommandConfig:     {operandsNone, nil},
+}
+
+var (
+	// packageNamePattern matches formula and cask names, optionally fully qualified,
+	// e.g. "wget", "python@3.12" or "homebrew/cask/firefox"
+	packageNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+@-]*(/[A-Za-z0-9][A-Za-z0-9._+@-]*){0,2}$`)
+
+	// tapNamePattern matches tap names in user/repo form, e.g. "homebrew/cask"
+	tapNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*/[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+)
+
+// CommandBuilder builds a CreateBrewCommandRequest from typed parts and validates
+// it before it is sent to the API.
+type CommandBuilder struct {
+	subcommand Subcommand
+	operands   []string
+	flags      []Flag
+	deviceIDs  []string
+	runAfter   time.Time
+	recurrence Recurrence
+}
+
+// NewCommandBuilder starts a brew command for a subcommand.
+//
+// Parameters:
+//   - subcommand: The brew subcommand, e.g. SubcommandInstall
+//   - operands: Formula, cask or tap names the subcommand acts on
+//
+// Returns:
+//   - *CommandBuilder: A builder for further configuration
+//
+// Example:
+//
+//	request, err := brewcommands.NewCommandBuilder(brewcommands.SubcommandInstall, "firefox").
+//	    WithFlags(brewcommands.FlagCask).
+//	    OnDevices("TC6R2DHVHG").
+//	    Build()
+func NewCommandBuilder(subcommand Subcommand, operands ...string) *CommandBuilder {
+	return &CommandBuilder{subcommand: subcommand, operands: operands}
+}
+
+// Install builds "brew install" for the given formulae or casks
+func Install(names ...string) *CommandBuilder {
+	return NewCommandBuilder(SubcommandInstall, names...)
+}
+
+// Reinstall builds "brew reinstall" for the given formulae or casks
+func Reinstall(names ...string) *CommandBuilder {
+	return NewCommandBuilder(SubcommandReinstall, names...)
+}
+
+// Upgrade builds "brew upgrade". With no names every outdated package is upgraded.
+func Upgrade(names ...string) *CommandBuilder {
+	return NewCommandBuilder(SubcommandUpgrade, names...)
+}
+
+// Uninstall builds "brew uninstall" for the given formulae or casks
+func Uninstall(names ...string) *CommandBuilder {
+	return NewCommandBuilder(SubcommandUninstall, names...)
+}
+
+// Update builds "brew update"
+func Update() *CommandBuilder {
+	return NewCommandBuilder(SubcommandUpdate)
+}
+
+// Tap builds "brew tap" for a tap in user/repo form
+func Tap(name string) *CommandBuilder {
+	return NewCommandBuilder(SubcommandTap, name)
+}
+
+// Untap builds "brew untap" for taps in user/repo form
+func Untap(names ...string) *CommandBuilder {
+	return NewCommandBuilder(SubcommandUntap, names...)
+}
+
+// Cleanup builds "brew cleanup". With no names every package is cleaned up.
+func Cleanup(names ...string) *CommandBuilder {
+	return NewCommandBuilder(SubcommandCleanup, names...)
+}
+
+// Autoremove builds "brew autoremove"
+func Autoremove() *CommandBuilder {
+	return NewCommandBuilder(SubcommandAutoremove)
+}
+
+// Pin builds "brew pin" for the given formulae
+func Pin(names ...string) *CommandBuilder {
+	return NewCommandBuilder(SubcommandPin, names...)
+}
+
+// Unpin builds "brew unpin" for the given formulae
+func Unpin(names ...string) *CommandBuilder {
+	return NewCommandBuilder(SubcommandUnpin, names...)
+}
+
+// Doctor builds "brew doctor"
+func Doctor() *CommandBuilder {
+	return NewCommandBuilder(SubcommandDoctor)
+}
+
+// WithFlags adds flags to the command. Duplicate flags are ignored.
+func (b *CommandBuilder) WithFlags(flags ...Flag) *CommandBuilder {
+	for _, flag := range flags {
+		if !slices.Contains(b.flags, flag) {
+			b.flags = append(b.flags, flag)
+		}
+	}
+	return b
+}
+
+// OnDevices limits the command to the given devices. Without it the command
+// runs on every device in the workspace. Duplicate IDs are ignored.
+func (b *CommandBuilder) OnDevices(deviceIDs ...string) *CommandBuilder {
+	for _, id := range deviceIDs {
+		if !slices.Contains(b.deviceIDs, id) {
+			b.deviceIDs = append(b.deviceIDs, id)
+		}
+	}
+	return b
+}
+
+// RunAfter schedules the command. The time is sent with minute precision in its
+// own location, so convert it with t.In first if the workspace expects another time zone.
+func (b *CommandBuilder) RunAfter(t time.Time) *CommandBuilder {
+	b.runAfter = t
+	return b
+}
+
+// Recurring sets how often the command is run
+func (b *CommandBuilder) Recurring(recurrence Recurrence) *CommandBuilder {
+	b.recurrence = recurrence
+	return b
+}
+
+// Arguments returns the brew arguments the builder produces, e.g. "install --cask firefox".
+//
+// Returns:
+//   - string: The validated arguments
+//   - error: The problems found with the subcommand, operands or flags
+func (b *CommandBuilder) Arguments() (string, error) {
+	spec, ok := subcommandSpecs[b.subcommand]
+	if !ok {
+		return "", fmt.Errorf("unsupported brew subcommand %q", b.subcommand)
+	}
+
+	var errs []error
+	errs = append(errs, validateOperands(b.subcommand, spec.operands, b.operands)...)
+
+	for _, flag := range b.flags {
+		if !slices.Contains(spec.flags, flag) {
+			errs = append(errs, fmt.Errorf("flag %s is not supported by brew %s", flag, b.subcommand))
+		}
+	}
+	if slices.Contains(b.flags, FlagCask) && slices.Contains(b.flags, FlagFormula) {
+		errs = append(errs, fmt.Errorf("flags %s and %s cannot be combined", FlagCask, FlagFormula))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return "", err
+	}
+
+	parts := []string{string(b.subcommand)}
+	for _, flag := range b.flags {
+		parts = append(parts, string(flag))
+	}
+	parts = append(parts, b.operands...)
+	return strings.Join(parts, " "), nil
+}
+
+// Build validates the command and returns the request to pass to CreateBrewCommand.
+//
+// Returns:
+//   - *CreateBrewCommandRequest: The request
+//   - error: The problems found, joined, or nil if the command is valid
+//
+// Example:
+//
+//	request, err := brewcommands.Upgrade().
+//	    WithFlags(brewcommands.FlagGreedy).
+//	    RunAfter(time.Date(2025, 1, 10, 10, 9, 0, 0, time.Local)).
+//	    Recurring(brewcommands.RecurrenceWeekly).
+//	    Build()
+//	if err != nil {
+//	    return err
+//	}
+//	_, _, err = client.BrewCommands.CreateBrewCommand(ctx, request)
+func (b *CommandBuilder) Build() (*CreateBrewCommandRequest, error) {
+	var errs []error
+
+	arguments, err := b.Arguments()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	request := &CreateBrewCommandRequest{Arguments: arguments}
+
+	if len(b.deviceIDs) > 0 {
+		for _, id := range b.deviceIDs {
+			if strings.TrimSpace(id) == "" || strings.Contains(id, ",") {
+				errs = append(errs, fmt.Errorf("invalid device ID %q", id))
+			}
+		}
+		deviceIDs := strings.Join(b.deviceIDs, ",")
+		request.DeviceIDs = &deviceIDs
+	}
+
+	if !b.runAfter.IsZero() {
+		runAfter := b.runAfter.Format(RunAfterLayout)
+		request.RunAfterDatetime = &runAfter
+	}
+
+	if b.recurrence != "" {
+		if !b.recurrence.Valid() {
+			errs = append(errs, fmt.Errorf("recurrence %q must be one of once, daily, weekly or monthly", b.recurrence))
+		}
+		recurrence := string(b.recurrence)
+		request.Recurrence = &recurrence
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	// Guards against operands that are valid names but unsafe arguments
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// validateOperands checks the number and form of a subcommand's operands
+func validateOperands(subcommand Subcommand, kind operandKind, operands []string) []error {
+	var errs []error
+
+	switch kind {
+	case operandsNone:
+		if len(operands) > 0 {
+			errs = append(errs, fmt.Errorf("brew %s does not take arguments", subcommand))
+		}
+		return errs
+	case operandsRequired, operandsTaps:
+		if len(operands) == 0 {
+			errs = append(errs, fmt.Errorf("brew %s requires at least one name", subcommand))
+		}
+	case operandsOneTap:
+		if len(operands) != 1 {
+			errs = append(errs, fmt.Errorf("brew %s requires exactly one tap", subcommand))
+		}
+	}
+
+	pattern, what := packageNamePattern, "formula or cask name"
+	if kind == operandsOneTap || kind == operandsTaps {
+		pattern, what = tapNamePattern, "tap name (expected user/repo)"
+	}
+	for _, operand := range operands {
+		if !pattern.MatchString(operand) {
+			errs = append(errs, fmt.Errorf("invalid %s %q", what, operand))
+		}
+	}
+
+	return errs
+}
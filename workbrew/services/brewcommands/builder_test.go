@@ -0,0 +1,128 @@
+package brewcommands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandBuilder_Build(t *testing.T) {
+	runAfter := time.Date(2025, 1, 10, 10, 9, 42, 0, time.UTC)
+
+	request, err := Install("firefox", "homebrew/cask/slack").
+		WithFlags(FlagCask, FlagForce, FlagCask).
+		OnDevices("TC6R2DHVHG", "1234567890", "TC6R2DHVHG").
+		RunAfter(runAfter).
+		Recurring(RecurrenceWeekly).
+		Build()
+
+	require.NoError(t, err)
+	assert.Equal(t, "install --cask --force firefox homebrew/cask/slack", request.Arguments)
+	require.NotNil(t, request.DeviceIDs)
+	assert.Equal(t, "TC6R2DHVHG,1234567890", *request.DeviceIDs)
+	require.NotNil(t, request.RunAfterDatetime)
+	assert.Equal(t, "2025-01-10T10:09", *request.RunAfterDatetime)
+	require.NotNil(t, request.Recurrence)
+	assert.Equal(t, "weekly", *request.Recurrence)
+}
+
+func TestCommandBuilder_Defaults(t *testing.T) {
+	request, err := Upgrade().Build()
+
+	require.NoError(t, err)
+	assert.Equal(t, "upgrade", request.Arguments)
+	assert.Nil(t, request.DeviceIDs)
+	assert.Nil(t, request.RunAfterDatetime)
+	assert.Nil(t, request.Recurrence)
+}
+
+func TestCommandBuilder_Arguments(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *CommandBuilder
+		want    string
+		wantErr string
+	}{
+		{name: "tap", builder: Tap("deploymenttheory/tools"), want: "tap deploymenttheory/tools"},
+		{name: "versioned formula", builder: Uninstall("python@3.12").WithFlags(FlagIgnoreDependencies), want: "uninstall --ignore-dependencies python@3.12"},
+		{name: "cleanup prune", builder: Cleanup().WithFlags(FlagPruneAll, FlagDryRun), want: "cleanup --prune=all --dry-run"},
+		{name: "update", builder: Update(), want: "update"},
+		{name: "install without names", builder: Install(), wantErr: "brew install requires at least one name"},
+		{name: "update with names", builder: NewCommandBuilder(SubcommandUpdate, "wget"), wantErr: "brew update does not take arguments"},
+		{name: "tap without repo", builder: Tap("cask"), wantErr: `invalid tap name (expected user/repo) "cask"`},
+		{name: "two taps", builder: NewCommandBuilder(SubcommandTap, "a/b", "c/d"), wantErr: "brew tap requires exactly one tap"},
+		{name: "flag as name", builder: Install("--HEAD"), wantErr: `invalid formula or cask name "--HEAD"`},
+		{name: "chained command", builder: Install("wget;rm"), wantErr: `invalid formula or cask name "wget;rm"`},
+		{name: "unsupported flag", builder: Pin("wget").WithFlags(FlagCask), wantErr: "flag --cask is not supported by brew pin"},
+		{name: "cask and formula", builder: Install("wget").WithFlags(FlagCask, FlagFormula), wantErr: "flags --cask and --formula cannot be combined"},
+		{name: "unknown subcommand", builder: NewCommandBuilder("sh"), wantErr: `unsupported brew subcommand "sh"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Arguments()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommandBuilder_BuildErrors(t *testing.T) {
+	_, err := Install().
+		OnDevices("TC6R2DHVHG", "").
+		Recurring("hourly").
+		Build()
+
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "brew install requires at least one name")
+	assert.ErrorContains(t, err, `invalid device ID ""`)
+	assert.ErrorContains(t, err, `recurrence "hourly" must be one of once, daily, weekly or monthly`)
+}
+
+func TestCreateBrewCommandRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request CreateBrewCommandRequest
+		wantErr string
+	}{
+		{name: "valid", request: CreateBrewCommandRequest{Arguments: "install wget", RunAfterDatetime: ptr("2025-01-10T10:09"), Recurrence: ptr("daily")}},
+		{name: "blank", request: CreateBrewCommandRequest{Arguments: "  "}, wantErr: "arguments cannot be blank"},
+		{name: "and operator", request: CreateBrewCommandRequest{Arguments: "update && upgrade"}, wantErr: `arguments cannot include "&&"`},
+		{name: "pipe", request: CreateBrewCommandRequest{Arguments: "list | grep wget"}, wantErr: `arguments cannot include "|"`},
+		{name: "command substitution", request: CreateBrewCommandRequest{Arguments: "install $(whoami)"}, wantErr: `arguments cannot include "$("`},
+		{name: "newline", request: CreateBrewCommandRequest{Arguments: "update\nupgrade"}, wantErr: `arguments cannot include "\n"`},
+		{name: "brew prefix", request: CreateBrewCommandRequest{Arguments: "brew install wget"}, wantErr: `arguments must not start with "brew"; use e.g. "install wget"`},
+		{name: "arbitrary code", request: CreateBrewCommandRequest{Arguments: "ruby -e 'puts 1'"}, wantErr: `brew subcommand "ruby" is not allowed`},
+		{name: "empty device ID", request: CreateBrewCommandRequest{Arguments: "update", DeviceIDs: ptr("a,,b")}, wantErr: "contains an empty device ID"},
+		{name: "run after layout", request: CreateBrewCommandRequest{Arguments: "update", RunAfterDatetime: ptr("10/01/2025 10:09")}, wantErr: "must use the layout 2006-01-02T15:04"},
+		{name: "recurrence", request: CreateBrewCommandRequest{Arguments: "update", Recurrence: ptr("yearly")}, wantErr: `recurrence "yearly"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRecurrence_Valid(t *testing.T) {
+	assert.True(t, Recurrence(RecurrenceOnce).Valid())
+	assert.True(t, Recurrence(RecurrenceMonthly).Valid())
+	assert.False(t, Recurrence("").Valid())
+	assert.False(t, Recurrence("hourly").Valid())
+}
+
+func ptr(s string) *string {
+	return &s
+}
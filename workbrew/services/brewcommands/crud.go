@@ -3,7 +3,9 @@ package brewcommands
 import (
 	"context"
 	"fmt"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -17,6 +19,9 @@ type (
 		// Returns brew commands with command, label, last updated user, start/finish timestamps, devices, and run count
 		ListBrewCommands(ctx context.Context) (*BrewCommandsResponse, *interfaces.Response, error)
 
+		// ListBrewCommandsSeq returns an iterator over brew commands
+		ListBrewCommandsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[BrewCommand, error]
+
 		// ListBrewCommandsCSV returns a list of brew commands in CSV format
 		//
 		// Returns the same brew commands data as ListBrewCommands but formatted as CSV
@@ -32,6 +37,9 @@ type (
 		// Returns run history including command, label, device, timestamps, success status, and output for the specified brew command label
 		ListBrewCommandRuns(ctx context.Context, brewCommandLabel string) (*BrewCommandRunsResponse, *interfaces.Response, error)
 
+		// ListBrewCommandRunsSeq returns an iterator over the runs of a brew command
+		ListBrewCommandRunsSeq(ctx context.Context, brewCommandLabel string, pageOpts *client.PageOptions) iter.Seq2[BrewCommandRun, error]
+
 		// ListBrewCommandRunsCSV returns a list of brew command runs in CSV format
 		//
 		// Returns the same run data as ListBrewCommandRuns but formatted as CSV
@@ -60,6 +68,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // ListBrewCommands retrieves all brew commands in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_commands.json
 func (s *Service) ListBrewCommands(ctx context.Context) (*BrewCommandsResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewcommands.ListBrewCommands")
+
 	endpoint := EndpointBrewCommandsJSON
 
 	headers := map[string]string{
@@ -78,9 +88,31 @@ func (s *Service) ListBrewCommands(ctx context.Context) (*BrewCommandsResponse,
 	return &result, resp, nil
 }
 
+// ListBrewCommandsSeq iterates over brew commands in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_commands.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListBrewCommandsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[BrewCommand, error] {
+	ctx = interfaces.WithOperation(ctx, "brewcommands.ListBrewCommandsSeq")
+
+	endpoint := EndpointBrewCommandsJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[BrewCommand](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListBrewCommandsCSV retrieves all brew commands in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_commands.csv
 func (s *Service) ListBrewCommandsCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewcommands.ListBrewCommandsCSV")
+
 	endpoint := EndpointBrewCommandsCSV
 
 	headers := map[string]string{
@@ -104,7 +136,19 @@ func (s *Service) ListBrewCommandsCSV(ctx context.Context) ([]byte, *interfaces.
 //   - 201: Brew Command created successfully
 //   - 403: On a Free tier plan (requires upgrade)
 //   - 422: Validation error (e.g., "Arguments cannot include `&&`")
+//
+// The request is validated with CreateBrewCommandRequest.Validate before it is sent.
+// Use CommandBuilder to construct requests from typed subcommands and flags.
 func (s *Service) CreateBrewCommand(ctx context.Context, request *CreateBrewCommandRequest) (*CreateBrewCommandResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewcommands.CreateBrewCommand")
+
+	if request == nil {
+		return nil, nil, fmt.Errorf("brew command request is required")
+	}
+	if err := request.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid brew command request: %w", err)
+	}
+
 	endpoint := EndpointBrewCommandsJSON
 
 	headers := map[string]string{
@@ -124,6 +168,8 @@ func (s *Service) CreateBrewCommand(ctx context.Context, request *CreateBrewComm
 // ListBrewCommandRuns retrieves all runs for a specific brew command in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_commands/{brew_command_label}/runs.json
 func (s *Service) ListBrewCommandRuns(ctx context.Context, brewCommandLabel string) (*BrewCommandRunsResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewcommands.ListBrewCommandRuns")
+
 	if brewCommandLabel == "" {
 		return nil, nil, fmt.Errorf("brew command label is required")
 	}
@@ -146,9 +192,37 @@ func (s *Service) ListBrewCommandRuns(ctx context.Context, brewCommandLabel stri
 	return &result, resp, nil
 }
 
+// ListBrewCommandRunsSeq iterates over the runs of a brew command in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_commands/{brew_command_label}/runs.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListBrewCommandRunsSeq(ctx context.Context, brewCommandLabel string, pageOpts *client.PageOptions) iter.Seq2[BrewCommandRun, error] {
+	ctx = interfaces.WithOperation(ctx, "brewcommands.ListBrewCommandRunsSeq")
+
+	if brewCommandLabel == "" {
+		return func(yield func(BrewCommandRun, error) bool) {
+			yield(BrewCommandRun{}, fmt.Errorf("brew command label is required"))
+		}
+	}
+
+	endpoint := fmt.Sprintf(EndpointBrewCommandRunsJSONFormat, brewCommandLabel)
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[BrewCommandRun](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListBrewCommandRunsCSV retrieves all runs for a specific brew command in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_commands/{brew_command_label}/runs.csv
 func (s *Service) ListBrewCommandRunsCSV(ctx context.Context, brewCommandLabel string) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewcommands.ListBrewCommandRunsCSV")
+
 	if brewCommandLabel == "" {
 		return nil, nil, fmt.Errorf("brew command label is required")
 	}
@@ -127,3 +127,59 @@ func TestCreateBrewCommand_FreeTier(t *testing.T) {
 
 	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
+
+func TestCreateBrewCommand_InvalidRequest(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.BrewCommandsMock{}
+	mockHandler.RegisterMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	ctx := context.Background()
+	request := &CreateBrewCommandRequest{
+		Arguments: "update && upgrade",
+	}
+
+	result, resp, err := service.CreateBrewCommand(ctx, request)
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), `arguments cannot include "&&"`)
+
+	// Rejected before the POST
+	assert.Equal(t, 0, httpmock.GetTotalCallCount())
+}
+
+func TestListBrewCommandsSeq_Success(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.BrewCommandsMock{}
+	mockHandler.RegisterMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	ctx := context.Background()
+	expected, _, err := service.ListBrewCommands(ctx)
+	require.NoError(t, err)
+
+	var items []BrewCommand
+	for item, err := range service.ListBrewCommandsSeq(ctx, nil) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	// The mock ignores page and limit and returns the full list, so one page is fetched
+	assert.ElementsMatch(t, *expected, items)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestListBrewCommandRunsSeq_EmptyLabel(t *testing.T) {
+	service, _ := setupMockClient(t)
+
+	var errs []error
+	for _, err := range service.ListBrewCommandRunsSeq(context.Background(), "", nil) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "brew command label is required")
+	assert.Equal(t, 0, httpmock.GetTotalCallCount())
+}
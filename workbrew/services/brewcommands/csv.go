@@ -0,0 +1,43 @@
+package brewcommands
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeBrewCommandsCSV decodes the output of ListBrewCommandsCSV into typed brew commands
+// The result matches the shape returned by the JSON endpoint.
+func DecodeBrewCommandsCSV(data []byte) (*BrewCommandsResponse, error) {
+	rows, err := csvdecode.Unmarshal[BrewCommand](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := BrewCommandsResponse(rows)
+	return &result, nil
+}
+
+// NewBrewCommandsCSVReader returns a streaming reader over a brew commands CSV export
+// Use this instead of DecodeBrewCommandsCSV for large exports.
+func NewBrewCommandsCSVReader(r io.Reader) (*csvdecode.Reader[BrewCommand], error) {
+	return csvdecode.NewReader[BrewCommand](r)
+}
+
+// DecodeBrewCommandRunsCSV decodes the output of ListBrewCommandRunsCSV into typed brew command runs
+// The result matches the shape returned by the JSON endpoint.
+func DecodeBrewCommandRunsCSV(data []byte) (*BrewCommandRunsResponse, error) {
+	rows, err := csvdecode.Unmarshal[BrewCommandRun](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := BrewCommandRunsResponse(rows)
+	return &result, nil
+}
+
+// NewBrewCommandRunsCSVReader returns a streaming reader over a brew command runs CSV export
+// Use this instead of DecodeBrewCommandRunsCSV for large exports.
+func NewBrewCommandRunsCSVReader(r io.Reader) (*csvdecode.Reader[BrewCommandRun], error) {
+	return csvdecode.NewReader[BrewCommandRun](r)
+}
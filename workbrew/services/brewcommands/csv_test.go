@@ -0,0 +1,45 @@
+package brewcommands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBrewCommandsCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_brew_commands.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeBrewCommandsCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 3)
+
+	command := (*result)[1]
+	assert.Equal(t, "brew list --versions --formula", command.Command)
+	assert.True(t, command.StartedAt.HasTime())
+	assert.True(t, command.FinishedAt.IsNotFinished())
+	assert.Equal(t, []string{"TC6R2DHVHG"}, command.Devices)
+
+	assert.True(t, (*result)[2].StartedAt.IsNotStarted())
+	assert.Empty(t, (*result)[0].Devices)
+}
+
+func TestDecodeBrewCommandRunsCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_brew_command_runs.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeBrewCommandRunsCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+
+	run := (*result)[0]
+	assert.Equal(t, "TC6R2DHVHG", run.Device)
+	assert.True(t, run.Success)
+	assert.Equal(t, "c-ares\nlibuv", run.Output)
+	assert.Equal(t, 2025, run.CreatedAt.Year())
+
+	assert.True(t, (*result)[1].FinishedAt.IsNotFinished())
+}
@@ -0,0 +1,101 @@
+package brewcommands
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RunAfterLayout is the time layout of run_after_datetime, e.g. "2025-01-10T10:09"
+const RunAfterLayout = "2006-01-02T15:04"
+
+// Recurrence is how often a brew command is run. The Recurrence constants
+// (RecurrenceOnce, RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly) are its valid values.
+type Recurrence string
+
+// Valid reports whether r is one of the recurrences accepted by the API
+func (r Recurrence) Valid() bool {
+	switch r {
+	case RecurrenceOnce, RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly:
+		return true
+	}
+	return false
+}
+
+// forbiddenSequences are shell operators and metacharacters. Arguments are passed to
+// brew, not a shell, so they are never needed and usually indicate an attempt to
+// chain commands.
+var forbiddenSequences = []string{"&&", "||", ";", "|", "&", "`", "$(", "${", ">", "<", "\n", "\r"}
+
+// forbiddenSubcommands run arbitrary code or need an interactive terminal
+var forbiddenSubcommands = []string{"sh", "ruby", "irb", "edit", "debugger"}
+
+// ValidateArguments checks brew arguments before they are sent to the API.
+// It rejects blank arguments, arguments that include the "brew" prefix, shell
+// operators such as "&&" and ";", and subcommands that run arbitrary code
+// (sh, ruby, irb, edit, debugger).
+//
+// Parameters:
+//   - arguments: The brew arguments, e.g. "install wget"
+//
+// Returns:
+//   - error: A description of the first problem found, or nil if the arguments are valid
+func ValidateArguments(arguments string) error {
+	fields := strings.Fields(arguments)
+	if len(fields) == 0 {
+		return fmt.Errorf("arguments cannot be blank")
+	}
+
+	for _, sequence := range forbiddenSequences {
+		if strings.Contains(arguments, sequence) {
+			return fmt.Errorf("arguments cannot include %q", sequence)
+		}
+	}
+
+	if fields[0] == "brew" {
+		return fmt.Errorf("arguments must not start with \"brew\"; use e.g. %q", strings.Join(fields[1:], " "))
+	}
+	if slices.Contains(forbiddenSubcommands, fields[0]) {
+		return fmt.Errorf("brew subcommand %q is not allowed", fields[0])
+	}
+
+	return nil
+}
+
+// Validate checks the request before it is sent to the API.
+// CreateBrewCommand calls it automatically.
+//
+// Returns:
+//   - error: The problems found, joined, or nil if the request is valid
+func (r *CreateBrewCommandRequest) Validate() error {
+	var errs []error
+
+	if err := ValidateArguments(r.Arguments); err != nil {
+		errs = append(errs, err)
+	}
+
+	if r.DeviceIDs != nil {
+		for _, id := range strings.Split(*r.DeviceIDs, ",") {
+			if strings.TrimSpace(id) == "" {
+				errs = append(errs, fmt.Errorf("device_ids %q contains an empty device ID", *r.DeviceIDs))
+				break
+			}
+		}
+	}
+
+	if r.RunAfterDatetime != nil {
+		if _, err := time.Parse(RunAfterLayout, *r.RunAfterDatetime); err != nil {
+			if _, err := time.Parse(time.RFC3339, *r.RunAfterDatetime); err != nil {
+				errs = append(errs, fmt.Errorf("run_after_datetime %q must use the layout %s, e.g. 2025-01-10T10:09", *r.RunAfterDatetime, RunAfterLayout))
+			}
+		}
+	}
+
+	if r.Recurrence != nil && !Recurrence(*r.Recurrence).Valid() {
+		errs = append(errs, fmt.Errorf("recurrence %q must be one of once, daily, weekly or monthly", *r.Recurrence))
+	}
+
+	return errors.Join(errs...)
+}
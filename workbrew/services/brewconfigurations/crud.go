@@ -2,7 +2,9 @@ package brewconfigurations
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -16,6 +18,9 @@ type (
 		// Returns Homebrew environment variable configurations with their keys, values, last updated user, and assigned device groups.
 		ListBrewConfigurations(ctx context.Context) (*BrewConfigurationsResponse, *interfaces.Response, error)
 
+		// ListBrewConfigurationsSeq returns an iterator over brew configurations
+		ListBrewConfigurationsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[BrewConfiguration, error]
+
 		// ListBrewConfigurationsCSV returns a list of Brew Configurations in CSV format
 		//
 		// Returns brew configuration data as CSV with columns: key, value, last_updated_by_user, device_group.
@@ -42,6 +47,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // ListBrewConfigurations retrieves all brew configurations in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_configurations.json
 func (s *Service) ListBrewConfigurations(ctx context.Context) (*BrewConfigurationsResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewconfigurations.ListBrewConfigurations")
+
 	endpoint := EndpointBrewConfigurationsJSON
 
 	headers := map[string]string{
@@ -60,9 +67,31 @@ func (s *Service) ListBrewConfigurations(ctx context.Context) (*BrewConfiguratio
 	return &result, resp, nil
 }
 
+// ListBrewConfigurationsSeq iterates over brew configurations in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_configurations.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListBrewConfigurationsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[BrewConfiguration, error] {
+	ctx = interfaces.WithOperation(ctx, "brewconfigurations.ListBrewConfigurationsSeq")
+
+	endpoint := EndpointBrewConfigurationsJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[BrewConfiguration](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListBrewConfigurationsCSV retrieves all brew configurations in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_configurations.csv
 func (s *Service) ListBrewConfigurationsCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewconfigurations.ListBrewConfigurationsCSV")
+
 	endpoint := EndpointBrewConfigurationsCSV
 
 	headers := map[string]string{
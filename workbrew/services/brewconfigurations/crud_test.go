@@ -84,3 +84,24 @@ func TestListBrewConfigurationsCSV_Success(t *testing.T) {
 	assert.Contains(t, csvString, "key,value,last_updated_by_user,device_group")
 	assert.Contains(t, csvString, "HOMEBREW_DEVELOPER")
 }
+
+func TestListBrewConfigurationsSeq_Success(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.BrewConfigurationsMock{}
+	mockHandler.RegisterMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	ctx := context.Background()
+	expected, _, err := service.ListBrewConfigurations(ctx)
+	require.NoError(t, err)
+
+	var items []BrewConfiguration
+	for item, err := range service.ListBrewConfigurationsSeq(ctx, nil) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	// The mock ignores page and limit and returns the full list, so one page is fetched
+	assert.ElementsMatch(t, *expected, items)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
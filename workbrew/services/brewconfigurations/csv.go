@@ -0,0 +1,25 @@
+package brewconfigurations
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeBrewConfigurationsCSV decodes the output of ListBrewConfigurationsCSV into typed brew configurations
+// The result matches the shape returned by the JSON endpoint.
+func DecodeBrewConfigurationsCSV(data []byte) (*BrewConfigurationsResponse, error) {
+	rows, err := csvdecode.Unmarshal[BrewConfiguration](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := BrewConfigurationsResponse(rows)
+	return &result, nil
+}
+
+// NewBrewConfigurationsCSVReader returns a streaming reader over a brew configurations CSV export
+// Use this instead of DecodeBrewConfigurationsCSV for large exports.
+func NewBrewConfigurationsCSVReader(r io.Reader) (*csvdecode.Reader[BrewConfiguration], error) {
+	return csvdecode.NewReader[BrewConfiguration](r)
+}
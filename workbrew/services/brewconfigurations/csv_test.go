@@ -0,0 +1,24 @@
+package brewconfigurations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBrewConfigurationsCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_brew_configurations.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeBrewConfigurationsCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 4)
+
+	row := (*result)[2]
+	assert.Equal(t, "HOMEBREW_FORBIDDEN_FORMULAE", row.Key)
+	assert.Equal(t, "util-linux ruby", row.Value)
+	assert.Equal(t, "All Devices", row.DeviceGroup)
+}
@@ -3,7 +3,9 @@ package brewfiles
 import (
 	"context"
 	"fmt"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -17,6 +19,9 @@ type (
 		// Returns brewfiles with last updated user, start/finish timestamps, assigned devices, and run count
 		ListBrewfiles(ctx context.Context) (*BrewfilesResponse, *interfaces.Response, error)
 
+		// ListBrewfilesSeq returns an iterator over brewfiles
+		ListBrewfilesSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Brewfile, error]
+
 		// ListBrewfilesCSV returns a list of brewfiles in CSV format
 		//
 		// Returns the same brewfiles data as ListBrewfiles but formatted as CSV
@@ -42,6 +47,9 @@ type (
 		// Returns run history including label, device, timestamps, success status, and output for the specified brewfile label
 		ListBrewfileRuns(ctx context.Context, label string) (*BrewfileRunsResponse, *interfaces.Response, error)
 
+		// ListBrewfileRunsSeq returns an iterator over the runs of a brewfile
+		ListBrewfileRunsSeq(ctx context.Context, label string, pageOpts *client.PageOptions) iter.Seq2[BrewfileRun, error]
+
 		// ListBrewfileRunsCSV returns a list of brewfile runs in CSV format
 		//
 		// Returns the same run data as ListBrewfileRuns but formatted as CSV
@@ -70,6 +78,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // ListBrewfiles retrieves all brewfiles in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brewfiles.json
 func (s *Service) ListBrewfiles(ctx context.Context) (*BrewfilesResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewfiles.ListBrewfiles")
+
 	endpoint := EndpointBrewfilesJSON
 
 	headers := map[string]string{
@@ -88,9 +98,31 @@ func (s *Service) ListBrewfiles(ctx context.Context) (*BrewfilesResponse, *inter
 	return &result, resp, nil
 }
 
+// ListBrewfilesSeq iterates over brewfiles in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brewfiles.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListBrewfilesSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Brewfile, error] {
+	ctx = interfaces.WithOperation(ctx, "brewfiles.ListBrewfilesSeq")
+
+	endpoint := EndpointBrewfilesJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[Brewfile](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListBrewfilesCSV retrieves all brewfiles in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brewfiles.csv
 func (s *Service) ListBrewfilesCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewfiles.ListBrewfilesCSV")
+
 	endpoint := EndpointBrewfilesCSV
 
 	headers := map[string]string{
@@ -110,6 +142,8 @@ func (s *Service) ListBrewfilesCSV(ctx context.Context) ([]byte, *interfaces.Res
 // CreateBrewfile creates a new brewfile
 // URL: POST https://console.workbrew.com/workspaces/{workspace_name}/brewfiles.json
 func (s *Service) CreateBrewfile(ctx context.Context, request *CreateBrewfileRequest) (*BrewfileMessageResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewfiles.CreateBrewfile")
+
 	endpoint := EndpointBrewfilesJSON
 
 	headers := map[string]string{
@@ -133,6 +167,8 @@ func (s *Service) CreateBrewfile(ctx context.Context, request *CreateBrewfileReq
 //   - 200: Brewfile updated successfully
 //   - 422: Validation error
 func (s *Service) UpdateBrewfile(ctx context.Context, label string, request *UpdateBrewfileRequest) (*BrewfileMessageResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewfiles.UpdateBrewfile")
+
 	if label == "" {
 		return nil, nil, fmt.Errorf("brewfile label is required")
 	}
@@ -159,6 +195,8 @@ func (s *Service) UpdateBrewfile(ctx context.Context, label string, request *Upd
 // Response codes:
 //   - 200: Brewfile deleted successfully
 func (s *Service) DeleteBrewfile(ctx context.Context, label string) (*BrewfileMessageResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewfiles.DeleteBrewfile")
+
 	if label == "" {
 		return nil, nil, fmt.Errorf("brewfile label is required")
 	}
@@ -184,6 +222,8 @@ func (s *Service) DeleteBrewfile(ctx context.Context, label string) (*BrewfileMe
 // ListBrewfileRuns retrieves all runs for a specific brewfile in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brewfiles/{label}/runs.json
 func (s *Service) ListBrewfileRuns(ctx context.Context, label string) (*BrewfileRunsResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewfiles.ListBrewfileRuns")
+
 	if label == "" {
 		return nil, nil, fmt.Errorf("brewfile label is required")
 	}
@@ -206,9 +246,37 @@ func (s *Service) ListBrewfileRuns(ctx context.Context, label string) (*Brewfile
 	return &result, resp, nil
 }
 
+// ListBrewfileRunsSeq iterates over the runs of a brewfile in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brewfiles/{label}/runs.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListBrewfileRunsSeq(ctx context.Context, label string, pageOpts *client.PageOptions) iter.Seq2[BrewfileRun, error] {
+	ctx = interfaces.WithOperation(ctx, "brewfiles.ListBrewfileRunsSeq")
+
+	if label == "" {
+		return func(yield func(BrewfileRun, error) bool) {
+			yield(BrewfileRun{}, fmt.Errorf("brewfile label is required"))
+		}
+	}
+
+	endpoint := fmt.Sprintf(EndpointBrewfileRunsJSONFormat, label)
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[BrewfileRun](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListBrewfileRunsCSV retrieves all runs for a specific brewfile in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brewfiles/{label}/runs.csv
 func (s *Service) ListBrewfileRunsCSV(ctx context.Context, label string) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewfiles.ListBrewfileRunsCSV")
+
 	if label == "" {
 		return nil, nil, fmt.Errorf("brewfile label is required")
 	}
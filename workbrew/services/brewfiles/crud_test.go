@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles/mocks"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
@@ -471,3 +472,44 @@ func TestListBrewfileRunsCSV_Unauthorized(t *testing.T) {
 
 	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
+
+// operationRecorder captures the operation name the service attaches to the request context
+type operationRecorder struct {
+	interfaces.HTTPClient
+	operation string
+}
+
+func (r *operationRecorder) Put(ctx context.Context, path string, body any, headers map[string]string, result any) (*interfaces.Response, error) {
+	r.operation = interfaces.OperationFromContext(ctx)
+	return &interfaces.Response{StatusCode: 200}, nil
+}
+
+func TestUpdateBrewfile_SetsOperation(t *testing.T) {
+	recorder := &operationRecorder{}
+	service := NewService(recorder)
+
+	_, _, err := service.UpdateBrewfile(context.Background(), "dev", &UpdateBrewfileRequest{Content: "brew \"wget\""})
+	require.NoError(t, err)
+	assert.Equal(t, "brewfiles.UpdateBrewfile", recorder.operation)
+}
+
+func TestListBrewfilesSeq_Success(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.BrewfilesMock{}
+	mockHandler.RegisterMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	ctx := context.Background()
+	expected, _, err := service.ListBrewfiles(ctx)
+	require.NoError(t, err)
+
+	var items []Brewfile
+	for item, err := range service.ListBrewfilesSeq(ctx, nil) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	// The mock ignores page and limit and returns the full list, so one page is fetched
+	assert.ElementsMatch(t, *expected, items)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
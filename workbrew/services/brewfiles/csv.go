@@ -0,0 +1,51 @@
+package brewfiles
+
+import (
+	"io"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeBrewfilesCSV decodes the output of ListBrewfilesCSV into typed brewfiles
+// The result matches the shape returned by the JSON endpoint.
+func DecodeBrewfilesCSV(data []byte) (*BrewfilesResponse, error) {
+	rows, err := csvdecode.Unmarshal[Brewfile](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := BrewfilesResponse(rows)
+	return &result, nil
+}
+
+// NewBrewfilesCSVReader returns a streaming reader over a brewfiles CSV export
+// Use this instead of DecodeBrewfilesCSV for large exports.
+func NewBrewfilesCSVReader(r io.Reader) (*csvdecode.Reader[Brewfile], error) {
+	return csvdecode.NewReader[Brewfile](r)
+}
+
+// DecodeBrewfileRunsCSV decodes the output of ListBrewfileRunsCSV into typed brewfile runs
+// The result matches the shape returned by the JSON endpoint.
+func DecodeBrewfileRunsCSV(data []byte) (*BrewfileRunsResponse, error) {
+	rows, err := csvdecode.Unmarshal[BrewfileRun](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := BrewfileRunsResponse(rows)
+	return &result, nil
+}
+
+// NewBrewfileRunsCSVReader returns a streaming reader over a brewfile runs CSV export
+// Use this instead of DecodeBrewfileRunsCSV for large exports.
+func NewBrewfileRunsCSVReader(r io.Reader) (*csvdecode.Reader[BrewfileRun], error) {
+	return csvdecode.NewReader[BrewfileRun](r)
+}
+
+// UnmarshalCSV implements csvdecode.Unmarshaler for BrewfileDevice
+// CSV exports list devices by serial number only
+func (d *BrewfileDevice) UnmarshalCSV(value string) error {
+	d.SerialNumber = strings.TrimSpace(value)
+	return nil
+}
@@ -0,0 +1,41 @@
+package brewfiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBrewfilesCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_brewfiles.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeBrewfilesCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+
+	brewfile := (*result)[0]
+	assert.Equal(t, "my-brewfile", brewfile.Label)
+	assert.Equal(t, "Not Started", brewfile.StartedAt)
+	assert.Equal(t, []BrewfileDevice{{SerialNumber: "TC6R2DHVHG"}}, brewfile.Devices)
+	assert.Equal(t, 1, brewfile.RunCount)
+
+	assert.Empty(t, (*result)[1].Devices)
+}
+
+func TestDecodeBrewfileRunsCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_brewfile_runs.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeBrewfileRunsCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+
+	assert.False(t, (*result)[0].Success)
+	assert.Equal(t, "Not Finished", (*result)[0].FinishedAt)
+	assert.True(t, (*result)[1].Success)
+	assert.Equal(t, "Using git", (*result)[1].Output)
+}
@@ -2,7 +2,9 @@ package brewtaps
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -16,6 +18,9 @@ type (
 		// Returns Homebrew taps with their names, assigned devices, installed formulae/casks counts, and available packages.
 		ListBrewTaps(ctx context.Context) (*BrewTapsResponse, *interfaces.Response, error)
 
+		// ListBrewTapsSeq returns an iterator over brew taps
+		ListBrewTapsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[BrewTap, error]
+
 		// ListBrewTapsCSV returns a list of Taps in CSV format
 		//
 		// Returns tap data as CSV with columns: tap, devices, formulae_installed, casks_installed, available_packages.
@@ -42,6 +47,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // ListBrewTaps retrieves all brew taps in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_taps.json
 func (s *Service) ListBrewTaps(ctx context.Context) (*BrewTapsResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewtaps.ListBrewTaps")
+
 	endpoint := EndpointBrewTapsJSON
 
 	headers := map[string]string{
@@ -60,9 +67,31 @@ func (s *Service) ListBrewTaps(ctx context.Context) (*BrewTapsResponse, *interfa
 	return &result, resp, nil
 }
 
+// ListBrewTapsSeq iterates over brew taps in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_taps.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListBrewTapsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[BrewTap, error] {
+	ctx = interfaces.WithOperation(ctx, "brewtaps.ListBrewTapsSeq")
+
+	endpoint := EndpointBrewTapsJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[BrewTap](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListBrewTapsCSV retrieves all brew taps in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/brew_taps.csv
 func (s *Service) ListBrewTapsCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "brewtaps.ListBrewTapsCSV")
+
 	endpoint := EndpointBrewTapsCSV
 
 	headers := map[string]string{
@@ -125,3 +125,24 @@ func TestListBrewTapsCSV_Unauthorized(t *testing.T) {
 
 	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
+
+func TestListBrewTapsSeq_Success(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.BrewTapsMock{}
+	mockHandler.RegisterMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	ctx := context.Background()
+	expected, _, err := service.ListBrewTaps(ctx)
+	require.NoError(t, err)
+
+	var items []BrewTap
+	for item, err := range service.ListBrewTapsSeq(ctx, nil) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	// The mock ignores page and limit and returns the full list, so one page is fetched
+	assert.ElementsMatch(t, *expected, items)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
@@ -0,0 +1,25 @@
+package brewtaps
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeBrewTapsCSV decodes the output of ListBrewTapsCSV into typed brew taps
+// The result matches the shape returned by the JSON endpoint.
+func DecodeBrewTapsCSV(data []byte) (*BrewTapsResponse, error) {
+	rows, err := csvdecode.Unmarshal[BrewTap](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := BrewTapsResponse(rows)
+	return &result, nil
+}
+
+// NewBrewTapsCSVReader returns a streaming reader over a brew taps CSV export
+// Use this instead of DecodeBrewTapsCSV for large exports.
+func NewBrewTapsCSVReader(r io.Reader) (*csvdecode.Reader[BrewTap], error) {
+	return csvdecode.NewReader[BrewTap](r)
+}
@@ -0,0 +1,25 @@
+package brewtaps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBrewTapsCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_brew_taps.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeBrewTapsCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 4)
+
+	row := (*result)[0]
+	assert.Equal(t, "Homebrew/homebrew-core", row.Tap)
+	assert.Equal(t, []string{"TC6R2DHVHG", "1234567890"}, row.Devices)
+	assert.Equal(t, 10, row.FormulaeInstalled)
+	assert.Equal(t, "7388 Formulae", row.AvailablePackages)
+}
@@ -2,7 +2,9 @@ package casks
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -16,6 +18,9 @@ type (
 		// Returns installed Homebrew casks with their names, assigned devices, outdated status, deprecation info, and versions.
 		ListCasks(ctx context.Context) (*CasksResponse, *interfaces.Response, error)
 
+		// ListCasksSeq returns an iterator over casks
+		ListCasksSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Cask, error]
+
 		// ListCasksCSV returns a list of Casks in CSV format
 		//
 		// Returns cask data as CSV with columns: name, devices, outdated, deprecated, homebrew_cask_version.
@@ -42,6 +47,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // ListCasks retrieves all casks in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/casks.json
 func (s *Service) ListCasks(ctx context.Context) (*CasksResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "casks.ListCasks")
+
 	endpoint := EndpointCasksJSON
 
 	headers := map[string]string{
@@ -60,9 +67,31 @@ func (s *Service) ListCasks(ctx context.Context) (*CasksResponse, *interfaces.Re
 	return &result, resp, nil
 }
 
+// ListCasksSeq iterates over casks in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/casks.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListCasksSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Cask, error] {
+	ctx = interfaces.WithOperation(ctx, "casks.ListCasksSeq")
+
+	endpoint := EndpointCasksJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[Cask](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListCasksCSV retrieves all casks in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/casks.csv
 func (s *Service) ListCasksCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "casks.ListCasksCSV")
+
 	endpoint := EndpointCasksCSV
 
 	headers := map[string]string{
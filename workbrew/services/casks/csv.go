@@ -0,0 +1,25 @@
+package casks
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeCasksCSV decodes the output of ListCasksCSV into typed casks
+// The result matches the shape returned by the JSON endpoint.
+func DecodeCasksCSV(data []byte) (*CasksResponse, error) {
+	rows, err := csvdecode.Unmarshal[Cask](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := CasksResponse(rows)
+	return &result, nil
+}
+
+// NewCasksCSVReader returns a streaming reader over a casks CSV export
+// Use this instead of DecodeCasksCSV for large exports.
+func NewCasksCSVReader(r io.Reader) (*csvdecode.Reader[Cask], error) {
+	return csvdecode.NewReader[Cask](r)
+}
@@ -0,0 +1,27 @@
+package casks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCasksCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_casks.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeCasksCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 4)
+
+	cask := (*result)[0]
+	assert.Equal(t, "1password", cask.Name)
+	assert.Equal(t, []string{"TC6R2DHVHG"}, cask.Devices)
+	assert.True(t, cask.Outdated)
+	assert.Nil(t, cask.Deprecated)
+	require.NotNil(t, cask.HomebrewCaskVersion)
+	assert.Equal(t, "8.10.75", *cask.HomebrewCaskVersion)
+}
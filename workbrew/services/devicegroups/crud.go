@@ -2,7 +2,9 @@ package devicegroups
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -16,6 +18,9 @@ type (
 		// Returns device groups with their IDs, names, and assigned device serial numbers.
 		ListDeviceGroups(ctx context.Context) (*DeviceGroupsResponse, *interfaces.Response, error)
 
+		// ListDeviceGroupsSeq returns an iterator over device groups
+		ListDeviceGroupsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[DeviceGroup, error]
+
 		// ListDeviceGroupsCSV returns a list of Device Groups in CSV format
 		//
 		// Returns device group data as CSV with columns: id, name, devices.
@@ -42,6 +47,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // ListDeviceGroups retrieves all device groups in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/device_groups.json
 func (s *Service) ListDeviceGroups(ctx context.Context) (*DeviceGroupsResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "devicegroups.ListDeviceGroups")
+
 	endpoint := EndpointDeviceGroupsJSON
 
 	headers := map[string]string{
@@ -60,9 +67,31 @@ func (s *Service) ListDeviceGroups(ctx context.Context) (*DeviceGroupsResponse,
 	return &result, resp, nil
 }
 
+// ListDeviceGroupsSeq iterates over device groups in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/device_groups.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListDeviceGroupsSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[DeviceGroup, error] {
+	ctx = interfaces.WithOperation(ctx, "devicegroups.ListDeviceGroupsSeq")
+
+	endpoint := EndpointDeviceGroupsJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[DeviceGroup](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListDeviceGroupsCSV retrieves all device groups in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/device_groups.csv
 func (s *Service) ListDeviceGroupsCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "devicegroups.ListDeviceGroupsCSV")
+
 	endpoint := EndpointDeviceGroupsCSV
 
 	headers := map[string]string{
@@ -0,0 +1,25 @@
+package devicegroups
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeDeviceGroupsCSV decodes the output of ListDeviceGroupsCSV into typed device groups
+// The result matches the shape returned by the JSON endpoint.
+func DecodeDeviceGroupsCSV(data []byte) (*DeviceGroupsResponse, error) {
+	rows, err := csvdecode.Unmarshal[DeviceGroup](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := DeviceGroupsResponse(rows)
+	return &result, nil
+}
+
+// NewDeviceGroupsCSVReader returns a streaming reader over a device groups CSV export
+// Use this instead of DecodeDeviceGroupsCSV for large exports.
+func NewDeviceGroupsCSVReader(r io.Reader) (*csvdecode.Reader[DeviceGroup], error) {
+	return csvdecode.NewReader[DeviceGroup](r)
+}
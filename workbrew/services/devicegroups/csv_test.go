@@ -0,0 +1,23 @@
+package devicegroups
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeDeviceGroupsCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_device_groups.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeDeviceGroupsCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+
+	assert.Equal(t, "Admin", (*result)[0].Name)
+	assert.Empty(t, (*result)[0].Devices)
+	assert.Equal(t, []string{"TC6R2DHVHG"}, (*result)[1].Devices)
+}
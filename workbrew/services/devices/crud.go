@@ -2,7 +2,9 @@ package devices
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -17,6 +19,9 @@ type (
 		// OS versions, Homebrew/Workbrew versions, and installed package counts.
 		ListDevices(ctx context.Context) (*DevicesResponse, *interfaces.Response, error)
 
+		// ListDevicesSeq returns an iterator over devices
+		ListDevicesSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Device, error]
+
 		// ListDevicesCSV returns a list of devices in CSV format
 		//
 		// Returns device data as CSV with columns: serial_number, groups, mdm_user_or_device_name, last_seen_at,
@@ -44,6 +49,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // ListDevices retrieves all devices in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/devices.json
 func (s *Service) ListDevices(ctx context.Context) (*DevicesResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "devices.ListDevices")
+
 	endpoint := EndpointDevicesJSON
 
 	headers := map[string]string{
@@ -62,9 +69,31 @@ func (s *Service) ListDevices(ctx context.Context) (*DevicesResponse, *interface
 	return &result, resp, nil
 }
 
+// ListDevicesSeq iterates over devices in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/devices.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListDevicesSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Device, error] {
+	ctx = interfaces.WithOperation(ctx, "devices.ListDevicesSeq")
+
+	endpoint := EndpointDevicesJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[Device](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListDevicesCSV retrieves all devices in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/devices.csv
 func (s *Service) ListDevicesCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "devices.ListDevicesCSV")
+
 	endpoint := EndpointDevicesCSV
 
 	headers := map[string]string{
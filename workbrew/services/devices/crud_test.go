@@ -129,3 +129,39 @@ func TestListDevicesCSV_Forbidden(t *testing.T) {
 
 	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
+
+func TestListDevicesSeq_Success(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.DevicesMock{}
+	mockHandler.RegisterMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	ctx := context.Background()
+	expected, _, err := service.ListDevices(ctx)
+	require.NoError(t, err)
+
+	var items []Device
+	for item, err := range service.ListDevicesSeq(ctx, nil) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	// The mock ignores page and limit and returns the full list, so one page is fetched
+	assert.ElementsMatch(t, *expected, items)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestListDevicesSeq_Unauthorized(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.DevicesMock{}
+	mockHandler.RegisterErrorMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	var errs []error
+	for _, err := range service.ListDevicesSeq(context.Background(), &client.PageOptions{PageSize: 10}) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "401")
+}
@@ -0,0 +1,25 @@
+package devices
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeDevicesCSV decodes the output of ListDevicesCSV into typed devices
+// The result matches the shape returned by the JSON endpoint.
+func DecodeDevicesCSV(data []byte) (*DevicesResponse, error) {
+	rows, err := csvdecode.Unmarshal[Device](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := DevicesResponse(rows)
+	return &result, nil
+}
+
+// NewDevicesCSVReader returns a streaming reader over a devices CSV export
+// Use this instead of DecodeDevicesCSV for large exports.
+func NewDevicesCSVReader(r io.Reader) (*csvdecode.Reader[Device], error) {
+	return csvdecode.NewReader[Device](r)
+}
@@ -0,0 +1,63 @@
+package devices
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeDevicesCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_devices_csv.txt"))
+	require.NoError(t, err)
+
+	result, err := DecodeDevicesCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 1)
+
+	device := (*result)[0]
+	assert.Equal(t, "TC6R2DHVHG", device.SerialNumber)
+	assert.Equal(t, []string{"OSX 14"}, device.Groups)
+	require.NotNil(t, device.MDMUserOrDeviceName)
+	assert.Equal(t, "Mike's MacBook Pro", *device.MDMUserOrDeviceName)
+	require.NotNil(t, device.LastSeenAt.Time)
+	assert.Equal(t, "2023-08-25T00:00:00Z", device.LastSeenAt.String())
+	assert.False(t, device.LastSeenAt.Never)
+	assert.Equal(t, 9, device.FormulaeCount)
+	assert.Equal(t, 3, device.CasksCount)
+}
+
+func TestNewDevicesCSVReader(t *testing.T) {
+	data := []byte("serial_number,last_seen_at,command_last_run_at,formulae_count\n" +
+		"AAA,Never,2024-01-01 00:00:00 UTC,1\n" +
+		"BBB,2024-02-01T10:00:00Z,Never,2\n")
+
+	reader, err := NewDevicesCSVReader(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	var serials []string
+	for device, err := range reader.All() {
+		require.NoError(t, err)
+		serials = append(serials, device.SerialNumber)
+		if device.SerialNumber == "AAA" {
+			assert.True(t, device.LastSeenAt.Never)
+			assert.NotNil(t, device.CommandLastRunAt.Time)
+		}
+	}
+	assert.Equal(t, []string{"AAA", "BBB"}, serials)
+}
+
+func TestTimeOrStatus_UnmarshalCSV(t *testing.T) {
+	var status TimeOrStatus
+	require.NoError(t, status.UnmarshalCSV("Not Finished"))
+	assert.True(t, status.IsNotFinished())
+
+	require.NoError(t, status.UnmarshalCSV("2023-11-01 21:43:12 UTC"))
+	assert.True(t, status.HasTime())
+	assert.Empty(t, status.Status)
+
+	assert.Error(t, status.UnmarshalCSV("yesterday"))
+}
@@ -1,7 +1,10 @@
 package devices
 
 import (
+	"strings"
 	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
 )
 
 // Device represents a device in the workspace
@@ -62,6 +65,26 @@ func (t TimeOrNever) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + t.Time.Format(time.RFC3339) + `"`), nil
 }
 
+// UnmarshalCSV implements csvdecode.Unmarshaler for TimeOrNever
+// CSV exports use "2006-01-02 15:04:05 UTC" rather than RFC3339
+func (t *TimeOrNever) UnmarshalCSV(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "Never" {
+		t.Never = true
+		t.Time = nil
+		return nil
+	}
+
+	parsedTime, err := csvdecode.ParseTime(value)
+	if err != nil {
+		return err
+	}
+
+	t.Time = &parsedTime
+	t.Never = false
+	return nil
+}
+
 // String returns a string representation of TimeOrNever
 func (t TimeOrNever) String() string {
 	if t.Never || t.Time == nil {
@@ -113,6 +136,31 @@ func (t TimeOrStatus) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + t.Time.Format(time.RFC3339) + `"`), nil
 }
 
+// UnmarshalCSV implements csvdecode.Unmarshaler for TimeOrStatus
+// CSV exports use "2006-01-02 15:04:05 UTC" rather than RFC3339
+func (t *TimeOrStatus) UnmarshalCSV(value string) error {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "":
+		t.Status = "Never"
+		t.Time = nil
+		return nil
+	case "Never", "Not Started", "Not Finished":
+		t.Status = value
+		t.Time = nil
+		return nil
+	}
+
+	parsedTime, err := csvdecode.ParseTime(value)
+	if err != nil {
+		return err
+	}
+
+	t.Time = &parsedTime
+	t.Status = ""
+	return nil
+}
+
 // String returns a string representation of TimeOrStatus
 func (t TimeOrStatus) String() string {
 	if t.Status != "" {
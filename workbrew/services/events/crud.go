@@ -2,7 +2,9 @@ package events
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -17,6 +19,9 @@ type (
 		// Supports filtering by actor type (user, system, or all) via query options.
 		ListEvents(ctx context.Context, opts *RequestQueryOptions) (*EventsResponse, *interfaces.Response, error)
 
+		// ListEventsSeq returns an iterator over audit log events
+		ListEventsSeq(ctx context.Context, opts *RequestQueryOptions, pageOpts *client.PageOptions) iter.Seq2[Event, error]
+
 		// ListEventsCSV returns audit log events as CSV
 		//
 		// Returns audit log event data as CSV with columns: id, event_type, occurred_at, actor_id, actor_type, target_id, target_type, target_identifier.
@@ -47,6 +52,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // Parameters:
 //   - opts: Optional query parameters (filter by actor type: user, system, all)
 func (s *Service) ListEvents(ctx context.Context, opts *RequestQueryOptions) (*EventsResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "events.ListEvents")
+
 	endpoint := EndpointEventsJSON
 
 	headers := map[string]string{
@@ -71,12 +78,41 @@ func (s *Service) ListEvents(ctx context.Context, opts *RequestQueryOptions) (*E
 	return &result, resp, nil
 }
 
+// ListEventsSeq iterates over audit log events in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/events.json
+//
+// Parameters:
+//   - opts: Optional query parameters, as for ListEvents
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListEventsSeq(ctx context.Context, opts *RequestQueryOptions, pageOpts *client.PageOptions) iter.Seq2[Event, error] {
+	ctx = interfaces.WithOperation(ctx, "events.ListEventsSeq")
+
+	endpoint := EndpointEventsJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	if opts == nil {
+		opts = &RequestQueryOptions{}
+	}
+
+	queryParams := s.client.QueryBuilder().
+		AddIfNotEmpty("filter", opts.Filter).
+		Build()
+
+	return client.Paginate[Event](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListEventsCSV retrieves all events in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/events.csv
 //
 // Parameters:
 //   - opts: Optional query parameters (filter by actor type, download flag)
 func (s *Service) ListEventsCSV(ctx context.Context, opts *RequestQueryOptions) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "events.ListEventsCSV")
+
 	endpoint := EndpointEventsCSV
 
 	headers := map[string]string{
@@ -238,3 +238,24 @@ func TestRequestQueryOptions_NilSafety(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, csv)
 }
+
+func TestListEventsSeq_Success(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.EventsMock{}
+	mockHandler.RegisterMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	ctx := context.Background()
+	expected, _, err := service.ListEvents(ctx, nil)
+	require.NoError(t, err)
+
+	var items []Event
+	for item, err := range service.ListEventsSeq(ctx, nil, nil) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	// The mock ignores page and limit and returns the full list, so one page is fetched
+	assert.ElementsMatch(t, *expected, items)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
@@ -0,0 +1,25 @@
+package events
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeEventsCSV decodes the output of ListEventsCSV into typed events
+// The result matches the shape returned by the JSON endpoint.
+func DecodeEventsCSV(data []byte) (*EventsResponse, error) {
+	rows, err := csvdecode.Unmarshal[Event](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := EventsResponse(rows)
+	return &result, nil
+}
+
+// NewEventsCSVReader returns a streaming reader over a events CSV export
+// Use this instead of DecodeEventsCSV for large exports.
+func NewEventsCSVReader(r io.Reader) (*csvdecode.Reader[Event], error) {
+	return csvdecode.NewReader[Event](r)
+}
@@ -0,0 +1,26 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeEventsCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_events.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeEventsCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 1)
+
+	event := (*result)[0]
+	assert.Equal(t, "device.created", event.EventType)
+	assert.Nil(t, event.ActorID)
+	require.NotNil(t, event.TargetIdentifier)
+	assert.Equal(t, "TC6R2DHVHG", *event.TargetIdentifier)
+	assert.Equal(t, 2024, event.OccurredAt.Year())
+}
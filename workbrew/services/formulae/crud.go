@@ -2,7 +2,9 @@ package formulae
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -17,6 +19,9 @@ type (
 		// known vulnerabilities, deprecation status, licenses, and Homebrew core versions.
 		ListFormulae(ctx context.Context) (*FormulaeResponse, *interfaces.Response, error)
 
+		// ListFormulaeSeq returns an iterator over formulae
+		ListFormulaeSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Formula, error]
+
 		// ListFormulaeCSV returns a list of Formulae in CSV format
 		//
 		// Returns formulae data as CSV with columns: name, devices, outdated, installed_on_request, installed_as_dependency,
@@ -44,6 +49,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // ListFormulae retrieves all formulae in JSON format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/formulae.json
 func (s *Service) ListFormulae(ctx context.Context) (*FormulaeResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "formulae.ListFormulae")
+
 	endpoint := EndpointFormulaeJSON
 
 	headers := map[string]string{
@@ -62,9 +69,31 @@ func (s *Service) ListFormulae(ctx context.Context) (*FormulaeResponse, *interfa
 	return &result, resp, nil
 }
 
+// ListFormulaeSeq iterates over formulae in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/formulae.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListFormulaeSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Formula, error] {
+	ctx = interfaces.WithOperation(ctx, "formulae.ListFormulaeSeq")
+
+	endpoint := EndpointFormulaeJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[Formula](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListFormulaeCSV retrieves all formulae in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/formulae.csv
 func (s *Service) ListFormulaeCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "formulae.ListFormulaeCSV")
+
 	endpoint := EndpointFormulaeCSV
 
 	headers := map[string]string{
@@ -0,0 +1,25 @@
+package formulae
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeFormulaeCSV decodes the output of ListFormulaeCSV into typed formulae
+// The result matches the shape returned by the JSON endpoint.
+func DecodeFormulaeCSV(data []byte) (*FormulaeResponse, error) {
+	rows, err := csvdecode.Unmarshal[Formula](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := FormulaeResponse(rows)
+	return &result, nil
+}
+
+// NewFormulaeCSVReader returns a streaming reader over a formulae CSV export
+// Use this instead of DecodeFormulaeCSV for large exports.
+func NewFormulaeCSVReader(r io.Reader) (*csvdecode.Reader[Formula], error) {
+	return csvdecode.NewReader[Formula](r)
+}
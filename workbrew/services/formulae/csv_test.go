@@ -0,0 +1,34 @@
+package formulae
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeFormulaeCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_formulae.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeFormulaeCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 4)
+
+	curl := (*result)[0]
+	assert.Equal(t, "curl", curl.Name)
+	assert.Equal(t, []string{"TC6R2DHVHG", "1234567890"}, curl.Devices)
+	assert.True(t, curl.Outdated)
+	assert.True(t, curl.InstalledAsDependency)
+	assert.Len(t, curl.Vulnerabilities, 6)
+	assert.Nil(t, curl.Deprecated)
+	require.NotNil(t, curl.License)
+	assert.Equal(t, []string{"curl"}, *curl.License)
+	require.NotNil(t, curl.HomebrewCoreVersion)
+	assert.Equal(t, "8.11.1", *curl.HomebrewCoreVersion)
+
+	actionlint := (*result)[1]
+	assert.Empty(t, actionlint.Vulnerabilities)
+}
@@ -2,7 +2,9 @@ package licenses
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -16,6 +18,9 @@ type (
 		// Returns software licenses found across installed formulae, with license names and counts of affected devices and formulae.
 		ListLicenses(ctx context.Context) (*LicensesResponse, *interfaces.Response, error)
 
+		// ListLicensesSeq returns an iterator over licenses
+		ListLicensesSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[License, error]
+
 		// ListLicensesCSV returns a list of Licenses in CSV format
 		//
 		// Returns license data as CSV with columns: name, device_count, formula_count.
@@ -50,6 +55,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 //	  -H "Accept: application/json" \
 //	  "https://console.workbrew.com/workspaces/{workspace}/licenses.json"
 func (s *Service) ListLicenses(ctx context.Context) (*LicensesResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "licenses.ListLicenses")
+
 	endpoint := EndpointLicensesJSON
 
 	headers := map[string]string{
@@ -68,6 +75,26 @@ func (s *Service) ListLicenses(ctx context.Context) (*LicensesResponse, *interfa
 	return &result, resp, nil
 }
 
+// ListLicensesSeq iterates over licenses in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/licenses.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListLicensesSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[License, error] {
+	ctx = interfaces.WithOperation(ctx, "licenses.ListLicensesSeq")
+
+	endpoint := EndpointLicensesJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[License](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListLicensesCSV retrieves all licenses in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/licenses.csv
 //
@@ -79,6 +106,8 @@ func (s *Service) ListLicenses(ctx context.Context) (*LicensesResponse, *interfa
 //	  -H "Accept: text/csv" \
 //	  "https://console.workbrew.com/workspaces/{workspace}/licenses.csv"
 func (s *Service) ListLicensesCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "licenses.ListLicensesCSV")
+
 	endpoint := EndpointLicensesCSV
 
 	headers := map[string]string{
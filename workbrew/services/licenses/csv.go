@@ -0,0 +1,25 @@
+package licenses
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeLicensesCSV decodes the output of ListLicensesCSV into typed licenses
+// The result matches the shape returned by the JSON endpoint.
+func DecodeLicensesCSV(data []byte) (*LicensesResponse, error) {
+	rows, err := csvdecode.Unmarshal[License](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := LicensesResponse(rows)
+	return &result, nil
+}
+
+// NewLicensesCSVReader returns a streaming reader over a licenses CSV export
+// Use this instead of DecodeLicensesCSV for large exports.
+func NewLicensesCSVReader(r io.Reader) (*csvdecode.Reader[License], error) {
+	return csvdecode.NewReader[License](r)
+}
@@ -0,0 +1,23 @@
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeLicensesCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_licenses.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeLicensesCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 7)
+
+	assert.Equal(t, "GPL-3.0-or-later", (*result)[0].Name)
+	assert.Equal(t, 2, (*result)[0].DeviceCount)
+	assert.Equal(t, 0, (*result)[6].FormulaCount)
+}
@@ -2,7 +2,9 @@ package vulnerabilities
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -18,6 +20,9 @@ type (
 		// May return 403 Forbidden on Free tier plans.
 		ListVulnerabilities(ctx context.Context) (*VulnerabilitiesResponse, *interfaces.Response, error)
 
+		// ListVulnerabilitiesSeq returns an iterator over vulnerable formulae
+		ListVulnerabilitiesSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Vulnerability, error]
+
 		// ListVulnerabilitiesCSV returns a list of Vulnerabilities in CSV format
 		//
 		// Returns vulnerability data as CSV with columns: vulnerabilities, formula, outdated_devices, supported, homebrew_core_version.
@@ -46,6 +51,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 //
 // Note: This endpoint may return 403 on Free tier plans
 func (s *Service) ListVulnerabilities(ctx context.Context) (*VulnerabilitiesResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "vulnerabilities.ListVulnerabilities")
+
 	endpoint := EndpointVulnerabilitiesJSON
 
 	headers := map[string]string{
@@ -64,11 +71,33 @@ func (s *Service) ListVulnerabilities(ctx context.Context) (*VulnerabilitiesResp
 	return &result, resp, nil
 }
 
+// ListVulnerabilitiesSeq iterates over vulnerable formulae in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/vulnerabilities.json
+//
+// Parameters:
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListVulnerabilitiesSeq(ctx context.Context, pageOpts *client.PageOptions) iter.Seq2[Vulnerability, error] {
+	ctx = interfaces.WithOperation(ctx, "vulnerabilities.ListVulnerabilitiesSeq")
+
+	endpoint := EndpointVulnerabilitiesJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	queryParams := make(map[string]string)
+
+	return client.Paginate[Vulnerability](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListVulnerabilitiesCSV retrieves all vulnerabilities in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/vulnerabilities.csv
 //
 // Note: This endpoint may return 403 on Free tier plans
 func (s *Service) ListVulnerabilitiesCSV(ctx context.Context) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "vulnerabilities.ListVulnerabilitiesCSV")
+
 	endpoint := EndpointVulnerabilitiesCSV
 
 	headers := map[string]string{
@@ -0,0 +1,49 @@
+package vulnerabilities
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeVulnerabilitiesCSV decodes the output of ListVulnerabilitiesCSV into typed vulnerabilities
+// The result matches the shape returned by the JSON endpoint.
+func DecodeVulnerabilitiesCSV(data []byte) (*VulnerabilitiesResponse, error) {
+	rows, err := csvdecode.Unmarshal[Vulnerability](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := VulnerabilitiesResponse(rows)
+	return &result, nil
+}
+
+// NewVulnerabilitiesCSVReader returns a streaming reader over a vulnerabilities CSV export
+// Use this instead of DecodeVulnerabilitiesCSV for large exports.
+func NewVulnerabilitiesCSVReader(r io.Reader) (*csvdecode.Reader[Vulnerability], error) {
+	return csvdecode.NewReader[Vulnerability](r)
+}
+
+// UnmarshalCSV implements csvdecode.Unmarshaler for VulnerabilityDetail
+// CSV exports render each entry as "<id>" or "<id> (<cvss score>)"
+func (v *VulnerabilityDetail) UnmarshalCSV(value string) error {
+	value = strings.TrimSpace(value)
+	v.CleanID = value
+	v.CVSSScore = nil
+
+	open := strings.LastIndex(value, " (")
+	if open == -1 || !strings.HasSuffix(value, ")") {
+		return nil
+	}
+
+	score, err := strconv.ParseFloat(value[open+2:len(value)-1], 64)
+	if err != nil {
+		return nil
+	}
+
+	v.CleanID = value[:open]
+	v.CVSSScore = &score
+	return nil
+}
@@ -0,0 +1,36 @@
+package vulnerabilities
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeVulnerabilitiesCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_vulnerabilities.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeVulnerabilitiesCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 4)
+
+	curl := (*result)[0]
+	assert.Equal(t, "curl", curl.Formula)
+	assert.Equal(t, []string{"TC6R2DHVHG", "1234567890"}, curl.OutdatedDevices)
+	require.Len(t, curl.Vulnerabilities, 6)
+	assert.Equal(t, "CVE-2024-11053", curl.Vulnerabilities[0].CleanID)
+	assert.Nil(t, curl.Vulnerabilities[0].CVSSScore)
+
+	last := curl.Vulnerabilities[5]
+	assert.Equal(t, "THIS-IS-AN-INVALID-CVE-001", last.CleanID)
+	require.NotNil(t, last.CVSSScore)
+	assert.Equal(t, 8.0, *last.CVSSScore)
+
+	renovate := (*result)[3]
+	require.Len(t, renovate.Vulnerabilities, 1)
+	assert.Equal(t, "GHSA-rqgv-292v-5qgr", renovate.Vulnerabilities[0].CleanID)
+	assert.Equal(t, 5.4, *renovate.Vulnerabilities[0].CVSSScore)
+}
@@ -0,0 +1,86 @@
+package vulnerabilities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is a CVSS qualitative severity rating, ordered from SeverityNone to SeverityCritical
+type Severity int
+
+// CVSS severity ratings
+const (
+	SeverityNone Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// severityNames maps severities to the names used by the API
+var severityNames = map[Severity]string{
+	SeverityNone:     "None",
+	SeverityLow:      "Low",
+	SeverityMedium:   "Medium",
+	SeverityHigh:     "High",
+	SeverityCritical: "Critical",
+}
+
+// String returns the severity name used by the API, e.g. "Critical"
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MarshalText encodes the severity as its lower-case name, e.g. "critical"
+func (s Severity) MarshalText() ([]byte, error) {
+	name, ok := severityNames[s]
+	if !ok {
+		return nil, fmt.Errorf("invalid CVSS severity %d", int(s))
+	}
+	return []byte(strings.ToLower(name)), nil
+}
+
+// UnmarshalText decodes a severity name, ignoring case
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// ParseSeverity parses a severity name, ignoring case
+//
+// Parameters:
+//   - name: "none", "low", "medium", "high" or "critical"
+//
+// Returns:
+//   - Severity: The parsed severity
+//   - error: An error if the name is not a CVSS severity
+func ParseSeverity(name string) (Severity, error) {
+	for severity, severityName := range severityNames {
+		if strings.EqualFold(strings.TrimSpace(name), severityName) {
+			return severity, nil
+		}
+	}
+	return SeverityNone, fmt.Errorf("unknown CVSS severity %q (expected none, low, medium, high or critical)", name)
+}
+
+// SeverityForScore returns the CVSS v3 severity rating for a base score
+func SeverityForScore(score float64) Severity {
+	switch {
+	case score >= 9.0:
+		return SeverityCritical
+	case score >= 7.0:
+		return SeverityHigh
+	case score >= 4.0:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityNone
+}
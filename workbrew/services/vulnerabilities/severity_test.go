@@ -0,0 +1,51 @@
+package vulnerabilities
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSeverity(t *testing.T) {
+	severity, err := ParseSeverity(" CRITICAL ")
+	require.NoError(t, err)
+	assert.Equal(t, SeverityCritical, severity)
+	assert.Equal(t, "Critical", severity.String())
+
+	_, err = ParseSeverity("urgent")
+	assert.ErrorContains(t, err, `unknown CVSS severity "urgent"`)
+}
+
+func TestSeverity_JSON(t *testing.T) {
+	data, err := json.Marshal(map[string]Severity{"severity": SeverityCritical})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"severity":"critical"}`, string(data))
+
+	var decoded struct{ Severity Severity }
+	require.NoError(t, json.Unmarshal([]byte(`{"Severity":"High"}`), &decoded))
+	assert.Equal(t, SeverityHigh, decoded.Severity)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"Severity":"urgent"}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"Severity":4}`), &decoded))
+	_, err = json.Marshal(Severity(9))
+	assert.ErrorContains(t, err, "invalid CVSS severity 9")
+}
+
+func TestSeverityForScore(t *testing.T) {
+	tests := map[float64]Severity{
+		0:    SeverityNone,
+		0.1:  SeverityLow,
+		3.9:  SeverityLow,
+		4.0:  SeverityMedium,
+		6.9:  SeverityMedium,
+		7.0:  SeverityHigh,
+		8.9:  SeverityHigh,
+		9.0:  SeverityCritical,
+		10.0: SeverityCritical,
+	}
+	for score, want := range tests {
+		assert.Equal(t, want, SeverityForScore(score), "score %v", score)
+	}
+}
@@ -2,7 +2,9 @@ package vulnerabilitychanges
 
 import (
 	"context"
+	"iter"
 
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
 	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
 )
 
@@ -18,6 +20,9 @@ type (
 		// Supports filtering by status (detected/fixed) and search queries via query options.
 		ListVulnerabilityChanges(ctx context.Context, opts *RequestQueryOptions) (*VulnerabilityChangesResponse, *interfaces.Response, error)
 
+		// ListVulnerabilityChangesSeq returns an iterator over vulnerability changes
+		ListVulnerabilityChangesSeq(ctx context.Context, opts *RequestQueryOptions, pageOpts *client.PageOptions) iter.Seq2[VulnerabilityChange, error]
+
 		// ListVulnerabilityChangesCSV returns vulnerability change events as CSV
 		//
 		// Returns vulnerability change event data as CSV with columns: id, event_type, occurred_at, status, device_id,
@@ -49,6 +54,8 @@ func NewService(client interfaces.HTTPClient) *Service {
 // Parameters:
 //   - opts: Optional query parameters (status filter, search query)
 func (s *Service) ListVulnerabilityChanges(ctx context.Context, opts *RequestQueryOptions) (*VulnerabilityChangesResponse, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "vulnerabilitychanges.ListVulnerabilityChanges")
+
 	endpoint := EndpointVulnerabilityChangesJSON
 
 	headers := map[string]string{
@@ -74,12 +81,42 @@ func (s *Service) ListVulnerabilityChanges(ctx context.Context, opts *RequestQue
 	return &result, resp, nil
 }
 
+// ListVulnerabilityChangesSeq iterates over vulnerability changes in JSON format
+// URL: GET https://console.workbrew.com/workspaces/{workspace_name}/vulnerability_changes.json
+//
+// Parameters:
+//   - opts: Optional query parameters, as for ListVulnerabilityChanges
+//   - pageOpts: Optional pagination options; nil makes one request for the whole list
+func (s *Service) ListVulnerabilityChangesSeq(ctx context.Context, opts *RequestQueryOptions, pageOpts *client.PageOptions) iter.Seq2[VulnerabilityChange, error] {
+	ctx = interfaces.WithOperation(ctx, "vulnerabilitychanges.ListVulnerabilityChangesSeq")
+
+	endpoint := EndpointVulnerabilityChangesJSON
+
+	headers := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	if opts == nil {
+		opts = &RequestQueryOptions{}
+	}
+
+	queryParams := s.client.QueryBuilder().
+		AddIfNotEmpty("status", opts.Status).
+		AddIfNotEmpty("query", opts.Query).
+		Build()
+
+	return client.Paginate[VulnerabilityChange](ctx, s.client, endpoint, queryParams, headers, pageOpts)
+}
+
 // ListVulnerabilityChangesCSV retrieves all vulnerability changes in CSV format
 // URL: GET https://console.workbrew.com/workspaces/{workspace_name}/vulnerability_changes.csv
 //
 // Parameters:
 //   - opts: Optional query parameters (status filter, search query, download flag)
 func (s *Service) ListVulnerabilityChangesCSV(ctx context.Context, opts *RequestQueryOptions) ([]byte, *interfaces.Response, error) {
+	ctx = interfaces.WithOperation(ctx, "vulnerabilitychanges.ListVulnerabilityChangesCSV")
+
 	endpoint := EndpointVulnerabilityChangesCSV
 
 	headers := map[string]string{
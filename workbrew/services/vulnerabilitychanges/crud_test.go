@@ -313,3 +313,24 @@ func TestRequestQueryOptions_StatusFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestListVulnerabilityChangesSeq_Success(t *testing.T) {
+	service, baseURL := setupMockClient(t)
+	mockHandler := &mocks.VulnerabilityChangesMock{}
+	mockHandler.RegisterMocks(baseURL)
+	defer mockHandler.CleanupMockState()
+
+	ctx := context.Background()
+	expected, _, err := service.ListVulnerabilityChanges(ctx, nil)
+	require.NoError(t, err)
+
+	var items []VulnerabilityChange
+	for item, err := range service.ListVulnerabilityChangesSeq(ctx, nil, nil) {
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	// The mock ignores page and limit and returns the full list, so one page is fetched
+	assert.ElementsMatch(t, *expected, items)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
@@ -0,0 +1,25 @@
+package vulnerabilitychanges
+
+import (
+	"io"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/csvdecode"
+)
+
+// DecodeVulnerabilityChangesCSV decodes the output of ListVulnerabilityChangesCSV into typed vulnerability changes
+// The result matches the shape returned by the JSON endpoint.
+func DecodeVulnerabilityChangesCSV(data []byte) (*VulnerabilityChangesResponse, error) {
+	rows, err := csvdecode.Unmarshal[VulnerabilityChange](data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := VulnerabilityChangesResponse(rows)
+	return &result, nil
+}
+
+// NewVulnerabilityChangesCSVReader returns a streaming reader over a vulnerability changes CSV export
+// Use this instead of DecodeVulnerabilityChangesCSV for large exports.
+func NewVulnerabilityChangesCSVReader(r io.Reader) (*csvdecode.Reader[VulnerabilityChange], error) {
+	return csvdecode.NewReader[VulnerabilityChange](r)
+}
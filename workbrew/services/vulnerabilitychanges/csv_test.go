@@ -0,0 +1,28 @@
+package vulnerabilitychanges
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeVulnerabilityChangesCSV(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("mocks", "validate_get_vulnerability_changes.csv"))
+	require.NoError(t, err)
+
+	result, err := DecodeVulnerabilityChangesCSV(data)
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+
+	change := (*result)[0]
+	assert.Equal(t, "detected", change.Status)
+	assert.Equal(t, "curl", change.FormulaName)
+	require.NotNil(t, change.DeviceSerialNumber)
+	assert.Equal(t, "TC6R2DHVHG", *change.DeviceSerialNumber)
+	require.NotNil(t, change.CVSSScore)
+	assert.Equal(t, 6.5, *change.CVSSScore)
+	assert.Equal(t, 2024, change.OccurredAt.Year())
+}
@@ -0,0 +1,170 @@
+package siem
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+)
+
+// Header values used by the formatters
+const (
+	DefaultVendor  = "Workbrew"
+	DefaultProduct = "Workbrew Console"
+)
+
+// customKey matches CEF custom extension keys, which are written with a label
+// naming the source field, e.g. cs1=wget cs1Label=formula_name
+var customKey = regexp.MustCompile(`^(cs|cn|cfp|flexString|flexNumber|deviceCustomDate)\d$`)
+
+// DefaultCEFMapping returns the default CEF extension keys. Fields without a
+// standard CEF key use custom string (csN) and float (cfpN) keys.
+func DefaultCEFMapping() Mapping {
+	return Mapping{
+		FieldID:         "externalId",
+		FieldEventType:  "cat",
+		FieldOccurredAt: "rt",
+
+		FieldActorID:          "suid",
+		FieldActorType:        "cs1",
+		FieldTargetType:       "cs2",
+		FieldTargetID:         "cs3",
+		FieldTargetIdentifier: "cs4",
+		FieldChanges:          "cs5",
+
+		FieldStatus:             "act",
+		FieldDeviceSerialNumber: "dhost",
+		FieldFormulaName:        "cs1",
+		FieldFormulaVersion:     "cs2",
+		FieldVulnerabilityID:    "cs3",
+		FieldCVSSSeverity:       "cs4",
+		FieldDeviceID:           "cs6",
+		FieldCVSSScore:          "cfp1",
+	}
+}
+
+// CEFFormatter writes ArcSight Common Event Format (CEF) version 0 records:
+//
+//	CEF:0|Workbrew|Workbrew Console|1.0.0|vulnerability.detected|CVE-2024-10524 detected in wget 1.24.5|9|act=detected ...
+//
+// Times are written as milliseconds since the Unix epoch and nested values as JSON.
+type CEFFormatter struct {
+	// Vendor, Product and Version fill the device fields of the header
+	Vendor  string
+	Product string
+	Version string
+
+	// Mapping maps source fields to extension keys
+	Mapping Mapping
+}
+
+// NewCEFFormatter creates a CEF formatter with the default header and mapping
+func NewCEFFormatter() *CEFFormatter {
+	return &CEFFormatter{
+		Vendor:  DefaultVendor,
+		Product: DefaultProduct,
+		Version: client.Version,
+		Mapping: DefaultCEFMapping(),
+	}
+}
+
+// Format implements Formatter
+func (f *CEFFormatter) Format(record *Record) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CEF:0|%s|%s|%s|%s|%s|%d|",
+		escapeCEFHeader(f.Vendor),
+		escapeCEFHeader(f.Product),
+		escapeCEFHeader(f.Version),
+		escapeCEFHeader(signatureID(record)),
+		escapeCEFHeader(record.Name),
+		record.Severity)
+
+	extensions, err := mapFields(record, f.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	for i, key := range slices.Sorted(maps.Keys(extensions)) {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		field := extensions[key]
+		b.WriteString(key + "=" + escapeCEFValue(field.value))
+		if customKey.MatchString(key) {
+			b.WriteString(" " + key + "Label=" + escapeCEFValue(field.source))
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+// mappedField is a source field value rendered as a string
+type mappedField struct {
+	source string
+	value  string
+}
+
+// mapFields renders the record's fields under their mapped keys
+func mapFields(record *Record, mapping Mapping) (map[string]mappedField, error) {
+	result := make(map[string]mappedField)
+	for source, value := range record.Fields {
+		key, ok := mapping[source]
+		if !ok || key == "" {
+			continue
+		}
+		if existing, taken := result[key]; taken {
+			return nil, fmt.Errorf("fields %q and %q are both mapped to %q", existing.source, source, key)
+		}
+		text, err := stringValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format field %q: %w", source, err)
+		}
+		result[key] = mappedField{source: source, value: text}
+	}
+	return result, nil
+}
+
+// stringValue renders a field value for the key=value formats
+func stringValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case time.Time:
+		return strconv.FormatInt(v.UnixMilli(), 10), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// signatureID returns the record's event type, or its kind when the type is missing
+func signatureID(record *Record) string {
+	if eventType, ok := record.Fields[FieldEventType].(string); ok && eventType != "" {
+		return eventType
+	}
+	return record.Kind
+}
+
+var (
+	cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ")
+	cefValueEscaper  = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`)
+)
+
+// escapeCEFHeader escapes backslashes and pipes in a header field
+func escapeCEFHeader(value string) string {
+	return cefHeaderEscaper.Replace(value)
+}
+
+// escapeCEFValue escapes backslashes, equals signs and line breaks in an extension value
+func escapeCEFValue(value string) string {
+	return cefValueEscaper.Replace(value)
+}
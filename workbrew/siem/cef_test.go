@@ -0,0 +1,99 @@
+package siem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var occurredAt = time.Date(2025, 1, 5, 14, 0, 0, 0, time.UTC)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func testEvent() *events.Event {
+	return &events.Event{
+		ID:               "e1",
+		EventType:        "brewfile.updated",
+		OccurredAt:       occurredAt,
+		ActorID:          ptr("u1"),
+		ActorType:        ptr("User"),
+		TargetID:         ptr("b1"),
+		TargetType:       ptr("Brewfile"),
+		TargetIdentifier: ptr("dev|tools"),
+		Changes:          map[string]any{"content": []any{"a=1", "b\\c"}},
+	}
+}
+
+func testChange() *vulnerabilitychanges.VulnerabilityChange {
+	return &vulnerabilitychanges.VulnerabilityChange{
+		ID:                 "v1",
+		EventType:          "vulnerability.detected",
+		OccurredAt:         occurredAt,
+		Status:             vulnerabilitychanges.StatusDetected,
+		DeviceSerialNumber: ptr("TC6R2DHVHG"),
+		FormulaName:        "wget",
+		FormulaVersion:     "1.24.5",
+		VulnerabilityID:    "CVE-2024-10524",
+		CVSSSeverity:       ptr("Critical"),
+		CVSSScore:          ptr(9.1),
+	}
+}
+
+func TestFromVulnerabilityChange_Severity(t *testing.T) {
+	change := testChange()
+	assert.Equal(t, 9, FromVulnerabilityChange(change).Severity)
+
+	change.CVSSScore = nil
+	change.CVSSSeverity = ptr("High")
+	assert.Equal(t, 8, FromVulnerabilityChange(change).Severity)
+
+	change.Status = vulnerabilitychanges.StatusFixed
+	assert.Equal(t, 1, FromVulnerabilityChange(change).Severity)
+}
+
+func TestCEFFormatter_VulnerabilityChange(t *testing.T) {
+	line, err := NewCEFFormatter().Format(FromVulnerabilityChange(testChange()))
+	require.NoError(t, err)
+
+	assert.Equal(t, "CEF:0|Workbrew|Workbrew Console|1.0.0|vulnerability.detected|CVE-2024-10524 detected in wget 1.24.5|9|"+
+		"act=detected cat=vulnerability.detected cfp1=9.1 cfp1Label=cvss_score cs1=wget cs1Label=formula_name "+
+		"cs2=1.24.5 cs2Label=formula_version cs3=CVE-2024-10524 cs3Label=vulnerability_id "+
+		"cs4=Critical cs4Label=cvss_severity dhost=TC6R2DHVHG externalId=v1 rt=1736085600000", string(line))
+}
+
+func TestCEFFormatter_EventEscaping(t *testing.T) {
+	line, err := NewCEFFormatter().Format(FromEvent(testEvent()))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(line), `|brewfile.updated|brewfile.updated dev\|tools|1|`)
+	assert.Contains(t, string(line), `cs4=dev|tools cs4Label=target_identifier`)
+	assert.Contains(t, string(line), `cs5={"content":["a\=1","b\\\\c"]} cs5Label=changes`)
+	assert.Contains(t, string(line), "suid=u1")
+}
+
+func TestCEFFormatter_CustomMapping(t *testing.T) {
+	formatter := NewCEFFormatter()
+	formatter.Mapping[FieldActorID] = "suser"
+	delete(formatter.Mapping, FieldChanges)
+
+	line, err := formatter.Format(FromEvent(testEvent()))
+	require.NoError(t, err)
+	assert.Contains(t, string(line), "suser=u1")
+	assert.NotContains(t, string(line), "suid=")
+	assert.NotContains(t, string(line), "cs5=")
+
+	formatter.Mapping[FieldTargetID] = "suser"
+	_, err = formatter.Format(FromEvent(testEvent()))
+	assert.ErrorContains(t, err, `mapped to "suser"`)
+}
+
+func TestEscapeCEFValue(t *testing.T) {
+	assert.Equal(t, `a\=b\\c\nd`, escapeCEFValue("a=b\\c\nd"))
+	assert.Equal(t, `a\|b\\c`, escapeCEFHeader(`a|b\c`))
+}
@@ -0,0 +1,99 @@
+package siem
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+)
+
+// DefaultLEEFDelimiter separates LEEF attributes
+const DefaultLEEFDelimiter = '^'
+
+// DefaultLEEFMapping returns the default LEEF attribute keys. Fields without a
+// predefined LEEF key use the source field name in camel case.
+func DefaultLEEFMapping() Mapping {
+	return Mapping{
+		FieldID:         "externalId",
+		FieldEventType:  "cat",
+		FieldOccurredAt: "devTime",
+
+		FieldActorID:          "usrName",
+		FieldActorType:        "actorType",
+		FieldTargetType:       "targetType",
+		FieldTargetID:         "targetId",
+		FieldTargetIdentifier: "resource",
+		FieldChanges:          "changes",
+
+		FieldStatus:             "action",
+		FieldDeviceID:           "deviceId",
+		FieldDeviceSerialNumber: "identHostName",
+		FieldFormulaName:        "formulaName",
+		FieldFormulaVersion:     "formulaVersion",
+		FieldVulnerabilityID:    "vulnerabilityId",
+		FieldCVSSSeverity:       "cvssSeverity",
+		FieldCVSSScore:          "cvssScore",
+	}
+}
+
+// LEEFFormatter writes IBM QRadar Log Event Extended Format (LEEF) 2.0 records:
+//
+//	LEEF:2.0|Workbrew|Workbrew Console|1.0.0|vulnerability.detected|^|sev=9^action=detected^...
+//
+// The record severity is always written as sev. Times are written as
+// milliseconds since the Unix epoch and nested values as JSON. LEEF has no
+// escape sequences, so delimiters and line breaks in values, and pipes in the
+// header, are replaced with spaces.
+type LEEFFormatter struct {
+	// Vendor, Product and Version fill the header
+	Vendor  string
+	Product string
+	Version string
+
+	// Delimiter separates attributes. Defaults to DefaultLEEFDelimiter.
+	Delimiter rune
+
+	// Mapping maps source fields to attribute keys
+	Mapping Mapping
+}
+
+// NewLEEFFormatter creates a LEEF formatter with the default header, delimiter and mapping
+func NewLEEFFormatter() *LEEFFormatter {
+	return &LEEFFormatter{
+		Vendor:    DefaultVendor,
+		Product:   DefaultProduct,
+		Version:   client.Version,
+		Delimiter: DefaultLEEFDelimiter,
+		Mapping:   DefaultLEEFMapping(),
+	}
+}
+
+// Format implements Formatter
+func (f *LEEFFormatter) Format(record *Record) ([]byte, error) {
+	delimiter := f.Delimiter
+	if delimiter == 0 {
+		delimiter = DefaultLEEFDelimiter
+	}
+	clean := strings.NewReplacer(string(delimiter), " ", "\r", " ", "\n", " ").Replace
+	cleanHeader := func(value string) string { return strings.ReplaceAll(clean(value), "|", " ") }
+
+	attributes, err := mapFields(record, f.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	if _, taken := attributes["sev"]; taken {
+		return nil, fmt.Errorf("field %q is mapped to the reserved key %q", attributes["sev"].source, "sev")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "LEEF:2.0|%s|%s|%s|%s|%c|sev=%d",
+		cleanHeader(f.Vendor), cleanHeader(f.Product), cleanHeader(f.Version), cleanHeader(signatureID(record)),
+		delimiter, record.Severity)
+	for _, key := range slices.Sorted(maps.Keys(attributes)) {
+		b.WriteRune(delimiter)
+		b.WriteString(key + "=" + clean(attributes[key].value))
+	}
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,40 @@
+package siem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLEEFFormatter_VulnerabilityChange(t *testing.T) {
+	line, err := NewLEEFFormatter().Format(FromVulnerabilityChange(testChange()))
+	require.NoError(t, err)
+
+	assert.Equal(t, "LEEF:2.0|Workbrew|Workbrew Console|1.0.0|vulnerability.detected|^|sev=9"+
+		"^action=detected^cat=vulnerability.detected^cvssScore=9.1^cvssSeverity=Critical^devTime=1736085600000"+
+		"^externalId=v1^formulaName=wget^formulaVersion=1.24.5^identHostName=TC6R2DHVHG^vulnerabilityId=CVE-2024-10524",
+		string(line))
+}
+
+func TestLEEFFormatter_Delimiter(t *testing.T) {
+	event := testEvent()
+	event.TargetIdentifier = ptr("dev\ttools")
+
+	formatter := NewLEEFFormatter()
+	formatter.Delimiter = '\t'
+	line, err := formatter.Format(FromEvent(event))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(line), "|brewfile.updated|\t|sev=1\t")
+	assert.Contains(t, string(line), "\tresource=dev tools\t")
+	assert.NotContains(t, string(line), "\n")
+}
+
+func TestLEEFFormatter_ReservedKey(t *testing.T) {
+	formatter := NewLEEFFormatter()
+	formatter.Mapping[FieldCVSSSeverity] = "sev"
+
+	_, err := formatter.Format(FromVulnerabilityChange(testChange()))
+	assert.ErrorContains(t, err, `reserved key "sev"`)
+}
@@ -0,0 +1,224 @@
+package siem
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+)
+
+// OCSFVersion is the OCSF schema version records are written against
+const OCSFVersion = "1.3.0"
+
+// OCSF classes records are written as
+const (
+	// OCSFClassAPIActivity is used for audit events
+	OCSFClassAPIActivity = 6003
+
+	// OCSFClassVulnerabilityFinding is used for vulnerability changes
+	OCSFClassVulnerabilityFinding = 2002
+)
+
+// maxPathIndex bounds array indexes in OCSF mapping paths
+const maxPathIndex = 64
+
+// DefaultOCSFMapping returns the default OCSF attribute paths. Paths are
+// dot-separated; numeric segments index arrays, e.g. "resources.0.uid".
+// Fields without an OCSF attribute are written under "unmapped".
+func DefaultOCSFMapping() Mapping {
+	return Mapping{
+		FieldID:         "metadata.uid",
+		FieldEventType:  "metadata.event_code",
+		FieldOccurredAt: "time",
+
+		FieldActorID:          "actor.user.uid",
+		FieldActorType:        "actor.user.type",
+		FieldTargetID:         "resources.0.uid",
+		FieldTargetType:       "resources.0.type",
+		FieldTargetIdentifier: "resources.0.name",
+		FieldTargetSnapshot:   "resources.0.data",
+		FieldChanges:          "unmapped.changes",
+
+		FieldStatus:             "status",
+		FieldDeviceID:           "device.uid",
+		FieldDeviceSerialNumber: "device.hw_info.serial_number",
+		FieldFormulaName:        "vulnerabilities.0.affected_packages.0.name",
+		FieldFormulaVersion:     "vulnerabilities.0.affected_packages.0.version",
+		FieldVulnerabilityID:    "vulnerabilities.0.cve.uid",
+		FieldCVSSSeverity:       "vulnerabilities.0.severity",
+		FieldCVSSScore:          "vulnerabilities.0.cve.cvss.0.base_score",
+	}
+}
+
+// OCSFFormatter writes Open Cybersecurity Schema Framework (OCSF) JSON records.
+// Audit events are API Activity (6003) records and vulnerability changes are
+// Vulnerability Finding (2002) records.
+//
+// The formatter sets the class, category, activity, type, severity, status,
+// time, message and product metadata; mapped fields are applied afterwards
+// and may override them. Times are written as milliseconds since the Unix epoch.
+type OCSFFormatter struct {
+	// Vendor, Product and Version fill metadata.product
+	Vendor  string
+	Product string
+	Version string
+
+	// Mapping maps source fields to attribute paths
+	Mapping Mapping
+}
+
+// NewOCSFFormatter creates an OCSF formatter with the default product and mapping
+func NewOCSFFormatter() *OCSFFormatter {
+	return &OCSFFormatter{
+		Vendor:  DefaultVendor,
+		Product: DefaultProduct,
+		Version: client.Version,
+		Mapping: DefaultOCSFMapping(),
+	}
+}
+
+// Format implements Formatter
+func (f *OCSFFormatter) Format(record *Record) ([]byte, error) {
+	object, err := f.Object(record)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(object)
+}
+
+// Object builds the OCSF record as a JSON object, for callers that add
+// attributes before encoding it
+func (f *OCSFFormatter) Object(record *Record) (map[string]any, error) {
+	object := map[string]any{
+		"time":        record.Time.UnixMilli(),
+		"message":     record.Name,
+		"severity_id": ocsfSeverityID(record.Severity),
+		"metadata": map[string]any{
+			"version": OCSFVersion,
+			"product": map[string]any{
+				"name":        f.Product,
+				"vendor_name": f.Vendor,
+				"version":     f.Version,
+			},
+		},
+	}
+
+	var classUID, categoryUID, activityID int
+	switch record.Kind {
+	case KindEvent:
+		classUID, categoryUID = OCSFClassAPIActivity, 6
+		eventType, _ := record.Fields[FieldEventType].(string)
+		activityID = apiActivityID(eventType)
+		object["api"] = map[string]any{"operation": eventType}
+	case KindVulnerabilityChange:
+		classUID, categoryUID = OCSFClassVulnerabilityFinding, 2
+		id, _ := record.Fields[FieldID].(string)
+		object["finding_info"] = map[string]any{
+			"uid":          id,
+			"title":        record.Name,
+			"created_time": record.Time.UnixMilli(),
+		}
+		activityID, object["status_id"] = 1, 1 // Create, New
+		if record.Fields[FieldStatus] == vulnerabilitychanges.StatusFixed {
+			activityID, object["status_id"] = 3, 4 // Close, Resolved
+		}
+	default:
+		return nil, fmt.Errorf("unsupported record kind %q", record.Kind)
+	}
+	object["class_uid"] = classUID
+	object["category_uid"] = categoryUID
+	object["activity_id"] = activityID
+	object["type_uid"] = classUID*100 + activityID
+
+	for source, value := range record.Fields {
+		path, ok := f.Mapping[source]
+		if !ok || path == "" {
+			continue
+		}
+		if t, isTime := value.(time.Time); isTime {
+			value = t.UnixMilli()
+		}
+		if _, err := setPath(object, strings.Split(path, "."), value); err != nil {
+			return nil, fmt.Errorf("failed to map field %q to %q: %w", source, path, err)
+		}
+	}
+	return object, nil
+}
+
+// apiActivityID returns the API Activity activity_id for an event type such
+// as "device.updated"
+func apiActivityID(eventType string) int {
+	action := eventType[strings.LastIndex(eventType, ".")+1:]
+	switch action {
+	case "created", "create", "added":
+		return 1 // Create
+	case "read", "viewed", "downloaded":
+		return 2 // Read
+	case "updated", "update", "changed":
+		return 3 // Update
+	case "deleted", "delete", "destroyed", "removed":
+		return 4 // Delete
+	}
+	return 99 // Other
+}
+
+// ocsfSeverityID converts a 0-10 severity to an OCSF severity_id
+func ocsfSeverityID(severity int) int {
+	switch {
+	case severity >= 9:
+		return 5 // Critical
+	case severity >= 7:
+		return 4 // High
+	case severity >= 4:
+		return 3 // Medium
+	case severity >= 2:
+		return 2 // Low
+	}
+	return 1 // Informational
+}
+
+// setPath sets value at a path of object keys and array indexes within
+// container, creating objects and arrays as needed, and returns the updated container
+func setPath(container any, keys []string, value any) (any, error) {
+	if len(keys) == 0 {
+		return value, nil
+	}
+
+	key := keys[0]
+	if index, err := strconv.Atoi(key); err == nil {
+		if index < 0 || index > maxPathIndex {
+			return nil, fmt.Errorf("array index %d out of range", index)
+		}
+		list, ok := container.([]any)
+		if container != nil && !ok {
+			return nil, fmt.Errorf("%q indexes a value that is not an array", key)
+		}
+		for len(list) <= index {
+			list = append(list, nil)
+		}
+		child, err := setPath(list[index], keys[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		list[index] = child
+		return list, nil
+	}
+
+	object, ok := container.(map[string]any)
+	if container != nil && !ok {
+		return nil, fmt.Errorf("%q is a key of a value that is not an object", key)
+	}
+	if object == nil {
+		object = make(map[string]any)
+	}
+	child, err := setPath(object[key], keys[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	object[key] = child
+	return object, nil
+}
@@ -0,0 +1,85 @@
+package siem
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formatOCSF(t *testing.T, formatter *OCSFFormatter, record *Record) map[string]any {
+	t.Helper()
+	line, err := formatter.Format(record)
+	require.NoError(t, err)
+
+	var object map[string]any
+	require.NoError(t, json.Unmarshal(line, &object))
+	return object
+}
+
+func TestOCSFFormatter_Event(t *testing.T) {
+	object := formatOCSF(t, NewOCSFFormatter(), FromEvent(testEvent()))
+
+	assert.EqualValues(t, OCSFClassAPIActivity, object["class_uid"])
+	assert.EqualValues(t, 3, object["activity_id"])
+	assert.EqualValues(t, 600303, object["type_uid"])
+	assert.EqualValues(t, 1, object["severity_id"])
+	assert.EqualValues(t, 1736085600000, object["time"])
+	assert.Equal(t, map[string]any{"operation": "brewfile.updated"}, object["api"])
+	assert.Equal(t, map[string]any{"user": map[string]any{"uid": "u1", "type": "User"}}, object["actor"])
+	assert.Equal(t, []any{map[string]any{"uid": "b1", "type": "Brewfile", "name": "dev|tools"}}, object["resources"])
+
+	metadata := object["metadata"].(map[string]any)
+	assert.Equal(t, "e1", metadata["uid"])
+	assert.Equal(t, OCSFVersion, metadata["version"])
+	assert.Equal(t, "Workbrew", metadata["product"].(map[string]any)["vendor_name"])
+}
+
+func TestOCSFFormatter_VulnerabilityChange(t *testing.T) {
+	object := formatOCSF(t, NewOCSFFormatter(), FromVulnerabilityChange(testChange()))
+
+	assert.EqualValues(t, OCSFClassVulnerabilityFinding, object["class_uid"])
+	assert.EqualValues(t, 200201, object["type_uid"])
+	assert.EqualValues(t, 1, object["status_id"])
+	assert.EqualValues(t, 5, object["severity_id"])
+	assert.Equal(t, "v1", object["finding_info"].(map[string]any)["uid"])
+	assert.Equal(t, "TC6R2DHVHG", object["device"].(map[string]any)["hw_info"].(map[string]any)["serial_number"])
+
+	vulnerability := object["vulnerabilities"].([]any)[0].(map[string]any)
+	assert.Equal(t, "Critical", vulnerability["severity"])
+	assert.Equal(t, []any{map[string]any{"name": "wget", "version": "1.24.5"}}, vulnerability["affected_packages"])
+	cve := vulnerability["cve"].(map[string]any)
+	assert.Equal(t, "CVE-2024-10524", cve["uid"])
+	assert.Equal(t, []any{map[string]any{"base_score": 9.1}}, cve["cvss"])
+
+	fixed := testChange()
+	fixed.Status = vulnerabilitychanges.StatusFixed
+	object = formatOCSF(t, NewOCSFFormatter(), FromVulnerabilityChange(fixed))
+	assert.EqualValues(t, 200203, object["type_uid"])
+	assert.EqualValues(t, 4, object["status_id"])
+}
+
+func TestOCSFFormatter_CustomMapping(t *testing.T) {
+	formatter := NewOCSFFormatter()
+	formatter.Mapping[FieldDeviceSerialNumber] = "unmapped.serial"
+	object := formatOCSF(t, formatter, FromVulnerabilityChange(testChange()))
+	assert.Equal(t, "TC6R2DHVHG", object["unmapped"].(map[string]any)["serial"])
+
+	formatter.Mapping[FieldFormulaName] = "time.name"
+	_, err := formatter.Format(FromVulnerabilityChange(testChange()))
+	assert.ErrorContains(t, err, `failed to map field "formula_name" to "time.name"`)
+}
+
+func TestSetPath(t *testing.T) {
+	root, err := setPath(nil, []string{"a", "1", "b"}, "x")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": []any{nil, map[string]any{"b": "x"}}}, root)
+
+	_, err = setPath(root, []string{"a", "b"}, "y")
+	assert.ErrorContains(t, err, "not an object")
+
+	_, err = setPath(nil, []string{"1000"}, "z")
+	assert.ErrorContains(t, err, "out of range")
+}
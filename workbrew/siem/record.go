@@ -0,0 +1,184 @@
+// Package siem converts Workbrew audit events and vulnerability changes into
+// records for security information and event management (SIEM) systems: ArcSight
+// Common Event Format (CEF), IBM QRadar Log Event Extended Format (LEEF) and Open
+// Cybersecurity Schema Framework (OCSF) JSON.
+//
+// Each format maps source fields to its own keys through a Mapping, which can
+// be customised to match a collector's parsing rules.
+package siem
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/vulnwatch"
+)
+
+// Record kinds
+const (
+	KindEvent               = "event"
+	KindVulnerabilityChange = "vulnerability_change"
+)
+
+// Source field names shared by events and vulnerability changes. Mappings are
+// keyed by these names, which match the API's JSON field names.
+const (
+	FieldID         = "id"
+	FieldEventType  = "event_type"
+	FieldOccurredAt = "occurred_at"
+)
+
+// Source field names of audit events
+const (
+	FieldActorID          = "actor_id"
+	FieldActorType        = "actor_type"
+	FieldTargetID         = "target_id"
+	FieldTargetType       = "target_type"
+	FieldTargetIdentifier = "target_identifier"
+	FieldTargetSnapshot   = "target_snapshot"
+	FieldChanges          = "changes"
+)
+
+// Source field names of vulnerability changes
+const (
+	FieldStatus             = "status"
+	FieldDeviceID           = "device_id"
+	FieldDeviceSerialNumber = "device_serial_number"
+	FieldFormulaName        = "formula_name"
+	FieldFormulaVersion     = "formula_version"
+	FieldVulnerabilityID    = "vulnerability_id"
+	FieldCVSSSeverity       = "cvss_severity"
+	FieldCVSSScore          = "cvss_score"
+)
+
+// Record is a format-neutral view of an event or vulnerability change
+type Record struct {
+	// Kind is KindEvent or KindVulnerabilityChange
+	Kind string
+
+	// Name is a short human-readable summary, e.g. "CVE-2024-10524 detected in wget 1.24.5"
+	Name string
+
+	// Severity is on the 0-10 CEF scale. Audit events are 1; vulnerability
+	// changes follow the CVSS score, or are 1 once fixed.
+	Severity int
+
+	// Time is when the event or change occurred
+	Time time.Time
+
+	// Fields holds the source fields that are set, keyed by the Field constants.
+	// Values are strings, float64, time.Time or map[string]any.
+	Fields map[string]any
+}
+
+// FromEvent converts an audit event to a record
+func FromEvent(event *events.Event) *Record {
+	record := &Record{
+		Kind:     KindEvent,
+		Name:     event.EventType,
+		Severity: 1,
+		Time:     event.OccurredAt,
+		Fields: map[string]any{
+			FieldID:         event.ID,
+			FieldEventType:  event.EventType,
+			FieldOccurredAt: event.OccurredAt,
+		},
+	}
+	if event.TargetIdentifier != nil && *event.TargetIdentifier != "" {
+		record.Name = event.EventType + " " + *event.TargetIdentifier
+	}
+
+	record.setString(FieldActorID, event.ActorID)
+	record.setString(FieldActorType, event.ActorType)
+	record.setString(FieldTargetID, event.TargetID)
+	record.setString(FieldTargetType, event.TargetType)
+	record.setString(FieldTargetIdentifier, event.TargetIdentifier)
+	if len(event.TargetSnapshot) > 0 {
+		record.Fields[FieldTargetSnapshot] = event.TargetSnapshot
+	}
+	if len(event.Changes) > 0 {
+		record.Fields[FieldChanges] = event.Changes
+	}
+	return record
+}
+
+// FromVulnerabilityChange converts a vulnerability change to a record
+func FromVulnerabilityChange(change *vulnerabilitychanges.VulnerabilityChange) *Record {
+	record := &Record{
+		Kind:     KindVulnerabilityChange,
+		Name:     strings.TrimSpace(change.VulnerabilityID + " " + change.Status + " in " + change.FormulaName + " " + change.FormulaVersion),
+		Severity: changeSeverity(change),
+		Time:     change.OccurredAt,
+		Fields: map[string]any{
+			FieldID:         change.ID,
+			FieldEventType:  change.EventType,
+			FieldOccurredAt: change.OccurredAt,
+		},
+	}
+
+	record.setString(FieldStatus, &change.Status)
+	record.setString(FieldDeviceID, change.DeviceID)
+	record.setString(FieldDeviceSerialNumber, change.DeviceSerialNumber)
+	record.setString(FieldFormulaName, &change.FormulaName)
+	record.setString(FieldFormulaVersion, &change.FormulaVersion)
+	record.setString(FieldVulnerabilityID, &change.VulnerabilityID)
+	record.setString(FieldCVSSSeverity, change.CVSSSeverity)
+	if change.CVSSScore != nil {
+		record.Fields[FieldCVSSScore] = *change.CVSSScore
+	}
+	return record
+}
+
+// setString sets a field if value is a non-empty string
+func (r *Record) setString(field string, value *string) {
+	if value != nil && *value != "" {
+		r.Fields[field] = *value
+	}
+}
+
+// changeSeverity returns the 0-10 severity of a vulnerability change
+func changeSeverity(change *vulnerabilitychanges.VulnerabilityChange) int {
+	if change.Status == vulnerabilitychanges.StatusFixed {
+		return 1
+	}
+	if change.CVSSScore != nil {
+		return int(math.Round(min(max(*change.CVSSScore, 0), 10)))
+	}
+
+	severity, rated := vulnwatch.ChangeSeverity(change)
+	if !rated {
+		return 5
+	}
+	return severityScores[severity]
+}
+
+// severityScores are the midpoints of the CVSS v3 rating ranges, used when a
+// change has a severity but no score
+var severityScores = map[vulnerabilities.Severity]int{
+	vulnerabilities.SeverityNone:     0,
+	vulnerabilities.SeverityLow:      2,
+	vulnerabilities.SeverityMedium:   5,
+	vulnerabilities.SeverityHigh:     8,
+	vulnerabilities.SeverityCritical: 10,
+}
+
+// Mapping maps source field names (the Field constants) to the keys a format
+// writes them under. Fields without an entry are omitted. The Default*Mapping
+// functions return fresh copies that can be changed freely.
+//
+// Example:
+//
+//	mapping := siem.DefaultCEFMapping()
+//	mapping[siem.FieldActorID] = "suser"
+//	delete(mapping, siem.FieldChanges)
+type Mapping map[string]string
+
+// Formatter converts records to one SIEM format
+type Formatter interface {
+	// Format encodes a record as a single line, without a trailing newline
+	Format(record *Record) ([]byte, error)
+}
@@ -0,0 +1,102 @@
+package siem
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/vulnwatch"
+)
+
+// Format names accepted by NewFormatter
+const (
+	FormatCEF  = "cef"
+	FormatLEEF = "leef"
+	FormatOCSF = "ocsf"
+)
+
+// NewFormatter creates a formatter with default settings by name
+//
+// Parameters:
+//   - format: FormatCEF, FormatLEEF or FormatOCSF, ignoring case
+//
+// Returns:
+//   - Formatter: The formatter
+//   - error: An error if the format is unknown
+func NewFormatter(format string) (Formatter, error) {
+	switch strings.ToLower(format) {
+	case FormatCEF:
+		return NewCEFFormatter(), nil
+	case FormatLEEF:
+		return NewLEEFFormatter(), nil
+	case FormatOCSF:
+		return NewOCSFFormatter(), nil
+	}
+	return nil, fmt.Errorf("unknown SIEM format %q (expected cef, leef or ocsf)", format)
+}
+
+// Writer writes formatted records to an io.Writer, one per line. It is safe
+// for concurrent use, and implements vulnwatch.Sink so a vulnerability watcher
+// can export directly.
+type Writer struct {
+	mu        sync.Mutex
+	w         io.Writer
+	formatter Formatter
+}
+
+// NewWriter creates a writer that formats records with formatter
+//
+// Parameters:
+//   - w: Destination, e.g. os.Stdout, a file or a syslog connection
+//   - formatter: The SIEM format
+//
+// Example:
+//
+//	writer := siem.NewWriter(os.Stdout, siem.NewCEFFormatter())
+//	for event := range tailer.Tail(ctx) {
+//	    if err := writer.WriteEvent(&event); err != nil {
+//	        return err
+//	    }
+//	}
+func NewWriter(w io.Writer, formatter Formatter) *Writer {
+	return &Writer{w: w, formatter: formatter}
+}
+
+// Write formats a record and writes it as a line
+func (w *Writer) Write(record *Record) error {
+	line, err := w.formatter.Format(record)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := w.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	return nil
+}
+
+// WriteEvent writes an audit event
+func (w *Writer) WriteEvent(event *events.Event) error {
+	return w.Write(FromEvent(event))
+}
+
+// WriteVulnerabilityChange writes a vulnerability change
+func (w *Writer) WriteVulnerabilityChange(change *vulnerabilitychanges.VulnerabilityChange) error {
+	return w.Write(FromVulnerabilityChange(change))
+}
+
+// Send implements vulnwatch.Sink, writing each change in the batch
+func (w *Writer) Send(ctx context.Context, batch *vulnwatch.Batch) error {
+	for i := range batch.Changes {
+		if err := w.WriteVulnerabilityChange(&batch.Changes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/vulnwatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFormatter(t *testing.T) {
+	for _, format := range []string{"cef", "LEEF", "ocsf"} {
+		_, err := NewFormatter(format)
+		assert.NoError(t, err, format)
+	}
+
+	_, err := NewFormatter("syslog")
+	assert.ErrorContains(t, err, `unknown SIEM format "syslog"`)
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewWriter(&buf, NewCEFFormatter())
+
+	require.NoError(t, writer.WriteEvent(testEvent()))
+	var sink vulnwatch.Sink = writer
+	require.NoError(t, sink.Send(context.Background(), &vulnwatch.Batch{
+		Changes: []vulnerabilitychanges.VulnerabilityChange{*testChange()},
+	}))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "CEF:0|Workbrew|Workbrew Console|1.0.0|brewfile.updated|"))
+	assert.True(t, strings.HasPrefix(lines[1], "CEF:0|Workbrew|Workbrew Console|1.0.0|vulnerability.detected|"))
+}
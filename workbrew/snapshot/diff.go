@@ -0,0 +1,245 @@
+package snapshot
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Diff describes the changes between two snapshots of the same workspace
+type Diff struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	DevicesAdded   []string `json:"devices_added,omitempty"`
+	DevicesRemoved []string `json:"devices_removed,omitempty"`
+
+	FormulaeAdded         []string `json:"formulae_added,omitempty"`
+	FormulaeRemoved       []string `json:"formulae_removed,omitempty"`
+	FormulaeNewlyOutdated []string `json:"formulae_newly_outdated,omitempty"`
+
+	CasksAdded         []string `json:"casks_added,omitempty"`
+	CasksRemoved       []string `json:"casks_removed,omitempty"`
+	CasksNewlyOutdated []string `json:"casks_newly_outdated,omitempty"`
+
+	TapsAdded   []string `json:"taps_added,omitempty"`
+	TapsRemoved []string `json:"taps_removed,omitempty"`
+
+	NewVulnerabilities      []VulnerabilityDelta `json:"new_vulnerabilities,omitempty"`
+	ResolvedVulnerabilities []VulnerabilityDelta `json:"resolved_vulnerabilities,omitempty"`
+
+	GroupsAdded            []string                `json:"groups_added,omitempty"`
+	GroupsRemoved          []string                `json:"groups_removed,omitempty"`
+	GroupMembershipChanges []GroupMembershipChange `json:"group_membership_changes,omitempty"`
+}
+
+// VulnerabilityDelta identifies a single vulnerability affecting a formula
+type VulnerabilityDelta struct {
+	Formula         string   `json:"formula"`
+	ID              string   `json:"id"`
+	CVSSScore       *float64 `json:"cvss_score,omitempty"`
+	OutdatedDevices []string `json:"outdated_devices,omitempty"`
+}
+
+// GroupMembershipChange lists devices that joined or left a device group
+type GroupMembershipChange struct {
+	Group   string   `json:"group"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// Compare computes the changes from one snapshot to a later one.
+// All name lists in the result are sorted for stable output.
+//
+// Parameters:
+//   - from: The older snapshot
+//   - to: The newer snapshot
+//
+// Returns:
+//   - *Diff: The structured differences
+//
+// Example:
+//
+//	yesterday, _ := snapshot.Load("yesterday.json")
+//	today, _ := snapshot.Capture(ctx, src)
+//	diff := snapshot.Compare(yesterday, today)
+func Compare(from, to *Snapshot) *Diff {
+	diff := &Diff{
+		From: from.CapturedAt,
+		To:   to.CapturedAt,
+	}
+
+	oldDevices, newDevices := make(map[string]bool), make(map[string]bool)
+	for _, d := range from.Devices {
+		oldDevices[d.SerialNumber] = true
+	}
+	for _, d := range to.Devices {
+		newDevices[d.SerialNumber] = true
+	}
+	diff.DevicesAdded, diff.DevicesRemoved = setDelta(oldDevices, newDevices)
+
+	oldFormulae, newFormulae := make(map[string]bool), make(map[string]bool)
+	for _, f := range from.Formulae {
+		oldFormulae[f.Name] = f.Outdated
+	}
+	for _, f := range to.Formulae {
+		newFormulae[f.Name] = f.Outdated
+	}
+	diff.FormulaeAdded, diff.FormulaeRemoved = setDelta(oldFormulae, newFormulae)
+	diff.FormulaeNewlyOutdated = newlyTrue(oldFormulae, newFormulae)
+
+	oldCasks, newCasks := make(map[string]bool), make(map[string]bool)
+	for _, c := range from.Casks {
+		oldCasks[c.Name] = c.Outdated
+	}
+	for _, c := range to.Casks {
+		newCasks[c.Name] = c.Outdated
+	}
+	diff.CasksAdded, diff.CasksRemoved = setDelta(oldCasks, newCasks)
+	diff.CasksNewlyOutdated = newlyTrue(oldCasks, newCasks)
+
+	oldTaps, newTaps := make(map[string]bool), make(map[string]bool)
+	for _, t := range from.BrewTaps {
+		oldTaps[t.Tap] = true
+	}
+	for _, t := range to.BrewTaps {
+		newTaps[t.Tap] = true
+	}
+	diff.TapsAdded, diff.TapsRemoved = setDelta(oldTaps, newTaps)
+
+	oldVulns, newVulns := vulnerabilityIndex(from), vulnerabilityIndex(to)
+	for key, delta := range newVulns {
+		if _, ok := oldVulns[key]; !ok {
+			diff.NewVulnerabilities = append(diff.NewVulnerabilities, delta)
+		}
+	}
+	for key, delta := range oldVulns {
+		if _, ok := newVulns[key]; !ok {
+			diff.ResolvedVulnerabilities = append(diff.ResolvedVulnerabilities, delta)
+		}
+	}
+	sortVulnerabilities(diff.NewVulnerabilities)
+	sortVulnerabilities(diff.ResolvedVulnerabilities)
+
+	diff.compareGroups(from, to)
+
+	return diff
+}
+
+// IsEmpty reports whether the diff contains no changes
+func (d *Diff) IsEmpty() bool {
+	return len(d.DevicesAdded) == 0 && len(d.DevicesRemoved) == 0 &&
+		len(d.FormulaeAdded) == 0 && len(d.FormulaeRemoved) == 0 && len(d.FormulaeNewlyOutdated) == 0 &&
+		len(d.CasksAdded) == 0 && len(d.CasksRemoved) == 0 && len(d.CasksNewlyOutdated) == 0 &&
+		len(d.TapsAdded) == 0 && len(d.TapsRemoved) == 0 &&
+		len(d.NewVulnerabilities) == 0 && len(d.ResolvedVulnerabilities) == 0 &&
+		len(d.GroupsAdded) == 0 && len(d.GroupsRemoved) == 0 && len(d.GroupMembershipChanges) == 0
+}
+
+// compareGroups records added/removed groups and membership changes.
+// Groups are matched by ID, falling back to name when no ID is present.
+func (d *Diff) compareGroups(from, to *Snapshot) {
+	type group struct {
+		name    string
+		members map[string]bool
+	}
+
+	index := func(s *Snapshot) map[string]group {
+		groups := make(map[string]group, len(s.DeviceGroups))
+		for _, g := range s.DeviceGroups {
+			key := g.ID
+			if key == "" {
+				key = g.Name
+			}
+			members := make(map[string]bool, len(g.Devices))
+			for _, serial := range g.Devices {
+				members[serial] = true
+			}
+			groups[key] = group{name: g.Name, members: members}
+		}
+		return groups
+	}
+
+	oldGroups, newGroups := index(from), index(to)
+
+	for key, g := range newGroups {
+		old, ok := oldGroups[key]
+		if !ok {
+			d.GroupsAdded = append(d.GroupsAdded, g.name)
+			continue
+		}
+		added, removed := setDelta(old.members, g.members)
+		if len(added) > 0 || len(removed) > 0 {
+			d.GroupMembershipChanges = append(d.GroupMembershipChanges, GroupMembershipChange{
+				Group:   g.name,
+				Added:   added,
+				Removed: removed,
+			})
+		}
+	}
+	for key, g := range oldGroups {
+		if _, ok := newGroups[key]; !ok {
+			d.GroupsRemoved = append(d.GroupsRemoved, g.name)
+		}
+	}
+
+	slices.Sort(d.GroupsAdded)
+	slices.Sort(d.GroupsRemoved)
+	slices.SortFunc(d.GroupMembershipChanges, func(a, b GroupMembershipChange) int {
+		return cmp.Compare(a.Group, b.Group)
+	})
+}
+
+// vulnerabilityIndex flattens a snapshot's vulnerabilities keyed by formula and ID
+func vulnerabilityIndex(s *Snapshot) map[string]VulnerabilityDelta {
+	index := make(map[string]VulnerabilityDelta)
+	for _, v := range s.Vulnerabilities {
+		for _, detail := range v.Vulnerabilities {
+			index[v.Formula+"\x00"+detail.CleanID] = VulnerabilityDelta{
+				Formula:         v.Formula,
+				ID:              detail.CleanID,
+				CVSSScore:       detail.CVSSScore,
+				OutdatedDevices: v.OutdatedDevices,
+			}
+		}
+	}
+	return index
+}
+
+// setDelta returns the sorted keys present only in newer and only in older
+func setDelta(older, newer map[string]bool) (added, removed []string) {
+	for key := range newer {
+		if _, ok := older[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	for key := range older {
+		if _, ok := newer[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
+// newlyTrue returns the sorted keys that were false (or absent) in older and true in newer
+func newlyTrue(older, newer map[string]bool) []string {
+	var keys []string
+	for key, value := range newer {
+		if value && !older[key] {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func sortVulnerabilities(deltas []VulnerabilityDelta) {
+	slices.SortFunc(deltas, func(a, b VulnerabilityDelta) int {
+		if c := cmp.Compare(a.Formula, b.Formula); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
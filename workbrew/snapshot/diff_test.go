@@ -0,0 +1,89 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewtaps"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/casks"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	score := 9.8
+
+	from := &Snapshot{
+		Version: CurrentVersion,
+		Devices: devices.DevicesResponse{{SerialNumber: "AAA"}, {SerialNumber: "BBB"}},
+		Formulae: formulae.FormulaeResponse{
+			{Name: "curl", Outdated: false},
+			{Name: "wget", Outdated: true},
+			{Name: "jq"},
+		},
+		Casks:    casks.CasksResponse{{Name: "firefox"}},
+		BrewTaps: brewtaps.BrewTapsResponse{{Tap: "homebrew/core"}},
+		DeviceGroups: devicegroups.DeviceGroupsResponse{
+			{ID: "g1", Name: "Engineering", Devices: []string{"AAA"}},
+			{ID: "g2", Name: "Sales", Devices: []string{"BBB"}},
+		},
+		Vulnerabilities: vulnerabilities.VulnerabilitiesResponse{
+			{Formula: "wget", Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-OLD"}}},
+		},
+	}
+
+	to := &Snapshot{
+		Version: CurrentVersion,
+		Devices: devices.DevicesResponse{{SerialNumber: "AAA"}, {SerialNumber: "CCC"}},
+		Formulae: formulae.FormulaeResponse{
+			{Name: "curl", Outdated: true},
+			{Name: "wget", Outdated: true},
+			{Name: "git", Outdated: true},
+		},
+		Casks:    casks.CasksResponse{{Name: "firefox", Outdated: true}},
+		BrewTaps: brewtaps.BrewTapsResponse{{Tap: "homebrew/core"}, {Tap: "acme/tools"}},
+		DeviceGroups: devicegroups.DeviceGroupsResponse{
+			{ID: "g1", Name: "Engineering", Devices: []string{"AAA", "CCC"}},
+			{ID: "g3", Name: "Design", Devices: nil},
+		},
+		Vulnerabilities: vulnerabilities.VulnerabilitiesResponse{
+			{
+				Formula:         "curl",
+				OutdatedDevices: []string{"AAA"},
+				Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-NEW", CVSSScore: &score}},
+			},
+		},
+	}
+
+	diff := Compare(from, to)
+
+	assert.Equal(t, []string{"CCC"}, diff.DevicesAdded)
+	assert.Equal(t, []string{"BBB"}, diff.DevicesRemoved)
+	assert.Equal(t, []string{"git"}, diff.FormulaeAdded)
+	assert.Equal(t, []string{"jq"}, diff.FormulaeRemoved)
+	assert.Equal(t, []string{"curl", "git"}, diff.FormulaeNewlyOutdated)
+	assert.Equal(t, []string{"firefox"}, diff.CasksNewlyOutdated)
+	assert.Equal(t, []string{"acme/tools"}, diff.TapsAdded)
+	assert.Empty(t, diff.TapsRemoved)
+
+	assert.Equal(t, []VulnerabilityDelta{{
+		Formula: "curl", ID: "CVE-NEW", CVSSScore: &score, OutdatedDevices: []string{"AAA"},
+	}}, diff.NewVulnerabilities)
+	assert.Equal(t, []VulnerabilityDelta{{Formula: "wget", ID: "CVE-OLD"}}, diff.ResolvedVulnerabilities)
+
+	assert.Equal(t, []string{"Design"}, diff.GroupsAdded)
+	assert.Equal(t, []string{"Sales"}, diff.GroupsRemoved)
+	assert.Equal(t, []GroupMembershipChange{{Group: "Engineering", Added: []string{"CCC"}}}, diff.GroupMembershipChanges)
+
+	assert.False(t, diff.IsEmpty())
+}
+
+func TestCompare_NoChanges(t *testing.T) {
+	snap := &Snapshot{
+		Devices:  devices.DevicesResponse{{SerialNumber: "AAA"}},
+		Formulae: formulae.FormulaeResponse{{Name: "curl", Outdated: true}},
+	}
+	assert.True(t, Compare(snap, snap).IsEmpty())
+}
@@ -0,0 +1,181 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewtaps"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/casks"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+)
+
+// CurrentVersion is the snapshot file format version written by this package.
+// Load rejects files with a newer version than it understands.
+const CurrentVersion = 1
+
+// Snapshot is a point-in-time capture of a workspace inventory
+type Snapshot struct {
+	Version         int                                     `json:"version"`
+	Workspace       string                                  `json:"workspace,omitempty"`
+	CapturedAt      time.Time                               `json:"captured_at"`
+	Devices         devices.DevicesResponse                 `json:"devices"`
+	Formulae        formulae.FormulaeResponse               `json:"formulae"`
+	Casks           casks.CasksResponse                     `json:"casks"`
+	BrewTaps        brewtaps.BrewTapsResponse               `json:"brew_taps"`
+	DeviceGroups    devicegroups.DeviceGroupsResponse       `json:"device_groups"`
+	Vulnerabilities vulnerabilities.VulnerabilitiesResponse `json:"vulnerabilities"`
+}
+
+// Source holds the services a snapshot is captured from.
+// Any service interface implementation may be used, which allows fakes in tests.
+type Source struct {
+	Workspace       string
+	Devices         devices.DevicesServiceInterface
+	Formulae        formulae.FormulaeServiceInterface
+	Casks           casks.CasksServiceInterface
+	BrewTaps        brewtaps.BrewTapsServiceInterface
+	DeviceGroups    devicegroups.DeviceGroupsServiceInterface
+	Vulnerabilities vulnerabilities.VulnerabilitiesServiceInterface
+}
+
+// NewSource creates a snapshot source backed by a Workbrew client
+//
+// Parameters:
+//   - client: The Workbrew client to capture from
+//   - workspace: The workspace name recorded in the snapshot
+func NewSource(client *workbrew.Client, workspace string) *Source {
+	return &Source{
+		Workspace:       workspace,
+		Devices:         client.Devices,
+		Formulae:        client.Formulae,
+		Casks:           client.Casks,
+		BrewTaps:        client.BrewTaps,
+		DeviceGroups:    client.DeviceGroups,
+		Vulnerabilities: client.Vulnerabilities,
+	}
+}
+
+// Capture takes a snapshot of the workspace inventory by calling each list endpoint in turn.
+// The first failing call aborts the capture.
+//
+// Parameters:
+//   - ctx: Context for the underlying API calls
+//   - src: The services to capture from
+//
+// Returns:
+//   - *Snapshot: The captured inventory
+//   - error: Any error returned by the API
+//
+// Example:
+//
+//	snap, err := snapshot.Capture(ctx, snapshot.NewSource(client, "my-workspace"))
+func Capture(ctx context.Context, src *Source) (*Snapshot, error) {
+	if src == nil {
+		return nil, fmt.Errorf("snapshot source is required")
+	}
+
+	snap := &Snapshot{
+		Version:   CurrentVersion,
+		Workspace: src.Workspace,
+	}
+
+	deviceList, _, err := src.Devices.ListDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	snap.Devices = *deviceList
+
+	formulaList, _, err := src.Formulae.ListFormulae(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list formulae: %w", err)
+	}
+	snap.Formulae = *formulaList
+
+	caskList, _, err := src.Casks.ListCasks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list casks: %w", err)
+	}
+	snap.Casks = *caskList
+
+	tapList, _, err := src.BrewTaps.ListBrewTaps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list brew taps: %w", err)
+	}
+	snap.BrewTaps = *tapList
+
+	groupList, _, err := src.DeviceGroups.ListDeviceGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device groups: %w", err)
+	}
+	snap.DeviceGroups = *groupList
+
+	vulnList, _, err := src.Vulnerabilities.ListVulnerabilities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vulnerabilities: %w", err)
+	}
+	snap.Vulnerabilities = *vulnList
+
+	snap.CapturedAt = time.Now().UTC()
+
+	return snap, nil
+}
+
+// Write serialises the snapshot as indented JSON
+func (s *Snapshot) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(s); err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return nil
+}
+
+// Save writes the snapshot to the named file, replacing any existing file
+func (s *Snapshot) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+
+	if err := s.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Read decodes a snapshot previously written by Write
+//
+// Returns an error if the snapshot version is missing or newer than CurrentVersion.
+func Read(r io.Reader) (*Snapshot, error) {
+	var snap Snapshot
+	if err := json.NewDecoder(r).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+
+	if snap.Version < 1 || snap.Version > CurrentVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (supported: 1-%d)", snap.Version, CurrentVersion)
+	}
+
+	return &snap, nil
+}
+
+// Load reads a snapshot from the named file
+func Load(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer file.Close()
+
+	return Read(file)
+}
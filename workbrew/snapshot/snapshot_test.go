@@ -0,0 +1,158 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewtaps"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/casks"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDevices struct {
+	devices.DevicesServiceInterface
+	result devices.DevicesResponse
+	err    error
+}
+
+func (f *fakeDevices) ListDevices(ctx context.Context) (*devices.DevicesResponse, *interfaces.Response, error) {
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	return &f.result, nil, nil
+}
+
+type fakeFormulae struct {
+	formulae.FormulaeServiceInterface
+	result formulae.FormulaeResponse
+}
+
+func (f *fakeFormulae) ListFormulae(ctx context.Context) (*formulae.FormulaeResponse, *interfaces.Response, error) {
+	return &f.result, nil, nil
+}
+
+type fakeCasks struct {
+	casks.CasksServiceInterface
+	result casks.CasksResponse
+}
+
+func (f *fakeCasks) ListCasks(ctx context.Context) (*casks.CasksResponse, *interfaces.Response, error) {
+	return &f.result, nil, nil
+}
+
+type fakeBrewTaps struct {
+	brewtaps.BrewTapsServiceInterface
+	result brewtaps.BrewTapsResponse
+}
+
+func (f *fakeBrewTaps) ListBrewTaps(ctx context.Context) (*brewtaps.BrewTapsResponse, *interfaces.Response, error) {
+	return &f.result, nil, nil
+}
+
+type fakeDeviceGroups struct {
+	devicegroups.DeviceGroupsServiceInterface
+	result devicegroups.DeviceGroupsResponse
+}
+
+func (f *fakeDeviceGroups) ListDeviceGroups(ctx context.Context) (*devicegroups.DeviceGroupsResponse, *interfaces.Response, error) {
+	return &f.result, nil, nil
+}
+
+type fakeVulnerabilities struct {
+	vulnerabilities.VulnerabilitiesServiceInterface
+	result vulnerabilities.VulnerabilitiesResponse
+}
+
+func (f *fakeVulnerabilities) ListVulnerabilities(ctx context.Context) (*vulnerabilities.VulnerabilitiesResponse, *interfaces.Response, error) {
+	return &f.result, nil, nil
+}
+
+func newFakeSource() *Source {
+	return &Source{
+		Workspace: "test-workspace",
+		Devices: &fakeDevices{result: devices.DevicesResponse{
+			{SerialNumber: "AAA"},
+		}},
+		Formulae: &fakeFormulae{result: formulae.FormulaeResponse{
+			{Name: "curl", Devices: []string{"AAA"}},
+		}},
+		Casks: &fakeCasks{result: casks.CasksResponse{
+			{Name: "firefox", Devices: []string{"AAA"}},
+		}},
+		BrewTaps: &fakeBrewTaps{result: brewtaps.BrewTapsResponse{
+			{Tap: "homebrew/core", Devices: []string{"AAA"}},
+		}},
+		DeviceGroups: &fakeDeviceGroups{result: devicegroups.DeviceGroupsResponse{
+			{ID: "g1", Name: "Engineering", Devices: []string{"AAA"}},
+		}},
+		Vulnerabilities: &fakeVulnerabilities{result: vulnerabilities.VulnerabilitiesResponse{
+			{Formula: "curl", Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-2024-0001"}}},
+		}},
+	}
+}
+
+func TestCapture(t *testing.T) {
+	snap, err := Capture(context.Background(), newFakeSource())
+	require.NoError(t, err)
+
+	assert.Equal(t, CurrentVersion, snap.Version)
+	assert.Equal(t, "test-workspace", snap.Workspace)
+	assert.False(t, snap.CapturedAt.IsZero())
+	assert.Len(t, snap.Devices, 1)
+	assert.Len(t, snap.Formulae, 1)
+	assert.Len(t, snap.Casks, 1)
+	assert.Len(t, snap.BrewTaps, 1)
+	assert.Len(t, snap.DeviceGroups, 1)
+	assert.Len(t, snap.Vulnerabilities, 1)
+}
+
+func TestCapture_Error(t *testing.T) {
+	src := newFakeSource()
+	src.Devices = &fakeDevices{err: errors.New("boom")}
+
+	snap, err := Capture(context.Background(), src)
+	require.Error(t, err)
+	assert.Nil(t, snap)
+	assert.Contains(t, err.Error(), "failed to list devices")
+}
+
+func TestSaveLoad_RoundTrip(t *testing.T) {
+	snap, err := Capture(context.Background(), newFakeSource())
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, snap.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, snap.Workspace, loaded.Workspace)
+	assert.True(t, snap.CapturedAt.Equal(loaded.CapturedAt))
+	assert.Equal(t, snap.Formulae, loaded.Formulae)
+	assert.True(t, Compare(snap, loaded).IsEmpty())
+}
+
+func TestRead_UnsupportedVersion(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"version": 99}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported snapshot version 99")
+
+	_, err = Read(strings.NewReader(`{}`))
+	require.Error(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	snap := &Snapshot{Version: CurrentVersion, Workspace: "ws"}
+	require.NoError(t, snap.Write(&buf))
+	assert.Contains(t, buf.String(), `"version": 1`)
+}
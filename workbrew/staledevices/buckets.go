@@ -0,0 +1,89 @@
+// Package staledevices reports devices that have stopped checking in or were
+// never fully enrolled.
+//
+// Each device's last-seen and last-command ages are sorted into age buckets,
+// and devices are flagged when they were never seen, never ran a command,
+// belong to no device group or have nothing installed. Reports are written as
+// JSON or CSV for reconciliation against hardware asset registers.
+package staledevices
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/duration"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+)
+
+// Day is 24 hours
+const Day = duration.Day
+
+// BucketNever holds devices whose timestamp is "Never"
+const BucketNever = "never"
+
+// DefaultThresholds are the bucket boundaries used when Options.Thresholds is empty
+var DefaultThresholds = []time.Duration{Day, 7 * Day, 30 * Day, 90 * Day}
+
+// Buckets sorts ages into named ranges bounded by ascending thresholds. Thresholds
+// of 1d, 7d and 30d give the buckets "<1d", "1d-7d", "7d-30d" and "30d+".
+type Buckets struct {
+	thresholds []time.Duration
+	names      []string
+}
+
+// NewBuckets creates buckets from ascending, positive thresholds
+//
+// Parameters:
+//   - thresholds: The upper bounds of every bucket but the last
+//
+// Returns:
+//   - *Buckets: The buckets
+//   - error: If there are no thresholds, or they are not positive and ascending
+func NewBuckets(thresholds []time.Duration) (*Buckets, error) {
+	if len(thresholds) == 0 {
+		return nil, fmt.Errorf("at least one bucket threshold is required")
+	}
+	for i, threshold := range thresholds {
+		if threshold <= 0 {
+			return nil, fmt.Errorf("bucket threshold %s must be positive", threshold)
+		}
+		if i > 0 && threshold <= thresholds[i-1] {
+			return nil, fmt.Errorf("bucket thresholds must be ascending, %s follows %s", threshold, thresholds[i-1])
+		}
+	}
+
+	names := make([]string, 0, len(thresholds)+1)
+	names = append(names, "<"+duration.Format(thresholds[0]))
+	for i := 1; i < len(thresholds); i++ {
+		names = append(names, duration.Format(thresholds[i-1])+"-"+duration.Format(thresholds[i]))
+	}
+	names = append(names, duration.Format(thresholds[len(thresholds)-1])+"+")
+
+	return &Buckets{thresholds: append([]time.Duration{}, thresholds...), names: names}, nil
+}
+
+// Names returns the bucket names from youngest to oldest, followed by BucketNever
+func (b *Buckets) Names() []string {
+	return append(append([]string{}, b.names...), BucketNever)
+}
+
+// Bucket returns the name of the bucket an age falls in. Negative ages, from
+// timestamps after the evaluation time, fall in the youngest bucket.
+func (b *Buckets) Bucket(age time.Duration) string {
+	for i, threshold := range b.thresholds {
+		if age < threshold {
+			return b.names[i]
+		}
+	}
+	return b.names[len(b.names)-1]
+}
+
+// Classify returns the age of a timestamp at now and its bucket. The age is nil
+// and the bucket BucketNever when the timestamp is "Never".
+func (b *Buckets) Classify(value devices.TimeOrNever, now time.Time) (*time.Duration, string) {
+	if value.Never || value.Time == nil {
+		return nil, BucketNever
+	}
+	age := now.Sub(*value.Time)
+	return &age, b.Bucket(age)
+}
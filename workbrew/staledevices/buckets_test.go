@@ -0,0 +1,64 @@
+package staledevices
+
+import (
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBuckets(t *testing.T) {
+	buckets, err := NewBuckets([]time.Duration{12 * time.Hour, 7 * Day, 30 * Day})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"<12h0m0s", "12h0m0s-7d", "7d-30d", "30d+", BucketNever}, buckets.Names())
+
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{-time.Hour, "<12h0m0s"},
+		{0, "<12h0m0s"},
+		{12 * time.Hour, "12h0m0s-7d"},
+		{7*Day - time.Second, "12h0m0s-7d"},
+		{7 * Day, "7d-30d"},
+		{30 * Day, "30d+"},
+		{365 * Day, "30d+"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, buckets.Bucket(tt.age), tt.age.String())
+	}
+}
+
+func TestNewBuckets_Invalid(t *testing.T) {
+	tests := map[string][]time.Duration{
+		"empty":         nil,
+		"zero":          {0, Day},
+		"negative":      {-Day},
+		"not ascending": {7 * Day, 7 * Day},
+	}
+	for name, thresholds := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewBuckets(thresholds)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBuckets_Classify(t *testing.T) {
+	buckets, err := NewBuckets(DefaultThresholds)
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)
+	seen := now.Add(-36 * time.Hour)
+
+	age, bucket := buckets.Classify(devices.TimeOrNever{Time: &seen}, now)
+	require.NotNil(t, age)
+	assert.Equal(t, 36*time.Hour, *age)
+	assert.Equal(t, "1d-7d", bucket)
+
+	age, bucket = buckets.Classify(devices.TimeOrNever{Never: true}, now)
+	assert.Nil(t, age)
+	assert.Equal(t, BucketNever, bucket)
+}
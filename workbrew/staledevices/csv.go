@@ -0,0 +1,84 @@
+package staledevices
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVHeader is the header row written by WriteCSV
+var CSVHeader = []string{
+	"serial_number",
+	"name",
+	"device_type",
+	"os_version",
+	"last_seen_at",
+	"last_seen_days",
+	"last_seen_bucket",
+	"command_last_run_at",
+	"command_last_run_days",
+	"command_last_run_bucket",
+	"groups",
+	"formulae_count",
+	"casks_count",
+	"flags",
+}
+
+// WriteCSV writes one row per device, for reconciliation in a spreadsheet or
+// asset register. Timestamps are RFC3339 or "Never", ages are whole days and
+// empty for "Never", and groups and flags are separated by semicolons.
+//
+// Parameters:
+//   - w: The writer to write to
+//
+// Returns:
+//   - error: Any write error
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVHeader); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+
+	for _, device := range r.Devices {
+		flags := make([]string, len(device.Flags))
+		for i, flag := range device.Flags {
+			flags[i] = string(flag)
+		}
+
+		row := []string{
+			device.SerialNumber,
+			device.Name,
+			device.DeviceType,
+			device.OSVersion,
+			device.LastSeenAt.String(),
+			formatDays(device.LastSeenDays),
+			device.LastSeenBucket,
+			device.CommandLastRunAt.String(),
+			formatDays(device.CommandLastRunDays),
+			device.CommandLastRunBucket,
+			strings.Join(device.Groups, ";"),
+			strconv.Itoa(device.FormulaeCount),
+			strconv.Itoa(device.CasksCount),
+			strings.Join(flags, ";"),
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// formatDays formats an age in days, or an empty string for no age
+func formatDays(days *int) string {
+	if days == nil {
+		return ""
+	}
+	return strconv.Itoa(*days)
+}
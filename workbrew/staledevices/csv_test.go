@@ -0,0 +1,52 @@
+package staledevices
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_WriteCSV(t *testing.T) {
+	fixtures := testFixtures()
+	report, err := Build(fixtures.Devices, fixtures.DeviceGroups, &Options{Now: testNow, StaleAfter: 7 * Day})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, CSVHeader, rows[0])
+
+	assert.Equal(t, []string{
+		workbrewtest.FixtureDeviceLinux,
+		"",
+		"Linux",
+		"Ubuntu 22.04.5 LTS",
+		"2025-01-06T09:30:00Z",
+		"10",
+		"7d-30d",
+		"2025-01-06T10:02:00Z",
+		"9",
+		"7d-30d",
+		"All Devices",
+		"1",
+		"0",
+		"stale",
+	}, rows[1])
+
+	assert.Equal(t, "Admin;All Devices", rows[3][10])
+
+	never := rows[2]
+	assert.Equal(t, "NEVERSEEN1", never[0])
+	assert.Equal(t, "Never", never[4])
+	assert.Equal(t, "", never[5])
+	assert.Equal(t, BucketNever, never[6])
+	assert.Equal(t, "", never[10])
+	assert.Equal(t, "never_seen;command_never_run;no_groups;no_packages", never[13])
+}
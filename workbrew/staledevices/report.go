@@ -0,0 +1,336 @@
+package staledevices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/duration"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+)
+
+// DefaultStaleAfter is the last-seen age above which a device is stale when Options.StaleAfter is zero
+const DefaultStaleAfter = 30 * Day
+
+// Flag marks a device for follow-up
+type Flag string
+
+// Device flags
+const (
+	// FlagStale marks devices last seen longer ago than Options.StaleAfter
+	FlagStale Flag = "stale"
+
+	// FlagNeverSeen marks devices whose last seen time is "Never"
+	FlagNeverSeen Flag = "never_seen"
+
+	// FlagCommandNeverRun marks devices that have never run a brew command
+	FlagCommandNeverRun Flag = "command_never_run"
+
+	// FlagNoGroups marks devices that are not a member of any device group
+	FlagNoGroups Flag = "no_groups"
+
+	// FlagNoPackages marks devices with no formulae or casks installed
+	FlagNoPackages Flag = "no_packages"
+)
+
+// Flags lists every flag in report order
+var Flags = []Flag{FlagStale, FlagNeverSeen, FlagCommandNeverRun, FlagNoGroups, FlagNoPackages}
+
+// DeviceEntry is one device in the report
+type DeviceEntry struct {
+	SerialNumber string `json:"serial_number"`
+	Name         string `json:"name,omitempty"`
+	DeviceType   string `json:"device_type"`
+	OSVersion    string `json:"os_version"`
+
+	LastSeenAt     devices.TimeOrNever `json:"last_seen_at"`
+	LastSeenDays   *int                `json:"last_seen_days"`
+	LastSeenBucket string              `json:"last_seen_bucket"`
+
+	CommandLastRunAt     devices.TimeOrNever `json:"command_last_run_at"`
+	CommandLastRunDays   *int                `json:"command_last_run_days"`
+	CommandLastRunBucket string              `json:"command_last_run_bucket"`
+
+	// Groups are the names of the device groups listing the device
+	Groups []string `json:"groups"`
+
+	FormulaeCount int    `json:"formulae_count"`
+	CasksCount    int    `json:"casks_count"`
+	Flags         []Flag `json:"flags"`
+}
+
+// HasFlag reports whether the device carries a flag
+func (d *DeviceEntry) HasFlag(flag Flag) bool {
+	return slices.Contains(d.Flags, flag)
+}
+
+// BucketCount is the number of devices in an age bucket
+type BucketCount struct {
+	Bucket  string `json:"bucket"`
+	Devices int    `json:"devices"`
+}
+
+// Summary counts devices by age bucket and flag
+type Summary struct {
+	Devices int `json:"devices"`
+
+	// Flagged counts devices with at least one flag
+	Flagged int `json:"flagged"`
+
+	// LastSeen and CommandLastRun list every bucket from youngest to oldest, then "never"
+	LastSeen       []BucketCount `json:"last_seen"`
+	CommandLastRun []BucketCount `json:"command_last_run"`
+
+	Flags map[Flag]int `json:"flags"`
+}
+
+// Report classifies every device in a workspace by check-in age
+type Report struct {
+	Workspace   string    `json:"workspace,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+	StaleAfter  string    `json:"stale_after"`
+	Summary     Summary   `json:"summary"`
+
+	// Devices are sorted by serial number
+	Devices []DeviceEntry `json:"devices"`
+}
+
+// Options configures the report
+type Options struct {
+	// Workspace is recorded in the report
+	Workspace string
+
+	// Now is the evaluation time. Defaults to the current time.
+	Now time.Time
+
+	// StaleAfter is the last-seen age above which a device is flagged stale. Defaults to DefaultStaleAfter.
+	StaleAfter time.Duration
+
+	// Thresholds bound the age buckets. Defaults to DefaultThresholds.
+	Thresholds []time.Duration
+}
+
+// Source holds the services a report is collected from
+type Source struct {
+	Workspace    string
+	Devices      devices.DevicesServiceInterface
+	DeviceGroups devicegroups.DeviceGroupsServiceInterface
+}
+
+// NewSource creates a report source backed by a Workbrew client
+//
+// Parameters:
+//   - client: The Workbrew client to collect from
+//   - workspace: The workspace name recorded in the report
+func NewSource(client *workbrew.Client, workspace string) *Source {
+	return &Source{
+		Workspace:    workspace,
+		Devices:      client.Devices,
+		DeviceGroups: client.DeviceGroups,
+	}
+}
+
+// Generate lists devices and device groups and classifies every device
+//
+// Parameters:
+//   - ctx: Context for the underlying API calls
+//   - src: The services to collect from
+//   - options: Report options; may be nil. The workspace defaults to the source's.
+//
+// Returns:
+//   - *Report: Every device with its age buckets and flags
+//   - error: Any error returned by the API, or invalid options
+//
+// Example:
+//
+//	report, err := staledevices.Generate(ctx, staledevices.NewSource(client, "my-workspace"), &staledevices.Options{
+//	    StaleAfter: 14 * staledevices.Day,
+//	})
+//	for _, device := range report.Flagged(staledevices.FlagStale) {
+//	    fmt.Println(device.SerialNumber, device.LastSeenAt)
+//	}
+func Generate(ctx context.Context, src *Source, options *Options) (*Report, error) {
+	if src == nil || src.Devices == nil || src.DeviceGroups == nil {
+		return nil, fmt.Errorf("stale device source with devices and device groups services is required")
+	}
+
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Workspace == "" {
+		opts.Workspace = src.Workspace
+	}
+
+	deviceList, _, err := src.Devices.ListDevices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	groupList, _, err := src.DeviceGroups.ListDeviceGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list device groups: %w", err)
+	}
+
+	return Build(*deviceList, *groupList, &opts)
+}
+
+// Build classifies devices from responses already fetched, e.g. from a snapshot
+//
+// Parameters:
+//   - deviceList: The devices to classify
+//   - groups: Device groups, to find devices in no group
+//   - options: Report options; may be nil
+//
+// Returns:
+//   - *Report: Every device with its age buckets and flags
+//   - error: If the stale age is negative or the bucket thresholds are invalid
+func Build(deviceList devices.DevicesResponse, groups devicegroups.DeviceGroupsResponse, options *Options) (*Report, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	now := options.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	staleAfter := options.StaleAfter
+	if staleAfter < 0 {
+		return nil, fmt.Errorf("stale age %s must not be negative", staleAfter)
+	}
+	if staleAfter == 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	thresholds := options.Thresholds
+	if len(thresholds) == 0 {
+		thresholds = DefaultThresholds
+	}
+	buckets, err := NewBuckets(thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceGroups := make(map[string][]string)
+	for _, group := range groups {
+		for _, serial := range group.Devices {
+			deviceGroups[serial] = append(deviceGroups[serial], group.Name)
+		}
+	}
+
+	report := &Report{
+		Workspace:   options.Workspace,
+		GeneratedAt: now,
+		StaleAfter:  duration.Format(staleAfter),
+		Summary: Summary{
+			Flags: make(map[Flag]int, len(Flags)),
+		},
+		Devices: make([]DeviceEntry, 0, len(deviceList)),
+	}
+	lastSeen := make(map[string]int)
+	commandLastRun := make(map[string]int)
+
+	for _, device := range deviceList {
+		entry := DeviceEntry{
+			SerialNumber:     device.SerialNumber,
+			DeviceType:       device.DeviceType,
+			OSVersion:        device.OSVersion,
+			LastSeenAt:       device.LastSeenAt,
+			CommandLastRunAt: device.CommandLastRunAt,
+			Groups:           append([]string{}, deviceGroups[device.SerialNumber]...),
+			FormulaeCount:    device.FormulaeCount,
+			CasksCount:       device.CasksCount,
+			Flags:            []Flag{},
+		}
+		if device.MDMUserOrDeviceName != nil {
+			entry.Name = *device.MDMUserOrDeviceName
+		}
+		slices.Sort(entry.Groups)
+
+		var seenAge, commandAge *time.Duration
+		seenAge, entry.LastSeenBucket = buckets.Classify(device.LastSeenAt, now)
+		commandAge, entry.CommandLastRunBucket = buckets.Classify(device.CommandLastRunAt, now)
+		entry.LastSeenDays = days(seenAge)
+		entry.CommandLastRunDays = days(commandAge)
+
+		if seenAge != nil && *seenAge > staleAfter {
+			entry.Flags = append(entry.Flags, FlagStale)
+		}
+		if seenAge == nil {
+			entry.Flags = append(entry.Flags, FlagNeverSeen)
+		}
+		if commandAge == nil {
+			entry.Flags = append(entry.Flags, FlagCommandNeverRun)
+		}
+		if len(entry.Groups) == 0 {
+			entry.Flags = append(entry.Flags, FlagNoGroups)
+		}
+		if device.FormulaeCount == 0 && device.CasksCount == 0 {
+			entry.Flags = append(entry.Flags, FlagNoPackages)
+		}
+
+		lastSeen[entry.LastSeenBucket]++
+		commandLastRun[entry.CommandLastRunBucket]++
+		for _, flag := range entry.Flags {
+			report.Summary.Flags[flag]++
+		}
+		if len(entry.Flags) > 0 {
+			report.Summary.Flagged++
+		}
+		report.Devices = append(report.Devices, entry)
+	}
+	report.Summary.Devices = len(report.Devices)
+	for _, name := range buckets.Names() {
+		report.Summary.LastSeen = append(report.Summary.LastSeen, BucketCount{Bucket: name, Devices: lastSeen[name]})
+		report.Summary.CommandLastRun = append(report.Summary.CommandLastRun, BucketCount{Bucket: name, Devices: commandLastRun[name]})
+	}
+	slices.SortFunc(report.Devices, func(a, b DeviceEntry) int {
+		return strings.Compare(a.SerialNumber, b.SerialNumber)
+	})
+
+	return report, nil
+}
+
+// Device returns the entry for the device with the given serial number
+func (r *Report) Device(serial string) (*DeviceEntry, bool) {
+	for i := range r.Devices {
+		if r.Devices[i].SerialNumber == serial {
+			return &r.Devices[i], true
+		}
+	}
+	return nil, false
+}
+
+// Flagged returns the devices carrying a flag, or carrying any flag when none is given
+func (r *Report) Flagged(flags ...Flag) []DeviceEntry {
+	var flagged []DeviceEntry
+	for _, device := range r.Devices {
+		if (len(flags) == 0 && len(device.Flags) > 0) || slices.ContainsFunc(flags, device.HasFlag) {
+			flagged = append(flagged, device)
+		}
+	}
+	return flagged
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// days returns an age in whole days, or nil for no age
+func days(age *time.Duration) *int {
+	if age == nil {
+		return nil
+	}
+	count := int(*age / Day)
+	return &count
+}
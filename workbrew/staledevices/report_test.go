@@ -0,0 +1,160 @@
+package staledevices
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// testNow is ten days after the fixture devices were last seen
+var testNow = time.Date(2025, 1, 16, 9, 30, 0, 0, time.UTC)
+
+// testFixtures adds a device that never checked in, is in no group and has nothing installed
+func testFixtures() *workbrewtest.Fixtures {
+	fixtures := workbrewtest.DefaultFixtures()
+	fixtures.Devices = append(fixtures.Devices, devices.Device{
+		SerialNumber:     "NEVERSEEN1",
+		LastSeenAt:       devices.TimeOrNever{Never: true},
+		CommandLastRunAt: devices.TimeOrNever{Never: true},
+		DeviceType:       "Mac mini",
+	})
+	return fixtures
+}
+
+func TestGenerate(t *testing.T) {
+	server := workbrewtest.NewServer(workbrewtest.WithFixtures(testFixtures()))
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	report, err := Generate(context.Background(), NewSource(wb, workbrewtest.DefaultWorkspace), &Options{
+		Now:        testNow,
+		StaleAfter: 7 * Day,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, workbrewtest.DefaultWorkspace, report.Workspace)
+	assert.Equal(t, "7d", report.StaleAfter)
+	assert.Equal(t, 3, report.Summary.Devices)
+	assert.Equal(t, 3, report.Summary.Flagged)
+	assert.Equal(t, []BucketCount{
+		{Bucket: "<1d", Devices: 0},
+		{Bucket: "1d-7d", Devices: 0},
+		{Bucket: "7d-30d", Devices: 2},
+		{Bucket: "30d-90d", Devices: 0},
+		{Bucket: "90d+", Devices: 0},
+		{Bucket: BucketNever, Devices: 1},
+	}, report.Summary.LastSeen)
+	assert.Equal(t, map[Flag]int{
+		FlagStale:           2,
+		FlagNeverSeen:       1,
+		FlagCommandNeverRun: 1,
+		FlagNoGroups:        1,
+		FlagNoPackages:      1,
+	}, report.Summary.Flags)
+
+	macbook, ok := report.Device(workbrewtest.FixtureDeviceMacBook)
+	require.True(t, ok)
+	assert.Equal(t, "Mike's MacBook Pro", macbook.Name)
+	assert.Equal(t, []string{"Admin", "All Devices"}, macbook.Groups)
+	assert.Equal(t, ptr(10), macbook.LastSeenDays)
+	assert.Equal(t, "7d-30d", macbook.LastSeenBucket)
+	assert.Equal(t, []Flag{FlagStale}, macbook.Flags)
+
+	never, ok := report.Device("NEVERSEEN1")
+	require.True(t, ok)
+	assert.Nil(t, never.LastSeenDays)
+	assert.Equal(t, BucketNever, never.LastSeenBucket)
+	assert.Equal(t, BucketNever, never.CommandLastRunBucket)
+	assert.Empty(t, never.Groups)
+	assert.Equal(t, []Flag{FlagNeverSeen, FlagCommandNeverRun, FlagNoGroups, FlagNoPackages}, never.Flags)
+
+	assert.Len(t, report.Flagged(), 3)
+	assert.Len(t, report.Flagged(FlagStale), 2)
+	orphaned := report.Flagged(FlagNoGroups, FlagNoPackages)
+	require.Len(t, orphaned, 1)
+	assert.Equal(t, "NEVERSEEN1", orphaned[0].SerialNumber)
+}
+
+func TestGenerate_Error(t *testing.T) {
+	server := workbrewtest.NewServer()
+	defer server.Close()
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	server.InjectFault(workbrewtest.Fault{Path: "/device_groups.json", StatusCode: 403})
+
+	_, err = Generate(context.Background(), NewSource(wb, workbrewtest.DefaultWorkspace), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list device groups")
+
+	_, err = Generate(context.Background(), &Source{}, nil)
+	assert.Error(t, err)
+}
+
+func TestBuild_Defaults(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures()
+
+	report, err := Build(fixtures.Devices, fixtures.DeviceGroups, &Options{Now: testNow})
+	require.NoError(t, err)
+
+	assert.Equal(t, "30d", report.StaleAfter)
+	assert.Equal(t, 0, report.Summary.Flagged)
+	assert.Empty(t, report.Flagged())
+	for _, device := range report.Devices {
+		assert.Equal(t, []Flag{}, device.Flags)
+		assert.Equal(t, "7d-30d", device.CommandLastRunBucket)
+	}
+}
+
+func TestBuild_DeviceGroupsDecideMembership(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures()
+
+	// The device record still names its groups, but no group lists it
+	report, err := Build(fixtures.Devices, devicegroups.DeviceGroupsResponse{fixtures.DeviceGroups[0]}, &Options{Now: testNow})
+	require.NoError(t, err)
+
+	linux, ok := report.Device(workbrewtest.FixtureDeviceLinux)
+	require.True(t, ok)
+	assert.Equal(t, []Flag{FlagNoGroups}, linux.Flags)
+}
+
+func TestBuild_InvalidOptions(t *testing.T) {
+	fixtures := workbrewtest.DefaultFixtures()
+
+	_, err := Build(fixtures.Devices, fixtures.DeviceGroups, &Options{StaleAfter: -time.Hour})
+	assert.Error(t, err)
+
+	_, err = Build(fixtures.Devices, fixtures.DeviceGroups, &Options{Thresholds: []time.Duration{7 * Day, Day}})
+	assert.Error(t, err)
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	fixtures := testFixtures()
+	report, err := Build(fixtures.Devices, fixtures.DeviceGroups, &Options{Now: testNow})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJSON(&buf))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	entries := decoded["devices"].([]any)
+	require.Len(t, entries, 3)
+	never := entries[1].(map[string]any)
+	assert.Equal(t, "NEVERSEEN1", never["serial_number"])
+	assert.Equal(t, "Never", never["last_seen_at"])
+	assert.Nil(t, never["last_seen_days"])
+	assert.Equal(t, map[string]any{"never_seen": 1.0, "command_never_run": 1.0, "no_groups": 1.0, "no_packages": 1.0}, decoded["summary"].(map[string]any)["flags"])
+}
@@ -0,0 +1,115 @@
+package vulnreport
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/cyclonedx"
+)
+
+// VEX analysis states used by the report
+const (
+	// AnalysisInTriage marks findings with devices still running an affected version
+	AnalysisInTriage = "in_triage"
+
+	// AnalysisResolved marks findings with no outdated devices left
+	AnalysisResolved = "resolved"
+)
+
+// CycloneDX converts the report to a CycloneDX 1.5 VEX document. Findings
+// with outdated devices are in_triage; findings without are resolved. Outdated
+// devices are listed as workbrew:outdated_device properties.
+//
+// Returns:
+//   - *cyclonedx.BOM: The VEX document, with a new random serial number
+func (r *Report) CycloneDX() *cyclonedx.BOM {
+	bom := cyclonedx.NewBOM(r.GeneratedAt)
+	if r.Workspace != "" {
+		bom.Metadata.Properties = []cyclonedx.Property{{Name: "workbrew:workspace", Value: r.Workspace}}
+	}
+
+	added := make(map[string]bool)
+	for _, finding := range r.Findings {
+		ref := FormulaPURL(finding.Formula)
+		if !added[ref] {
+			added[ref] = true
+			bom.Components = append(bom.Components, formulaComponent(&finding))
+		}
+
+		vulnerability := cyclonedx.Vulnerability{
+			BOMRef:  finding.VulnerabilityID + "/" + finding.Formula,
+			ID:      finding.VulnerabilityID,
+			Source:  advisorySource(finding.VulnerabilityID),
+			Affects: []cyclonedx.Affects{{Ref: ref}},
+			Analysis: cyclonedx.Analysis{
+				State:  AnalysisResolved,
+				Detail: "No devices run an affected version",
+			},
+		}
+		if count := len(finding.OutdatedDevices); count > 0 {
+			vulnerability.Analysis = cyclonedx.Analysis{
+				State:  AnalysisInTriage,
+				Detail: fmt.Sprintf("%d device(s) run an affected version", count),
+			}
+		}
+		if finding.CVSSScore != nil {
+			vulnerability.Ratings = []cyclonedx.Rating{{
+				Score:    *finding.CVSSScore,
+				Severity: strings.ToLower(finding.Severity().String()),
+				Method:   "other",
+			}}
+		}
+		if finding.LatestVersion != "" {
+			vulnerability.Recommendation = fmt.Sprintf("Upgrade %s to %s or later", finding.Formula, finding.LatestVersion)
+		}
+		for _, device := range finding.OutdatedDevices {
+			vulnerability.Properties = append(vulnerability.Properties, cyclonedx.Property{Name: "workbrew:outdated_device", Value: device})
+		}
+		bom.Vulnerabilities = append(bom.Vulnerabilities, vulnerability)
+	}
+	return bom
+}
+
+// WriteCycloneDX writes the report as an indented CycloneDX VEX document
+func (r *Report) WriteCycloneDX(w io.Writer) error {
+	return r.CycloneDX().Write(w)
+}
+
+// FormulaPURL returns the package URL of a Homebrew formula, e.g. "pkg:brew/wget".
+// The installed version is not known, so the URL has none.
+func FormulaPURL(formula string) string {
+	return "pkg:brew/" + formula
+}
+
+// formulaComponent describes the formula of a finding
+func formulaComponent(finding *Finding) cyclonedx.Component {
+	component := cyclonedx.Component{
+		Type:   cyclonedx.ComponentLibrary,
+		BOMRef: FormulaPURL(finding.Formula),
+		Name:   finding.Formula,
+		PURL:   FormulaPURL(finding.Formula),
+	}
+	for _, license := range finding.Licenses {
+		component.Licenses = append(component.Licenses, cyclonedx.LicenseChoice{License: &cyclonedx.License{Name: license}})
+	}
+	if finding.LatestVersion != "" {
+		component.Properties = append(component.Properties, cyclonedx.Property{Name: "workbrew:latest_version", Value: finding.LatestVersion})
+	}
+	if finding.Deprecated != nil {
+		component.Properties = append(component.Properties, cyclonedx.Property{Name: "workbrew:deprecated", Value: *finding.Deprecated})
+	}
+	return component
+}
+
+// advisorySource names the database that publishes a vulnerability ID
+func advisorySource(id string) cyclonedx.Source {
+	name := "OSV"
+	switch {
+	case strings.HasPrefix(id, "CVE-"):
+		name = "NVD"
+	case strings.HasPrefix(id, "GHSA-"):
+		name = "GitHub"
+	}
+	return cyclonedx.Source{Name: name, URL: advisoryURL(id)}
+}
@@ -0,0 +1,62 @@
+package vulnreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/cyclonedx"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_CycloneDX(t *testing.T) {
+	bom := testReport(t, true).CycloneDX()
+
+	assert.Equal(t, cyclonedx.BOMFormat, bom.BOMFormat)
+	assert.Equal(t, cyclonedx.SpecVersion, bom.SpecVersion)
+	assert.Regexp(t, regexp.MustCompile(`^urn:uuid:[0-9a-f-]{36}$`), bom.SerialNumber)
+	assert.Equal(t, []cyclonedx.Property{{Name: "workbrew:workspace", Value: "test-workspace"}}, bom.Metadata.Properties)
+
+	require.Len(t, bom.Components, 2)
+	wget := bom.Components[0]
+	assert.Equal(t, "pkg:brew/wget", wget.BOMRef)
+	assert.Equal(t, "pkg:brew/wget", wget.PURL)
+	assert.Equal(t, []cyclonedx.LicenseChoice{{License: &cyclonedx.License{Name: "GPL-3.0-or-later"}}}, wget.Licenses)
+
+	require.Len(t, bom.Vulnerabilities, 2)
+	vulnerability := bom.Vulnerabilities[0]
+	assert.Equal(t, "CVE-2024-10524", vulnerability.ID)
+	assert.Equal(t, cyclonedx.Source{Name: "NVD", URL: "https://nvd.nist.gov/vuln/detail/CVE-2024-10524"}, vulnerability.Source)
+	assert.Equal(t, []cyclonedx.Rating{{Score: 9.1, Severity: "critical", Method: "other"}}, vulnerability.Ratings)
+	assert.Equal(t, []cyclonedx.Affects{{Ref: "pkg:brew/wget"}}, vulnerability.Affects)
+	assert.Equal(t, AnalysisInTriage, vulnerability.Analysis.State)
+	assert.Equal(t, "Upgrade wget to 1.25.0 or later", vulnerability.Recommendation)
+	assert.Equal(t, []cyclonedx.Property{{Name: "workbrew:outdated_device", Value: "TC6R2DHVHG"}}, vulnerability.Properties)
+}
+
+func TestReport_CycloneDX_Resolved(t *testing.T) {
+	vulns := vulnerabilities.VulnerabilitiesResponse{
+		{Formula: "jq", Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "GHSA-xxxx-yyyy-zzzz"}, {CleanID: "OSV-1"}}},
+	}
+	bom := Build(vulns, nil, nil).CycloneDX()
+
+	assert.Len(t, bom.Components, 1, "components are listed once")
+	require.Len(t, bom.Vulnerabilities, 2)
+	assert.Equal(t, AnalysisResolved, bom.Vulnerabilities[0].Analysis.State)
+	assert.Equal(t, "GitHub", bom.Vulnerabilities[0].Source.Name)
+	assert.Equal(t, "https://osv.dev/vulnerability/OSV-1", bom.Vulnerabilities[1].Source.URL)
+	assert.Empty(t, bom.Vulnerabilities[0].Ratings)
+}
+
+func TestReport_WriteCycloneDX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testReport(t, false).WriteCycloneDX(&buf))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Equal(t, "CycloneDX", document["bomFormat"])
+	assert.Len(t, document["vulnerabilities"], 2)
+}
@@ -0,0 +1,206 @@
+// Package vulnreport builds standard vulnerability reports from the workspace's
+// vulnerabilities: SARIF 2.1.0 logs for code-scanning dashboards and CycloneDX
+// 1.5 VEX documents for vulnerability-management tools.
+package vulnreport
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+)
+
+// Finding is one vulnerability affecting one formula
+type Finding struct {
+	// Formula is the affected formula name
+	Formula string `json:"formula"`
+
+	// VulnerabilityID is the CVE or other advisory ID, e.g. "CVE-2024-10524"
+	VulnerabilityID string `json:"vulnerability_id"`
+
+	// CVSSScore is the CVSS base score, when known
+	CVSSScore *float64 `json:"cvss_score,omitempty"`
+
+	// OutdatedDevices are the serial numbers of devices running an affected version
+	OutdatedDevices []string `json:"outdated_devices,omitempty"`
+
+	// Supported reports whether Workbrew can remediate the formula
+	Supported bool `json:"supported"`
+
+	// LatestVersion is the formula's current homebrew/core version
+	LatestVersion string `json:"latest_version,omitempty"`
+
+	// Enrichment from ListFormulae; empty when the report was built without formulae
+
+	// Devices are the serial numbers of every device with the formula installed
+	Devices []string `json:"devices,omitempty"`
+
+	// Licenses are the formula's declared licenses
+	Licenses []string `json:"licenses,omitempty"`
+
+	// Deprecated is the formula's deprecation reason, if it is deprecated
+	Deprecated *string `json:"deprecated,omitempty"`
+
+	// InstalledOnRequest reports whether the formula was installed directly
+	// rather than only as a dependency
+	InstalledOnRequest bool `json:"installed_on_request,omitempty"`
+}
+
+// Severity returns the CVSS v3 rating of the finding's score, or SeverityNone without one
+func (f *Finding) Severity() vulnerabilities.Severity {
+	if f.CVSSScore == nil {
+		return vulnerabilities.SeverityNone
+	}
+	return vulnerabilities.SeverityForScore(*f.CVSSScore)
+}
+
+// Report is a point-in-time list of vulnerability findings
+type Report struct {
+	// Workspace names the workspace the findings came from
+	Workspace string `json:"workspace,omitempty"`
+
+	// GeneratedAt is when the report was built
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// Findings are sorted by descending CVSS score, then formula and vulnerability ID
+	Findings []Finding `json:"findings"`
+}
+
+// Options configures Generate and Build
+type Options struct {
+	// Workspace labels the report
+	Workspace string
+
+	// MinSeverity drops findings rated below it. Findings without a score are
+	// kept only when MinSeverity is SeverityNone.
+	MinSeverity vulnerabilities.Severity
+}
+
+// Generate lists the workspace's vulnerabilities, and formulae when a formulae
+// service is given, and builds a report from them
+//
+// Parameters:
+//   - ctx: Context for the API calls
+//   - vulnerabilitiesService: The vulnerabilities service (e.g. client.Vulnerabilities)
+//   - formulaeService: The formulae service (e.g. client.Formulae), or nil to skip enrichment
+//   - options: Report options; may be nil
+//
+// Returns:
+//   - *Report: The report
+//   - error: Any error calling the API
+//
+// Example:
+//
+//	report, err := vulnreport.Generate(ctx, client.Vulnerabilities, client.Formulae, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	return report.WriteSARIF(os.Stdout, nil)
+func Generate(ctx context.Context, vulnerabilitiesService vulnerabilities.VulnerabilitiesServiceInterface, formulaeService formulae.FormulaeServiceInterface, options *Options) (*Report, error) {
+	vulns, _, err := vulnerabilitiesService.ListVulnerabilities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vulnerabilities: %w", err)
+	}
+
+	var installed formulae.FormulaeResponse
+	if formulaeService != nil {
+		list, _, err := formulaeService.ListFormulae(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list formulae: %w", err)
+		}
+		installed = *list
+	}
+
+	return Build(*vulns, installed, options), nil
+}
+
+// Build creates a report from already fetched vulnerabilities and, optionally, formulae
+//
+// Parameters:
+//   - vulns: The ListVulnerabilities response
+//   - installed: The ListFormulae response, or nil to skip enrichment
+//   - options: Report options; may be nil
+//
+// Returns:
+//   - *Report: The report, with one finding per formula and vulnerability ID
+func Build(vulns vulnerabilities.VulnerabilitiesResponse, installed formulae.FormulaeResponse, options *Options) *Report {
+	if options == nil {
+		options = &Options{}
+	}
+
+	byName := make(map[string]*formulae.Formula, len(installed))
+	for i := range installed {
+		byName[installed[i].Name] = &installed[i]
+	}
+
+	report := &Report{
+		Workspace:   options.Workspace,
+		GeneratedAt: time.Now().UTC(),
+		Findings:    []Finding{},
+	}
+	for _, vuln := range vulns {
+		for _, detail := range vuln.Vulnerabilities {
+			finding := Finding{
+				Formula:         vuln.Formula,
+				VulnerabilityID: detail.CleanID,
+				CVSSScore:       detail.CVSSScore,
+				OutdatedDevices: slices.Sorted(slices.Values(vuln.OutdatedDevices)),
+				Supported:       vuln.Supported,
+				LatestVersion:   vuln.HomebrewCoreVersion,
+			}
+			if formula, ok := byName[vuln.Formula]; ok {
+				enrich(&finding, formula)
+			}
+			if options.MinSeverity > vulnerabilities.SeverityNone && finding.Severity() < options.MinSeverity {
+				continue
+			}
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+
+	slices.SortFunc(report.Findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(score(b), score(a)),
+			cmp.Compare(a.Formula, b.Formula),
+			cmp.Compare(a.VulnerabilityID, b.VulnerabilityID),
+		)
+	})
+	return report
+}
+
+// enrich copies formula details into a finding
+func enrich(finding *Finding, formula *formulae.Formula) {
+	finding.Devices = slices.Sorted(slices.Values(formula.Devices))
+	if formula.License != nil {
+		finding.Licenses = *formula.License
+	}
+	finding.Deprecated = formula.Deprecated
+	finding.InstalledOnRequest = formula.InstalledOnRequest
+	if finding.LatestVersion == "" && formula.HomebrewCoreVersion != nil {
+		finding.LatestVersion = *formula.HomebrewCoreVersion
+	}
+}
+
+// score returns a finding's CVSS score, or -1 without one so unscored findings sort last
+func score(f Finding) float64 {
+	if f.CVSSScore == nil {
+		return -1
+	}
+	return *f.CVSSScore
+}
+
+// advisoryURL returns a link to the public advisory for a vulnerability ID
+func advisoryURL(id string) string {
+	switch {
+	case strings.HasPrefix(id, "CVE-"):
+		return "https://nvd.nist.gov/vuln/detail/" + id
+	case strings.HasPrefix(id, "GHSA-"):
+		return "https://github.com/advisories/" + id
+	}
+	return "https://osv.dev/vulnerability/" + id
+}
@@ -0,0 +1,82 @@
+package vulnreport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// testReport builds a report from the default fake workspace
+func testReport(t *testing.T, enrich bool) *Report {
+	t.Helper()
+
+	server := workbrewtest.NewServer()
+	t.Cleanup(server.Close)
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+
+	var formulaeService formulae.FormulaeServiceInterface
+	if enrich {
+		formulaeService = wb.Formulae
+	}
+	report, err := Generate(context.Background(), wb.Vulnerabilities, formulaeService, &Options{Workspace: workbrewtest.DefaultWorkspace})
+	require.NoError(t, err)
+	return report
+}
+
+func TestGenerate(t *testing.T) {
+	report := testReport(t, true)
+
+	assert.Equal(t, workbrewtest.DefaultWorkspace, report.Workspace)
+	require.Len(t, report.Findings, 2)
+
+	// Highest score first
+	wget := report.Findings[0]
+	assert.Equal(t, "wget", wget.Formula)
+	assert.Equal(t, "CVE-2024-10524", wget.VulnerabilityID)
+	assert.Equal(t, vulnerabilities.SeverityCritical, wget.Severity())
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceMacBook}, wget.OutdatedDevices)
+	assert.Equal(t, "1.25.0", wget.LatestVersion)
+	assert.Equal(t, []string{"GPL-3.0-or-later"}, wget.Licenses)
+	assert.True(t, wget.InstalledOnRequest)
+
+	curl := report.Findings[1]
+	assert.Equal(t, "curl", curl.Formula)
+	assert.Equal(t, []string{workbrewtest.FixtureDeviceLinux, workbrewtest.FixtureDeviceMacBook}, curl.Devices)
+}
+
+func TestGenerate_WithoutFormulae(t *testing.T) {
+	report := testReport(t, false)
+
+	require.Len(t, report.Findings, 2)
+	assert.Empty(t, report.Findings[0].Licenses)
+	assert.Empty(t, report.Findings[0].Devices)
+	assert.Equal(t, "1.25.0", report.Findings[0].LatestVersion)
+}
+
+func TestBuild_MinSeverity(t *testing.T) {
+	vulns := vulnerabilities.VulnerabilitiesResponse{
+		{Formula: "a", Vulnerabilities: []vulnerabilities.VulnerabilityDetail{
+			{CleanID: "CVE-1", CVSSScore: ptr(9.8)},
+			{CleanID: "CVE-2", CVSSScore: ptr(5.0)},
+			{CleanID: "CVE-3"},
+		}},
+	}
+
+	all := Build(vulns, nil, nil)
+	assert.Len(t, all.Findings, 3)
+	assert.Equal(t, "CVE-3", all.Findings[2].VulnerabilityID, "unscored findings sort last")
+
+	high := Build(vulns, nil, &Options{MinSeverity: vulnerabilities.SeverityHigh})
+	require.Len(t, high.Findings, 1)
+	assert.Equal(t, "CVE-1", high.Findings[0].VulnerabilityID)
+}
@@ -0,0 +1,274 @@
+package vulnreport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+)
+
+// SARIF document constants
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// DefaultSARIFArtifactURI is the file results are attached to by default
+	DefaultSARIFArtifactURI = "Brewfile"
+)
+
+// SARIFOptions configures SARIF output
+type SARIFOptions struct {
+	// ArtifactURI is the file every result is attached to. Code-scanning
+	// dashboards such as GitHub's require a file location for each result.
+	// Defaults to DefaultSARIFArtifactURI.
+	ArtifactURI string
+
+	// Category distinguishes this analysis from others uploaded for the same
+	// repository, e.g. the workspace name. Defaults to the report's workspace.
+	Category string
+}
+
+// SARIFLog is a SARIF 2.1.0 log with a single run
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is the analysis run in a SARIFLog
+type SARIFRun struct {
+	Tool              SARIFTool               `json:"tool"`
+	AutomationDetails *SARIFAutomationDetails `json:"automationDetails,omitempty"`
+	Results           []SARIFResult           `json:"results"`
+	Invocations       []SARIFInvocation       `json:"invocations,omitempty"`
+}
+
+// SARIFTool describes the tool that produced the results
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component and its rules, one per vulnerability ID
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Version        string      `json:"version"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes one vulnerability
+type SARIFRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     SARIFMessage       `json:"shortDescription"`
+	HelpURI              string             `json:"helpUri"`
+	Help                 SARIFMessage       `json:"help"`
+	DefaultConfiguration SARIFConfiguration `json:"defaultConfiguration"`
+	Properties           SARIFRuleProps     `json:"properties"`
+}
+
+// SARIFConfiguration holds a rule's default level
+type SARIFConfiguration struct {
+	Level string `json:"level"`
+}
+
+// SARIFRuleProps holds rule properties understood by code-scanning dashboards
+type SARIFRuleProps struct {
+	SecuritySeverity string   `json:"security-severity,omitempty"`
+	Tags             []string `json:"tags"`
+}
+
+// SARIFResult is one finding
+type SARIFResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             SARIFMessage      `json:"message"`
+	Locations           []SARIFLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          map[string]any    `json:"properties,omitempty"`
+}
+
+// SARIFMessage is a plain-text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFLocation attaches a result to a file and names the affected formula
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// SARIFPhysicalLocation identifies a file
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifact `json:"artifactLocation"`
+}
+
+// SARIFArtifact is a file URI
+type SARIFArtifact struct {
+	URI string `json:"uri"`
+}
+
+// SARIFLogicalLocation identifies a formula
+type SARIFLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// SARIFAutomationDetails identifies the analysis category
+type SARIFAutomationDetails struct {
+	ID string `json:"id"`
+}
+
+// SARIFInvocation records when the report was generated
+type SARIFInvocation struct {
+	ExecutionSuccessful bool   `json:"executionSuccessful"`
+	EndTimeUTC          string `json:"endTimeUtc"`
+}
+
+// SARIF converts the report to a SARIF log. Each vulnerability ID becomes a
+// rule, and each finding a result whose level follows its CVSS rating:
+// critical and high are errors, medium is a warning, and the rest are notes.
+//
+// Parameters:
+//   - options: SARIF options; may be nil
+//
+// Returns:
+//   - *SARIFLog: The SARIF log
+func (r *Report) SARIF(options *SARIFOptions) *SARIFLog {
+	if options == nil {
+		options = &SARIFOptions{}
+	}
+	artifact := options.ArtifactURI
+	if artifact == "" {
+		artifact = DefaultSARIFArtifactURI
+	}
+	category := options.Category
+	if category == "" {
+		category = r.Workspace
+	}
+
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           "Workbrew",
+			InformationURI: "https://workbrew.com",
+			Version:        client.Version,
+			Rules:          []SARIFRule{},
+		}},
+		Results: []SARIFResult{},
+		Invocations: []SARIFInvocation{{
+			ExecutionSuccessful: true,
+			EndTimeUTC:          r.GeneratedAt.UTC().Format("2006-01-02T15:04:05Z"),
+		}},
+	}
+	if category != "" {
+		run.AutomationDetails = &SARIFAutomationDetails{ID: "workbrew/" + category + "/"}
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, finding := range r.Findings {
+		index, ok := ruleIndex[finding.VulnerabilityID]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[finding.VulnerabilityID] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule(&finding))
+		}
+
+		properties := map[string]any{"formula": finding.Formula}
+		if len(finding.OutdatedDevices) > 0 {
+			properties["outdated_devices"] = finding.OutdatedDevices
+		}
+		if finding.LatestVersion != "" {
+			properties["latest_version"] = finding.LatestVersion
+		}
+
+		run.Results = append(run.Results, SARIFResult{
+			RuleID:    finding.VulnerabilityID,
+			RuleIndex: index,
+			Level:     sarifLevel(finding.Severity()),
+			Message:   SARIFMessage{Text: findingMessage(&finding)},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: SARIFPhysicalLocation{ArtifactLocation: SARIFArtifact{URI: artifact}},
+				LogicalLocations: []SARIFLogicalLocation{{Name: finding.Formula, Kind: "package"}},
+			}},
+			PartialFingerprints: map[string]string{
+				"workbrewFinding/v1": finding.Formula + ":" + finding.VulnerabilityID,
+			},
+			Properties: properties,
+		})
+	}
+
+	return &SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs:    []SARIFRun{run},
+	}
+}
+
+// WriteSARIF writes the report as an indented SARIF document
+func (r *Report) WriteSARIF(w io.Writer, options *SARIFOptions) error {
+	return writeJSON(w, r.SARIF(options))
+}
+
+// sarifRule describes the vulnerability of a finding
+func sarifRule(finding *Finding) SARIFRule {
+	rule := SARIFRule{
+		ID:                   finding.VulnerabilityID,
+		Name:                 strings.ReplaceAll(finding.VulnerabilityID, "-", ""),
+		ShortDescription:     SARIFMessage{Text: "Vulnerable Homebrew package: " + finding.VulnerabilityID},
+		HelpURI:              advisoryURL(finding.VulnerabilityID),
+		Help:                 SARIFMessage{Text: "See " + advisoryURL(finding.VulnerabilityID)},
+		DefaultConfiguration: SARIFConfiguration{Level: sarifLevel(finding.Severity())},
+		Properties:           SARIFRuleProps{Tags: []string{"security", "vulnerability", "homebrew"}},
+	}
+	if finding.CVSSScore != nil {
+		rule.Properties.SecuritySeverity = strconv.FormatFloat(*finding.CVSSScore, 'f', 1, 64)
+	}
+	return rule
+}
+
+// sarifLevel maps a CVSS rating to a SARIF result level
+func sarifLevel(severity vulnerabilities.Severity) string {
+	switch {
+	case severity >= vulnerabilities.SeverityHigh:
+		return "error"
+	case severity == vulnerabilities.SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
+
+// findingMessage describes a finding and its remediation
+func findingMessage(finding *Finding) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s is affected by %s", finding.Formula, finding.VulnerabilityID)
+	if finding.CVSSScore != nil {
+		fmt.Fprintf(&b, " (CVSS %.1f, %s)", *finding.CVSSScore, finding.Severity())
+	}
+	if count := len(finding.OutdatedDevices); count > 0 {
+		fmt.Fprintf(&b, " on %d outdated device(s)", count)
+	}
+	b.WriteString(".")
+	if finding.LatestVersion != "" {
+		fmt.Fprintf(&b, " Upgrade to %s or later.", finding.LatestVersion)
+	}
+	if finding.Deprecated != nil {
+		fmt.Fprintf(&b, " The formula is deprecated: %s.", strings.TrimSuffix(*finding.Deprecated, "."))
+	}
+	return b.String()
+}
+
+// writeJSON writes v as indented JSON followed by a newline
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
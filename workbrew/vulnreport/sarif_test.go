@@ -0,0 +1,64 @@
+package vulnreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_SARIF(t *testing.T) {
+	log := testReport(t, true).SARIF(nil)
+
+	assert.Equal(t, SARIFVersion, log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, "workbrew/test-workspace/", run.AutomationDetails.ID)
+
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	rule := run.Tool.Driver.Rules[0]
+	assert.Equal(t, "CVE-2024-10524", rule.ID)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2024-10524", rule.HelpURI)
+	assert.Equal(t, "9.1", rule.Properties.SecuritySeverity)
+
+	require.Len(t, run.Results, 2)
+	result := run.Results[0]
+	assert.Equal(t, "error", result.Level)
+	assert.Equal(t, "wget is affected by CVE-2024-10524 (CVSS 9.1, Critical) on 1 outdated device(s). Upgrade to 1.25.0 or later.", result.Message.Text)
+	assert.Equal(t, DefaultSARIFArtifactURI, result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "wget", result.Locations[0].LogicalLocations[0].Name)
+	assert.Equal(t, "wget:CVE-2024-10524", result.PartialFingerprints["workbrewFinding/v1"])
+	assert.Equal(t, "warning", run.Results[1].Level)
+}
+
+func TestReport_SARIF_SharedRule(t *testing.T) {
+	vulns := vulnerabilities.VulnerabilitiesResponse{
+		{Formula: "a", Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-1"}}},
+		{Formula: "b", Vulnerabilities: []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-1"}}},
+	}
+	run := Build(vulns, nil, nil).SARIF(&SARIFOptions{ArtifactURI: "infra/Brewfile"}).Runs[0]
+
+	assert.Len(t, run.Tool.Driver.Rules, 1)
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, 0, run.Results[1].RuleIndex)
+	assert.Equal(t, "note", run.Results[1].Level)
+	assert.Equal(t, "infra/Brewfile", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Nil(t, run.AutomationDetails)
+}
+
+func TestReport_WriteSARIF(t *testing.T) {
+	report := testReport(t, false)
+	report.GeneratedAt = time.Date(2025, 1, 6, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteSARIF(&buf, nil))
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Equal(t, SARIFSchema, document["$schema"])
+	assert.Contains(t, buf.String(), `"endTimeUtc": "2025-01-06T12:00:00Z"`)
+}
@@ -0,0 +1,109 @@
+package vulnwatch
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+)
+
+// ChangeSeverity returns the severity of a vulnerability change, from its
+// CVSSSeverity or, when that is missing, its CVSSScore
+//
+// Returns:
+//   - vulnerabilities.Severity: The change's severity
+//   - bool: False if the change has neither a severity nor a score
+func ChangeSeverity(change *vulnerabilitychanges.VulnerabilityChange) (vulnerabilities.Severity, bool) {
+	if change.CVSSSeverity != nil {
+		if severity, err := vulnerabilities.ParseSeverity(*change.CVSSSeverity); err == nil {
+			return severity, true
+		}
+	}
+	if change.CVSSScore != nil {
+		return vulnerabilities.SeverityForScore(*change.CVSSScore), true
+	}
+	return vulnerabilities.SeverityNone, false
+}
+
+// Filter selects the vulnerability changes a Watcher dispatches. A change must
+// pass every criterion that is set; the zero Filter passes everything.
+type Filter struct {
+	// Statuses restricts changes by status, e.g. vulnerabilitychanges.StatusDetected.
+	// Empty passes every status.
+	Statuses []string
+
+	// MinSeverity passes changes rated at least this severe
+	MinSeverity vulnerabilities.Severity
+
+	// MinScore passes changes with at least this CVSS score
+	MinScore float64
+
+	// IncludeUnrated passes changes without a severity or score when
+	// MinSeverity or MinScore is set. By default they are dropped.
+	IncludeUnrated bool
+
+	// AllowFormulae passes only formulae matching one of these path.Match
+	// patterns, e.g. "openssl@*". Empty passes every formula.
+	AllowFormulae []string
+
+	// DenyFormulae drops formulae matching any of these path.Match patterns.
+	// It takes precedence over AllowFormulae.
+	DenyFormulae []string
+}
+
+// Validate checks the formula patterns
+func (f *Filter) Validate() error {
+	for _, pattern := range slices.Concat(f.AllowFormulae, f.DenyFormulae) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid formula pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Match reports whether a change passes the filter
+func (f *Filter) Match(change *vulnerabilitychanges.VulnerabilityChange) bool {
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, change.Status) {
+		return false
+	}
+
+	if matchesAny(f.DenyFormulae, change.FormulaName) {
+		return false
+	}
+	if len(f.AllowFormulae) > 0 && !matchesAny(f.AllowFormulae, change.FormulaName) {
+		return false
+	}
+
+	if f.MinSeverity > vulnerabilities.SeverityNone {
+		severity, rated := ChangeSeverity(change)
+		if !rated {
+			return f.IncludeUnrated
+		}
+		if severity < f.MinSeverity {
+			return false
+		}
+	}
+
+	if f.MinScore > 0 {
+		if change.CVSSScore == nil {
+			return f.IncludeUnrated
+		}
+		if *change.CVSSScore < f.MinScore {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesAny reports whether name matches any of the path.Match patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package vulnwatch
+
+import (
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/stretchr/testify/assert"
+)
+
+func change(formula, status string, severity *string, score *float64) *vulnerabilitychanges.VulnerabilityChange {
+	return &vulnerabilitychanges.VulnerabilityChange{
+		FormulaName:  formula,
+		Status:       status,
+		CVSSSeverity: severity,
+		CVSSScore:    score,
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestFilter_Match(t *testing.T) {
+	critical := change("wget", "detected", ptr("Critical"), ptr(9.1))
+	medium := change("curl", "detected", ptr("Medium"), ptr(6.5))
+	scoreOnly := change("openssl@3", "detected", nil, ptr(7.5))
+	unrated := change("git", "detected", nil, nil)
+	fixed := change("wget", "fixed", ptr("Critical"), ptr(9.1))
+
+	tests := []struct {
+		name   string
+		filter Filter
+		change *vulnerabilitychanges.VulnerabilityChange
+		want   bool
+	}{
+		{"zero filter passes everything", Filter{}, unrated, true},
+		{"status match", Filter{Statuses: []string{"detected"}}, critical, true},
+		{"status mismatch", Filter{Statuses: []string{"detected"}}, fixed, false},
+		{"severity at threshold", Filter{MinSeverity: vulnerabilities.SeverityCritical}, critical, true},
+		{"severity below threshold", Filter{MinSeverity: vulnerabilities.SeverityHigh}, medium, false},
+		{"severity derived from score", Filter{MinSeverity: vulnerabilities.SeverityHigh}, scoreOnly, true},
+		{"unrated dropped by severity", Filter{MinSeverity: vulnerabilities.SeverityLow}, unrated, false},
+		{"unrated included", Filter{MinSeverity: vulnerabilities.SeverityLow, IncludeUnrated: true}, unrated, true},
+		{"score at threshold", Filter{MinScore: 9.1}, critical, true},
+		{"score below threshold", Filter{MinScore: 7}, medium, false},
+		{"unrated dropped by score", Filter{MinScore: 1}, unrated, false},
+		{"both thresholds must pass", Filter{MinSeverity: vulnerabilities.SeverityHigh, MinScore: 8}, scoreOnly, false},
+		{"allow list match", Filter{AllowFormulae: []string{"openssl@*"}}, scoreOnly, true},
+		{"allow list mismatch", Filter{AllowFormulae: []string{"openssl@*"}}, critical, false},
+		{"deny list", Filter{DenyFormulae: []string{"wget"}}, critical, false},
+		{"deny beats allow", Filter{AllowFormulae: []string{"*"}, DenyFormulae: []string{"w*"}}, critical, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Match(tt.change))
+		})
+	}
+}
+
+func TestFilter_Validate(t *testing.T) {
+	assert.NoError(t, (&Filter{AllowFormulae: []string{"openssl@*"}}).Validate())
+	assert.ErrorContains(t, (&Filter{DenyFormulae: []string{"[a-"}}).Validate(), `invalid formula pattern "[a-"`)
+}
@@ -0,0 +1,185 @@
+package vulnwatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+)
+
+// Batch is the set of new vulnerability changes found by one check
+type Batch struct {
+	Workspace string                                     `json:"workspace,omitempty"`
+	CheckedAt time.Time                                  `json:"checked_at"`
+	Changes   []vulnerabilitychanges.VulnerabilityChange `json:"changes"`
+}
+
+// Sink receives batches of new vulnerability changes
+type Sink interface {
+	// Send delivers a batch. A returned error makes the Watcher retry the
+	// batch at its next check.
+	Send(ctx context.Context, batch *Batch) error
+}
+
+// SinkFunc adapts a function to the Sink interface
+type SinkFunc func(ctx context.Context, batch *Batch) error
+
+// Send calls f(ctx, batch)
+func (f SinkFunc) Send(ctx context.Context, batch *Batch) error {
+	return f(ctx, batch)
+}
+
+// WriterSink writes each change as a line of JSON, tagged with the workspace
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a sink that writes JSON lines to w
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// NewStdoutSink creates a sink that writes JSON lines to standard output
+func NewStdoutSink() *WriterSink {
+	return NewWriterSink(os.Stdout)
+}
+
+// Send implements Sink
+func (s *WriterSink) Send(ctx context.Context, batch *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return writeLines(s.w, batch)
+}
+
+// FileSink appends each change as a line of JSON to a file
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileSink creates a sink that appends JSON lines to the named file,
+// creating it if needed
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Send implements Sink
+func (s *FileSink) Send(ctx context.Context, batch *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open sink file: %w", err)
+	}
+	if err := writeLines(file, batch); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// line is one change written by WriterSink and FileSink
+type line struct {
+	Workspace string `json:"workspace,omitempty"`
+	vulnerabilitychanges.VulnerabilityChange
+}
+
+// writeLines writes each change in a batch as a line of JSON
+func writeLines(w io.Writer, batch *Batch) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, change := range batch.Changes {
+		if err := encoder.Encode(line{Workspace: batch.Workspace, VulnerabilityChange: change}); err != nil {
+			return fmt.Errorf("failed to encode vulnerability change: %w", err)
+		}
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write vulnerability changes: %w", err)
+	}
+	return nil
+}
+
+// WebhookSink posts each batch as JSON to a URL
+type WebhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// WebhookOption configures a WebhookSink
+type WebhookOption func(*WebhookSink)
+
+// WithWebhookHeader sets a header on every request, e.g. an authorization token
+func WithWebhookHeader(name, value string) WebhookOption {
+	return func(s *WebhookSink) {
+		s.headers[name] = value
+	}
+}
+
+// WithWebhookHTTPClient sets the HTTP client used to post batches
+func WithWebhookHTTPClient(client *http.Client) WebhookOption {
+	return func(s *WebhookSink) {
+		s.client = client
+	}
+}
+
+// NewWebhookSink creates a sink that posts each batch to url as a JSON Batch.
+// Any response other than 2xx is treated as a failed delivery.
+//
+// Parameters:
+//   - url: The webhook URL
+//   - options: Optional headers and HTTP client; the default client times out after 30 seconds
+//
+// Example:
+//
+//	sink := vulnwatch.NewWebhookSink("https://alerts.example.com/workbrew",
+//	    vulnwatch.WithWebhookHeader("Authorization", "Bearer "+token))
+func NewWebhookSink(url string, options ...WebhookOption) *WebhookSink {
+	s := &WebhookSink{
+		url:     url,
+		headers: make(map[string]string),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Send implements Sink
+func (s *WebhookSink) Send(ctx context.Context, batch *Batch) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode batch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+// Package vulnwatch alerts on new vulnerability changes. A Watcher polls the
+// vulnerability changes endpoint, keeps only the changes it has not seen
+// before, filters them by severity, score and formula, and dispatches them to
+// pluggable sinks.
+package vulnwatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/eventstream"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"go.uber.org/zap"
+)
+
+// Defaults used when Options leaves a field unset
+const (
+	DefaultInterval = 15 * time.Minute
+	DefaultLookback = 24 * time.Hour
+)
+
+// Options configures a Watcher
+type Options struct {
+	// Filter selects the changes dispatched to sinks. Changes that do not match
+	// are still recorded in the checkpoint, so they are not reconsidered later.
+	Filter Filter
+
+	// Sinks receive each batch of new, matching changes
+	Sinks []Sink
+
+	// Workspace labels batches, for sinks that receive changes from several workspaces
+	Workspace string
+
+	// Interval is the wait between checks in Run. Defaults to DefaultInterval.
+	Interval time.Duration
+
+	// Lookback is how far before the newest recorded change a change is still
+	// considered new, to catch changes that appear out of order. Defaults to
+	// DefaultLookback; set a negative value to disable.
+	Lookback time.Duration
+
+	// Checkpoints persists which changes have been handled, so a restarted
+	// watcher only alerts on changes since the last check. Nil keeps progress in memory only.
+	Checkpoints eventstream.CheckpointStore
+
+	// StartTime skips changes that occurred before it. Zero alerts on every
+	// existing change on the first check without a saved checkpoint.
+	StartTime time.Time
+
+	// Logger receives progress messages. Defaults to a no-op logger.
+	Logger *zap.Logger
+}
+
+// Watcher emits vulnerability changes that are new since the last check
+type Watcher struct {
+	service vulnerabilitychanges.VulnerabilityChangesServiceInterface
+	options Options
+
+	mu         sync.Mutex
+	checkpoint *eventstream.Checkpoint
+}
+
+// NewWatcher creates a watcher using the given vulnerability changes service
+//
+// Parameters:
+//   - service: The vulnerability changes service (e.g. client.VulnerabilityChanges)
+//   - options: Watcher options; nil uses defaults with no filter, no sinks and an in-memory checkpoint
+//
+// Example:
+//
+//	watcher := vulnwatch.NewWatcher(client.VulnerabilityChanges, &vulnwatch.Options{
+//	    Filter:      vulnwatch.Filter{Statuses: []string{vulnerabilitychanges.StatusDetected}, MinSeverity: vulnerabilities.SeverityCritical},
+//	    Sinks:       []vulnwatch.Sink{vulnwatch.NewStdoutSink()},
+//	    Checkpoints: eventstream.NewFileCheckpointStore("vulnerabilities.checkpoint.json"),
+//	})
+func NewWatcher(service vulnerabilitychanges.VulnerabilityChangesServiceInterface, options *Options) *Watcher {
+	w := &Watcher{service: service}
+	if options != nil {
+		w.options = *options
+	}
+	if w.options.Interval <= 0 {
+		w.options.Interval = DefaultInterval
+	}
+	if w.options.Lookback == 0 {
+		w.options.Lookback = DefaultLookback
+	}
+	w.options.Lookback = max(w.options.Lookback, 0)
+	if w.options.Logger == nil {
+		w.options.Logger = zap.NewNop()
+	}
+	return w
+}
+
+// Check fetches vulnerability changes once, dispatches the new ones that match
+// the filter to every sink, and returns them oldest first.
+//
+// The checkpoint only advances when every sink accepts the batch, so a failed
+// delivery is retried at the next check. Sinks that succeeded may then receive
+// the batch again.
+//
+// Parameters:
+//   - ctx: Context for the API call and sinks
+//
+// Returns:
+//   - []vulnerabilitychanges.VulnerabilityChange: The new, matching changes
+//   - error: Any error loading the checkpoint, calling the API, or from a sink
+func (w *Watcher) Check(ctx context.Context) ([]vulnerabilitychanges.VulnerabilityChange, error) {
+	if err := w.options.Filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	fresh, err := w.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []vulnerabilitychanges.VulnerabilityChange
+	for i := range fresh {
+		if w.options.Filter.Match(&fresh[i]) {
+			matched = append(matched, fresh[i])
+		}
+	}
+
+	if len(matched) > 0 {
+		batch := &Batch{
+			Workspace: w.options.Workspace,
+			CheckedAt: time.Now().UTC(),
+			Changes:   matched,
+		}
+		if err := w.dispatch(ctx, batch); err != nil {
+			return nil, err
+		}
+		w.options.Logger.Info("Dispatched vulnerability changes",
+			zap.Int("count", len(matched)),
+			zap.Int("new", len(fresh)))
+	}
+
+	if len(fresh) == 0 {
+		return matched, nil
+	}
+
+	w.mu.Lock()
+	for _, change := range fresh {
+		w.checkpoint.Record(change.ID, change.OccurredAt, w.options.Lookback)
+	}
+	w.mu.Unlock()
+
+	return matched, w.save()
+}
+
+// Run checks for new vulnerability changes every Interval until ctx is done.
+// Transient API errors (429, 5xx and network failures) and sink failures are
+// logged and retried at the next interval; other errors stop the watcher.
+//
+// Parameters:
+//   - ctx: Controls how long to watch
+//
+// Returns:
+//   - error: The error that stopped the watcher, or nil if ctx is done
+func (w *Watcher) Run(ctx context.Context) error {
+	if err := w.options.Filter.Validate(); err != nil {
+		return err
+	}
+	if err := w.load(); err != nil {
+		return err
+	}
+
+	for {
+		_, err := w.Check(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && !isRetryable(err):
+			return err
+		case err != nil:
+			w.options.Logger.Warn("Checking vulnerability changes failed, will retry", zap.Error(err))
+		}
+
+		timer := time.NewTimer(w.options.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// Checkpoint returns a copy of the current checkpoint, or nil before the first check
+func (w *Watcher) Checkpoint() *eventstream.Checkpoint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.checkpoint == nil {
+		return nil
+	}
+	copied := *w.checkpoint
+	copied.Seen = maps.Clone(w.checkpoint.Seen)
+	return &copied
+}
+
+// SinkError reports a batch that one or more sinks failed to deliver
+type SinkError struct {
+	Err error
+}
+
+// Error implements the error interface
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("failed to dispatch vulnerability changes: %v", e.Err)
+}
+
+// Unwrap returns the joined sink errors
+func (e *SinkError) Unwrap() error {
+	return e.Err
+}
+
+// isRetryable reports whether Run should retry after err
+func isRetryable(err error) bool {
+	var sinkErr *SinkError
+	if errors.As(err, &sinkErr) {
+		return true
+	}
+	return client.IsRetryableError(err)
+}
+
+// dispatch sends a batch to every sink, returning a SinkError if any fail
+func (w *Watcher) dispatch(ctx context.Context, batch *Batch) error {
+	var errs []error
+	for i, sink := range w.options.Sinks {
+		if err := sink.Send(ctx, batch); err != nil {
+			errs = append(errs, fmt.Errorf("sink %d: %w", i, err))
+		}
+	}
+	if len(errs) > 0 {
+		return &SinkError{Err: errors.Join(errs...)}
+	}
+	return nil
+}
+
+// fetch lists vulnerability changes and returns those not yet recorded, oldest first
+func (w *Watcher) fetch(ctx context.Context) ([]vulnerabilitychanges.VulnerabilityChange, error) {
+	if err := w.load(); err != nil {
+		return nil, err
+	}
+
+	// The API filters by a single status; with several, filter client-side
+	opts := &vulnerabilitychanges.RequestQueryOptions{}
+	if len(w.options.Filter.Statuses) == 1 {
+		opts.Status = w.options.Filter.Statuses[0]
+	}
+
+	response, _, err := w.service.ListVulnerabilityChanges(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vulnerability changes: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var fresh []vulnerabilitychanges.VulnerabilityChange
+	for _, change := range *response {
+		if change.OccurredAt.Before(w.options.StartTime) {
+			continue
+		}
+		if w.checkpoint.Accepts(change.ID, change.OccurredAt, w.options.Lookback) {
+			fresh = append(fresh, change)
+		}
+	}
+
+	slices.SortStableFunc(fresh, func(a, b vulnerabilitychanges.VulnerabilityChange) int {
+		if c := a.OccurredAt.Compare(b.OccurredAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return slices.CompactFunc(fresh, func(a, b vulnerabilitychanges.VulnerabilityChange) bool {
+		return a.ID == b.ID
+	}), nil
+}
+
+// load reads the saved checkpoint on first use
+func (w *Watcher) load() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.checkpoint != nil {
+		return nil
+	}
+
+	var checkpoint *eventstream.Checkpoint
+	if w.options.Checkpoints != nil {
+		var err error
+		if checkpoint, err = w.options.Checkpoints.Load(); err != nil {
+			return fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+	}
+	if checkpoint == nil {
+		checkpoint = eventstream.NewCheckpoint()
+	} else {
+		w.options.Logger.Info("Resuming from checkpoint", zap.Time("high_water", checkpoint.HighWater))
+	}
+
+	w.checkpoint = checkpoint
+	return nil
+}
+
+// save persists the checkpoint. It is a no-op without a CheckpointStore.
+func (w *Watcher) save() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.options.Checkpoints == nil {
+		return nil
+	}
+	if err := w.options.Checkpoints.Save(w.checkpoint); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
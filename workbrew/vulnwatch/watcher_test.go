@@ -0,0 +1,229 @@
+package vulnwatch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/eventstream"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// IDs of the default fixture's vulnerability changes
+const (
+	curlChangeID = "0b6f7c1e-3f5a-4a57-9d8e-2b1c9a7e0001"
+	wgetChangeID = "0b6f7c1e-3f5a-4a57-9d8e-2b1c9a7e0002"
+)
+
+func newTestServer(t *testing.T) (*workbrewtest.Server, *workbrew.Client) {
+	t.Helper()
+
+	server := workbrewtest.NewServer()
+	t.Cleanup(server.Close)
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	return server, wb
+}
+
+// addChange appends a vulnerability change to the fake workspace
+func addChange(t *testing.T, server *workbrewtest.Server, id, formula, severity string, occurredAt time.Time) {
+	t.Helper()
+	err := server.Update(workbrewtest.DefaultWorkspace, func(f *workbrewtest.Fixtures) {
+		f.VulnerabilityChanges = append(f.VulnerabilityChanges, vulnerabilitychanges.VulnerabilityChange{
+			ID:           id,
+			OccurredAt:   occurredAt,
+			Status:       vulnerabilitychanges.StatusDetected,
+			FormulaName:  formula,
+			CVSSSeverity: ptr(severity),
+		})
+	})
+	require.NoError(t, err)
+}
+
+func changeIDs(changes []vulnerabilitychanges.VulnerabilityChange) []string {
+	ids := make([]string, 0, len(changes))
+	for _, change := range changes {
+		ids = append(ids, change.ID)
+	}
+	return ids
+}
+
+// recordingSink remembers every batch it receives and fails while err is set
+type recordingSink struct {
+	mu      sync.Mutex
+	batches []*Batch
+	err     error
+}
+
+func (s *recordingSink) Send(ctx context.Context, batch *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func TestWatcher_Check(t *testing.T) {
+	server, wb := newTestServer(t)
+	sink := &recordingSink{}
+	watcher := NewWatcher(wb.VulnerabilityChanges, &Options{
+		Filter:    Filter{MinSeverity: vulnerabilities.SeverityCritical},
+		Sinks:     []Sink{sink},
+		Workspace: workbrewtest.DefaultWorkspace,
+	})
+	ctx := context.Background()
+
+	first, err := watcher.Check(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{wgetChangeID}, changeIDs(first))
+	require.Len(t, sink.batches, 1)
+	assert.Equal(t, workbrewtest.DefaultWorkspace, sink.batches[0].Workspace)
+
+	// Filtered-out changes are recorded too, so neither fixture change is reconsidered
+	assert.Contains(t, watcher.Checkpoint().Seen, wgetChangeID)
+	again, err := watcher.Check(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+	assert.Len(t, sink.batches, 1, "sinks are not called without new matching changes")
+
+	addChange(t, server, "new-critical", "openssl@3", "Critical", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+	addChange(t, server, "new-low", "git", "Low", time.Date(2025, 1, 6, 1, 0, 0, 0, time.UTC))
+	next, err := watcher.Check(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new-critical"}, changeIDs(next))
+}
+
+func TestWatcher_SinkFailureKeepsChangesPending(t *testing.T) {
+	_, wb := newTestServer(t)
+	failing := &recordingSink{err: errors.New("sink unavailable")}
+	watcher := NewWatcher(wb.VulnerabilityChanges, &Options{Sinks: []Sink{failing}})
+
+	_, err := watcher.Check(context.Background())
+	var sinkErr *SinkError
+	require.ErrorAs(t, err, &sinkErr)
+	assert.ErrorContains(t, err, "sink unavailable")
+	assert.Empty(t, watcher.Checkpoint().Seen)
+
+	failing.err = nil
+	got, err := watcher.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{curlChangeID, wgetChangeID}, changeIDs(got))
+}
+
+func TestWatcher_ResumesFromCheckpoint(t *testing.T) {
+	server, wb := newTestServer(t)
+	store := eventstream.NewFileCheckpointStore(filepath.Join(t.TempDir(), "vulnerabilities.checkpoint.json"))
+
+	_, err := NewWatcher(wb.VulnerabilityChanges, &Options{Checkpoints: store}).Check(context.Background())
+	require.NoError(t, err)
+
+	addChange(t, server, "after-restart", "wget", "High", time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC))
+	got, err := NewWatcher(wb.VulnerabilityChanges, &Options{Checkpoints: store}).Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"after-restart"}, changeIDs(got))
+}
+
+func TestWatcher_StartTime(t *testing.T) {
+	_, wb := newTestServer(t)
+	watcher := NewWatcher(wb.VulnerabilityChanges, &Options{StartTime: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)})
+
+	got, err := watcher.Check(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{wgetChangeID}, changeIDs(got))
+}
+
+func TestWatcher_RunStopsOnAuthError(t *testing.T) {
+	server, wb := newTestServer(t)
+	server.InjectFault(workbrewtest.Fault{Path: "/vulnerability_changes.json", StatusCode: 401})
+
+	err := NewWatcher(wb.VulnerabilityChanges, &Options{Interval: 5 * time.Millisecond}).Run(context.Background())
+	assert.ErrorContains(t, err, "failed to list vulnerability changes")
+}
+
+func TestWatcher_RunRetriesSinkFailures(t *testing.T) {
+	_, wb := newTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	attempts := 0
+	sink := SinkFunc(func(ctx context.Context, batch *Batch) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("temporarily down")
+		}
+		cancel()
+		return nil
+	})
+
+	watcher := NewWatcher(wb.VulnerabilityChanges, &Options{Sinks: []Sink{sink}, Interval: 5 * time.Millisecond})
+	require.NoError(t, watcher.Run(ctx))
+	assert.Equal(t, 2, attempts)
+	assert.Contains(t, watcher.Checkpoint().Seen, wgetChangeID)
+}
+
+func TestFileSink(t *testing.T) {
+	_, wb := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "alerts.ndjson")
+	watcher := NewWatcher(wb.VulnerabilityChanges, &Options{
+		Sinks:     []Sink{NewFileSink(path)},
+		Workspace: "acme",
+	})
+
+	_, err := watcher.Check(context.Background())
+	require.NoError(t, err)
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 2)
+	assert.Equal(t, "acme", lines[1]["workspace"])
+	assert.Equal(t, "CVE-2024-10524", lines[1]["vulnerability_id"])
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received Batch
+	var authorization string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+
+	sink := NewWebhookSink(webhook.URL, WithWebhookHeader("Authorization", "Bearer secret"))
+	batch := &Batch{Workspace: "acme", Changes: []vulnerabilitychanges.VulnerabilityChange{{ID: "a", FormulaName: "wget"}}}
+	require.NoError(t, sink.Send(context.Background(), batch))
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Equal(t, "acme", received.Workspace)
+	assert.Equal(t, []string{"a"}, changeIDs(received.Changes))
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	assert.ErrorContains(t, NewWebhookSink(failing.URL).Send(context.Background(), batch), "webhook returned 502 Bad Gateway")
+}
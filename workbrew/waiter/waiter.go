@@ -0,0 +1,444 @@
+package waiter
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"go.uber.org/zap"
+)
+
+// Default polling intervals
+const (
+	DefaultInitialInterval = 5 * time.Second
+	DefaultMaxInterval     = time.Minute
+	DefaultMultiplier      = 2.0
+)
+
+// Status is the state of a run on one device
+type Status string
+
+const (
+	// StatusPending means the device has no run yet, or its run has not started
+	StatusPending Status = "pending"
+	// StatusRunning means the device's run has started but not finished
+	StatusRunning Status = "running"
+	// StatusSucceeded means the device's run finished successfully
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed means the device's run finished unsuccessfully
+	StatusFailed Status = "failed"
+)
+
+// Finished reports whether the status is final
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed
+}
+
+// DeviceOutcome is the state of the latest run on one targeted device
+type DeviceOutcome struct {
+	Device     string     `json:"device"`
+	Status     Status     `json:"status"`
+	Output     string     `json:"output,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Result summarises the runs of a brew command or Brewfile on its targeted devices
+type Result struct {
+	Label   string          `json:"label"`
+	Devices []DeviceOutcome `json:"devices"` // Sorted by device
+	Polls   int             `json:"polls"`
+}
+
+// Done reports whether every targeted device has finished its run
+func (r *Result) Done() bool {
+	for _, outcome := range r.Devices {
+		if !outcome.Status.Finished() {
+			return false
+		}
+	}
+	return true
+}
+
+// Succeeded reports whether every targeted device finished its run successfully
+func (r *Result) Succeeded() bool {
+	return r.Count(StatusSucceeded) == len(r.Devices)
+}
+
+// Count returns the number of devices with the given status
+func (r *Result) Count(status Status) int {
+	count := 0
+	for _, outcome := range r.Devices {
+		if outcome.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed returns the outcomes of devices whose run failed
+func (r *Result) Failed() []DeviceOutcome {
+	var failed []DeviceOutcome
+	for _, outcome := range r.Devices {
+		if outcome.Status == StatusFailed {
+			failed = append(failed, outcome)
+		}
+	}
+	return failed
+}
+
+// Progress is reported after every poll
+type Progress struct {
+	Label string
+	Poll  int
+
+	// Finished and Total count the targeted devices
+	Finished int
+	Total    int
+
+	// Devices holds every targeted device's outcome, sorted by device
+	Devices []DeviceOutcome
+
+	// Changed holds the outcomes whose status changed since the previous poll.
+	// On the first poll it holds every device that is not pending.
+	Changed []DeviceOutcome
+}
+
+// Options configures how runs are waited for
+type Options struct {
+	// Devices are the targeted devices, as reported in the run's device field.
+	// When empty, the devices of the brew command or Brewfile are looked up once
+	// before polling starts.
+	Devices []string
+
+	// Since ignores runs created before this time, e.g. runs of a Brewfile from
+	// before its latest update. Zero considers every run.
+	Since time.Time
+
+	// InitialInterval is the wait before the second poll. Defaults to DefaultInitialInterval.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the wait between polls. Defaults to DefaultMaxInterval.
+	MaxInterval time.Duration
+
+	// Multiplier grows the interval after each poll. Defaults to DefaultMultiplier.
+	Multiplier float64
+
+	// OnProgress is called after every poll, from the waiting goroutine
+	OnProgress func(Progress)
+
+	// Logger receives progress messages. Defaults to a no-op logger.
+	Logger *zap.Logger
+}
+
+// run is a brew command or Brewfile run in a common form
+type run struct {
+	device     string
+	createdAt  time.Time
+	startedAt  devices.TimeOrStatus
+	finishedAt devices.TimeOrStatus
+	success    bool
+	output     string
+}
+
+// source lists the runs and targeted devices of one brew command or Brewfile
+type source struct {
+	label   string
+	runs    func(ctx context.Context) ([]run, error)
+	targets func(ctx context.Context) ([]string, error)
+}
+
+// WaitForBrewCommand polls a brew command's runs until every targeted device has
+// finished, the context is done, or the API returns a non-retryable error.
+//
+// Parameters:
+//   - ctx: Context for the API calls; its deadline bounds the wait
+//   - service: The brew commands service (e.g. client.BrewCommands)
+//   - label: The brew command label
+//   - options: Polling options; nil uses defaults
+//
+// Returns:
+//   - *Result: The outcome on each targeted device, also returned with an error
+//     when the wait is cut short
+//   - error: The context's error or an API error
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
+//	defer cancel()
+//
+//	result, err := waiter.WaitForBrewCommand(ctx, client.BrewCommands, "install-wget", &waiter.Options{
+//	    OnProgress: func(p waiter.Progress) { fmt.Printf("%d/%d finished\n", p.Finished, p.Total) },
+//	})
+func WaitForBrewCommand(ctx context.Context, service brewcommands.BrewCommandsServiceInterface, label string, options *Options) (*Result, error) {
+	if label == "" {
+		return nil, fmt.Errorf("brew command label is required")
+	}
+
+	src := source{
+		label: label,
+		runs: func(ctx context.Context) ([]run, error) {
+			response, _, err := service.ListBrewCommandRuns(ctx, label)
+			if err != nil {
+				return nil, err
+			}
+			runs := make([]run, 0, len(*response))
+			for _, r := range *response {
+				runs = append(runs, run{
+					device:     r.Device,
+					createdAt:  r.CreatedAt,
+					startedAt:  r.StartedAt,
+					finishedAt: r.FinishedAt,
+					success:    r.Success,
+					output:     r.Output,
+				})
+			}
+			return runs, nil
+		},
+		targets: func(ctx context.Context) ([]string, error) {
+			commands, _, err := service.ListBrewCommands(ctx)
+			if err != nil {
+				return nil, err
+			}
+			index := slices.IndexFunc(*commands, func(c brewcommands.BrewCommand) bool { return c.Label == label })
+			if index < 0 {
+				return nil, fmt.Errorf("brew command %q not found", label)
+			}
+			return (*commands)[index].Devices, nil
+		},
+	}
+
+	return wait(ctx, src, options)
+}
+
+// WaitForBrewfile polls a Brewfile's runs until every targeted device has
+// finished, the context is done, or the API returns a non-retryable error.
+// Set Options.Since to the time of the create or update being waited for, so that
+// runs of earlier versions of the Brewfile are ignored.
+//
+// Parameters:
+//   - ctx: Context for the API calls; its deadline bounds the wait
+//   - service: The brewfiles service (e.g. client.Brewfiles)
+//   - label: The Brewfile label
+//   - options: Polling options; nil uses defaults
+//
+// Returns:
+//   - *Result: The outcome on each targeted device, also returned with an error
+//     when the wait is cut short
+//   - error: The context's error or an API error
+//
+// Example:
+//
+//	updatedAt := time.Now()
+//	_, _, err := client.Brewfiles.UpdateBrewfile(ctx, "dev-tools", request)
+//	result, err := waiter.WaitForBrewfile(ctx, client.Brewfiles, "dev-tools", &waiter.Options{Since: updatedAt})
+func WaitForBrewfile(ctx context.Context, service brewfiles.BrewfilesServiceInterface, label string, options *Options) (*Result, error) {
+	if label == "" {
+		return nil, fmt.Errorf("brewfile label is required")
+	}
+
+	src := source{
+		label: label,
+		runs: func(ctx context.Context) ([]run, error) {
+			response, _, err := service.ListBrewfileRuns(ctx, label)
+			if err != nil {
+				return nil, err
+			}
+			runs := make([]run, 0, len(*response))
+			for _, r := range *response {
+				createdAt, _ := time.Parse(time.RFC3339, r.CreatedAt)
+				runs = append(runs, run{
+					device:     r.Device,
+					createdAt:  createdAt,
+					startedAt:  parseTimeOrStatus(r.StartedAt),
+					finishedAt: parseTimeOrStatus(r.FinishedAt),
+					success:    r.Success,
+					output:     r.Output,
+				})
+			}
+			return runs, nil
+		},
+		targets: func(ctx context.Context) ([]string, error) {
+			list, _, err := service.ListBrewfiles(ctx)
+			if err != nil {
+				return nil, err
+			}
+			index := slices.IndexFunc(*list, func(b brewfiles.Brewfile) bool { return b.Label == label })
+			if index < 0 {
+				return nil, fmt.Errorf("brewfile %q not found", label)
+			}
+			var targets []string
+			for _, device := range (*list)[index].Devices {
+				targets = append(targets, device.SerialNumber)
+			}
+			return targets, nil
+		},
+	}
+
+	return wait(ctx, src, options)
+}
+
+// wait polls a source with exponential backoff until every target has finished
+func wait(ctx context.Context, src source, options *Options) (*Result, error) {
+	var opts Options
+	if options != nil {
+		opts = *options
+	}
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = DefaultInitialInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = DefaultMaxInterval
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = DefaultMultiplier
+	}
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop()
+	}
+
+	targets := opts.Devices
+	if len(targets) == 0 {
+		var err error
+		if targets, err = src.targets(ctx); err != nil {
+			return nil, fmt.Errorf("failed to look up targeted devices: %w", err)
+		}
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("%s has no targeted devices", src.label)
+		}
+	}
+
+	result := &Result{Label: src.label, Devices: pendingOutcomes(targets)}
+	previous := make(map[string]Status, len(result.Devices))
+	interval := opts.InitialInterval
+
+	for {
+		runs, err := src.runs(ctx)
+		switch {
+		case err == nil:
+			result.Polls++
+			result.Devices = outcomes(targets, runs, opts.Since)
+			report(&opts, result, previous)
+			if result.Done() {
+				opts.Logger.Info("All runs finished",
+					zap.String("label", src.label),
+					zap.Int("succeeded", result.Count(StatusSucceeded)),
+					zap.Int("failed", result.Count(StatusFailed)))
+				return result, nil
+			}
+		case client.IsRetryableError(err):
+			opts.Logger.Warn("Polling runs failed, will retry",
+				zap.String("label", src.label),
+				zap.Error(err))
+		default:
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return result, fmt.Errorf("stopped waiting for %s: %w", src.label, ctxErr)
+			}
+			return result, fmt.Errorf("failed to list runs of %s: %w", src.label, err)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, fmt.Errorf("stopped waiting for %s: %w", src.label, ctx.Err())
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*opts.Multiplier), opts.MaxInterval)
+	}
+}
+
+// report calls OnProgress with the devices whose status changed since the previous poll
+func report(opts *Options, result *Result, previous map[string]Status) {
+	progress := Progress{
+		Label:   result.Label,
+		Poll:    result.Polls,
+		Total:   len(result.Devices),
+		Devices: slices.Clone(result.Devices),
+	}
+
+	for _, outcome := range result.Devices {
+		if outcome.Status.Finished() {
+			progress.Finished++
+		}
+		last, seen := previous[outcome.Device]
+		if last != outcome.Status && (seen || outcome.Status != StatusPending) {
+			progress.Changed = append(progress.Changed, outcome)
+			opts.Logger.Debug("Run status changed",
+				zap.String("label", result.Label),
+				zap.String("device", outcome.Device),
+				zap.String("status", string(outcome.Status)))
+		}
+		previous[outcome.Device] = outcome.Status
+	}
+
+	if opts.OnProgress != nil {
+		opts.OnProgress(progress)
+	}
+}
+
+// pendingOutcomes returns a pending outcome for each target, sorted by device
+func pendingOutcomes(targets []string) []DeviceOutcome {
+	sorted := slices.Clone(targets)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	result := make([]DeviceOutcome, 0, len(sorted))
+	for _, device := range sorted {
+		result = append(result, DeviceOutcome{Device: device, Status: StatusPending})
+	}
+	return result
+}
+
+// outcomes derives each target's outcome from its latest run created at or after since
+func outcomes(targets []string, runs []run, since time.Time) []DeviceOutcome {
+	latest := make(map[string]run)
+	for _, r := range runs {
+		if !since.IsZero() && !r.createdAt.IsZero() && r.createdAt.Before(since.Truncate(time.Second)) {
+			continue
+		}
+		if current, ok := latest[r.device]; !ok || !r.createdAt.Before(current.createdAt) {
+			latest[r.device] = r
+		}
+	}
+
+	result := pendingOutcomes(targets)
+	for i := range result {
+		r, ok := latest[result[i].Device]
+		if !ok {
+			continue
+		}
+		outcome := &result[i]
+		outcome.StartedAt = r.startedAt.Time
+		outcome.FinishedAt = r.finishedAt.Time
+		// Only a finish time means the run finished; "Not Started", "Not Finished",
+		// empty and unrecognised statuses leave it pending or running
+		switch {
+		case r.finishedAt.HasTime():
+			outcome.Status = StatusFailed
+			if r.success {
+				outcome.Status = StatusSucceeded
+			}
+			outcome.Output = r.output
+		case r.startedAt.HasTime():
+			outcome.Status = StatusRunning
+		}
+	}
+	return result
+}
+
+// parseTimeOrStatus converts a Brewfile run's string timestamp, which may be a
+// status such as "Not Finished", to a TimeOrStatus
+func parseTimeOrStatus(value string) devices.TimeOrStatus {
+	var t devices.TimeOrStatus
+	if err := t.UnmarshalJSON([]byte(value)); err != nil {
+		return devices.TimeOrStatus{Status: value}
+	}
+	return t
+}
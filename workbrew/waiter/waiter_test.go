@@ -0,0 +1,253 @@
+package waiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a server clock advanced by the test
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestServer serves runs that take a minute of fake time and fail on the Linux device
+func newTestServer(t *testing.T) (*fakeClock, *workbrew.Client) {
+	t.Helper()
+
+	clock := &fakeClock{now: time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)}
+	server := workbrewtest.NewServer(
+		workbrewtest.WithClock(clock.Now),
+		workbrewtest.WithRunDuration(time.Minute),
+		workbrewtest.WithRunResult(func(device, command string) (string, bool) {
+			if device == workbrewtest.FixtureDeviceLinux {
+				return "Error: No such keg", false
+			}
+			return "==> Installing", true
+		}),
+	)
+	t.Cleanup(server.Close)
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	return clock, wb
+}
+
+func TestWaitForBrewCommand(t *testing.T) {
+	clock, wb := newTestServer(t)
+	ctx := context.Background()
+
+	_, _, err := wb.BrewCommands.CreateBrewCommand(ctx, &brewcommands.CreateBrewCommandRequest{Arguments: "install wget"})
+	require.NoError(t, err)
+
+	var progress []Progress
+	result, err := WaitForBrewCommand(ctx, wb.BrewCommands, "install-wget", &Options{
+		InitialInterval: time.Millisecond,
+		OnProgress: func(p Progress) {
+			progress = append(progress, p)
+			if p.Poll == 2 {
+				clock.Advance(time.Minute)
+			}
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, result.Done())
+	assert.False(t, result.Succeeded())
+	assert.Equal(t, 3, result.Polls)
+	require.Len(t, result.Devices, 2)
+
+	linux, mac := result.Devices[0], result.Devices[1]
+	assert.Equal(t, workbrewtest.FixtureDeviceLinux, linux.Device)
+	assert.Equal(t, StatusFailed, linux.Status)
+	assert.Equal(t, "Error: No such keg", linux.Output)
+	assert.Equal(t, StatusSucceeded, mac.Status)
+	assert.Equal(t, "==> Installing", mac.Output)
+	require.NotNil(t, mac.FinishedAt)
+	assert.Equal(t, []DeviceOutcome{linux}, result.Failed())
+
+	require.Len(t, progress, 3)
+	assert.Equal(t, 0, progress[0].Finished)
+	assert.Len(t, progress[0].Changed, 2, "both devices are running on the first poll")
+	assert.Empty(t, progress[1].Changed)
+	assert.Equal(t, 2, progress[2].Finished)
+	assert.Equal(t, 2, progress[2].Total)
+	assert.Len(t, progress[2].Changed, 2)
+}
+
+func TestWaitForBrewfile_Since(t *testing.T) {
+	clock, wb := newTestServer(t)
+	ctx := context.Background()
+
+	update := func() time.Time {
+		updatedAt := clock.Now()
+		_, _, err := wb.Brewfiles.UpdateBrewfile(ctx, "my-brewfile", &brewfiles.UpdateBrewfileRequest{
+			Content:             `brew "wget"`,
+			DeviceSerialNumbers: ptr(workbrewtest.FixtureDeviceMacBook),
+		})
+		require.NoError(t, err)
+		return updatedAt
+	}
+
+	// The runs of the first update finish; the second update is still running
+	update()
+	clock.Advance(2 * time.Minute)
+	updatedAt := update()
+
+	result, err := WaitForBrewfile(ctx, wb.Brewfiles, "my-brewfile", &Options{
+		Since:           updatedAt,
+		InitialInterval: time.Millisecond,
+		OnProgress: func(p Progress) {
+			assert.Equal(t, []string{workbrewtest.FixtureDeviceMacBook}, []string{p.Devices[0].Device})
+			if p.Poll == 1 {
+				assert.Equal(t, StatusRunning, p.Devices[0].Status, "runs of the earlier update are ignored")
+				clock.Advance(time.Minute)
+			}
+		},
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Succeeded())
+	assert.Equal(t, 2, result.Polls)
+}
+
+func TestWaitForBrewCommand_Deadline(t *testing.T) {
+	_, wb := newTestServer(t)
+
+	_, _, err := wb.BrewCommands.CreateBrewCommand(context.Background(), &brewcommands.CreateBrewCommandRequest{
+		Arguments: "install wget",
+		DeviceIDs: ptr(workbrewtest.FixtureDeviceMacBook),
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	result, err := WaitForBrewCommand(ctx, wb.BrewCommands, "install-wget", &Options{InitialInterval: 5 * time.Millisecond})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// The partial result reports the run as still in progress
+	require.NotNil(t, result)
+	require.Len(t, result.Devices, 1)
+	assert.Equal(t, StatusRunning, result.Devices[0].Status)
+	assert.False(t, result.Done())
+}
+
+func TestWaitForBrewCommand_ExplicitDevices(t *testing.T) {
+	_, wb := newTestServer(t)
+	ctx := context.Background()
+
+	// The fixture's "outdated" command has finished runs on both devices;
+	// a device without a run stays pending
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	result, err := WaitForBrewCommand(ctx, wb.BrewCommands, "outdated", &Options{
+		Devices:         []string{workbrewtest.FixtureDeviceMacBook, "UNKNOWN"},
+		InitialInterval: 5 * time.Millisecond,
+	})
+	require.Error(t, err)
+	require.Len(t, result.Devices, 2)
+	assert.Equal(t, StatusSucceeded, result.Devices[0].Status)
+	assert.Equal(t, "UNKNOWN", result.Devices[1].Device)
+	assert.Equal(t, StatusPending, result.Devices[1].Status)
+}
+
+func TestWaitForBrewCommand_RetriesServerErrors(t *testing.T) {
+	server := workbrewtest.NewServer()
+	t.Cleanup(server.Close)
+	wb, err := server.NewClient(client.WithRetryCount(0))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, _, err = wb.BrewCommands.CreateBrewCommand(ctx, &brewcommands.CreateBrewCommandRequest{
+		Arguments: "install wget",
+		DeviceIDs: ptr(workbrewtest.FixtureDeviceMacBook),
+	})
+	require.NoError(t, err)
+
+	// 500 and 502 are not transient, but polling survives them like the retry policy does
+	server.InjectFault(workbrewtest.Fault{Path: "/brew_commands/install-wget/runs.json", StatusCode: 500, Times: 1})
+	server.InjectFault(workbrewtest.Fault{Path: "/brew_commands/install-wget/runs.json", StatusCode: 502, Times: 1})
+	result, err := WaitForBrewCommand(ctx, wb.BrewCommands, "install-wget", &Options{InitialInterval: time.Millisecond})
+	require.NoError(t, err)
+	assert.True(t, result.Succeeded())
+	assert.Equal(t, 1, result.Polls)
+
+	server.InjectFault(workbrewtest.Fault{Path: "/brew_commands/install-wget/runs.json", StatusCode: 403})
+	_, err = WaitForBrewCommand(ctx, wb.BrewCommands, "install-wget", &Options{InitialInterval: time.Millisecond})
+	assert.ErrorContains(t, err, "failed to list runs of")
+}
+
+func TestWaitForBrewCommand_NotFound(t *testing.T) {
+	_, wb := newTestServer(t)
+
+	_, err := WaitForBrewCommand(context.Background(), wb.BrewCommands, "missing", nil)
+	assert.ErrorContains(t, err, `brew command "missing" not found`)
+}
+
+func TestParseTimeOrStatus(t *testing.T) {
+	assert.True(t, parseTimeOrStatus("Not Finished").IsNotFinished())
+	assert.True(t, parseTimeOrStatus("2025-01-10T10:09:00Z").HasTime())
+	assert.Equal(t, "garbage", parseTimeOrStatus("garbage").Status)
+}
+
+func TestOutcomes_FinishedOnlyWithTime(t *testing.T) {
+	created := time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		startedAt  string
+		finishedAt string
+		success    bool
+		want       Status
+	}{
+		{name: "not started", startedAt: "Not Started", finishedAt: "Not Started", want: StatusPending},
+		{name: "not finished", startedAt: "2025-01-10T10:01:00Z", finishedAt: "Not Finished", want: StatusRunning},
+		{name: "empty", startedAt: "", finishedAt: "", want: StatusPending},
+		{name: "unknown status", startedAt: "2025-01-10T10:01:00Z", finishedAt: "Queued", want: StatusRunning},
+		{name: "never", startedAt: "Never", finishedAt: "Never", want: StatusPending},
+		{name: "failed", startedAt: "2025-01-10T10:01:00Z", finishedAt: "2025-01-10T10:02:00Z", want: StatusFailed},
+		{name: "succeeded", startedAt: "2025-01-10T10:01:00Z", finishedAt: "2025-01-10T10:02:00Z", success: true, want: StatusSucceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := outcomes([]string{"DEVICE1"}, []run{{
+				device:     "DEVICE1",
+				createdAt:  created,
+				startedAt:  parseTimeOrStatus(tt.startedAt),
+				finishedAt: parseTimeOrStatus(tt.finishedAt),
+				success:    tt.success,
+			}}, created)
+			require.Len(t, result, 1)
+			assert.Equal(t, tt.want, result[0].Status)
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
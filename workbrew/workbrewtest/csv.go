@@ -0,0 +1,102 @@
+package workbrewtest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+)
+
+// csvTimeLayout is the timestamp layout used by the API's CSV exports
+const csvTimeLayout = "2006-01-02 15:04:05 UTC"
+
+// writeCSV sends rows (a slice of model structs) as a CSV export with one
+// column per JSON field. download=1 adds an attachment Content-Disposition.
+func writeCSV(w http.ResponseWriter, r *http.Request, rows any) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	if r.URL.Query().Get("download") == "1" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(r.URL.Path)))
+	}
+	w.WriteHeader(http.StatusOK)
+
+	rv := reflect.ValueOf(rows)
+	rt := rv.Type().Elem()
+
+	var columns []int
+	var header []string
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		// Nested objects (event snapshots and changes) are not part of the CSV exports
+		if name == "" || name == "-" || field.Type.Kind() == reflect.Map {
+			continue
+		}
+		columns = append(columns, i)
+		header = append(header, name)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write(header)
+	for i := 0; i < rv.Len(); i++ {
+		row := rv.Index(i)
+		record := make([]string, len(columns))
+		for j, column := range columns {
+			record[j] = csvCell(row.Field(column))
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+}
+
+// csvCell formats a field the way the API's CSV exports do: lists are comma
+// separated, nulls are empty and timestamps use csvTimeLayout
+func csvCell(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.UTC().Format(csvTimeLayout)
+	case fmt.Stringer:
+		return value.String()
+	case brewfiles.BrewfileDevice:
+		return value.SerialNumber
+	case vulnerabilities.VulnerabilityDetail:
+		if value.CVSSScore == nil {
+			return value.CleanID
+		}
+		return fmt.Sprintf("%s (%s)", value.CleanID, strconv.FormatFloat(*value.CVSSScore, 'f', -1, 64))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = csvCell(v.Index(i))
+		}
+		return strings.Join(items, ", ")
+	}
+
+	data, _ := json.Marshal(v.Interface())
+	return string(data)
+}
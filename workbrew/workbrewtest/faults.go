@@ -0,0 +1,142 @@
+package workbrewtest
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+)
+
+// Fault makes matching requests fail instead of reaching the fake API.
+// Use it to exercise error handling, retries and rate limiting.
+type Fault struct {
+	// Method restricts the fault to one HTTP method. Empty matches any method.
+	Method string
+
+	// Path is a path.Match pattern relative to the workspace, e.g.
+	// "/brewfiles/*.json" or "/devices.*". Empty matches any path.
+	Path string
+
+	// Workspace restricts the fault to one workspace. Empty matches any workspace.
+	Workspace string
+
+	// StatusCode is the HTTP status returned, e.g. 401, 403, 422, 429 or 503.
+	StatusCode int
+
+	// Message and Errors form the JSON error body. A message matching the
+	// status code is used when Message is empty.
+	Message string
+	Errors  []string
+
+	// RetryAfter sets the Retry-After header. For 429 responses the quota
+	// headers are also set, reporting an exhausted quota that resets after RetryAfter.
+	RetryAfter time.Duration
+
+	// Delay is slept before responding, to exercise client timeouts.
+	Delay time.Duration
+
+	// Times is the number of requests the fault applies to. Zero applies it
+	// until ClearFaults is called.
+	Times int
+}
+
+// faultState tracks how often a fault has fired
+type faultState struct {
+	Fault
+	fired int
+}
+
+// matches reports whether the fault applies to a request
+func (f *faultState) matches(method, workspace, endpoint string) bool {
+	if f.Times > 0 && f.fired >= f.Times {
+		return false
+	}
+	if f.Method != "" && f.Method != method {
+		return false
+	}
+	if f.Workspace != "" && f.Workspace != workspace {
+		return false
+	}
+	if f.Path != "" {
+		if matched, err := path.Match(f.Path, endpoint); err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// InjectFault adds a fault. Faults are checked in the order they were added
+// and the first match is applied.
+//
+// Parameters:
+//   - fault: The failure to inject
+//
+// Example:
+//
+//	// Fail the next two device listings with 503, then recover
+//	server.InjectFault(workbrewtest.Fault{Path: "/devices.json", StatusCode: 503, Times: 2})
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &faultState{Fault: fault})
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// takeFault returns the first fault matching a request and counts it as fired.
+// Callers must hold s.mu.
+func (s *Server) takeFault(method, workspace, endpoint string) (Fault, bool) {
+	for _, fault := range s.faults {
+		if fault.matches(method, workspace, endpoint) {
+			fault.fired++
+			return fault.Fault, true
+		}
+	}
+	return Fault{}, false
+}
+
+// writeFault sends the response described by a fault
+func writeFault(w http.ResponseWriter, fault Fault) {
+	if fault.RetryAfter > 0 {
+		seconds := strconv.Itoa(int((fault.RetryAfter + time.Second - 1) / time.Second))
+		w.Header().Set(client.HeaderRetryAfter, seconds)
+		if fault.StatusCode == http.StatusTooManyRequests {
+			w.Header().Set(client.HeaderQuotaLimit, "1000")
+			w.Header().Set(client.HeaderQuotaRemaining, "0")
+			w.Header().Set(client.HeaderQuotaReset, seconds)
+		}
+	}
+
+	message := fault.Message
+	if message == "" {
+		message = defaultFaultMessage(fault.StatusCode)
+	}
+	writeError(w, fault.StatusCode, message, fault.Errors...)
+}
+
+// defaultFaultMessage returns an API-style message for a status code
+func defaultFaultMessage(statusCode int) string {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return "Authentication required or invalid API key"
+	case http.StatusForbidden:
+		return "You are not authorized to perform this action"
+	case http.StatusNotFound:
+		return "Not Found"
+	case http.StatusUnprocessableEntity:
+		return "Validation failed"
+	case http.StatusTooManyRequests:
+		return "Rate limit exceeded"
+	}
+	if text := http.StatusText(statusCode); text != "" {
+		return text
+	}
+	return "Injected fault"
+}
@@ -0,0 +1,287 @@
+package workbrewtest
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/analytics"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewconfigurations"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewtaps"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/casks"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/licenses"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/google/uuid"
+)
+
+// Fixtures is the state of a fake workspace. Every list endpoint serves the
+// matching field, and write endpoints modify it.
+type Fixtures struct {
+	Analytics            analytics.AnalyticsResponse                       `json:"analytics"`
+	BrewCommands         brewcommands.BrewCommandsResponse                 `json:"brew_commands"`
+	BrewCommandRuns      map[string]brewcommands.BrewCommandRunsResponse   `json:"brew_command_runs"` // keyed by brew command label
+	BrewConfigurations   brewconfigurations.BrewConfigurationsResponse     `json:"brew_configurations"`
+	Brewfiles            brewfiles.BrewfilesResponse                       `json:"brewfiles"`
+	BrewfileRuns         map[string]brewfiles.BrewfileRunsResponse         `json:"brewfile_runs"` // keyed by brewfile label
+	BrewTaps             brewtaps.BrewTapsResponse                         `json:"brew_taps"`
+	Casks                casks.CasksResponse                               `json:"casks"`
+	DeviceGroups         devicegroups.DeviceGroupsResponse                 `json:"device_groups"`
+	Devices              devices.DevicesResponse                           `json:"devices"`
+	Events               events.EventsResponse                             `json:"events"`
+	Formulae             formulae.FormulaeResponse                         `json:"formulae"`
+	Licenses             licenses.LicensesResponse                         `json:"licenses"`
+	Vulnerabilities      vulnerabilities.VulnerabilitiesResponse           `json:"vulnerabilities"`
+	VulnerabilityChanges vulnerabilitychanges.VulnerabilityChangesResponse `json:"vulnerability_changes"`
+
+	// FreeTier makes the workspace behave like one on the Free plan: the
+	// Brewfiles API and brew command creation return 403.
+	FreeTier bool `json:"free_tier"`
+}
+
+// clone returns a deep copy of the fixtures
+func (f *Fixtures) clone() *Fixtures {
+	data, err := json.Marshal(f)
+	if err != nil {
+		panic("workbrewtest: failed to copy fixtures: " + err.Error())
+	}
+
+	var copied Fixtures
+	if err := json.Unmarshal(data, &copied); err != nil {
+		panic("workbrewtest: failed to copy fixtures: " + err.Error())
+	}
+	if copied.BrewCommandRuns == nil {
+		copied.BrewCommandRuns = make(map[string]brewcommands.BrewCommandRunsResponse)
+	}
+	if copied.BrewfileRuns == nil {
+		copied.BrewfileRuns = make(map[string]brewfiles.BrewfileRunsResponse)
+	}
+	return &copied
+}
+
+// Serial numbers and IDs used by DefaultFixtures
+const (
+	FixtureDeviceMacBook   = "TC6R2DHVHG"
+	FixtureDeviceLinux     = "1234567890"
+	FixtureGroupAdmin      = "ddba0af6-bd3c-5abf-8311-e62dc6bd9fbc"
+	FixtureGroupAllDevices = "377d8aa2-64cd-56a6-8351-6163bcf7dca1"
+)
+
+// DeviceUUID returns the UUID the fake gives the device with a serial number.
+// The API lists devices by serial number only, while a brew command's
+// device_ids takes UUIDs; the fake accepts these UUIDs there.
+//
+// Example:
+//
+//	executor := rollout.NewExecutor(client.BrewCommands, &rollout.ExecuteOptions{
+//	    DeviceID: func(serial string) (string, error) { return workbrewtest.DeviceUUID(serial), nil },
+//	})
+func DeviceUUID(serial string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(serial)).String()
+}
+
+// DefaultFixtures returns a small, consistent workspace based on the examples
+// in the Workbrew API specification: two devices, two device groups, a
+// Brewfile, a brew command with runs, and vulnerable curl and wget formulae.
+//
+// Returns:
+//   - *Fixtures: A new copy that callers may modify freely
+func DefaultFixtures() *Fixtures {
+	seen := time.Date(2025, 1, 6, 9, 30, 0, 0, time.UTC)
+	commandRun := time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)
+	commandDone := commandRun.Add(2 * time.Minute)
+
+	return &Fixtures{
+		Analytics: analytics.AnalyticsResponse{
+			{Device: FixtureDeviceMacBook, Command: "brew install curl", LastRun: time.Date(2025, 1, 1, 12, 34, 56, 0, time.UTC), Count: 2},
+			{Device: FixtureDeviceMacBook, Command: "brew install wget", LastRun: time.Date(2025, 1, 3, 8, 22, 33, 0, time.UTC), Count: 1},
+		},
+		BrewCommands: brewcommands.BrewCommandsResponse{
+			{
+				Command:           "brew outdated",
+				Label:             "outdated",
+				LastUpdatedByUser: "mikemcquaid",
+				StartedAt:         devices.TimeOrStatus{Time: &commandRun},
+				FinishedAt:        devices.TimeOrStatus{Time: &commandDone},
+				Devices:           []string{FixtureDeviceMacBook, FixtureDeviceLinux},
+				RunCount:          1,
+			},
+		},
+		BrewCommandRuns: map[string]brewcommands.BrewCommandRunsResponse{
+			"outdated": {
+				{
+					Command:    "brew outdated",
+					Label:      "outdated",
+					Device:     FixtureDeviceMacBook,
+					CreatedAt:  commandRun,
+					UpdatedAt:  commandDone,
+					Success:    true,
+					Output:     "curl\nwget",
+					StartedAt:  devices.TimeOrStatus{Time: &commandRun},
+					FinishedAt: devices.TimeOrStatus{Time: &commandDone},
+				},
+				{
+					Command:    "brew outdated",
+					Label:      "outdated",
+					Device:     FixtureDeviceLinux,
+					CreatedAt:  commandRun,
+					UpdatedAt:  commandDone,
+					Success:    true,
+					Output:     "curl",
+					StartedAt:  devices.TimeOrStatus{Time: &commandRun},
+					FinishedAt: devices.TimeOrStatus{Time: &commandDone},
+				},
+			},
+		},
+		BrewConfigurations: brewconfigurations.BrewConfigurationsResponse{
+			{Key: "HOMEBREW_NO_ANALYTICS", Value: "1", LastUpdatedByUser: "mikemcquaid", DeviceGroup: "All Devices"},
+		},
+		Brewfiles: brewfiles.BrewfilesResponse{
+			{
+				Label:             "my-brewfile",
+				Slug:              "my-brewfile",
+				Content:           "brew \"wget\"",
+				LastUpdatedByUser: "onboarded",
+				StartedAt:         "Not Started",
+				FinishedAt:        "Not Finished",
+				Devices:           []brewfiles.BrewfileDevice{{SerialNumber: FixtureDeviceMacBook}},
+				RunCount:          0,
+			},
+		},
+		BrewfileRuns: map[string]brewfiles.BrewfileRunsResponse{},
+		BrewTaps: brewtaps.BrewTapsResponse{
+			{Tap: "homebrew/core", Devices: []string{FixtureDeviceMacBook, FixtureDeviceLinux}, FormulaeInstalled: 3, CasksInstalled: 0, AvailablePackages: ">=7000 Packages"},
+			{Tap: "homebrew/cask", Devices: []string{FixtureDeviceMacBook}, FormulaeInstalled: 0, CasksInstalled: 1, AvailablePackages: ">=7000 Packages"},
+		},
+		Casks: casks.CasksResponse{
+			{Name: "visual-studio-code", DisplayName: ptr("Microsoft Visual Studio Code"), Devices: []string{FixtureDeviceMacBook}, Outdated: true, HomebrewCaskVersion: ptr("1.96.2")},
+		},
+		DeviceGroups: devicegroups.DeviceGroupsResponse{
+			{ID: FixtureGroupAdmin, Name: "Admin", Devices: []string{FixtureDeviceMacBook}},
+			{ID: FixtureGroupAllDevices, Name: "All Devices", Devices: []string{FixtureDeviceMacBook, FixtureDeviceLinux}},
+		},
+		Devices: devices.DevicesResponse{
+			{
+				SerialNumber:        FixtureDeviceMacBook,
+				Groups:              []string{"Admin", "All Devices"},
+				MDMUserOrDeviceName: ptr("Mike's MacBook Pro"),
+				LastSeenAt:          devices.TimeOrNever{Time: &seen},
+				CommandLastRunAt:    devices.TimeOrNever{Time: &commandDone},
+				DeviceType:          "MacBook Pro",
+				OSVersion:           "macOS 15.2 (24C101)",
+				HomebrewPrefix:      "/opt/homebrew",
+				HomebrewVersion:     "4.4.15",
+				WorkbrewVersion:     "1.1.4",
+				FormulaeCount:       3,
+				CasksCount:          1,
+			},
+			{
+				SerialNumber:     FixtureDeviceLinux,
+				Groups:           []string{"All Devices"},
+				LastSeenAt:       devices.TimeOrNever{Time: &seen},
+				CommandLastRunAt: devices.TimeOrNever{Time: &commandDone},
+				DeviceType:       "Linux",
+				OSVersion:        "Ubuntu 22.04.5 LTS",
+				HomebrewPrefix:   "/home/linuxbrew/.linuxbrew",
+				HomebrewVersion:  "4.4.15",
+				WorkbrewVersion:  "1.1.4",
+				FormulaeCount:    1,
+			},
+		},
+		Events: events.EventsResponse{
+			{
+				ID:               "123e4567-e89b-12d3-a456-426614174000",
+				EventType:        "device.created",
+				OccurredAt:       time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC),
+				TargetID:         ptr("123e4567-e89b-12d3-a456-426614174001"),
+				TargetType:       ptr("Device"),
+				TargetIdentifier: ptr(FixtureDeviceMacBook),
+			},
+		},
+		Formulae: formulae.FormulaeResponse{
+			{
+				Name:                  "curl",
+				Devices:               []string{FixtureDeviceMacBook, FixtureDeviceLinux},
+				Outdated:              true,
+				InstalledAsDependency: true,
+				Vulnerabilities:       []string{"CVE-2024-2466"},
+				License:               &[]string{"curl"},
+				HomebrewCoreVersion:   ptr("8.11.1"),
+			},
+			{
+				Name:                "wget",
+				Devices:             []string{FixtureDeviceMacBook},
+				Outdated:            true,
+				InstalledOnRequest:  true,
+				Vulnerabilities:     []string{"CVE-2024-10524"},
+				License:             &[]string{"GPL-3.0-or-later"},
+				HomebrewCoreVersion: ptr("1.25.0"),
+			},
+			{
+				Name:                "actionlint",
+				Devices:             []string{FixtureDeviceMacBook},
+				InstalledOnRequest:  true,
+				Vulnerabilities:     []string{},
+				License:             &[]string{"MIT"},
+				HomebrewCoreVersion: ptr("1.7.7"),
+			},
+		},
+		Licenses: licenses.LicensesResponse{
+			{Name: "curl", DeviceCount: 2, FormulaCount: 1},
+			{Name: "GPL-3.0-or-later", DeviceCount: 1, FormulaCount: 1},
+			{Name: "MIT", DeviceCount: 1, FormulaCount: 1},
+		},
+		Vulnerabilities: vulnerabilities.VulnerabilitiesResponse{
+			{
+				Vulnerabilities:     []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-2024-2466", CVSSScore: ptr(6.5)}},
+				Formula:             "curl",
+				OutdatedDevices:     []string{FixtureDeviceMacBook, FixtureDeviceLinux},
+				Supported:           true,
+				HomebrewCoreVersion: "8.11.1",
+			},
+			{
+				Vulnerabilities:     []vulnerabilities.VulnerabilityDetail{{CleanID: "CVE-2024-10524", CVSSScore: ptr(9.1)}},
+				Formula:             "wget",
+				OutdatedDevices:     []string{FixtureDeviceMacBook},
+				Supported:           true,
+				HomebrewCoreVersion: "1.25.0",
+			},
+		},
+		VulnerabilityChanges: vulnerabilitychanges.VulnerabilityChangesResponse{
+			{
+				ID:                 "0b6f7c1e-3f5a-4a57-9d8e-2b1c9a7e0001",
+				EventType:          "vulnerability.detected",
+				OccurredAt:         time.Date(2025, 1, 4, 9, 15, 0, 0, time.UTC),
+				Status:             "detected",
+				DeviceSerialNumber: ptr(FixtureDeviceMacBook),
+				FormulaName:        "curl",
+				FormulaVersion:     "8.7.0",
+				VulnerabilityID:    "CVE-2024-2466",
+				CVSSSeverity:       ptr("Medium"),
+				CVSSScore:          ptr(6.5),
+			},
+			{
+				ID:                 "0b6f7c1e-3f5a-4a57-9d8e-2b1c9a7e0002",
+				EventType:          "vulnerability.detected",
+				OccurredAt:         time.Date(2025, 1, 5, 14, 0, 0, 0, time.UTC),
+				Status:             "detected",
+				DeviceSerialNumber: ptr(FixtureDeviceMacBook),
+				FormulaName:        "wget",
+				FormulaVersion:     "1.24.5",
+				VulnerabilityID:    "CVE-2024-10524",
+				CVSSSeverity:       ptr("Critical"),
+				CVSSScore:          ptr(9.1),
+			},
+		},
+	}
+}
+
+// ptr returns a pointer to v
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,514 @@
+package workbrewtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+)
+
+// endpointHandler serves an authenticated request for a workspace with the server locked
+type endpointHandler func(w http.ResponseWriter, r *http.Request, ws *workspace)
+
+// listFunc returns the rows served by a list endpoint, or an error response
+type listFunc func(ws *workspace, r *http.Request) (any, *apiError)
+
+// apiError is an error response returned by an endpoint
+type apiError struct {
+	statusCode int
+	message    string
+	errors     []string
+}
+
+// Error bodies documented by the API specification
+var (
+	errNotFound = &apiError{statusCode: http.StatusNotFound, message: "Not Found"}
+
+	errBrewfilesPlan = &apiError{
+		statusCode: http.StatusForbidden,
+		message:    "Brewfiles API is not available on your current plan.",
+		errors:     []string{"Brewfiles cannot be created or updated on a Workbrew Free subscription."},
+	}
+
+	errBrewCommandsPlan = &apiError{
+		statusCode: http.StatusForbidden,
+		message:    "An error occurred when trying to create Brew Command",
+		errors:     []string{"Please upgrade your plan to get access to Brew Commands."},
+	}
+)
+
+// write sends the error response
+func (e *apiError) write(w http.ResponseWriter) {
+	writeError(w, e.statusCode, e.message, e.errors...)
+}
+
+// validationError builds a 422 response
+func validationError(message string, errors ...string) *apiError {
+	return &apiError{statusCode: http.StatusUnprocessableEntity, message: message, errors: errors}
+}
+
+// routes registers every endpoint of the Workbrew API specification
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, handler endpointHandler) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			s.serveHTTP(w, r, handler)
+		})
+	}
+	list := func(resource string, rows listFunc) {
+		handle("GET /workspaces/{workspace}/"+resource+".json", func(w http.ResponseWriter, r *http.Request, ws *workspace) {
+			result, apiErr := rows(ws, r)
+			if apiErr != nil {
+				apiErr.write(w)
+				return
+			}
+			writeJSON(w, http.StatusOK, nonNilSlice(result))
+		})
+		handle("GET /workspaces/{workspace}/"+resource+".csv", func(w http.ResponseWriter, r *http.Request, ws *workspace) {
+			result, apiErr := rows(ws, r)
+			if apiErr != nil {
+				apiErr.write(w)
+				return
+			}
+			writeCSV(w, r, result)
+		})
+	}
+
+	list("analytics", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.Analytics, nil
+	})
+	list("brew_commands", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.BrewCommands, nil
+	})
+	list("brew_commands/{label}/runs", func(ws *workspace, r *http.Request) (any, *apiError) {
+		label := r.PathValue("label")
+		if !slices.ContainsFunc(ws.state.BrewCommands, func(c brewcommands.BrewCommand) bool { return c.Label == label }) {
+			return nil, errNotFound
+		}
+		return ws.state.BrewCommandRuns[label], nil
+	})
+	list("brew_configurations", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.BrewConfigurations, nil
+	})
+	list("brew_taps", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.BrewTaps, nil
+	})
+	list("brewfiles", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		if ws.state.FreeTier {
+			return nil, errBrewfilesPlan
+		}
+		return ws.state.Brewfiles, nil
+	})
+	list("brewfiles/{label}/runs", func(ws *workspace, r *http.Request) (any, *apiError) {
+		if ws.state.FreeTier {
+			return nil, errBrewfilesPlan
+		}
+		label := r.PathValue("label")
+		if findBrewfile(ws.state, label) < 0 {
+			return nil, errNotFound
+		}
+		return ws.state.BrewfileRuns[label], nil
+	})
+	list("casks", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.Casks, nil
+	})
+	list("device_groups", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.DeviceGroups, nil
+	})
+	list("devices", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.Devices, nil
+	})
+	list("events", listEvents)
+	list("formulae", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.Formulae, nil
+	})
+	list("licenses", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.Licenses, nil
+	})
+	list("vulnerabilities", func(ws *workspace, _ *http.Request) (any, *apiError) {
+		return ws.state.Vulnerabilities, nil
+	})
+	list("vulnerability_changes", listVulnerabilityChanges)
+
+	handle("POST /workspaces/{workspace}/brew_commands.json", s.createBrewCommand)
+	handle("POST /workspaces/{workspace}/brewfiles.json", s.createBrewfile)
+	handle("PUT /workspaces/{workspace}/brewfiles/{file}", s.updateBrewfile)
+	handle("DELETE /workspaces/{workspace}/brewfiles/{file}", s.deleteBrewfile)
+
+	return mux
+}
+
+// listEvents serves the audit log, filtered by actor type
+func listEvents(ws *workspace, r *http.Request) (any, *apiError) {
+	filter := r.URL.Query().Get("filter")
+	if filter == "" || filter == "all" {
+		return ws.state.Events, nil
+	}
+
+	result := events.EventsResponse{}
+	for _, event := range ws.state.Events {
+		byUser := event.ActorType != nil && *event.ActorType == "User"
+		if (filter == "user") == byUser {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+// listVulnerabilityChanges serves vulnerability changes filtered by status and a search query
+// matching the formula name, version, vulnerability ID or device
+func listVulnerabilityChanges(ws *workspace, r *http.Request) (any, *apiError) {
+	status := r.URL.Query().Get("status")
+	query := strings.ToLower(r.URL.Query().Get("query"))
+
+	result := vulnerabilitychanges.VulnerabilityChangesResponse{}
+	for _, change := range ws.state.VulnerabilityChanges {
+		if status != "" && change.Status != status {
+			continue
+		}
+		if query != "" {
+			fields := []string{change.FormulaName, change.FormulaVersion, change.VulnerabilityID}
+			if change.DeviceSerialNumber != nil {
+				fields = append(fields, *change.DeviceSerialNumber)
+			}
+			if !slices.ContainsFunc(fields, func(field string) bool { return strings.Contains(strings.ToLower(field), query) }) {
+				continue
+			}
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+// createBrewCommand handles POST /brew_commands.json
+func (s *Server) createBrewCommand(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	if ws.state.FreeTier {
+		errBrewCommandsPlan.write(w)
+		return
+	}
+
+	const failure = "An error occurred when trying to create Brew Command"
+
+	var request brewcommands.CreateBrewCommandRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		validationError(failure, "Request body is not valid JSON").write(w)
+		return
+	}
+
+	var problems []string
+	arguments := strings.TrimSpace(request.Arguments)
+	if arguments == "" {
+		problems = append(problems, "Arguments can't be blank")
+	}
+	for _, operator := range []string{"&&", "||", ";", "|"} {
+		if strings.Contains(arguments, operator) {
+			problems = append(problems, fmt.Sprintf("Arguments cannot include `%s`", operator))
+			break
+		}
+	}
+	if request.Recurrence != nil && !slices.Contains([]string{"once", "daily", "weekly", "monthly"}, *request.Recurrence) {
+		problems = append(problems, "Recurrence is not included in the list")
+	}
+
+	startAt := s.clock()
+	if request.RunAfterDatetime != nil && *request.RunAfterDatetime != "" {
+		runAfter, err := parseRunAfter(*request.RunAfterDatetime)
+		if err != nil {
+			problems = append(problems, "Run after datetime is invalid")
+		} else if runAfter.After(startAt) {
+			startAt = runAfter
+		}
+	}
+
+	targets := ws.allDevices()
+	if request.DeviceIDs != nil && *request.DeviceIDs != "" {
+		resolved, err := ws.resolveDeviceIDs(splitList(*request.DeviceIDs))
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+		targets = resolved
+	}
+
+	if len(problems) > 0 {
+		validationError(failure, problems...).write(w)
+		return
+	}
+
+	label := uniqueLabel(slugify(arguments), func(label string) bool {
+		return slices.ContainsFunc(ws.state.BrewCommands, func(c brewcommands.BrewCommand) bool { return c.Label == label })
+	})
+	command := "brew " + arguments
+
+	ws.state.BrewCommands = append(ws.state.BrewCommands, brewcommands.BrewCommand{
+		Command:           command,
+		Label:             label,
+		LastUpdatedByUser: DefaultUser,
+		StartedAt:         devices.TimeOrStatus{Status: notStarted},
+		FinishedAt:        devices.TimeOrStatus{Status: notFinished},
+		Devices:           targets,
+		RunCount:          1,
+	})
+	s.recordEvent(ws, "brew_command.created", "BrewCommand", label, nil, nil)
+	s.startRuns(ws, label, "", command, targets, startAt)
+
+	writeMessage(w, http.StatusCreated, "Brew Command was successfully created.")
+}
+
+// createBrewfile handles POST /brewfiles.json
+func (s *Server) createBrewfile(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	if ws.state.FreeTier {
+		errBrewfilesPlan.write(w)
+		return
+	}
+
+	const failure = "An error occurred when trying to create Brewfile"
+
+	var request brewfiles.CreateBrewfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		validationError(failure, "Request body is not valid JSON").write(w)
+		return
+	}
+
+	var problems []string
+	label := strings.TrimSpace(request.Label)
+	if label == "" {
+		problems = append(problems, "Label can't be blank")
+	} else if findBrewfile(ws.state, label) >= 0 {
+		problems = append(problems, "Label has already been taken")
+	}
+	problems = append(problems, validateBrewfileContent(request.Content)...)
+
+	targets, err := ws.brewfileTargets(request.DeviceSerialNumbers, request.DeviceGroupID)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		validationError(failure, problems...).write(w)
+		return
+	}
+
+	ws.state.Brewfiles = append(ws.state.Brewfiles, brewfiles.Brewfile{
+		Label:             label,
+		Slug:              slugify(label),
+		Content:           request.Content,
+		LastUpdatedByUser: DefaultUser,
+		StartedAt:         notStarted,
+		FinishedAt:        notFinished,
+		Devices:           brewfileDevices(targets),
+	})
+	s.recordEvent(ws, "brewfile.created", "Brewfile", label, nil, nil)
+	s.runBrewfile(ws, label, targets)
+
+	writeMessage(w, http.StatusCreated, "Brewfile was successfully created.")
+}
+
+// updateBrewfile handles PUT /brewfiles/{label}.json
+func (s *Server) updateBrewfile(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	if ws.state.FreeTier {
+		errBrewfilesPlan.write(w)
+		return
+	}
+
+	label, ok := strings.CutSuffix(r.PathValue("file"), ".json")
+	index := findBrewfile(ws.state, label)
+	if !ok || index < 0 {
+		errNotFound.write(w)
+		return
+	}
+
+	const failure = "An error occurred when trying to update Brewfile"
+
+	var request brewfiles.UpdateBrewfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		validationError(failure, "Request body is not valid JSON").write(w)
+		return
+	}
+
+	problems := validateBrewfileContent(request.Content)
+
+	brewfile := &ws.state.Brewfiles[index]
+	targets := brewfileSerials(brewfile.Devices)
+	if request.DeviceSerialNumbers != nil || request.DeviceGroupID != nil {
+		resolved, err := ws.brewfileTargets(request.DeviceSerialNumbers, request.DeviceGroupID)
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+		targets = resolved
+	}
+
+	if len(problems) > 0 {
+		validationError(failure, problems...).write(w)
+		return
+	}
+
+	changes := make(map[string]any)
+	if brewfile.Content != request.Content {
+		changes["content"] = []string{brewfile.Content, request.Content}
+	}
+	if previous := brewfileSerials(brewfile.Devices); !slices.Equal(previous, targets) {
+		changes["devices"] = []any{previous, targets}
+	}
+
+	brewfile.Content = request.Content
+	brewfile.Devices = brewfileDevices(targets)
+	brewfile.LastUpdatedByUser = DefaultUser
+
+	s.recordEvent(ws, "brewfile.updated", "Brewfile", label, changes, nil)
+	s.runBrewfile(ws, label, targets)
+
+	writeMessage(w, http.StatusOK, "Brewfile was successfully updated.")
+}
+
+// deleteBrewfile handles DELETE /brewfiles/{label}.json
+func (s *Server) deleteBrewfile(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	if ws.state.FreeTier {
+		errBrewfilesPlan.write(w)
+		return
+	}
+
+	label, ok := strings.CutSuffix(r.PathValue("file"), ".json")
+	index := findBrewfile(ws.state, label)
+	if !ok || index < 0 {
+		errNotFound.write(w)
+		return
+	}
+
+	deleted := ws.state.Brewfiles[index]
+	ws.state.Brewfiles = slices.Delete(ws.state.Brewfiles, index, index+1)
+	ws.dropRuns(label)
+
+	s.recordEvent(ws, "brewfile.deleted", "Brewfile", label, nil, map[string]any{
+		"label":   deleted.Label,
+		"content": deleted.Content,
+	})
+
+	writeMessage(w, http.StatusOK, "Brewfile was successfully destroyed.")
+}
+
+// runBrewfile starts a "brew bundle" run of a Brewfile on each targeted device
+func (s *Server) runBrewfile(ws *workspace, label string, targets []string) {
+	ws.batchStart[label] = len(ws.state.BrewfileRuns[label])
+	if len(targets) > 0 {
+		ws.state.Brewfiles[findBrewfile(ws.state, label)].RunCount++
+	}
+	s.startRuns(ws, "", label, "brew bundle", targets, s.clock())
+}
+
+// brewfileTargets resolves a Brewfile's device assignment. Without an
+// assignment the Brewfile is not run on any device.
+func (ws *workspace) brewfileTargets(serialNumbers, groupID *string) ([]string, error) {
+	hasSerials := serialNumbers != nil && *serialNumbers != ""
+	hasGroup := groupID != nil && *groupID != ""
+
+	switch {
+	case hasSerials && hasGroup:
+		return nil, fmt.Errorf("Only one of device_serial_numbers or device_group_id can be set")
+	case hasSerials:
+		return ws.resolveDevices(splitList(*serialNumbers))
+	case hasGroup:
+		index := slices.IndexFunc(ws.state.DeviceGroups, func(g devicegroups.DeviceGroup) bool { return g.ID == *groupID })
+		if index < 0 {
+			return nil, fmt.Errorf("Device group not found: %s", *groupID)
+		}
+		return slices.Clone(ws.state.DeviceGroups[index].Devices), nil
+	}
+	return nil, nil
+}
+
+// brewfileDirectives are the Brewfile entry types accepted by brew bundle
+var brewfileDirectives = []string{"brew", "cask", "tap", "mas", "vscode", "whalebrew", "go", "cargo", "uv", "flatpak", "cask_args"}
+
+// validateBrewfileContent checks that every line of a Brewfile is a known
+// directive, and that taps are named "user/repo"
+func validateBrewfileContent(content string) []string {
+	if strings.TrimSpace(content) == "" {
+		return []string{"Content can't be blank"}
+	}
+
+	var problems []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		directive, argument, _ := strings.Cut(line, " ")
+		valid := slices.Contains(brewfileDirectives, directive)
+		if valid && directive == "tap" {
+			name, _, _ := strings.Cut(strings.Trim(strings.TrimSpace(argument), `"'`), `"`)
+			valid = strings.Count(name, "/") == 1
+		}
+		if !valid {
+			problems = append(problems, "Brewfile has an invalid line: "+line)
+		}
+	}
+	return problems
+}
+
+// parseRunAfter parses the run_after_datetime field
+func parseRunAfter(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02T15:04", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid run_after_datetime %q", value)
+}
+
+// uniqueLabel appends a numeric suffix to label until taken reports it is free
+func uniqueLabel(label string, taken func(string) bool) string {
+	if label == "" {
+		label = "command"
+	}
+	candidate := label
+	for n := 2; taken(candidate); n++ {
+		candidate = fmt.Sprintf("%s-%d", label, n)
+	}
+	return candidate
+}
+
+// findBrewfile returns the index of the Brewfile with label, or -1
+func findBrewfile(state *Fixtures, label string) int {
+	return slices.IndexFunc(state.Brewfiles, func(b brewfiles.Brewfile) bool { return b.Label == label })
+}
+
+// brewfileDevices converts serial numbers to the Brewfile device list
+func brewfileDevices(serials []string) []brewfiles.BrewfileDevice {
+	result := make([]brewfiles.BrewfileDevice, 0, len(serials))
+	for _, serial := range serials {
+		result = append(result, brewfiles.BrewfileDevice{SerialNumber: serial})
+	}
+	return result
+}
+
+// brewfileSerials returns the serial numbers of a Brewfile's devices
+func brewfileSerials(assigned []brewfiles.BrewfileDevice) []string {
+	serials := make([]string, 0, len(assigned))
+	for _, device := range assigned {
+		serials = append(serials, device.SerialNumber)
+	}
+	return serials
+}
+
+// nonNilSlice replaces a nil slice with an empty one so it encodes as [] rather than null
+func nonNilSlice(v any) any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return reflect.MakeSlice(rv.Type(), 0, 0).Interface()
+	}
+	if v == nil {
+		return []any{}
+	}
+	return v
+}
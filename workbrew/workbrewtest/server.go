@@ -0,0 +1,359 @@
+package workbrewtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"go.uber.org/zap"
+)
+
+// Defaults used when the server is created without WithWorkspace
+const (
+	DefaultWorkspace = "test-workspace"
+	DefaultAPIKey    = "test-api-key"
+
+	// DefaultUser is reported as the user behind changes made through the fake API
+	DefaultUser = "workbrewtest"
+)
+
+// RunResultFunc decides the outcome of a brew command or Brewfile run on a device.
+// command is the full brew command (e.g. "brew install wget" or "brew bundle").
+type RunResultFunc func(device, command string) (output string, success bool)
+
+// Server is an in-memory fake of the Workbrew API served over HTTP.
+//
+// It implements every endpoint in the Workbrew API specification with stateful
+// behaviour: creating a Brewfile makes it appear in ListBrewfiles, creating a brew
+// command produces runs on the targeted devices, and writes are recorded as audit
+// events. Requests are authenticated with the workspace's API key, and failures
+// can be injected with InjectFault.
+//
+// Server is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	workspaces  map[string]*workspace
+	order       []string
+	faults      []*faultState
+	requests    []Request
+	now         func() time.Time
+	runDuration time.Duration
+	runResult   RunResultFunc
+}
+
+// Request is a request received by the fake server
+type Request struct {
+	Method    string
+	Workspace string
+	Path      string // Endpoint path relative to the workspace, e.g. "/brewfiles.json"
+	Query     url.Values
+	Body      []byte
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithWorkspace adds a workspace served under name and authenticated by apiKey.
+// When no WithWorkspace option is given, a single DefaultWorkspace using
+// DefaultAPIKey and DefaultFixtures is created.
+//
+// Parameters:
+//   - name: The workspace slug
+//   - apiKey: The API key accepted for the workspace
+//   - fixtures: The initial state (nil uses DefaultFixtures). The server keeps its own copy.
+func WithWorkspace(name, apiKey string, fixtures *Fixtures) Option {
+	return func(s *Server) {
+		if fixtures == nil {
+			fixtures = DefaultFixtures()
+		}
+		if _, exists := s.workspaces[name]; !exists {
+			s.order = append(s.order, name)
+		}
+		s.workspaces[name] = newWorkspace(name, apiKey, fixtures.clone())
+	}
+}
+
+// WithFixtures replaces the state of the DefaultWorkspace.
+func WithFixtures(fixtures *Fixtures) Option {
+	return WithWorkspace(DefaultWorkspace, DefaultAPIKey, fixtures)
+}
+
+// WithRunDuration makes brew command and Brewfile runs take d to finish.
+// Until then they report "Not Finished". By default runs finish immediately.
+func WithRunDuration(d time.Duration) Option {
+	return func(s *Server) {
+		s.runDuration = d
+	}
+}
+
+// WithRunResult sets how runs turn out on each device. By default every run
+// succeeds with empty output.
+func WithRunResult(fn RunResultFunc) Option {
+	return func(s *Server) {
+		s.runResult = fn
+	}
+}
+
+// WithClock sets the time source used for timestamps and run progress.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// NewServer starts a fake Workbrew API server. Call Close when done.
+//
+// Parameters:
+//   - options: Optional server configuration
+//
+// Returns:
+//   - *Server: A running server
+//
+// Example:
+//
+//	server := workbrewtest.NewServer()
+//	defer server.Close()
+//
+//	wb, err := server.NewClient()
+//	devices, _, err := wb.Devices.ListDevices(ctx)
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		workspaces: make(map[string]*workspace),
+		now:        time.Now,
+		runResult:  func(string, string) (string, bool) { return "", true },
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if len(s.workspaces) == 0 {
+		WithFixtures(nil)(s)
+	}
+
+	s.Server = httptest.NewServer(s.routes())
+	return s
+}
+
+// Workspaces returns the names of the served workspaces in the order they were added.
+func (s *Server) Workspaces() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.order...)
+}
+
+// APIKey returns the API key accepted for a workspace, or "" if it does not exist.
+func (s *Server) APIKey(workspaceName string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ws, ok := s.workspaces[workspaceName]; ok {
+		return ws.apiKey
+	}
+	return ""
+}
+
+// NewClient returns a Workbrew client for the first workspace served.
+// Logging is disabled unless a WithLogger option is given.
+//
+// Parameters:
+//   - options: Additional client options, applied after the server's base URL
+//
+// Returns:
+//   - *workbrew.Client: A client pointed at the fake server
+//   - error: Any error creating the client
+func (s *Server) NewClient(options ...client.ClientOption) (*workbrew.Client, error) {
+	return s.NewWorkspaceClient(s.Workspaces()[0], options...)
+}
+
+// NewWorkspaceClient returns a Workbrew client for the named workspace using its API key.
+//
+// Parameters:
+//   - workspaceName: The workspace to connect to
+//   - options: Additional client options, applied after the server's base URL
+//
+// Returns:
+//   - *workbrew.Client: A client pointed at the fake server
+//   - error: An error if the workspace does not exist or the client cannot be created
+func (s *Server) NewWorkspaceClient(workspaceName string, options ...client.ClientOption) (*workbrew.Client, error) {
+	apiKey := s.APIKey(workspaceName)
+	if apiKey == "" {
+		return nil, fmt.Errorf("workbrewtest: unknown workspace %q", workspaceName)
+	}
+
+	options = append([]client.ClientOption{
+		client.WithLogger(zap.NewNop()),
+		client.WithBaseURL(s.URL),
+	}, options...)
+	return workbrew.NewClient(apiKey, workspaceName, options...)
+}
+
+// Snapshot returns a copy of a workspace's current state.
+//
+// Parameters:
+//   - workspaceName: The workspace to read
+//
+// Returns:
+//   - *Fixtures: A deep copy of the state, or nil if the workspace does not exist
+func (s *Server) Snapshot(workspaceName string) *Fixtures {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws, ok := s.workspaces[workspaceName]
+	if !ok {
+		return nil
+	}
+	s.settleRuns(ws)
+	return ws.state.clone()
+}
+
+// Update modifies a workspace's state in place, e.g. to add a vulnerability
+// change between two calls of a watcher under test.
+//
+// Parameters:
+//   - workspaceName: The workspace to modify
+//   - fn: Called with the live state while the server is locked
+//
+// Returns:
+//   - error: An error if the workspace does not exist
+func (s *Server) Update(workspaceName string, fn func(*Fixtures)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws, ok := s.workspaces[workspaceName]
+	if !ok {
+		return fmt.Errorf("workbrewtest: unknown workspace %q", workspaceName)
+	}
+	s.settleRuns(ws)
+	fn(ws.state)
+	return nil
+}
+
+// Requests returns the requests received so far, including rejected ones.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests clears the request log.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// serveHTTP authenticates a request, applies faults and dispatches it to an
+// endpoint handler with the server locked
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request, handler endpointHandler) {
+	workspaceName := r.PathValue("workspace")
+	prefix := "/workspaces/" + workspaceName
+	endpoint := strings.TrimPrefix(r.URL.Path, prefix)
+
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method:    r.Method,
+		Workspace: workspaceName,
+		Path:      endpoint,
+		Query:     r.URL.Query(),
+		Body:      body,
+	})
+	fault, faulted := s.takeFault(r.Method, workspaceName, endpoint)
+	s.mu.Unlock()
+
+	if faulted {
+		if fault.Delay > 0 {
+			select {
+			case <-time.After(fault.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		writeFault(w, fault)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	apiKey, hasKey := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !hasKey || !s.knownAPIKey(apiKey) {
+		writeError(w, http.StatusUnauthorized, "Authentication required or invalid API key", "Invalid or missing authorization token")
+		return
+	}
+
+	if version := r.Header.Get(client.APIVersionHeader); version != "" && version != client.DefaultAPIVersion {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Unsupported API version %q", version))
+		return
+	}
+
+	ws, ok := s.workspaces[workspaceName]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Workspace not found")
+		return
+	}
+	if ws.apiKey != apiKey {
+		writeError(w, http.StatusForbidden, "You do not have access to this workspace")
+		return
+	}
+
+	s.settleRuns(ws)
+	handler(w, r, ws)
+}
+
+// knownAPIKey reports whether any workspace accepts apiKey. Callers must hold s.mu.
+func (s *Server) knownAPIKey(apiKey string) bool {
+	for _, ws := range s.workspaces {
+		if ws.apiKey == apiKey {
+			return true
+		}
+	}
+	return false
+}
+
+// clock returns the current time truncated to whole seconds, matching the API's timestamps
+func (s *Server) clock() time.Time {
+	return s.now().UTC().Truncate(time.Second)
+}
+
+// writeJSON sends v as a JSON response
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeMessage sends a {"message": ...} response
+func writeMessage(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]string{"message": message})
+}
+
+// writeError sends an error body in the format documented by the API
+func writeError(w http.ResponseWriter, statusCode int, message string, errors ...string) {
+	body := struct {
+		Message string   `json:"message"`
+		Errors  []string `json:"errors,omitempty"`
+	}{Message: message, Errors: errors}
+	writeJSON(w, statusCode, body)
+}
+
+// newID returns a random RFC 4122 version 4 UUID
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,374 @@
+package workbrewtest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/analytics"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewconfigurations"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewtaps"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/casks"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devicegroups"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/formulae"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/licenses"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilitychanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptrTo(s string) *string { return &s }
+
+func TestServer_ListEndpointsServeFixtures(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+	fixtures := DefaultFixtures()
+
+	devicesJSON, _, err := wb.Devices.ListDevices(ctx)
+	require.NoError(t, err)
+	assert.Len(t, *devicesJSON, len(fixtures.Devices))
+	assert.Equal(t, FixtureDeviceMacBook, (*devicesJSON)[0].SerialNumber)
+
+	// Every CSV export decodes back into the same number of rows as its JSON fixture
+	csvTests := []struct {
+		name   string
+		fetch  func() ([]byte, error)
+		decode func([]byte) (int, error)
+		want   int
+	}{
+		{"analytics", csvFetch(wb.Analytics.ListAnalyticsCSV, ctx), csvCount(analytics.DecodeAnalyticsCSV), len(fixtures.Analytics)},
+		{"brew commands", csvFetch(wb.BrewCommands.ListBrewCommandsCSV, ctx), csvCount(brewcommands.DecodeBrewCommandsCSV), len(fixtures.BrewCommands)},
+		{"brew configurations", csvFetch(wb.BrewConfigurations.ListBrewConfigurationsCSV, ctx), csvCount(brewconfigurations.DecodeBrewConfigurationsCSV), len(fixtures.BrewConfigurations)},
+		{"brewfiles", csvFetch(wb.Brewfiles.ListBrewfilesCSV, ctx), csvCount(brewfiles.DecodeBrewfilesCSV), len(fixtures.Brewfiles)},
+		{"brew taps", csvFetch(wb.BrewTaps.ListBrewTapsCSV, ctx), csvCount(brewtaps.DecodeBrewTapsCSV), len(fixtures.BrewTaps)},
+		{"casks", csvFetch(wb.Casks.ListCasksCSV, ctx), csvCount(casks.DecodeCasksCSV), len(fixtures.Casks)},
+		{"device groups", csvFetch(wb.DeviceGroups.ListDeviceGroupsCSV, ctx), csvCount(devicegroups.DecodeDeviceGroupsCSV), len(fixtures.DeviceGroups)},
+		{"devices", csvFetch(wb.Devices.ListDevicesCSV, ctx), csvCount(devices.DecodeDevicesCSV), len(fixtures.Devices)},
+		{"formulae", csvFetch(wb.Formulae.ListFormulaeCSV, ctx), csvCount(formulae.DecodeFormulaeCSV), len(fixtures.Formulae)},
+		{"licenses", csvFetch(wb.Licenses.ListLicensesCSV, ctx), csvCount(licenses.DecodeLicensesCSV), len(fixtures.Licenses)},
+		{"vulnerabilities", csvFetch(wb.Vulnerabilities.ListVulnerabilitiesCSV, ctx), csvCount(vulnerabilities.DecodeVulnerabilitiesCSV), len(fixtures.Vulnerabilities)},
+		{
+			"events",
+			func() ([]byte, error) { data, _, err := wb.Events.ListEventsCSV(ctx, nil); return data, err },
+			csvCount(events.DecodeEventsCSV),
+			len(fixtures.Events),
+		},
+		{
+			"vulnerability changes",
+			func() ([]byte, error) {
+				data, _, err := wb.VulnerabilityChanges.ListVulnerabilityChangesCSV(ctx, nil)
+				return data, err
+			},
+			csvCount(vulnerabilitychanges.DecodeVulnerabilityChangesCSV),
+			len(fixtures.VulnerabilityChanges),
+		},
+	}
+
+	for _, tt := range csvTests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.fetch()
+			require.NoError(t, err)
+			count, err := tt.decode(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, count)
+		})
+	}
+
+	vulns, err := vulnerabilities.DecodeVulnerabilitiesCSV(must(t, csvFetch(wb.Vulnerabilities.ListVulnerabilitiesCSV, ctx)))
+	require.NoError(t, err)
+	assert.Equal(t, fixtures.Vulnerabilities[0].Vulnerabilities, (*vulns)[0].Vulnerabilities)
+}
+
+// csvFetch adapts a List*CSV method to a fetch function
+func csvFetch(list func(context.Context) ([]byte, *interfaces.Response, error), ctx context.Context) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		data, _, err := list(ctx)
+		return data, err
+	}
+}
+
+// csvCount adapts a Decode*CSV function to return the number of rows
+func csvCount[T ~[]E, E any](decode func([]byte) (*T, error)) func([]byte) (int, error) {
+	return func(data []byte) (int, error) {
+		rows, err := decode(data)
+		if err != nil {
+			return 0, err
+		}
+		return len(*rows), nil
+	}
+}
+
+func must(t *testing.T, fetch func() ([]byte, error)) []byte {
+	t.Helper()
+	data, err := fetch()
+	require.NoError(t, err)
+	return data
+}
+
+func TestServer_BrewfileLifecycle(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	created, resp, err := wb.Brewfiles.CreateBrewfile(ctx, &brewfiles.CreateBrewfileRequest{
+		Label:         "dev-tools",
+		Content:       "brew \"go\"\nbrew \"jq\"",
+		DeviceGroupID: ptrTo(FixtureGroupAllDevices),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "Brewfile was successfully created.", created.Message)
+
+	list, _, err := wb.Brewfiles.ListBrewfiles(ctx)
+	require.NoError(t, err)
+	require.Len(t, *list, 2)
+	brewfile := (*list)[1]
+	assert.Equal(t, "dev-tools", brewfile.Label)
+	assert.Equal(t, DefaultUser, brewfile.LastUpdatedByUser)
+	assert.Len(t, brewfile.Devices, 2)
+	assert.Equal(t, 1, brewfile.RunCount)
+	assert.NotEqual(t, "Not Finished", brewfile.FinishedAt)
+
+	runs, _, err := wb.Brewfiles.ListBrewfileRuns(ctx, "dev-tools")
+	require.NoError(t, err)
+	assert.Len(t, *runs, 2)
+
+	_, _, err = wb.Brewfiles.UpdateBrewfile(ctx, "dev-tools", &brewfiles.UpdateBrewfileRequest{
+		Content:             "brew \"go\"",
+		DeviceSerialNumbers: ptrTo(FixtureDeviceLinux),
+	})
+	require.NoError(t, err)
+
+	runs, _, err = wb.Brewfiles.ListBrewfileRuns(ctx, "dev-tools")
+	require.NoError(t, err)
+	assert.Len(t, *runs, 3)
+
+	_, _, err = wb.Brewfiles.DeleteBrewfile(ctx, "dev-tools")
+	require.NoError(t, err)
+
+	list, _, err = wb.Brewfiles.ListBrewfiles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, *list, 1)
+
+	_, _, err = wb.Brewfiles.ListBrewfileRuns(ctx, "dev-tools")
+	assert.True(t, client.IsNotFound(err))
+
+	// Every change is recorded in the audit log
+	auditLog, _, err := wb.Events.ListEvents(ctx, &events.RequestQueryOptions{Filter: "user"})
+	require.NoError(t, err)
+	var types []string
+	for _, event := range *auditLog {
+		types = append(types, event.EventType)
+	}
+	assert.Equal(t, []string{"brewfile.created", "brewfile.updated", "brewfile.deleted"}, types)
+	assert.Contains(t, (*auditLog)[1].Changes, "content")
+	assert.Equal(t, "dev-tools", (*auditLog)[2].TargetSnapshot["label"])
+}
+
+func TestServer_BrewfileValidation(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, resp, err := wb.Brewfiles.CreateBrewfile(ctx, &brewfiles.CreateBrewfileRequest{
+		Label:   "my-brewfile",
+		Content: "tap \"foo/bar/baz\"",
+	})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	apiErr, ok := err.(*client.APIError)
+	require.True(t, ok)
+	assert.Contains(t, apiErr.Errors, "Label has already been taken")
+	assert.Contains(t, apiErr.Errors, "Brewfile has an invalid line: tap \"foo/bar/baz\"")
+
+	_, _, err = wb.Brewfiles.UpdateBrewfile(ctx, "missing", &brewfiles.UpdateBrewfileRequest{Content: "brew \"go\""})
+	assert.True(t, client.IsNotFound(err))
+}
+
+func TestServer_BrewCommandRunsProgress(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := NewServer(
+		WithClock(func() time.Time { return now }),
+		WithRunDuration(time.Minute),
+		WithRunResult(func(device, command string) (string, bool) {
+			return command + " on " + device, device != FixtureDeviceLinux
+		}),
+	)
+	defer server.Close()
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, resp, err := wb.BrewCommands.CreateBrewCommand(ctx, &brewcommands.CreateBrewCommandRequest{Arguments: "install wget"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	runs, _, err := wb.BrewCommands.ListBrewCommandRuns(ctx, "install-wget")
+	require.NoError(t, err)
+	require.Len(t, *runs, 2)
+	for _, run := range *runs {
+		assert.True(t, run.FinishedAt.IsNotFinished())
+	}
+
+	now = now.Add(time.Minute)
+
+	runs, _, err = wb.BrewCommands.ListBrewCommandRuns(ctx, "install-wget")
+	require.NoError(t, err)
+	assert.True(t, (*runs)[0].Success)
+	assert.Equal(t, "brew install wget on "+FixtureDeviceMacBook, (*runs)[0].Output)
+	assert.False(t, (*runs)[1].Success)
+
+	commands, _, err := wb.BrewCommands.ListBrewCommands(ctx)
+	require.NoError(t, err)
+	created := (*commands)[len(*commands)-1]
+	assert.Equal(t, "brew install wget", created.Command)
+	assert.True(t, created.FinishedAt.HasTime())
+
+	// Labels are unique and invalid arguments are rejected
+	_, _, err = wb.BrewCommands.CreateBrewCommand(ctx, &brewcommands.CreateBrewCommandRequest{Arguments: "install wget", DeviceIDs: ptrTo(FixtureDeviceMacBook)})
+	require.NoError(t, err)
+	snapshot := server.Snapshot(DefaultWorkspace)
+	assert.Len(t, snapshot.BrewCommandRuns["install-wget-2"], 1)
+
+	// device_ids also takes the UUIDs given by DeviceUUID
+	_, _, err = wb.BrewCommands.CreateBrewCommand(ctx, &brewcommands.CreateBrewCommandRequest{Arguments: "install wget", DeviceIDs: ptrTo(DeviceUUID(FixtureDeviceLinux))})
+	require.NoError(t, err)
+	snapshot = server.Snapshot(DefaultWorkspace)
+	assert.Equal(t, []string{FixtureDeviceLinux}, snapshot.BrewCommands[len(snapshot.BrewCommands)-1].Devices)
+
+	_, resp, err = wb.BrewCommands.CreateBrewCommand(ctx, &brewcommands.CreateBrewCommandRequest{Arguments: "install wget", DeviceIDs: ptrTo("UNKNOWN")})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.ErrorContains(t, err, "Devices not found: UNKNOWN")
+}
+
+func TestServer_Authentication(t *testing.T) {
+	server := NewServer(
+		WithWorkspace("alpha", "alpha-key", nil),
+		WithWorkspace("beta", "beta-key", &Fixtures{FreeTier: true}),
+	)
+	defer server.Close()
+	ctx := context.Background()
+
+	assert.Equal(t, []string{"alpha", "beta"}, server.Workspaces())
+
+	wrongKey, err := client.NewTransport("nope", "alpha", client.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	_, _, err = devices.NewService(wrongKey).ListDevices(ctx)
+	assert.True(t, client.IsUnauthorized(err))
+
+	otherWorkspace, err := client.NewTransport("beta-key", "alpha", client.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	_, _, err = devices.NewService(otherWorkspace).ListDevices(ctx)
+	assert.True(t, client.IsForbidden(err))
+
+	beta, err := server.NewWorkspaceClient("beta")
+	require.NoError(t, err)
+	_, _, err = beta.Brewfiles.ListBrewfiles(ctx)
+	assert.True(t, client.IsForbidden(err))
+	assert.True(t, client.IsFreeTierError(err))
+
+	empty, _, err := beta.Devices.ListDevices(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, *empty)
+	assert.Empty(t, *empty)
+
+	_, err = server.NewWorkspaceClient("gamma")
+	assert.Error(t, err)
+}
+
+func TestServer_Faults(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	wb, err := server.NewClient(client.WithRetryPolicy(&client.DefaultRetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	server.InjectFault(Fault{Path: "/devices.json", StatusCode: http.StatusServiceUnavailable, Times: 2})
+	_, resp, err := wb.Devices.ListDevices(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.Attempts)
+
+	server.InjectFault(Fault{Method: http.MethodPost, StatusCode: http.StatusTooManyRequests, RetryAfter: 30 * time.Second, Times: 1})
+	_, resp, err = wb.BrewCommands.CreateBrewCommand(ctx, &brewcommands.CreateBrewCommandRequest{Arguments: "update"})
+	require.Error(t, err)
+	assert.True(t, client.IsRateLimited(err))
+	assert.Equal(t, "30", resp.Headers.Get(client.HeaderRetryAfter))
+	assert.Equal(t, "0", resp.Headers.Get(client.HeaderQuotaRemaining))
+
+	server.InjectFault(Fault{Path: "/brewfiles/*.json", StatusCode: http.StatusUnprocessableEntity, Errors: []string{"nope"}})
+	_, _, err = wb.Brewfiles.UpdateBrewfile(ctx, "my-brewfile", &brewfiles.UpdateBrewfileRequest{Content: "brew \"go\""})
+	assert.True(t, client.IsValidationError(err))
+
+	server.ClearFaults()
+	_, _, err = wb.Brewfiles.UpdateBrewfile(ctx, "my-brewfile", &brewfiles.UpdateBrewfileRequest{Content: "brew \"go\""})
+	require.NoError(t, err)
+
+	var posts int
+	for _, request := range server.Requests() {
+		if request.Method == http.MethodPost {
+			posts++
+			assert.JSONEq(t, `{"arguments":"update"}`, string(request.Body))
+		}
+	}
+	assert.Equal(t, 1, posts, "rate limited POST is not retried")
+}
+
+func TestServer_QueryFilters(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	wb, err := server.NewClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, server.Update(DefaultWorkspace, func(f *Fixtures) {
+		f.VulnerabilityChanges = append(f.VulnerabilityChanges, vulnerabilitychanges.VulnerabilityChange{
+			ID:              "fixed-1",
+			EventType:       "vulnerability.fixed",
+			OccurredAt:      time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC),
+			Status:          "fixed",
+			FormulaName:     "curl",
+			FormulaVersion:  "8.11.1",
+			VulnerabilityID: "CVE-2024-2466",
+		})
+	}))
+
+	fixed, _, err := wb.VulnerabilityChanges.ListVulnerabilityChanges(ctx, &vulnerabilitychanges.RequestQueryOptions{Status: "fixed"})
+	require.NoError(t, err)
+	require.Len(t, *fixed, 1)
+	assert.Equal(t, "fixed-1", (*fixed)[0].ID)
+
+	curl, _, err := wb.VulnerabilityChanges.ListVulnerabilityChanges(ctx, &vulnerabilitychanges.RequestQueryOptions{Query: "CURL"})
+	require.NoError(t, err)
+	assert.Len(t, *curl, 2)
+
+	systemEvents, _, err := wb.Events.ListEvents(ctx, &events.RequestQueryOptions{Filter: "system"})
+	require.NoError(t, err)
+	assert.Len(t, *systemEvents, 1)
+
+	_, resp, err := wb.Events.ListEventsCSV(ctx, &events.RequestQueryOptions{Download: true})
+	require.NoError(t, err)
+	assert.Contains(t, resp.Headers.Get("Content-Disposition"), "events.csv")
+}
@@ -0,0 +1,357 @@
+package workbrewtest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewcommands"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/brewfiles"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/devices"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/events"
+)
+
+// Placeholder values the API reports for runs that have not started or finished
+const (
+	notStarted  = "Not Started"
+	notFinished = "Not Finished"
+)
+
+// workspace is the state of one fake workspace
+type workspace struct {
+	name   string
+	apiKey string
+	state  *Fixtures
+	userID string
+
+	// targetIDs holds the audit log target ID of each Brewfile and brew command
+	targetIDs map[string]string
+
+	// batchStart is the index of the first run of each Brewfile's latest
+	// create or update, which determines its reported run status
+	batchStart map[string]int
+
+	// pending runs that have not finished yet
+	pending []*pendingRun
+}
+
+// newWorkspace creates a workspace from its initial state
+func newWorkspace(name, apiKey string, state *Fixtures) *workspace {
+	return &workspace{
+		name:       name,
+		apiKey:     apiKey,
+		state:      state,
+		userID:     newID(),
+		targetIDs:  make(map[string]string),
+		batchStart: make(map[string]int),
+	}
+}
+
+// targetID returns the stable audit log ID of a Brewfile or brew command
+func (ws *workspace) targetID(targetType, identifier string) string {
+	key := targetType + "/" + identifier
+	id, ok := ws.targetIDs[key]
+	if !ok {
+		id = newID()
+		ws.targetIDs[key] = id
+	}
+	return id
+}
+
+// pendingRun is a run that progresses as the server clock advances
+type pendingRun struct {
+	brewCommand string // label of the brew command the run belongs to, if any
+	brewfile    string // label of the Brewfile the run belongs to, if any
+	index       int    // position in the owner's run list
+
+	startAt  time.Time
+	finishAt time.Time
+	output   string
+	success  bool
+}
+
+// startRuns creates a run per device for a brew command or Brewfile.
+// Runs start at startAt and finish after the server's run duration; they are
+// applied immediately when that time has already passed.
+func (s *Server) startRuns(ws *workspace, brewCommand, brewfile, command string, targets []string, startAt time.Time) {
+	now := s.clock()
+	for _, device := range targets {
+		output, success := s.runResult(device, command)
+		run := &pendingRun{
+			brewCommand: brewCommand,
+			brewfile:    brewfile,
+			startAt:     startAt,
+			finishAt:    startAt.Add(s.runDuration),
+			output:      output,
+			success:     success,
+		}
+
+		if brewCommand != "" {
+			run.index = len(ws.state.BrewCommandRuns[brewCommand])
+			ws.state.BrewCommandRuns[brewCommand] = append(ws.state.BrewCommandRuns[brewCommand], brewcommands.BrewCommandRun{
+				Command:    command,
+				Label:      brewCommand,
+				Device:     device,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+				StartedAt:  devices.TimeOrStatus{Status: notStarted},
+				FinishedAt: devices.TimeOrStatus{Status: notFinished},
+			})
+		} else {
+			run.index = len(ws.state.BrewfileRuns[brewfile])
+			ws.state.BrewfileRuns[brewfile] = append(ws.state.BrewfileRuns[brewfile], brewfiles.BrewfileRun{
+				Label:      brewfile,
+				Device:     device,
+				CreatedAt:  formatTime(now),
+				UpdatedAt:  formatTime(now),
+				StartedAt:  notStarted,
+				FinishedAt: notFinished,
+			})
+		}
+		ws.pending = append(ws.pending, run)
+	}
+	s.settleRuns(ws)
+}
+
+// settleRuns applies the progress of pending runs up to the current time and
+// refreshes the run status reported by their brew commands and Brewfiles.
+// Callers must hold s.mu.
+func (s *Server) settleRuns(ws *workspace) {
+	if len(ws.pending) == 0 {
+		return
+	}
+
+	now := s.clock()
+	changedCommands := make(map[string]bool)
+	changedBrewfiles := make(map[string]bool)
+
+	remaining := ws.pending[:0]
+	for _, run := range ws.pending {
+		started := !now.Before(run.startAt)
+		finished := !now.Before(run.finishAt)
+		if started {
+			if run.brewCommand != "" {
+				applyBrewCommandRun(ws.state, run, finished)
+				changedCommands[run.brewCommand] = true
+			} else {
+				applyBrewfileRun(ws.state, run, finished)
+				changedBrewfiles[run.brewfile] = true
+			}
+		}
+		if !finished {
+			remaining = append(remaining, run)
+		}
+	}
+	ws.pending = remaining
+
+	for label := range changedCommands {
+		summariseBrewCommand(ws.state, label)
+	}
+	for label := range changedBrewfiles {
+		summariseBrewfile(ws, label)
+	}
+}
+
+// applyBrewCommandRun records the start, and optionally the end, of a brew command run
+func applyBrewCommandRun(state *Fixtures, run *pendingRun, finished bool) {
+	runs := state.BrewCommandRuns[run.brewCommand]
+	if run.index >= len(runs) {
+		return
+	}
+	r := &runs[run.index]
+	r.StartedAt = devices.TimeOrStatus{Time: &run.startAt}
+	r.UpdatedAt = run.startAt
+	if finished {
+		r.FinishedAt = devices.TimeOrStatus{Time: &run.finishAt}
+		r.UpdatedAt = run.finishAt
+		r.Success = run.success
+		r.Output = run.output
+	}
+}
+
+// applyBrewfileRun records the start, and optionally the end, of a Brewfile run
+func applyBrewfileRun(state *Fixtures, run *pendingRun, finished bool) {
+	runs := state.BrewfileRuns[run.brewfile]
+	if run.index >= len(runs) {
+		return
+	}
+	r := &runs[run.index]
+	r.StartedAt = formatTime(run.startAt)
+	r.UpdatedAt = formatTime(run.startAt)
+	if finished {
+		r.FinishedAt = formatTime(run.finishAt)
+		r.UpdatedAt = formatTime(run.finishAt)
+		r.Success = run.success
+		r.Output = run.output
+	}
+}
+
+// summariseBrewCommand sets a brew command's started and finished times from its runs:
+// the earliest start, and the latest finish once every run has finished
+func summariseBrewCommand(state *Fixtures, label string) {
+	index := slices.IndexFunc(state.BrewCommands, func(c brewcommands.BrewCommand) bool { return c.Label == label })
+	if index < 0 {
+		return
+	}
+
+	var started, finished *time.Time
+	allFinished := true
+	for _, run := range state.BrewCommandRuns[label] {
+		if run.StartedAt.Time != nil && (started == nil || run.StartedAt.Time.Before(*started)) {
+			started = run.StartedAt.Time
+		}
+		if run.FinishedAt.Time == nil {
+			allFinished = false
+		} else if finished == nil || run.FinishedAt.Time.After(*finished) {
+			finished = run.FinishedAt.Time
+		}
+	}
+
+	command := &state.BrewCommands[index]
+	command.StartedAt = devices.TimeOrStatus{Status: notStarted}
+	if started != nil {
+		command.StartedAt = devices.TimeOrStatus{Time: started}
+	}
+	command.FinishedAt = devices.TimeOrStatus{Status: notFinished}
+	if allFinished && finished != nil {
+		command.FinishedAt = devices.TimeOrStatus{Time: finished}
+	}
+}
+
+// summariseBrewfile sets a Brewfile's started and finished times from the runs
+// of its latest create or update
+func summariseBrewfile(ws *workspace, label string) {
+	state := ws.state
+	index := slices.IndexFunc(state.Brewfiles, func(b brewfiles.Brewfile) bool { return b.Label == label })
+	if index < 0 {
+		return
+	}
+
+	runs := state.BrewfileRuns[label]
+	if start := ws.batchStart[label]; start <= len(runs) {
+		runs = runs[start:]
+	}
+
+	started, finished := "", ""
+	allFinished := true
+	for _, run := range runs {
+		// RFC 3339 UTC timestamps sort lexically
+		if run.StartedAt != notStarted && (started == "" || run.StartedAt < started) {
+			started = run.StartedAt
+		}
+		if run.FinishedAt == notFinished {
+			allFinished = false
+		} else if run.FinishedAt > finished {
+			finished = run.FinishedAt
+		}
+	}
+
+	brewfile := &state.Brewfiles[index]
+	brewfile.StartedAt = notStarted
+	if started != "" {
+		brewfile.StartedAt = started
+	}
+	brewfile.FinishedAt = notFinished
+	if allFinished && finished != "" {
+		brewfile.FinishedAt = finished
+	}
+}
+
+// dropRuns forgets the pending runs of a deleted Brewfile
+func (ws *workspace) dropRuns(brewfile string) {
+	ws.pending = slices.DeleteFunc(ws.pending, func(run *pendingRun) bool { return run.brewfile == brewfile })
+	delete(ws.state.BrewfileRuns, brewfile)
+	delete(ws.batchStart, brewfile)
+}
+
+// resolveDeviceIDs converts the device UUIDs of a brew command's device_ids to
+// serial numbers. Serial numbers are accepted too, and checked by resolveDevices.
+func (ws *workspace) resolveDeviceIDs(ids []string) ([]string, error) {
+	serials := make([]string, 0, len(ids))
+	for _, id := range ids {
+		serial := id
+		for _, device := range ws.state.Devices {
+			if DeviceUUID(device.SerialNumber) == id {
+				serial = device.SerialNumber
+				break
+			}
+		}
+		serials = append(serials, serial)
+	}
+	return ws.resolveDevices(serials)
+}
+
+// resolveDevices checks that each serial number belongs to a device in the workspace
+func (ws *workspace) resolveDevices(serials []string) ([]string, error) {
+	var unknown []string
+	for _, serial := range serials {
+		if !slices.ContainsFunc(ws.state.Devices, func(d devices.Device) bool { return d.SerialNumber == serial }) {
+			unknown = append(unknown, serial)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("Devices not found: %s", strings.Join(unknown, ", "))
+	}
+	return serials, nil
+}
+
+// allDevices returns the serial numbers of every device in the workspace
+func (ws *workspace) allDevices() []string {
+	serials := make([]string, 0, len(ws.state.Devices))
+	for _, device := range ws.state.Devices {
+		serials = append(serials, device.SerialNumber)
+	}
+	return serials
+}
+
+// recordEvent appends an audit log event for a change made through the API
+func (s *Server) recordEvent(ws *workspace, eventType, targetType, identifier string, changes, snapshot map[string]any) {
+	ws.state.Events = append(ws.state.Events, events.Event{
+		ID:               newID(),
+		EventType:        eventType,
+		OccurredAt:       s.clock(),
+		ActorID:          ptr(ws.userID),
+		ActorType:        ptr("User"),
+		TargetID:         ptr(ws.targetID(targetType, identifier)),
+		TargetType:       ptr(targetType),
+		TargetIdentifier: ptr(identifier),
+		TargetSnapshot:   snapshot,
+		Changes:          changes,
+	})
+}
+
+// formatTime formats a timestamp the way the API reports string run times
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// splitList splits a comma separated request field into trimmed, non-empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// slugify turns brew arguments or a label into a URL-safe label, e.g.
+// "list --versions --formula" becomes "list-versions-formula"
+func slugify(value string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return sb.String()
+}
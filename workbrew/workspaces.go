@@ -0,0 +1,289 @@
+package workbrew
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+)
+
+// DefaultFanOutConcurrency is the number of workspaces a WorkspaceSet calls at once
+// unless changed with SetMaxConcurrency.
+const DefaultFanOutConcurrency = 4
+
+// WorkspaceSet manages clients for several Workbrew workspaces, each authenticated
+// with its own API key.
+//
+// Every workspace gets its own Client and HTTP transport, so calls to different
+// workspaces never share a base URL and may run concurrently. Do not call
+// SetWorkspace on a client obtained from a set; add another workspace instead.
+//
+// WorkspaceSet is safe for concurrent use.
+type WorkspaceSet struct {
+	mu             sync.RWMutex
+	clients        map[string]*Client
+	options        []client.ClientOption
+	maxConcurrency int
+}
+
+// NewWorkspaceSet creates a set of clients, one per workspace.
+//
+// Parameters:
+//   - apiKeys: The API key of each workspace, keyed by workspace slug
+//   - options: Client options applied to every workspace's client
+//
+// Returns:
+//   - *WorkspaceSet: The workspace set
+//   - error: Any error creating a workspace's client
+//
+// Example:
+//
+//	set, err := workbrew.NewWorkspaceSet(map[string]string{
+//	    "production": os.Getenv("WORKBREW_PRODUCTION_API_KEY"),
+//	    "staging":    os.Getenv("WORKBREW_STAGING_API_KEY"),
+//	}, client.WithLogger(logger))
+func NewWorkspaceSet(apiKeys map[string]string, options ...client.ClientOption) (*WorkspaceSet, error) {
+	s := &WorkspaceSet{
+		clients:        make(map[string]*Client, len(apiKeys)),
+		options:        options,
+		maxConcurrency: DefaultFanOutConcurrency,
+	}
+
+	for workspaceName, apiKey := range apiKeys {
+		if err := s.Add(workspaceName, apiKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// Add creates a client for a workspace and adds it to the set, replacing any
+// existing client for the same workspace.
+//
+// Parameters:
+//   - workspaceName: The workspace slug
+//   - apiKey: The API key for the workspace
+//   - options: Client options applied after the set's options, e.g. a workspace-specific rate limit
+//
+// Returns:
+//   - error: An error if the workspace name or API key is empty, or the client cannot be created
+func (s *WorkspaceSet) Add(workspaceName, apiKey string, options ...client.ClientOption) error {
+	if workspaceName == "" {
+		return fmt.Errorf("workspace name is required")
+	}
+	if apiKey == "" {
+		return fmt.Errorf("API key is required for workspace %q", workspaceName)
+	}
+
+	workspaceOptions := append(slices.Clip(s.options), options...)
+	c, err := NewClient(apiKey, workspaceName, workspaceOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to create client for workspace %q: %w", workspaceName, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[workspaceName] = c
+	return nil
+}
+
+// Remove removes a workspace from the set. Removing an unknown workspace is a no-op.
+func (s *WorkspaceSet) Remove(workspaceName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, workspaceName)
+}
+
+// Client returns the client for a workspace.
+//
+// Parameters:
+//   - workspaceName: The workspace slug
+//
+// Returns:
+//   - *Client: The workspace's client
+//   - error: An error if the workspace is not in the set
+//
+// Example:
+//
+//	production, err := set.Client("production")
+//	devices, _, err := production.Devices.ListDevices(ctx)
+func (s *WorkspaceSet) Client(workspaceName string) (*Client, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.clients[workspaceName]
+	if !ok {
+		return nil, fmt.Errorf("workspace %q is not in the set", workspaceName)
+	}
+	return c, nil
+}
+
+// Workspaces returns the workspace slugs in the set, sorted by name.
+func (s *WorkspaceSet) Workspaces() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.clients))
+	for name := range s.clients {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Len returns the number of workspaces in the set.
+func (s *WorkspaceSet) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.clients)
+}
+
+// SetMaxConcurrency sets how many workspaces FanOut and Collect call at once.
+// Values below 1 are treated as 1.
+func (s *WorkspaceSet) SetMaxConcurrency(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxConcurrency = max(n, 1)
+}
+
+// WorkspaceResult is the outcome of a fanned-out call for one workspace
+type WorkspaceResult[T any] struct {
+	Workspace string
+	Value     T
+	Err       error
+}
+
+// WorkspaceError reports a fanned-out call that failed in one workspace
+type WorkspaceError struct {
+	Workspace string
+	Err       error
+}
+
+// Error implements the error interface
+func (e *WorkspaceError) Error() string {
+	return fmt.Sprintf("workspace %s: %v", e.Workspace, e.Err)
+}
+
+// Unwrap returns the underlying error. Pass Err to client.IsRateLimited and
+// similar helpers to classify it.
+func (e *WorkspaceError) Unwrap() error {
+	return e.Err
+}
+
+// Tagged is an item returned by a fanned-out list call, tagged with the
+// workspace it came from
+type Tagged[T any] struct {
+	Workspace string `json:"workspace"`
+	Item      T      `json:"item"`
+}
+
+// FanOut calls fn for every workspace in the set, up to the set's maximum
+// concurrency at a time. A failure in one workspace does not stop the others.
+//
+// Parameters:
+//   - ctx: Context passed to every call
+//   - set: The workspaces to call
+//   - fn: The call to make against each workspace's client
+//
+// Returns:
+//   - []WorkspaceResult[T]: One result per workspace, sorted by workspace name
+//
+// Example:
+//
+//	results := workbrew.FanOut(ctx, set, func(ctx context.Context, c *workbrew.Client) (int, error) {
+//	    devices, _, err := c.Devices.ListDevices(ctx)
+//	    if err != nil {
+//	        return 0, err
+//	    }
+//	    return len(*devices), nil
+//	})
+func FanOut[T any](ctx context.Context, set *WorkspaceSet, fn func(ctx context.Context, c *Client) (T, error)) []WorkspaceResult[T] {
+	set.mu.RLock()
+	names := make([]string, 0, len(set.clients))
+	clients := make(map[string]*Client, len(set.clients))
+	for name, c := range set.clients {
+		names = append(names, name)
+		clients[name] = c
+	}
+	limit := set.maxConcurrency
+	set.mu.RUnlock()
+	slices.Sort(names)
+
+	results := make([]WorkspaceResult[T], len(names))
+	semaphore := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+
+	for i, name := range names {
+		results[i].Workspace = name
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i].Value, results[i].Err = fn(ctx, clients[name])
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+// Collect calls a list endpoint in every workspace and merges the items into one
+// slice, each tagged with its workspace. Items are grouped by workspace name and
+// keep the API's order within a workspace.
+//
+// Workspaces that fail are left out of the merged slice and reported in the
+// returned error, which joins one *WorkspaceError per failed workspace.
+//
+// Parameters:
+//   - ctx: Context passed to every call
+//   - set: The workspaces to call
+//   - fn: The list call to make against each workspace's client
+//
+// Returns:
+//   - []Tagged[E]: The merged items from every workspace that succeeded
+//   - error: The failures, or nil if every workspace succeeded
+//
+// Example:
+//
+//	vulns, err := workbrew.Collect(ctx, set, func(ctx context.Context, c *workbrew.Client) (*vulnerabilities.VulnerabilitiesResponse, *interfaces.Response, error) {
+//	    return c.Vulnerabilities.ListVulnerabilities(ctx)
+//	})
+//	for _, v := range vulns {
+//	    fmt.Printf("%s: %s\n", v.Workspace, v.Item.Formula)
+//	}
+func Collect[S ~[]E, E any](ctx context.Context, set *WorkspaceSet, fn func(ctx context.Context, c *Client) (*S, *interfaces.Response, error)) ([]Tagged[E], error) {
+	results := FanOut(ctx, set, func(ctx context.Context, c *Client) (*S, error) {
+		items, _, err := fn(ctx, c)
+		return items, err
+	})
+
+	var merged []Tagged[E]
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, &WorkspaceError{Workspace: result.Workspace, Err: result.Err})
+			continue
+		}
+		if result.Value == nil {
+			continue
+		}
+		for _, item := range *result.Value {
+			merged = append(merged, Tagged[E]{Workspace: result.Workspace, Item: item})
+		}
+	}
+
+	return merged, errors.Join(errs...)
+}
@@ -0,0 +1,171 @@
+package workbrew_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/client"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/interfaces"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/services/vulnerabilities"
+	"github.com/deploymenttheory/go-api-sdk-workbrew/workbrew/workbrewtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestWorkspaceSet serves three workspaces with different vulnerability fixtures
+func newTestWorkspaceSet(t *testing.T) (*workbrewtest.Server, *workbrew.WorkspaceSet) {
+	t.Helper()
+
+	staging := workbrewtest.DefaultFixtures()
+	staging.Vulnerabilities = staging.Vulnerabilities[:1]
+	empty := workbrewtest.DefaultFixtures()
+	empty.Vulnerabilities = nil
+
+	server := workbrewtest.NewServer(
+		workbrewtest.WithWorkspace("production", "production-key", nil),
+		workbrewtest.WithWorkspace("staging", "staging-key", staging),
+		workbrewtest.WithWorkspace("sandbox", "sandbox-key", empty),
+	)
+	t.Cleanup(server.Close)
+
+	set, err := workbrew.NewWorkspaceSet(map[string]string{
+		"production": "production-key",
+		"staging":    "staging-key",
+		"sandbox":    "sandbox-key",
+	}, client.WithLogger(zap.NewNop()), client.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	return server, set
+}
+
+func listVulnerabilities(ctx context.Context, c *workbrew.Client) (*vulnerabilities.VulnerabilitiesResponse, *interfaces.Response, error) {
+	return c.Vulnerabilities.ListVulnerabilities(ctx)
+}
+
+func TestNewWorkspaceSet_RequiresAPIKey(t *testing.T) {
+	_, err := workbrew.NewWorkspaceSet(map[string]string{"production": ""})
+	assert.ErrorContains(t, err, `API key is required for workspace "production"`)
+}
+
+func TestWorkspaceSet_Clients(t *testing.T) {
+	_, set := newTestWorkspaceSet(t)
+
+	assert.Equal(t, []string{"production", "sandbox", "staging"}, set.Workspaces())
+	assert.Equal(t, 3, set.Len())
+
+	production, err := set.Client("production")
+	require.NoError(t, err)
+	staging, err := set.Client("staging")
+	require.NoError(t, err)
+	assert.NotSame(t, production, staging)
+
+	_, err = set.Client("unknown")
+	assert.ErrorContains(t, err, `workspace "unknown" is not in the set`)
+
+	set.Remove("sandbox")
+	assert.Equal(t, []string{"production", "staging"}, set.Workspaces())
+}
+
+func TestWorkspaceSet_ConcurrentClientsAreIsolated(t *testing.T) {
+	server, set := newTestWorkspaceSet(t)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		for _, name := range set.Workspaces() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c, err := set.Client(name)
+				require.NoError(t, err)
+				_, _, err = c.Devices.ListDevices(context.Background())
+				assert.NoError(t, err)
+			}()
+		}
+	}
+	wg.Wait()
+
+	// Each request reached the workspace it was made for, authenticated by its own key
+	counts := make(map[string]int)
+	for _, request := range server.Requests() {
+		counts[request.Workspace]++
+	}
+	assert.Equal(t, map[string]int{"production": 10, "sandbox": 10, "staging": 10}, counts)
+}
+
+func TestCollect_MergesTaggedResults(t *testing.T) {
+	_, set := newTestWorkspaceSet(t)
+
+	vulns, err := workbrew.Collect(context.Background(), set, listVulnerabilities)
+	require.NoError(t, err)
+
+	var got []string
+	for _, v := range vulns {
+		got = append(got, v.Workspace+"/"+v.Item.Formula)
+	}
+	assert.Equal(t, []string{"production/curl", "production/wget", "staging/curl"}, got)
+}
+
+func TestCollect_ReportsFailedWorkspaces(t *testing.T) {
+	server, set := newTestWorkspaceSet(t)
+	server.InjectFault(workbrewtest.Fault{Workspace: "staging", StatusCode: 403})
+
+	vulns, err := workbrew.Collect(context.Background(), set, listVulnerabilities)
+	require.Error(t, err)
+	assert.Len(t, vulns, 2)
+
+	var workspaceErr *workbrew.WorkspaceError
+	require.True(t, errors.As(err, &workspaceErr))
+	assert.Equal(t, "staging", workspaceErr.Workspace)
+	assert.True(t, client.IsForbidden(workspaceErr.Err))
+}
+
+func TestFanOut_LimitsConcurrency(t *testing.T) {
+	_, set := newTestWorkspaceSet(t)
+	set.SetMaxConcurrency(1)
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	results := workbrew.FanOut(context.Background(), set, func(ctx context.Context, c *workbrew.Client) (int, error) {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+
+		devices, _, err := c.Devices.ListDevices(ctx)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+		if err != nil {
+			return 0, err
+		}
+		return len(*devices), nil
+	})
+
+	assert.Equal(t, 1, peak)
+	require.Len(t, results, 3)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+		assert.Equal(t, 2, result.Value)
+	}
+	assert.Equal(t, "production", results[0].Workspace)
+}
+
+func TestFanOut_CancelledContext(t *testing.T) {
+	_, set := newTestWorkspaceSet(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := workbrew.FanOut(ctx, set, func(ctx context.Context, c *workbrew.Client) (int, error) {
+		_, _, err := c.Devices.ListDevices(ctx)
+		return 0, err
+	})
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	}
+}